@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -15,20 +17,28 @@ func Provider() terraform.ResourceProvider {
 			"access_key": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
-				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
-					"AWS_ACCESS_KEY",
-					"AWS_ACCESS_KEY_ID",
-				}, nil),
+				DefaultFunc: schema.ChainDefaultFunc(
+					schema.MultiEnvDefaultFunc([]string{
+						"AWS_ACCESS_KEY",
+						"AWS_ACCESS_KEY_ID",
+					}, nil),
+					schema.CredentialsFileDefaultFunc(
+						"~/.aws/credentials", "default", "aws_access_key_id", nil),
+				),
 				Description: descriptions["access_key"],
 			},
 
 			"secret_key": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
-				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
-					"AWS_SECRET_KEY",
-					"AWS_SECRET_ACCESS_KEY",
-				}, nil),
+				DefaultFunc: schema.ChainDefaultFunc(
+					schema.MultiEnvDefaultFunc([]string{
+						"AWS_SECRET_KEY",
+						"AWS_SECRET_ACCESS_KEY",
+					}, nil),
+					schema.CredentialsFileDefaultFunc(
+						"~/.aws/credentials", "default", "aws_secret_access_key", nil),
+				),
 				Description: descriptions["secret_key"],
 			},
 
@@ -70,6 +80,7 @@ func Provider() terraform.ResourceProvider {
 		},
 
 		ConfigureFunc: providerConfigure,
+		ValidateFunc:  providerValidate,
 	}
 }
 
@@ -97,3 +108,17 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	return config.Client()
 }
+
+func providerValidate(d *schema.ResourceData) ([]string, []error) {
+	config := Config{
+		Region: d.Get("region").(string),
+	}
+
+	// The region may not be known yet if it is interpolated
+	if config.Region != "" && !config.IsValidRegion() {
+		return nil, []error{fmt.Errorf(
+			"region: not a valid region: %s", config.Region)}
+	}
+
+	return nil, nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -25,6 +26,32 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderValidate(t *testing.T) {
+	cases := []struct {
+		Region string
+		Err    bool
+	}{
+		{"us-west-2", false},
+		{"us-west-9", true},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(map[string]interface{}{
+			"access_key": "foo",
+			"secret_key": "bar",
+			"region":     tc.Region,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := Provider().Validate(terraform.NewResourceConfig(c))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: %s", i, es)
+		}
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
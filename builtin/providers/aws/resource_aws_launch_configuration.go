@@ -6,11 +6,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/aws-sdk-go/aws"
 	"github.com/hashicorp/aws-sdk-go/gen/autoscaling"
-	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -20,6 +20,11 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 		Create: resourceAwsLaunchConfigurationCreate,
 		Read:   resourceAwsLaunchConfigurationRead,
 		Delete: resourceAwsLaunchConfigurationDelete,
+		Exists: resourceAwsLaunchConfigurationExists,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Second),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -73,26 +78,48 @@ func resourceAwsLaunchConfiguration() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
-				Set: func(v interface{}) int {
-					return hashcode.String(v.(string))
-				},
+				Set:      schema.HashString,
 			},
 
 			"associate_public_ip_address": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
+				ForceNew: true,
 				Default:  false,
 			},
 
 			"spot_price": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateLaunchConfigurationSpotPrice,
 			},
 		},
 	}
 }
 
+// validateLaunchConfigurationSpotPrice verifies that the spot price is a
+// positive decimal number. An empty value means on-demand instances.
+func validateLaunchConfigurationSpotPrice(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if value == "" {
+		return
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		es = append(es, fmt.Errorf(
+			"%q must be a decimal number such as \"0.05\", got %q", k, value))
+		return
+	}
+	if price <= 0 {
+		es = append(es, fmt.Errorf(
+			"%q must be greater than zero, got %q", k, value))
+	}
+
+	return
+}
+
 func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface{}) error {
 	autoscalingconn := meta.(*AWSClient).autoscalingconn
 
@@ -132,9 +159,23 @@ func resourceAwsLaunchConfigurationCreate(d *schema.ResourceData, meta interface
 	log.Printf("[INFO] launch configuration ID: %s", d.Id())
 
 	// We put a Retry here since sometimes eventual consistency bites
-	// us and we need to retry a few times to get the LC to load properly
-	return resource.Retry(30*time.Second, func() error {
-		return resourceAwsLaunchConfigurationRead(d, meta)
+	// us and we need to retry a few times to get the LC to load properly.
+	// Any other error won't go away by retrying.
+	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() error {
+		exists, err := resourceAwsLaunchConfigurationExists(d, meta)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if !exists {
+			return resource.RetryableError(fmt.Errorf(
+				"launch configuration %s not found yet", d.Id()))
+		}
+
+		if err := resourceAwsLaunchConfigurationRead(d, meta); err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		return nil
 	})
 }
 
@@ -150,13 +191,12 @@ func resourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}
 	if err != nil {
 		return fmt.Errorf("Error retrieving launch configuration: %s", err)
 	}
-	if len(describConfs.LaunchConfigurations) == 0 {
-		d.SetId("")
-		return nil
-	}
 
-	// Verify AWS returned our launch configuration
-	if *describConfs.LaunchConfigurations[0].LaunchConfigurationName != d.Id() {
+	// Verify AWS returned our launch configuration. If it was deleted,
+	// Exists has already removed it from the state, so this only happens
+	// when a new launch configuration isn't visible yet.
+	if len(describConfs.LaunchConfigurations) == 0 ||
+		*describConfs.LaunchConfigurations[0].LaunchConfigurationName != d.Id() {
 		return fmt.Errorf(
 			"Unable to find launch configuration: %#v",
 			describConfs.LaunchConfigurations)
@@ -175,20 +215,44 @@ func resourceAwsLaunchConfigurationRead(d *schema.ResourceData, meta interface{}
 		d.Set("iam_instance_profile", nil)
 	}
 
+	if lc.AssociatePublicIPAddress != nil {
+		d.Set("associate_public_ip_address", *lc.AssociatePublicIPAddress)
+	} else {
+		d.Set("associate_public_ip_address", false)
+	}
+
 	if lc.SpotPrice != nil {
 		d.Set("spot_price", *lc.SpotPrice)
 	} else {
 		d.Set("spot_price", nil)
 	}
 
-	if lc.SecurityGroups != nil {
-		d.Set("security_groups", lc.SecurityGroups)
-	} else {
-		d.Set("security_groups", nil)
-	}
+	// Always write the security groups, using an empty list when the API
+	// returns none, so that an unset configuration and an empty response
+	// compare equal. Since this is a set, the order the API returns them
+	// in doesn't matter.
+	sgs := make([]string, 0, len(lc.SecurityGroups))
+	sgs = append(sgs, lc.SecurityGroups...)
+	d.Set("security_groups", sgs)
+
 	return nil
 }
 
+func resourceAwsLaunchConfigurationExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	autoscalingconn := meta.(*AWSClient).autoscalingconn
+
+	describeOpts := autoscaling.LaunchConfigurationNamesType{
+		LaunchConfigurationNames: []string{d.Id()},
+	}
+
+	describConfs, err := autoscalingconn.DescribeLaunchConfigurations(&describeOpts)
+	if err != nil {
+		return false, fmt.Errorf("Error retrieving launch configuration: %s", err)
+	}
+
+	return len(describConfs.LaunchConfigurations) > 0, nil
+}
+
 func resourceAwsLaunchConfigurationDelete(d *schema.ResourceData, meta interface{}) error {
 	autoscalingconn := meta.(*AWSClient).autoscalingconn
 
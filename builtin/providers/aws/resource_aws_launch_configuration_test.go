@@ -2,6 +2,7 @@ package aws
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/aws-sdk-go/aws"
@@ -36,6 +37,17 @@ func TestAccAWSLaunchConfiguration(t *testing.T) {
 				),
 			},
 
+			// Applying again refreshes the launch configuration first,
+			// so this verifies the flag survives a refresh round-trip.
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &conf),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "associate_public_ip_address", "true"),
+				),
+			},
+
 			resource.TestStep{
 				Config: TestAccAWSLaunchConfigurationWithSpotPriceConfig,
 				Check: resource.ComposeTestCheckFunc(
@@ -49,6 +61,63 @@ func TestAccAWSLaunchConfiguration(t *testing.T) {
 	})
 }
 
+func TestAccAWSLaunchConfiguration_securityGroups(t *testing.T) {
+	var before, after autoscaling.LaunchConfiguration
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLaunchConfigurationDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationSecurityGroupsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &before),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "security_groups.#", "2"),
+				),
+			},
+
+			// Applying the same configuration again must not recreate
+			// the launch configuration, which would happen if refresh
+			// produced a diff for the (ForceNew) security groups.
+			resource.TestStep{
+				Config: testAccAWSLaunchConfigurationSecurityGroupsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSLaunchConfigurationExists("aws_launch_configuration.bar", &after),
+					resource.TestCheckResourceAttr(
+						"aws_launch_configuration.bar", "security_groups.#", "2"),
+					testAccCheckAWSLaunchConfigurationNotRecreated(&before, &after),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAWSLaunchConfiguration_validateSpotPrice(t *testing.T) {
+	cases := []struct {
+		Value string
+		Err   bool
+	}{
+		{"", false},
+		{"0.05", false},
+		{"1", false},
+		{".5", false},
+		{"0,05", true},
+		{"abc", true},
+		{"0", true},
+		{"-0.05", true},
+		{"0.05 ", true},
+	}
+
+	for _, tc := range cases {
+		_, es := validateLaunchConfigurationSpotPrice(tc.Value, "spot_price")
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%q: expected err %t, got: %#v", tc.Value, tc.Err, es)
+		}
+	}
+}
+
 func testAccCheckAWSLaunchConfigurationDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*AWSClient).autoscalingconn
 
@@ -96,6 +165,23 @@ func testAccCheckAWSLaunchConfigurationAttributes(conf *autoscaling.LaunchConfig
 			return fmt.Errorf("Bad instance_type: %s", *conf.InstanceType)
 		}
 
+		if conf.AssociatePublicIPAddress == nil || !*conf.AssociatePublicIPAddress {
+			return fmt.Errorf("Bad associate_public_ip_address: %#v", conf.AssociatePublicIPAddress)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSLaunchConfigurationNotRecreated(
+	before, after *autoscaling.LaunchConfiguration) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !reflect.DeepEqual(*before, *after) {
+			return fmt.Errorf(
+				"Launch configuration was recreated:\n\n%#v\n\n%#v",
+				*before, *after)
+		}
+
 		return nil
 	}
 }
@@ -153,3 +239,25 @@ resource "aws_launch_configuration" "bar" {
   spot_price = "0.01"
 }
 `
+
+const testAccAWSLaunchConfigurationSecurityGroupsConfig = `
+resource "aws_security_group" "foo" {
+  name = "tf-test-lc-foo"
+  description = "foo"
+}
+
+resource "aws_security_group" "bar" {
+  name = "tf-test-lc-bar"
+  description = "bar"
+}
+
+resource "aws_launch_configuration" "bar" {
+  name = "foobar-terraform-test"
+  image_id = "ami-21f78e11"
+  instance_type = "t1.micro"
+  security_groups = [
+    "${aws_security_group.bar.name}",
+    "${aws_security_group.foo.name}",
+  ]
+}
+`
@@ -108,6 +108,10 @@ func resourceAwsRoute53RecordCreate(d *schema.ResourceData, meta interface{}) er
 		Target:     "accepted",
 		Timeout:    5 * time.Minute,
 		MinTimeout: 1 * time.Second,
+
+		// Spread out the retries of concurrent changes to the zone
+		Jitter: 1 * time.Second,
+
 		Refresh: func() (interface{}, string, error) {
 			resp, err := conn.ChangeResourceRecordSets(req)
 			if err != nil {
@@ -229,6 +233,10 @@ func resourceAwsRoute53RecordDelete(d *schema.ResourceData, meta interface{}) er
 		Target:     "accepted",
 		Timeout:    5 * time.Minute,
 		MinTimeout: 1 * time.Second,
+
+		// Spread out the retries of concurrent changes to the zone
+		Jitter: 1 * time.Second,
+
 		Refresh: func() (interface{}, string, error) {
 			_, err := conn.ChangeResourceRecordSets(req)
 			if err != nil {
@@ -57,6 +57,19 @@ func (v *FlagKVFile) Set(raw string) error {
 	return nil
 }
 
+// FlagStringSlice is a flag.Value implementation for parsing a flag that
+// can be given multiple times, such as '-target foo -target bar'.
+type FlagStringSlice []string
+
+func (v *FlagStringSlice) String() string {
+	return ""
+}
+
+func (v *FlagStringSlice) Set(raw string) error {
+	*v = append(*v, raw)
+	return nil
+}
+
 func loadKVFile(rawPath string) (map[string]string, error) {
 	path, err := homedir.Expand(rawPath)
 	if err != nil {
@@ -105,3 +105,22 @@ foo = "bar"
 		}
 	}
 }
+
+func TestFlagStringSlice_impl(t *testing.T) {
+	var _ flag.Value = new(FlagStringSlice)
+}
+
+func TestFlagStringSlice(t *testing.T) {
+	f := new(FlagStringSlice)
+	for _, v := range []string{"foo", "bar"} {
+		if err := f.Set(v); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	actual := []string(*f)
+	expected := []string{"foo", "bar"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
@@ -78,6 +78,7 @@ func formatPlanModuleExpand(
 			continue
 		}
 
+		dataSource := strings.HasPrefix(name, "data.")
 		if moduleName != "" {
 			name = moduleName + "." + name
 		}
@@ -94,6 +95,12 @@ func formatPlanModuleExpand(
 		case terraform.DiffCreate:
 			color = "green"
 			symbol = "+"
+
+			// Data sources are read rather than created
+			if dataSource {
+				color = "cyan"
+				symbol = "<="
+			}
 		case terraform.DiffDestroy:
 			color = "red"
 			symbol = "-"
@@ -125,8 +132,14 @@ func formatPlanModuleExpand(
 			attrDiff := rdiff.Attributes[attrK]
 
 			v := attrDiff.New
+			u := attrDiff.Old
 			if attrDiff.NewComputed {
 				v = "<computed>"
+			} else if attrDiff.Sensitive {
+				v = "<sensitive>"
+			}
+			if attrDiff.Sensitive {
+				u = "<sensitive>"
 			}
 
 			newResource := ""
@@ -138,7 +151,7 @@ func formatPlanModuleExpand(
 				"    %s:%s %#v => %#v%s\n",
 				attrK,
 				strings.Repeat(" ", keyLen-len(attrK)),
-				attrDiff.Old,
+				u,
 				v,
 				newResource))
 		}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// planJSON is the structure of a plan formatted with FormatPlanJSON.
+type planJSON struct {
+	Resources []*planResourceJSON `json:"resources"`
+}
+
+// planResourceJSON is a single resource instance that the plan changes.
+type planResourceJSON struct {
+	// Address is the address of the resource instance, such as
+	// "module.foo.aws_instance.bar.0", the same as in the text output.
+	Address string `json:"address"`
+
+	// Action is one of "create", "read", "update", "destroy" or "replace".
+	Action string `json:"action"`
+
+	Attributes map[string]*planAttrJSON `json:"attributes"`
+}
+
+// planAttrJSON is the change of a single attribute. The values are left
+// out if the attribute is sensitive, and After is left out if the new
+// value is computed and so isn't known until apply.
+type planAttrJSON struct {
+	Before      *string `json:"before,omitempty"`
+	After       *string `json:"after,omitempty"`
+	Computed    bool    `json:"computed"`
+	Removed     bool    `json:"removed"`
+	RequiresNew bool    `json:"requires_new"`
+	Sensitive   bool    `json:"sensitive"`
+}
+
+// FormatPlanJSON takes a plan and returns it as indented JSON, so that
+// it can be inspected by other programs. All modules are expanded and the
+// resources are sorted by address.
+func FormatPlanJSON(p *terraform.Plan) ([]byte, error) {
+	result := &planJSON{Resources: make([]*planResourceJSON, 0)}
+	if p.Diff != nil {
+		for _, m := range p.Diff.Modules {
+			result.Resources = append(
+				result.Resources, formatPlanModuleJSON(m)...)
+		}
+	}
+
+	sort.Sort(planResourcesJSON(result.Resources))
+
+	return json.MarshalIndent(result, "", "    ")
+}
+
+func formatPlanModuleJSON(m *terraform.ModuleDiff) []*planResourceJSON {
+	var moduleName string
+	if !m.IsRoot() {
+		moduleName = fmt.Sprintf("module.%s", strings.Join(m.Path[1:], "."))
+	}
+
+	result := make([]*planResourceJSON, 0, len(m.Resources))
+	for name, rdiff := range m.Resources {
+		if rdiff.Empty() {
+			continue
+		}
+
+		dataSource := strings.HasPrefix(name, "data.")
+		if moduleName != "" {
+			name = moduleName + "." + name
+		}
+
+		action := "update"
+		switch rdiff.ChangeType() {
+		case terraform.DiffDestroyCreate:
+			action = "replace"
+		case terraform.DiffCreate:
+			action = "create"
+
+			// Data sources are read rather than created
+			if dataSource {
+				action = "read"
+			}
+		case terraform.DiffDestroy:
+			action = "destroy"
+		}
+
+		attrs := make(map[string]*planAttrJSON, len(rdiff.Attributes))
+		for k, attrDiff := range rdiff.Attributes {
+			attr := &planAttrJSON{
+				Computed:    attrDiff.NewComputed,
+				Removed:     attrDiff.NewRemoved,
+				RequiresNew: attrDiff.RequiresNew,
+				Sensitive:   attrDiff.Sensitive,
+			}
+			if !attrDiff.Sensitive {
+				before := attrDiff.Old
+				attr.Before = &before
+				if !attrDiff.NewComputed {
+					after := attrDiff.New
+					attr.After = &after
+				}
+			}
+
+			attrs[k] = attr
+		}
+
+		result = append(result, &planResourceJSON{
+			Address:    name,
+			Action:     action,
+			Attributes: attrs,
+		})
+	}
+
+	return result
+}
+
+// planResourcesJSON is a sort.Interface sorting resources by address.
+type planResourcesJSON []*planResourceJSON
+
+func (s planResourcesJSON) Len() int           { return len(s) }
+func (s planResourcesJSON) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s planResourcesJSON) Less(i, j int) bool { return s[i].Address < s[j].Address }
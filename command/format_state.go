@@ -124,6 +124,10 @@ func formatStateModuleExpand(
 			// Output each attribute
 			for _, ak := range attrKeys {
 				av := is.Attributes[ak]
+				if is.IsSensitive(ak) {
+					av = "<sensitive>"
+				}
+
 				buf.WriteString(fmt.Sprintf("  %s = %s\n", ak, av))
 			}
 		}
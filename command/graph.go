@@ -58,7 +58,9 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
-	c.Ui.Output(terraform.GraphDot(g, nil))
+	c.Ui.Output(terraform.GraphDot(g, &terraform.GraphDotOpts{
+		ModuleDepth: moduleDepth,
+	}))
 
 	return 0
 }
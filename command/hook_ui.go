@@ -193,6 +193,14 @@ func (h *UiHook) PreRefresh(
 	h.once.Do(h.init)
 
 	id := n.HumanId()
+
+	// Data sources are read without a prior state
+	if s == nil {
+		h.ui.Output(h.Colorize.Color(fmt.Sprintf(
+			"[reset][bold]%s: Reading data...", id)))
+		return terraform.HookActionContinue, nil
+	}
+
 	h.ui.Output(h.Colorize.Color(fmt.Sprintf(
 		"[reset][bold]%s: Refreshing state... (ID: %s)",
 		id, s.ID)))
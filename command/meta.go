@@ -38,6 +38,13 @@ type Meta struct {
 	input         bool
 	variables     map[string]string
 
+	// parallelism is the limit of concurrent operations during a walk
+	// of the graph. If this is zero, the context's default is used.
+	parallelism int
+
+	// targets are the resources to limit operations to, from -target
+	targets []string
+
 	color bool
 	oldUi cli.Ui
 
@@ -261,6 +268,12 @@ func (m *Meta) contextOpts() *terraform.ContextOpts {
 	}
 	opts.Variables = vs
 	opts.UIInput = m.UIInput()
+	if m.parallelism > 0 {
+		opts.Parallelism = m.parallelism
+	}
+	if len(m.targets) > 0 {
+		opts.Targets = m.targets
+	}
 
 	return &opts
 }
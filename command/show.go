@@ -18,11 +18,13 @@ type ShowCommand struct {
 
 func (c *ShowCommand) Run(args []string) int {
 	var moduleDepth int
+	var jsonOutput bool
 
 	args = c.Meta.process(args, false)
 
 	cmdFlags := flag.NewFlagSet("show", flag.ContinueOnError)
 	cmdFlags.IntVar(&moduleDepth, "module-depth", 0, "module-depth")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -93,6 +95,22 @@ func (c *ShowCommand) Run(args []string) int {
 		return 1
 	}
 
+	if jsonOutput {
+		if plan == nil {
+			c.Ui.Error("The -json flag can only be used with a plan file.")
+			return 1
+		}
+
+		out, err := FormatPlanJSON(plan)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting plan: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(string(out))
+		return 0
+	}
+
 	if plan != nil {
 		c.Ui.Output(FormatPlan(&FormatPlanOpts{
 			Plan:        plan,
@@ -119,6 +137,10 @@ Usage: terraform show [options] [path]
 
 Options:
 
+  -json               If specified, the plan is output as JSON so that it
+                      can be read by other programs. Only plan files can
+                      be output as JSON.
+
   -module-depth=n     Specifies the depth of modules to show in the output.
                       By default this is zero. -1 will expand all.
 
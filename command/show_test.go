@@ -1,9 +1,12 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config/module"
@@ -124,6 +127,134 @@ func TestShow_plan(t *testing.T) {
 	}
 }
 
+func TestShow_planJSON(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Module: new(module.Tree),
+		Diff: &terraform.Diff{
+			Modules: []*terraform.ModuleDiff{
+				&terraform.ModuleDiff{
+					Path: []string{"root"},
+					Resources: map[string]*terraform.InstanceDiff{
+						"test_instance.foo": &terraform.InstanceDiff{
+							Attributes: map[string]*terraform.ResourceAttrDiff{
+								"ami": &terraform.ResourceAttrDiff{
+									Old:         "foo",
+									New:         "bar",
+									RequiresNew: true,
+								},
+								"ip": &terraform.ResourceAttrDiff{
+									Old:         "1.2.3.4",
+									NewComputed: true,
+								},
+								"password": &terraform.ResourceAttrDiff{
+									Old:       "foo",
+									New:       "bar",
+									Sensitive: true,
+								},
+							},
+							Destroy: true,
+						},
+						"data.test_data.foo": &terraform.InstanceDiff{
+							Attributes: map[string]*terraform.ResourceAttrDiff{
+								"id": &terraform.ResourceAttrDiff{
+									NewComputed: true,
+									RequiresNew: true,
+								},
+							},
+						},
+					},
+				},
+				&terraform.ModuleDiff{
+					Path: []string{"root", "child"},
+					Resources: map[string]*terraform.InstanceDiff{
+						"test_instance.bar": &terraform.InstanceDiff{
+							Destroy: true,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		planPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &actual); err != nil {
+		t.Fatalf("err: %s\n\n%s", err, ui.OutputWriter.String())
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal([]byte(testShowPlanJSONStr), &expected); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad:\n\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestShow_planJSONEmpty(t *testing.T) {
+	planPath := testPlanFile(t, &terraform.Plan{
+		Module: new(module.Tree),
+	})
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		planPath,
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	expected := "{\n    \"resources\": []\n}"
+	if actual != expected {
+		t.Fatalf("bad: %q", actual)
+	}
+}
+
+func TestShow_stateJSON(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	ui := new(cli.MockUi)
+	c := &ShowCommand{
+		Meta: Meta{
+			ContextOpts: testCtxConfig(testProvider()),
+			Ui:          ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		statePath,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("bad: \n%s", ui.OutputWriter.String())
+	}
+}
+
 func TestShow_state(t *testing.T) {
 	originalState := testState()
 	statePath := testStateFile(t, originalState)
@@ -143,3 +274,55 @@ func TestShow_state(t *testing.T) {
 		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
 	}
 }
+
+const testShowPlanJSONStr = `
+{
+    "resources": [
+        {
+            "address": "data.test_data.foo",
+            "action": "read",
+            "attributes": {
+                "id": {
+                    "before": "",
+                    "computed": true,
+                    "removed": false,
+                    "requires_new": true,
+                    "sensitive": false
+                }
+            }
+        },
+        {
+            "address": "module.child.test_instance.bar",
+            "action": "destroy",
+            "attributes": {}
+        },
+        {
+            "address": "test_instance.foo",
+            "action": "replace",
+            "attributes": {
+                "ami": {
+                    "before": "foo",
+                    "after": "bar",
+                    "computed": false,
+                    "removed": false,
+                    "requires_new": true,
+                    "sensitive": false
+                },
+                "ip": {
+                    "before": "1.2.3.4",
+                    "computed": true,
+                    "removed": false,
+                    "requires_new": false,
+                    "sensitive": false
+                },
+                "password": {
+                    "computed": false,
+                    "removed": false,
+                    "requires_new": false,
+                    "sensitive": true
+                }
+            }
+        }
+    ]
+}
+`
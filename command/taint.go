@@ -3,7 +3,11 @@ package command
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
 )
 
 // TaintCommand is a cli.Command implementation that manually taints
@@ -36,11 +40,10 @@ func (c *TaintCommand) Run(args []string) int {
 		return 1
 	}
 
-	name := args[0]
-	if module == "" {
-		module = "root"
-	} else {
-		module = "root." + module
+	name, module, err := taintResourceName(args[0], module)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
 	}
 
 	// Get the state that we'll be modifying
@@ -98,10 +101,7 @@ func (c *TaintCommand) Run(args []string) int {
 			return c.allowMissingExit(name, module)
 		}
 
-		c.Ui.Error(fmt.Sprintf(
-			"The resource %s couldn't be found in the module %s.",
-			name,
-			module))
+		c.Ui.Error(taintMissingError(mod, name, module))
 		return 1
 	}
 
@@ -127,6 +127,12 @@ Usage: terraform taint [options] name
   Manually mark a resource as tainted, forcing a destroy and recreate
   on the next plan/apply.
 
+  The name is the resource in the state, such as "aws_instance.foo". A
+  single instance of a resource with a count is named by its index, such
+  as "aws_instance.foo.0". Resources in child modules can be named with
+  the module path, such as "module.consul.aws_instance.foo", or with the
+  -module flag.
+
   This will not modify your infrastructure. This command changes your
   state to mark a resource as tainted so that during the next plan or
   apply, that resource will be destroyed and recreated. This command on
@@ -169,3 +175,65 @@ func (c *TaintCommand) allowMissingExit(name, module string) int {
 		name, module))
 	return 0
 }
+
+// taintResourceName parses the name of the resource given to the taint
+// and untaint commands, along with the -module flag, returning the name
+// of the resource and the dotted path of its module, such as
+// "root.consul". The name may include the module path itself, such as
+// "module.consul.aws_instance.foo", but then -module can't be set.
+func taintResourceName(name, module string) (string, string, error) {
+	var path []string
+	for strings.HasPrefix(name, "module.") {
+		parts := strings.SplitN(name, ".", 3)
+		if len(parts) < 3 || parts[1] == "" {
+			return "", "", fmt.Errorf("Invalid resource name: %s", name)
+		}
+
+		path = append(path, parts[1])
+		name = parts[2]
+	}
+
+	if len(path) > 0 {
+		if module != "" {
+			return "", "", fmt.Errorf(
+				"The -module flag can't be used with a resource name that " +
+					"includes the module path.")
+		}
+
+		module = strings.Join(path, ".")
+	}
+
+	if module == "" {
+		return name, "root", nil
+	}
+
+	return name, "root." + module, nil
+}
+
+// taintMissingError returns the error message for a resource that isn't
+// in the module state. If the resource has a count, the message names
+// its instances, since each one must be given separately.
+func taintMissingError(
+	mod *terraform.ModuleState, name, module string) string {
+	var instances []string
+	for k := range mod.Resources {
+		idx := strings.TrimPrefix(k, name+".")
+		if idx == k {
+			continue
+		}
+		if _, err := strconv.Atoi(idx); err == nil {
+			instances = append(instances, k)
+		}
+	}
+
+	msg := fmt.Sprintf(
+		"The resource %s couldn't be found in the module %s.", name, module)
+	if len(instances) > 0 {
+		sort.Strings(instances)
+		msg += fmt.Sprintf(
+			"\n\nThis resource has a count. Specify a single instance of it,\n"+
+				"one of: %s", strings.Join(instances, ", "))
+	}
+
+	return msg
+}
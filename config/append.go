@@ -29,6 +29,13 @@ func Append(c1, c2 *Config) (*Config, error) {
 		}
 	}
 
+	if len(c1.Locals) > 0 || len(c2.Locals) > 0 {
+		c.Locals = make(
+			[]*Local, 0, len(c1.Locals)+len(c2.Locals))
+		c.Locals = append(c.Locals, c1.Locals...)
+		c.Locals = append(c.Locals, c2.Locals...)
+	}
+
 	if len(c1.Modules) > 0 || len(c2.Modules) > 0 {
 		c.Modules = make(
 			[]*Module, 0, len(c1.Modules)+len(c2.Modules))
@@ -5,6 +5,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -32,6 +33,7 @@ type Config struct {
 	ProviderConfigs []*ProviderConfig
 	Resources       []*Resource
 	Variables       []*Variable
+	Locals          []*Local
 	Outputs         []*Output
 
 	// The fields below can be filled in by loaders for validation
@@ -47,21 +49,39 @@ type Module struct {
 	Name      string
 	Source    string
 	RawConfig *RawConfig
+
+	// RawCount is the count of instances of this module. It is nil
+	// if no count was given, in which case there is a single instance
+	// that isn't indexed.
+	RawCount *RawConfig
+
+	// DependsOn are the resources and modules, such as "module.foo",
+	// that must be applied before anything in this module.
+	DependsOn []string
 }
 
 // ProviderConfig is the configuration for a resource provider.
 //
 // For example, Terraform needs to set the AWS access keys for the AWS
 // resource provider.
+//
+// A provider can be configured more than once, such as for multiple
+// regions, by giving the extra configurations an Alias. Resources use
+// an aliased configuration with `provider = "aws.west"`.
 type ProviderConfig struct {
 	Name      string
+	Alias     string
 	RawConfig *RawConfig
 }
 
 // A resource represents a single Terraform resource in the configuration.
 // A Terraform resource is something that represents some component that
 // can be created and managed, and has some properties associated with it.
+//
+// Data sources, from "data" blocks, are resources with the mode
+// DataResourceMode. They are only read, never created or destroyed.
 type Resource struct {
+	Mode         ResourceMode
 	Name         string
 	Type         string
 	RawCount     *RawConfig
@@ -69,12 +89,27 @@ type Resource struct {
 	Provisioners []*Provisioner
 	DependsOn    []string
 	Lifecycle    ResourceLifecycle
+
+	// Provider is the full name of the provider configuration to use,
+	// such as "aws.west". If empty, the provider is taken from the type.
+	Provider string
 }
 
+// ResourceMode is the mode of a resource: whether it is managed by
+// Terraform or is a data source that Terraform only reads.
+type ResourceMode int
+
+const (
+	ManagedResourceMode ResourceMode = iota
+	DataResourceMode
+)
+
 // ResourceLifecycle is used to store the lifecycle tuning parameters
 // to allow customized behavior
 type ResourceLifecycle struct {
-	CreateBeforeDestroy bool `hcl:"create_before_destroy"`
+	CreateBeforeDestroy bool     `hcl:"create_before_destroy"`
+	PreventDestroy      bool     `hcl:"prevent_destroy"`
+	IgnoreChanges       []string `hcl:"ignore_changes"`
 }
 
 // Provisioner is a configured provisioner step on a resource.
@@ -89,6 +124,46 @@ type Variable struct {
 	Name        string
 	Default     interface{}
 	Description string
+	Validations []*VariableValidation
+
+	// Attributes, if set, gives the variable an object type: a map
+	// with exactly these named attributes. They're sorted by name.
+	Attributes []*VariableAttribute
+}
+
+// VariableAttribute is a named attribute of a variable with an object
+// type. Attributes that aren't optional must be set.
+type VariableAttribute struct {
+	Name     string
+	Type     VariableAttributeType
+	Optional bool
+}
+
+// VariableAttributeType is the type of the value of an attribute.
+type VariableAttributeType byte
+
+const (
+	VariableAttributeTypeUnknown VariableAttributeType = iota
+	VariableAttributeTypeString
+	VariableAttributeTypeNumber
+	VariableAttributeTypeBool
+)
+
+// VariableValidation is a rule that the value of a variable must
+// satisfy. The condition is stored under the "condition" key of the
+// RawConfig and may only reference the variable itself. If the
+// condition isn't true, ErrorMessage is reported.
+type VariableValidation struct {
+	RawConfig    *RawConfig
+	ErrorMessage string
+}
+
+// Local is a named value defined within a "locals" block. Locals can
+// be referenced elsewhere in the same module as "${local.name}". The
+// value is stored under the "value" key of the RawConfig.
+type Local struct {
+	Name      string
+	RawConfig *RawConfig
 }
 
 // Output is an output defined within the configuration. An output is
@@ -123,11 +198,36 @@ func ProviderConfigName(t string, pcs []*ProviderConfig) string {
 	return lk
 }
 
+// FullName returns the name of the provider configuration, which
+// includes the alias if there is one, such as "aws.west".
+func (c *ProviderConfig) FullName() string {
+	if c.Alias == "" {
+		return c.Name
+	}
+
+	return fmt.Sprintf("%s.%s", c.Name, c.Alias)
+}
+
 // A unique identifier for this module.
 func (r *Module) Id() string {
 	return fmt.Sprintf("%s", r.Name)
 }
 
+// Count returns the count of this module. The RawCount must be
+// interpolated before this is called.
+func (r *Module) Count() (int, error) {
+	if r.RawCount == nil {
+		return 1, nil
+	}
+
+	v, err := strconv.ParseInt(r.RawCount.Value().(string), 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(v), nil
+}
+
 // Count returns the count of this resource.
 func (r *Resource) Count() (int, error) {
 	v, err := strconv.ParseInt(r.RawCount.Value().(string), 0, 0)
@@ -138,8 +238,13 @@ func (r *Resource) Count() (int, error) {
 	return int(v), nil
 }
 
-// A unique identifier for this resource.
+// A unique identifier for this resource. Data sources are prefixed
+// with "data.", such as "data.aws_ami.ubuntu".
 func (r *Resource) Id() string {
+	if r.Mode == DataResourceMode {
+		return fmt.Sprintf("data.%s.%s", r.Type, r.Name)
+	}
+
 	return fmt.Sprintf("%s.%s", r.Type, r.Name)
 }
 
@@ -170,14 +275,39 @@ func (c *Config) Validate() error {
 			continue
 		}
 
-		interp := false
-		fn := func(ast.Node) (string, error) {
-			interp = true
-			return "", nil
-		}
+		// String defaults can be computed from other variables, but
+		// maps must be static.
+		if v.Type() == VariableTypeString {
+			vars, err := v.defaultVariables()
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"Variable '%s': %s", v.Name, err))
+			}
+			for _, dv := range vars {
+				uv, ok := dv.(*UserVariable)
+				if !ok {
+					errs = append(errs, fmt.Errorf(
+						"Variable '%s': default can only reference "+
+							"other variables: %s",
+						v.Name, dv.FullKey()))
+					continue
+				}
 
-		w := &interpolationWalker{F: fn}
-		if v.Default != nil {
+				if _, ok := varMap[uv.Name]; !ok {
+					errs = append(errs, fmt.Errorf(
+						"Variable '%s': unknown variable referenced "+
+							"in default: %s",
+						v.Name, uv.Name))
+				}
+			}
+		} else if v.Default != nil {
+			interp := false
+			fn := func(ast.Node) (string, error) {
+				interp = true
+				return "", nil
+			}
+
+			w := &interpolationWalker{F: fn}
 			if err := reflectwalk.Walk(v.Default, w); err == nil {
 				if interp {
 					errs = append(errs, fmt.Errorf(
@@ -186,8 +316,25 @@ func (c *Config) Validate() error {
 				}
 			}
 		}
+
+		for _, vv := range v.Validations {
+			errs = append(errs, vv.validate(v)...)
+		}
+
+		if len(v.Attributes) > 0 {
+			if v.Type() != VariableTypeMap {
+				errs = append(errs, fmt.Errorf(
+					"Variable '%s': default must be a mapping of the "+
+						"attributes of the object type", v.Name))
+			} else if m, ok := v.Default.(map[string]string); ok {
+				errs = append(errs, v.ValidateAttributes(m)...)
+			}
+		}
 	}
 
+	// Check that the defaults don't depend on themselves
+	errs = append(errs, c.validateVariableDefaultCycles()...)
+
 	// Check for references to user variables that do not actually
 	// exist and record those errors.
 	for source, vs := range vars {
@@ -217,6 +364,13 @@ func (c *Config) Validate() error {
 						source,
 						v.FullKey()))
 				}
+			case *EachVariable:
+				if v.Type == EachValueInvalid {
+					errs = append(errs, fmt.Errorf(
+						"%s: invalid each variable: %s",
+						source,
+						v.FullKey()))
+				}
 			case *PathVariable:
 				if v.Type == PathValueInvalid {
 					errs = append(errs, fmt.Errorf(
@@ -228,9 +382,70 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Check that all locals are unique and don't reference values that
+	// are only available in the context of a resource.
+	locals := make(map[string]*Local)
+	dupped := make(map[string]struct{})
+	for _, l := range c.Locals {
+		if _, ok := locals[l.Name]; ok {
+			if _, ok := dupped[l.Name]; !ok {
+				dupped[l.Name] = struct{}{}
+
+				errs = append(errs, fmt.Errorf(
+					"local '%s': local repeated multiple times",
+					l.Name))
+			}
+
+			continue
+		}
+
+		locals[l.Name] = l
+
+		for _, v := range l.RawConfig.Variables {
+			if _, ok := v.(*CountVariable); ok {
+				errs = append(errs, fmt.Errorf(
+					"local '%s': cannot reference count variable: %s",
+					l.Name,
+					v.FullKey()))
+			}
+		}
+	}
+	dupped = nil
+
+	// Check for references to locals that do not exist
+	for source, vs := range vars {
+		for _, v := range vs {
+			lv, ok := v.(*LocalVariable)
+			if !ok {
+				continue
+			}
+
+			if _, ok := locals[lv.Name]; !ok {
+				errs = append(errs, fmt.Errorf(
+					"%s: unknown local referenced: %s",
+					source,
+					lv.Name))
+			}
+		}
+	}
+
+	// Check that each provider is only configured once
+	providerSet := make(map[string]struct{})
+	for _, p := range c.ProviderConfigs {
+		name := p.FullName()
+		if _, ok := providerSet[name]; ok {
+			errs = append(errs, fmt.Errorf(
+				"provider.%s: declared multiple times, you can only "+
+					"declare a provider once", name))
+			continue
+		}
+
+		providerSet[name] = struct{}{}
+	}
+
 	// Check that all references to modules are valid
 	modules := make(map[string]*Module)
-	dupped := make(map[string]struct{})
+	dupped = make(map[string]struct{})
 	for _, m := range c.Modules {
 		// Check for duplicates
 		if _, ok := modules[m.Id()]; ok {
@@ -262,6 +477,37 @@ func (c *Config) Validate() error {
 				m.Id()))
 		}
 
+		// Verify the count only references user variables, since it
+		// must be known when the graph is built.
+		if m.RawCount != nil {
+			for _, v := range m.RawCount.Variables {
+				if _, ok := v.(*UserVariable); !ok {
+					errs = append(errs, fmt.Errorf(
+						"%s: module count can only reference user variables: %s",
+						m.Id(),
+						v.FullKey()))
+				}
+			}
+
+			m.RawCount.interpolate(func(root ast.Node) (string, error) {
+				out, t, err := lang.Eval(
+					lang.FixedValueTransform(
+						root, &ast.LiteralNode{Value: "5", Typex: ast.TypeString}),
+					nil)
+				if err != nil {
+					return "", err
+				}
+
+				return interpolationResultString(out, t), nil
+			}, nil)
+			if _, err := m.Count(); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"%s: module count must be an integer",
+					m.Id()))
+			}
+			m.RawCount.init()
+		}
+
 		// Check that the name matches our regexp
 		if !NameRegexp.Match([]byte(m.Name)) {
 			errs = append(errs, fmt.Errorf(
@@ -301,11 +547,32 @@ func (c *Config) Validate() error {
 				continue
 			}
 
-			if _, ok := modules[mv.Name]; !ok {
+			m, ok := modules[mv.Name]
+			if !ok {
 				errs = append(errs, fmt.Errorf(
 					"%s: unknown module referenced: %s",
 					source,
 					mv.Name))
+				continue
+			}
+
+			// Instances of a module with a count can only be referenced
+			// by index or all together with a splat.
+			if m.RawCount != nil && !mv.Multi {
+				errs = append(errs, fmt.Errorf(
+					"%s: module %s has a count, so its outputs must be "+
+						"referenced by index or with a splat: %s",
+					source,
+					mv.Name,
+					mv.FullKey()))
+			}
+			if m.RawCount == nil && mv.Multi && mv.Index != -1 {
+				errs = append(errs, fmt.Errorf(
+					"%s: module %s doesn't have a count, so it can't be "+
+						"referenced by index: %s",
+					source,
+					mv.Name,
+					mv.FullKey()))
 			}
 		}
 	}
@@ -328,28 +595,64 @@ func (c *Config) Validate() error {
 	}
 	dupped = nil
 
+	// Verify depends on for modules points to resources and modules
+	// that all exist
+	for _, m := range c.Modules {
+		for _, d := range m.DependsOn {
+			// Check if we contain interpolations
+			rc, err := NewRawConfig(map[string]interface{}{
+				"value": d,
+			})
+			if err == nil && len(rc.Variables) > 0 {
+				errs = append(errs, fmt.Errorf(
+					"module %s: depends on value cannot contain interpolations: %s",
+					m.Id(), d))
+				continue
+			}
+
+			if strings.HasPrefix(d, "module.") {
+				name := d[len("module."):]
+				if _, ok := modules[name]; !ok {
+					errs = append(errs, fmt.Errorf(
+						"module %s: module depends on non-existent module '%s'",
+						m.Id(), name))
+				} else if name == m.Name {
+					errs = append(errs, fmt.Errorf(
+						"module %s: module can't depend on itself",
+						m.Id()))
+				}
+
+				continue
+			}
+
+			if _, ok := resources[d]; !ok {
+				errs = append(errs, fmt.Errorf(
+					"module %s: module depends on non-existent resource '%s'",
+					m.Id(), d))
+			}
+		}
+	}
+
 	// Validate resources
 	for n, r := range resources {
 		// Verify count variables
 		for _, v := range r.RawCount.Variables {
-			switch v.(type) {
+			switch rv := v.(type) {
 			case *CountVariable:
 				errs = append(errs, fmt.Errorf(
 					"%s: resource count can't reference count variable: %s",
 					n,
 					v.FullKey()))
-			case *ModuleVariable:
-				errs = append(errs, fmt.Errorf(
-					"%s: resource count can't reference module variable: %s",
-					n,
-					v.FullKey()))
 			case *ResourceVariable:
-				errs = append(errs, fmt.Errorf(
-					"%s: resource count can't reference resource variable: %s",
-					n,
-					v.FullKey()))
-			case *UserVariable:
-				// Good
+				if rv.ResourceId() == n {
+					errs = append(errs, fmt.Errorf(
+						"%s: resource count can't reference itself: %s",
+						n,
+						v.FullKey()))
+				}
+			case *ModuleVariable, *UserVariable:
+				// Good. Resource and module variables must be known
+				// when planning, which is checked then.
 			default:
 				panic("Unknown type in count var: " + n)
 			}
@@ -359,7 +662,7 @@ func (c *Config) Validate() error {
 		r.RawCount.interpolate(func(root ast.Node) (string, error) {
 			// Execute the node but transform the AST so that it returns
 			// a fixed value of "5" for all interpolations.
-			out, _, err := lang.Eval(
+			out, t, err := lang.Eval(
 				lang.FixedValueTransform(
 					root, &ast.LiteralNode{Value: "5", Typex: ast.TypeString}),
 				nil)
@@ -367,8 +670,8 @@ func (c *Config) Validate() error {
 				return "", err
 			}
 
-			return out.(string), nil
-		})
+			return interpolationResultString(out, t), nil
+		}, nil)
 		_, err := strconv.ParseInt(r.RawCount.Value().(string), 0, 0)
 		if err != nil {
 			errs = append(errs, fmt.Errorf(
@@ -439,7 +742,7 @@ func (c *Config) Validate() error {
 				continue
 			}
 
-			id := fmt.Sprintf("%s.%s", rv.Type, rv.Name)
+			id := rv.ResourceId()
 			if _, ok := resources[id]; !ok {
 				errs = append(errs, fmt.Errorf(
 					"%s: unknown resource '%s' referenced in variable %s",
@@ -466,6 +769,14 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Check that dynamic blocks are well formed and that the each
+	// variables are only used within their content.
+	for source, rc := range c.rawConfigs() {
+		if err := validateDynamicBlocks(rc.Raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", source, err))
+		}
+	}
+
 	// Check that all variables are in the proper context
 	for source, rc := range c.rawConfigs() {
 		walker := &interpolationWalker{
@@ -513,15 +824,179 @@ func (c *Config) InterpolatedVariables() map[string][]InterpolatedVariable {
 	return result
 }
 
+// VariableDefaults returns the defaults of all the variables in the
+// configuration, keyed like DefaultsMap. Defaults that reference other
+// variables are computed, using the values in vs, keyed by variable
+// name, for the variables that are set and the defaults otherwise.
+// Defaults that depend on values that aren't known yet, or on required
+// variables that aren't set, are UnknownVariableValue.
+func (c *Config) VariableDefaults(vs map[string]string) (map[string]string, error) {
+	result := make(map[string]string)
+	varMap := make(map[string]*Variable)
+	for _, v := range c.Variables {
+		varMap[v.Name] = v
+		for k, val := range v.DefaultsMap() {
+			result[k] = val
+		}
+	}
+
+	// Computed defaults are resolved as they're referenced, so that
+	// they're computed after the defaults they depend on.
+	done := make(map[string]struct{})
+	var resolve func(string, []string) (string, error)
+	resolve = func(k string, stack []string) (string, error) {
+		name := k[len("var."):]
+		if val, ok := vs[name]; ok {
+			return val, nil
+		}
+
+		v, ok := varMap[name]
+		if !ok || v.Type() != VariableTypeString {
+			val, ok := result[k]
+			if !ok {
+				val = UnknownVariableValue
+			}
+
+			return val, nil
+		}
+		if v.Default == nil {
+			return UnknownVariableValue, nil
+		}
+		if _, ok := done[name]; ok {
+			return result[k], nil
+		}
+		for _, n := range stack {
+			if n == name {
+				return "", fmt.Errorf(
+					"Variable '%s': default depends on itself", name)
+			}
+		}
+
+		vars, err := v.defaultVariables()
+		if err != nil {
+			return "", err
+		}
+		if len(vars) == 0 {
+			done[name] = struct{}{}
+			return result[k], nil
+		}
+
+		unknown := false
+		values := make(map[string]ast.Variable, len(vars))
+		for dk, _ := range vars {
+			val, err := resolve(dk, append(stack, name))
+			if err != nil {
+				return "", err
+			}
+			if val == UnknownVariableValue {
+				unknown = true
+			}
+
+			values[dk] = ast.Variable{Value: val, Type: ast.TypeString}
+		}
+
+		// If anything the default depends on isn't known, neither is
+		// the default.
+		if unknown {
+			result[k] = UnknownVariableValue
+			done[name] = struct{}{}
+			return UnknownVariableValue, nil
+		}
+
+		rc, err := NewRawConfig(map[string]interface{}{
+			"value": v.Default,
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := rc.Interpolate(values); err != nil {
+			return "", fmt.Errorf(
+				"Variable '%s': error computing default: %s", name, err)
+		}
+
+		val, ok := rc.Config()["value"].(string)
+		if !ok {
+			val = UnknownVariableValue
+		}
+
+		result[k] = val
+		done[name] = struct{}{}
+		return val, nil
+	}
+
+	for _, v := range c.Variables {
+		if v.Type() != VariableTypeString || v.Default == nil {
+			continue
+		}
+
+		if _, err := resolve("var."+v.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// validateVariableDefaultCycles checks that no variable has a default
+// that depends on itself, directly or through other defaults.
+func (c *Config) validateVariableDefaultCycles() []error {
+	deps := make(map[string][]string)
+	for _, v := range c.Variables {
+		if v.Type() != VariableTypeString {
+			continue
+		}
+
+		vars, err := v.defaultVariables()
+		if err != nil {
+			continue
+		}
+		for _, dv := range vars {
+			if uv, ok := dv.(*UserVariable); ok {
+				deps[v.Name] = append(deps[v.Name], uv.Name)
+			}
+		}
+	}
+
+	var errs []error
+	for _, v := range c.Variables {
+		seen := make(map[string]struct{})
+		queue := deps[v.Name]
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if n == v.Name {
+				errs = append(errs, fmt.Errorf(
+					"Variable '%s': default depends on itself", v.Name))
+				break
+			}
+			if _, ok := seen[n]; ok {
+				continue
+			}
+
+			seen[n] = struct{}{}
+			queue = append(queue, deps[n]...)
+		}
+	}
+
+	return errs
+}
+
 // rawConfigs returns all of the RawConfigs that are available keyed by
 // a human-friendly source.
 func (c *Config) rawConfigs() map[string]*RawConfig {
 	result := make(map[string]*RawConfig)
 	for _, pc := range c.ProviderConfigs {
-		source := fmt.Sprintf("provider config '%s'", pc.Name)
+		source := fmt.Sprintf("provider config '%s'", pc.FullName())
 		result[source] = pc.RawConfig
 	}
 
+	for _, m := range c.Modules {
+		if m.RawCount != nil {
+			source := fmt.Sprintf("module '%s' count", m.Id())
+			result[source] = m.RawCount
+		}
+	}
+
 	for _, rc := range c.Resources {
 		source := fmt.Sprintf("resource '%s'", rc.Id())
 		result[source+" count"] = rc.RawCount
@@ -535,6 +1010,11 @@ func (c *Config) rawConfigs() map[string]*RawConfig {
 		}
 	}
 
+	for _, l := range c.Locals {
+		source := fmt.Sprintf("local '%s'", l.Name)
+		result[source] = l.RawConfig
+	}
+
 	for _, o := range c.Outputs {
 		source := fmt.Sprintf("output '%s'", o.Name)
 		result[source] = o.RawConfig
@@ -607,6 +1087,26 @@ func (m *Module) mergerMerge(other merger) merger {
 	if m2.Source != "" {
 		result.Source = m2.Source
 	}
+	if m2.RawCount != nil {
+		result.RawCount = m2.RawCount
+	}
+	if len(m2.DependsOn) > 0 {
+		result.DependsOn = m2.DependsOn
+	}
+
+	return &result
+}
+
+func (l *Local) mergerName() string {
+	return l.Name
+}
+
+func (l *Local) mergerMerge(m merger) merger {
+	l2 := m.(*Local)
+
+	result := *l
+	result.Name = l2.Name
+	result.RawConfig = result.RawConfig.merge(l2.RawConfig)
 
 	return &result
 }
@@ -626,7 +1126,7 @@ func (o *Output) mergerMerge(m merger) merger {
 }
 
 func (c *ProviderConfig) mergerName() string {
-	return c.Name
+	return c.FullName()
 }
 
 func (c *ProviderConfig) mergerMerge(m merger) merger {
@@ -640,7 +1140,7 @@ func (c *ProviderConfig) mergerMerge(m merger) merger {
 }
 
 func (r *Resource) mergerName() string {
-	return fmt.Sprintf("%s.%s", r.Type, r.Name)
+	return r.Id()
 }
 
 func (r *Resource) mergerMerge(m merger) merger {
@@ -659,12 +1159,16 @@ func (r *Resource) mergerMerge(m merger) merger {
 		result.Provisioners = r2.Provisioners
 	}
 
+	if r2.Provider != "" {
+		result.Provider = r2.Provider
+	}
+
 	return &result
 }
 
 // DefaultsMap returns a map of default values for this variable.
 func (v *Variable) DefaultsMap() map[string]string {
-	if v.Default == nil {
+	if v.Default == nil && len(v.Attributes) == 0 {
 		return nil
 	}
 
@@ -673,8 +1177,21 @@ func (v *Variable) DefaultsMap() map[string]string {
 	case VariableTypeString:
 		return map[string]string{n: v.Default.(string)}
 	case VariableTypeMap:
+		// Optional attributes of an object type are empty unless set
+		defaults := make(map[string]string)
+		for _, a := range v.Attributes {
+			if a.Optional {
+				defaults[a.Name] = ""
+			}
+		}
+		if m, ok := v.Default.(map[string]string); ok {
+			for k, val := range m {
+				defaults[k] = val
+			}
+		}
+
 		result := flatmap.Flatten(map[string]interface{}{
-			n: v.Default.(map[string]string),
+			n: defaults,
 		})
 		result[n] = v.Name
 
@@ -698,6 +1215,12 @@ func (v *Variable) Merge(v2 *Variable) *Variable {
 	if v2.Description != "" {
 		result.Description = v2.Description
 	}
+	if len(v2.Validations) > 0 {
+		result.Validations = v2.Validations
+	}
+	if len(v2.Attributes) > 0 {
+		result.Attributes = v2.Attributes
+	}
 
 	return &result
 }
@@ -705,6 +1228,10 @@ func (v *Variable) Merge(v2 *Variable) *Variable {
 // Type returns the type of varialbe this is.
 func (v *Variable) Type() VariableType {
 	if v.Default == nil {
+		if len(v.Attributes) > 0 {
+			return VariableTypeMap
+		}
+
 		return VariableTypeString
 	}
 
@@ -731,7 +1258,210 @@ func (v *Variable) mergerMerge(m merger) merger {
 	return v.Merge(m.(*Variable))
 }
 
+// defaultVariables returns the variables referenced by the default of
+// a string variable, keyed by their full name such as "var.foo".
+func (v *Variable) defaultVariables() (map[string]InterpolatedVariable, error) {
+	if v.Default == nil {
+		return nil, nil
+	}
+
+	rc, err := NewRawConfig(map[string]interface{}{
+		"value": v.Default,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.Variables, nil
+}
+
 // Required tests whether a variable is required or not.
 func (v *Variable) Required() bool {
 	return v.Default == nil
 }
+
+// ValidateValue checks the given value of the variable against its
+// validation rules, returning an error for every rule that fails.
+// Values that aren't known yet always pass.
+func (v *Variable) ValidateValue(value string) []error {
+	if value == UnknownVariableValue {
+		return nil
+	}
+
+	vs := map[string]ast.Variable{
+		fmt.Sprintf("var.%s", v.Name): ast.Variable{
+			Value: value,
+			Type:  ast.TypeString,
+		},
+	}
+
+	var errs []error
+	for _, vv := range v.Validations {
+		// Interpolate a copy since the rules are shared by every
+		// module instance.
+		rc, err := NewRawConfig(vv.RawConfig.Raw)
+		if err != nil {
+			return []error{err}
+		}
+		if err := rc.Interpolate(vs); err != nil {
+			errs = append(errs, fmt.Errorf(
+				"Variable '%s': error checking validation rule: %s",
+				v.Name, err))
+			continue
+		}
+
+		raw, ok := rc.Config()["condition"]
+		if !ok {
+			// The condition isn't known yet
+			continue
+		}
+
+		var result bool
+		if err := mapstructure.WeakDecode(raw, &result); err != nil {
+			errs = append(errs, fmt.Errorf(
+				"Variable '%s': validation condition must be a bool: %s",
+				v.Name, err))
+			continue
+		}
+
+		if !result {
+			errs = append(errs, fmt.Errorf(
+				"Variable '%s': %s", v.Name, vv.ErrorMessage))
+		}
+	}
+
+	return errs
+}
+
+// ValidateAttributes checks the values of a variable with an object
+// type, keyed by attribute name, against the attributes of the type.
+// Values that aren't known yet aren't type checked.
+func (v *Variable) ValidateAttributes(values map[string]string) []error {
+	var errs []error
+	attrs := make(map[string]*VariableAttribute, len(v.Attributes))
+	for _, a := range v.Attributes {
+		attrs[a.Name] = a
+
+		value, ok := values[a.Name]
+		if !ok {
+			if !a.Optional {
+				errs = append(errs, fmt.Errorf(
+					"Variable '%s': required attribute not set: %s",
+					v.Name, a.Name))
+			}
+
+			continue
+		}
+		if value == UnknownVariableValue {
+			continue
+		}
+
+		var err error
+		switch a.Type {
+		case VariableAttributeTypeNumber:
+			_, err = strconv.ParseFloat(value, 64)
+		case VariableAttributeTypeBool:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf(
+				"Variable '%s': attribute %s must be a %s, got %q",
+				v.Name, a.Name, a.Type, value))
+		}
+	}
+
+	ks := make([]string, 0, len(values))
+	for k, _ := range values {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	for _, k := range ks {
+		if _, ok := attrs[k]; !ok {
+			errs = append(errs, fmt.Errorf(
+				"Variable '%s': unknown attribute: %s", v.Name, k))
+		}
+	}
+
+	return errs
+}
+
+// ParseVariableAttribute parses the type of an attribute of an object
+// type, such as "string", "number", "bool" or "optional(number)".
+func ParseVariableAttribute(name, raw string) (*VariableAttribute, error) {
+	result := &VariableAttribute{Name: name}
+
+	t := strings.TrimSpace(raw)
+	if strings.HasPrefix(t, "optional(") && strings.HasSuffix(t, ")") {
+		result.Optional = true
+		t = strings.TrimSpace(t[len("optional(") : len(t)-1])
+	}
+
+	switch t {
+	case "string":
+		result.Type = VariableAttributeTypeString
+	case "number":
+		result.Type = VariableAttributeTypeNumber
+	case "bool":
+		result.Type = VariableAttributeTypeBool
+	default:
+		return nil, fmt.Errorf(
+			"attribute %s: unknown type %q, must be string, number "+
+				"or bool", name, raw)
+	}
+
+	return result, nil
+}
+
+func (t VariableAttributeType) String() string {
+	switch t {
+	case VariableAttributeTypeString:
+		return "string"
+	case VariableAttributeTypeNumber:
+		return "number"
+	case VariableAttributeTypeBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// validate checks that the rule is complete and only references the
+// given variable.
+func (vv *VariableValidation) validate(v *Variable) []error {
+	var errs []error
+	if v.Type() != VariableTypeString {
+		errs = append(errs, fmt.Errorf(
+			"Variable '%s': validation rules can only be used with "+
+				"string variables", v.Name))
+	}
+	if vv.ErrorMessage == "" {
+		errs = append(errs, fmt.Errorf(
+			"Variable '%s': validation error_message must be set",
+			v.Name))
+	}
+
+	if _, ok := vv.RawConfig.Raw["condition"]; !ok {
+		errs = append(errs, fmt.Errorf(
+			"Variable '%s': validation condition must be set", v.Name))
+		return errs
+	}
+
+	self := false
+	for _, rv := range vv.RawConfig.Variables {
+		if uv, ok := rv.(*UserVariable); ok && uv.Name == v.Name {
+			self = true
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf(
+			"Variable '%s': validation condition can only reference "+
+				"var.%s, got %s", v.Name, v.Name, rv.FullKey()))
+	}
+	if !self {
+		errs = append(errs, fmt.Errorf(
+			"Variable '%s': validation condition must reference var.%s",
+			v.Name, v.Name))
+	}
+
+	return errs
+}
@@ -41,6 +41,12 @@ func (c *Config) TestString() string {
 		buf.WriteString("\n\n")
 	}
 
+	if len(c.Locals) > 0 {
+		buf.WriteString("Locals:\n\n")
+		buf.WriteString(localsStr(c.Locals))
+		buf.WriteString("\n\n")
+	}
+
 	if len(c.Outputs) > 0 {
 		buf.WriteString("Outputs:\n\n")
 		buf.WriteString(outputsStr(c.Outputs))
@@ -80,6 +86,51 @@ func modulesStr(ms []*Module) string {
 		for _, k := range ks {
 			result += fmt.Sprintf("  %s\n", k)
 		}
+
+		if len(m.DependsOn) > 0 {
+			result += fmt.Sprintf("  dependsOn\n")
+			for _, d := range m.DependsOn {
+				result += fmt.Sprintf("    %s\n", d)
+			}
+		}
+	}
+
+	return strings.TrimSpace(result)
+}
+
+func localsStr(ls []*Local) string {
+	ns := make([]string, 0, len(ls))
+	m := make(map[string]*Local)
+	for _, l := range ls {
+		ns = append(ns, l.Name)
+		m[l.Name] = l
+	}
+	sort.Strings(ns)
+
+	result := ""
+	for _, n := range ns {
+		l := m[n]
+
+		result += fmt.Sprintf("%s\n", n)
+
+		if len(l.RawConfig.Variables) > 0 {
+			result += fmt.Sprintf("  vars\n")
+			for _, rawV := range l.RawConfig.Variables {
+				kind := "unknown"
+				str := rawV.FullKey()
+
+				switch rawV.(type) {
+				case *LocalVariable:
+					kind = "local"
+				case *ResourceVariable:
+					kind = "resource"
+				case *UserVariable:
+					kind = "user"
+				}
+
+				result += fmt.Sprintf("    %s: %s\n", kind, str)
+			}
+		}
 	}
 
 	return strings.TrimSpace(result)
@@ -129,8 +180,8 @@ func providerConfigsStr(pcs []*ProviderConfig) string {
 	ns := make([]string, 0, len(pcs))
 	m := make(map[string]*ProviderConfig)
 	for _, n := range pcs {
-		ns = append(ns, n.Name)
-		m[n.Name] = n
+		ns = append(ns, n.FullName())
+		m[n.FullName()] = n
 	}
 	sort.Strings(ns)
 
@@ -178,7 +229,7 @@ func resourcesStr(rs []*Resource) string {
 	ks := make([]string, 0, len(rs))
 	mapping := make(map[string]int)
 	for i, r := range rs {
-		k := fmt.Sprintf("%s[%s]", r.Type, r.Name)
+		k := resourceStrKey(r)
 		ks = append(ks, k)
 		mapping[k] = i
 	}
@@ -190,9 +241,8 @@ func resourcesStr(rs []*Resource) string {
 	for _, i := range order {
 		r := rs[i]
 		result += fmt.Sprintf(
-			"%s[%s] (x%s)\n",
-			r.Type,
-			r.Name,
+			"%s (x%s)\n",
+			resourceStrKey(r),
 			r.RawCount.Value())
 
 		ks := make([]string, 0, len(r.RawConfig.Raw))
@@ -295,3 +345,12 @@ func variablesStr(vs []*Variable) string {
 
 	return strings.TrimSpace(result)
 }
+
+func resourceStrKey(r *Resource) string {
+	k := fmt.Sprintf("%s[%s]", r.Type, r.Name)
+	if r.Mode == DataResourceMode {
+		k = "data." + k
+	}
+
+	return k
+}
@@ -69,8 +69,8 @@ func TestConfigValidate_countCountVar(t *testing.T) {
 
 func TestConfigValidate_countModuleVar(t *testing.T) {
 	c := testConfig(t, "validate-count-module-var")
-	if err := c.Validate(); err == nil {
-		t.Fatal("should not be valid")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
 	}
 }
 
@@ -83,6 +83,13 @@ func TestConfigValidate_countNotInt(t *testing.T) {
 
 func TestConfigValidate_countResourceVar(t *testing.T) {
 	c := testConfig(t, "validate-count-resource-var")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_countSelfVar(t *testing.T) {
+	c := testConfig(t, "validate-count-self-var")
 	if err := c.Validate(); err == nil {
 		t.Fatal("should not be valid")
 	}
@@ -130,6 +137,76 @@ func TestConfigValidate_dupResource(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_local(t *testing.T) {
+	c := testConfig(t, "validate-local")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_localCountVar(t *testing.T) {
+	c := testConfig(t, "validate-local-count-var")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_localUnknown(t *testing.T) {
+	c := testConfig(t, "validate-local-unknown")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_moduleCountResourceVar(t *testing.T) {
+	c := testConfig(t, "validate-module-count-resource-var")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_moduleCountVar(t *testing.T) {
+	c := testConfig(t, "validate-module-count-var")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_moduleCountSplat(t *testing.T) {
+	c := testConfig(t, "validate-module-count-splat")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_moduleCountNoIndex(t *testing.T) {
+	c := testConfig(t, "validate-module-count-no-index")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_moduleIndexNoCount(t *testing.T) {
+	c := testConfig(t, "validate-module-index-no-count")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_moduleDependsOn(t *testing.T) {
+	c := testConfig(t, "validate-module-depends-on")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_moduleDependsOnBad(t *testing.T) {
+	c := testConfig(t, "validate-module-depends-on-bad")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
 func TestConfigValidate_moduleNameBad(t *testing.T) {
 	c := testConfig(t, "validate-module-name-bad")
 	if err := c.Validate(); err == nil {
@@ -186,6 +263,20 @@ func TestConfigValidate_pathVarInvalid(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_providerMulti(t *testing.T) {
+	c := testConfig(t, "validate-provider-multi")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_providerMultiGood(t *testing.T) {
+	c := testConfig(t, "validate-provider-multi-good")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func TestConfigValidate_provConnSplatOther(t *testing.T) {
 	c := testConfig(t, "validate-prov-conn-splat-other")
 	if err := c.Validate(); err != nil {
@@ -284,6 +375,20 @@ func TestConfigValidate_varDefaultInterpolate(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_varDefaultInterpolateVar(t *testing.T) {
+	c := testConfig(t, "validate-var-default-interpolate-var")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigValidate_varDefaultInterpolateCycle(t *testing.T) {
+	c := testConfig(t, "validate-var-default-interpolate-cycle")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
 func TestConfigValidate_varMultiExactNonSlice(t *testing.T) {
 	c := testConfig(t, "validate-var-multi-exact-non-slice")
 	if err := c.Validate(); err != nil {
@@ -326,6 +431,34 @@ func TestConfigValidate_varModuleInvalid(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_varObject(t *testing.T) {
+	c := testConfig(t, "validate-var-object")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("should be valid: %s", err)
+	}
+}
+
+func TestConfigValidate_varObjectBadDefault(t *testing.T) {
+	c := testConfig(t, "validate-var-object-bad-default")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
+func TestConfigValidate_varValidation(t *testing.T) {
+	c := testConfig(t, "validate-var-validation")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("should be valid: %s", err)
+	}
+}
+
+func TestConfigValidate_varValidationOtherVar(t *testing.T) {
+	c := testConfig(t, "validate-var-validation-other-var")
+	if err := c.Validate(); err == nil {
+		t.Fatal("should not be valid")
+	}
+}
+
 func TestNameRegexp(t *testing.T) {
 	cases := []struct {
 		Input string
@@ -360,6 +493,22 @@ func TestProviderConfigName(t *testing.T) {
 	}
 }
 
+func TestProviderConfigFullName(t *testing.T) {
+	cases := []struct {
+		Config   *ProviderConfig
+		Expected string
+	}{
+		{&ProviderConfig{Name: "aws"}, "aws"},
+		{&ProviderConfig{Name: "aws", Alias: "west"}, "aws.west"},
+	}
+
+	for i, tc := range cases {
+		if actual := tc.Config.FullName(); actual != tc.Expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+}
+
 func TestVariableDefaultsMap(t *testing.T) {
 	cases := []struct {
 		Default interface{}
@@ -397,6 +546,259 @@ func TestVariableDefaultsMap(t *testing.T) {
 	}
 }
 
+func TestConfigVariableDefaults(t *testing.T) {
+	c := &Config{
+		Variables: []*Variable{
+			&Variable{
+				Name: "env",
+			},
+			&Variable{
+				Name:    "region",
+				Default: "us-east-1",
+			},
+			&Variable{
+				Name:    "prefix",
+				Default: "${var.env}-${var.region}",
+			},
+			&Variable{
+				Name:    "name",
+				Default: "${upper(var.prefix)}",
+			},
+		},
+	}
+
+	cases := []struct {
+		Input  map[string]string
+		Output map[string]string
+	}{
+		{
+			map[string]string{"env": "prod"},
+			map[string]string{
+				"var.region": "us-east-1",
+				"var.prefix": "prod-us-east-1",
+				"var.name":   "PROD-US-EAST-1",
+			},
+		},
+
+		{
+			map[string]string{"env": "prod", "prefix": "foo"},
+			map[string]string{
+				"var.region": "us-east-1",
+				"var.prefix": "${var.env}-${var.region}",
+				"var.name":   "FOO",
+			},
+		},
+
+		{
+			nil,
+			map[string]string{
+				"var.region": "us-east-1",
+				"var.prefix": UnknownVariableValue,
+				"var.name":   UnknownVariableValue,
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := c.VariableDefaults(tc.Input)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Output) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestConfigVariableDefaults_cycle(t *testing.T) {
+	c := &Config{
+		Variables: []*Variable{
+			&Variable{
+				Name:    "foo",
+				Default: "${var.bar}",
+			},
+			&Variable{
+				Name:    "bar",
+				Default: "${var.foo}",
+			},
+		},
+	}
+
+	if _, err := c.VariableDefaults(nil); err == nil {
+		t.Fatal("should error")
+	}
+	if errs := c.validateVariableDefaultCycles(); len(errs) != 2 {
+		t.Fatalf("bad: %#v", errs)
+	}
+}
+
+func TestVariableDefaultsMap_object(t *testing.T) {
+	v := &Variable{
+		Name: "foo",
+		Attributes: []*VariableAttribute{
+			&VariableAttribute{
+				Name: "bar",
+				Type: VariableAttributeTypeString,
+			},
+			&VariableAttribute{
+				Name:     "baz",
+				Type:     VariableAttributeTypeString,
+				Optional: true,
+			},
+		},
+	}
+
+	actual := v.DefaultsMap()
+	expected := map[string]string{
+		"var.foo":     "foo",
+		"var.foo.baz": "",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestVariableValidateAttributes(t *testing.T) {
+	v := &Variable{
+		Name: "subnet",
+		Attributes: []*VariableAttribute{
+			&VariableAttribute{
+				Name: "cidr_block",
+				Type: VariableAttributeTypeString,
+			},
+			&VariableAttribute{
+				Name:     "name",
+				Type:     VariableAttributeTypeString,
+				Optional: true,
+			},
+			&VariableAttribute{
+				Name: "public",
+				Type: VariableAttributeTypeBool,
+			},
+			&VariableAttribute{
+				Name:     "size",
+				Type:     VariableAttributeTypeNumber,
+				Optional: true,
+			},
+		},
+	}
+
+	cases := []struct {
+		Values map[string]string
+		Errors int
+	}{
+		{
+			map[string]string{
+				"cidr_block": "10.0.1.0/24",
+				"public":     "true",
+			},
+			0,
+		},
+
+		{
+			map[string]string{
+				"cidr_block": "10.0.1.0/24",
+				"public":     UnknownVariableValue,
+				"size":       "2.5",
+			},
+			0,
+		},
+
+		// Missing required attribute
+		{
+			map[string]string{
+				"public": "false",
+			},
+			1,
+		},
+
+		// Bad types
+		{
+			map[string]string{
+				"cidr_block": "10.0.1.0/24",
+				"public":     "yes please",
+				"size":       "large",
+			},
+			2,
+		},
+
+		// Unknown attribute
+		{
+			map[string]string{
+				"cidr_block": "10.0.1.0/24",
+				"public":     "true",
+				"zone":       "us-east-1a",
+			},
+			1,
+		},
+	}
+
+	for i, tc := range cases {
+		errs := v.ValidateAttributes(tc.Values)
+		if len(errs) != tc.Errors {
+			t.Fatalf("%d: bad: %#v", i, errs)
+		}
+	}
+}
+
+func TestParseVariableAttribute(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Output *VariableAttribute
+		Error  bool
+	}{
+		{
+			"string",
+			&VariableAttribute{
+				Name: "foo",
+				Type: VariableAttributeTypeString,
+			},
+			false,
+		},
+
+		{
+			"optional(number)",
+			&VariableAttribute{
+				Name:     "foo",
+				Type:     VariableAttributeTypeNumber,
+				Optional: true,
+			},
+			false,
+		},
+
+		{
+			"bool",
+			&VariableAttribute{
+				Name: "foo",
+				Type: VariableAttributeTypeBool,
+			},
+			false,
+		},
+
+		{
+			"list",
+			nil,
+			true,
+		},
+
+		{
+			"optional(map)",
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		actual, err := ParseVariableAttribute("foo", tc.Input)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%s: err: %s", tc.Input, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Output) {
+			t.Fatalf("%s: bad: %#v", tc.Input, actual)
+		}
+	}
+}
+
 func testConfig(t *testing.T, name string) *Config {
 	c, err := Load(filepath.Join(fixtureDir, name, "main.tf"))
 	if err != nil {
@@ -405,3 +807,38 @@ func testConfig(t *testing.T, name string) *Config {
 
 	return c
 }
+
+func TestVariableValidateValue(t *testing.T) {
+	rc, err := NewRawConfig(map[string]interface{}{
+		"condition": `${length(regexall("^[0-9.]+/[0-9]+$", var.cidr)) > 0}`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	v := &Variable{
+		Name: "cidr",
+		Validations: []*VariableValidation{
+			&VariableValidation{
+				RawConfig:    rc,
+				ErrorMessage: "must be a CIDR",
+			},
+		},
+	}
+
+	cases := []struct {
+		Value string
+		Error bool
+	}{
+		{"10.0.0.0/16", false},
+		{"10.0.0.0", true},
+		{UnknownVariableValue, false},
+	}
+
+	for _, tc := range cases {
+		errs := v.ValidateValue(tc.Value)
+		if (len(errs) > 0) != tc.Error {
+			t.Fatalf("%s: bad: %#v", tc.Value, errs)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/config/lang"
+	"github.com/hashicorp/terraform/config/lang/ast"
+	"github.com/mitchellh/reflectwalk"
+)
+
+// DynamicBlockKey is the key of blocks that generate other nested
+// blocks from a list, such as:
+//
+//	dynamic "ingress" {
+//		for_each = "${var.ports}"
+//		content {
+//			from_port = "${each.value}"
+//		}
+//	}
+//
+// Every element of the for_each list generates an "ingress" block from
+// the content, with "each.value" set to the element and "each.index" set
+// to its index.
+const DynamicBlockKey = "dynamic"
+
+// dynamicBlock is a single parsed dynamic block.
+type dynamicBlock struct {
+	Name    string
+	ForEach interface{}
+	Content map[string]interface{}
+}
+
+// dynamicBlocks parses the dynamic blocks that are directly within the
+// given raw configuration.
+func dynamicBlocks(raw map[string]interface{}) ([]*dynamicBlock, error) {
+	v, ok := raw[DynamicBlockKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var result []*dynamicBlock
+	for _, m := range dynamicMaps(v) {
+		for name, body := range m {
+			for _, b := range dynamicMaps(body) {
+				forEach, ok := b["for_each"]
+				if !ok {
+					return nil, fmt.Errorf(
+						"dynamic %s: for_each must be set", name)
+				}
+
+				contents := dynamicMaps(b["content"])
+				if len(contents) != 1 {
+					return nil, fmt.Errorf(
+						"dynamic %s: exactly one content block must be set",
+						name)
+				}
+
+				for k, _ := range b {
+					if k != "for_each" && k != "content" {
+						return nil, fmt.Errorf(
+							"dynamic %s: unknown key %s", name, k)
+					}
+				}
+
+				result = append(result, &dynamicBlock{
+					Name:    name,
+					ForEach: forEach,
+					Content: contents[0],
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// dynamicMaps turns the ways that HCL can decode a block into a list
+// of maps.
+func dynamicMaps(v interface{}) []map[string]interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{t}
+	case []map[string]interface{}:
+		return t
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(t))
+		for _, elem := range t {
+			result = append(result, dynamicMaps(elem)...)
+		}
+
+		return result
+	default:
+		return nil
+	}
+}
+
+// expandDynamicBlocks replaces the dynamic blocks within raw, and
+// within any of its nested blocks, with the blocks that they generate.
+// The content of the generated blocks is interpolated with the given
+// variables along with the "each" variables. The keys of any generated
+// values that aren't known yet are returned.
+func expandDynamicBlocks(
+	raw map[string]interface{},
+	vs map[string]ast.Variable) ([]string, error) {
+	var unknownKeys []string
+
+	// Expand any nested blocks first
+	for k, v := range raw {
+		if k == DynamicBlockKey {
+			continue
+		}
+
+		for _, m := range dynamicMaps(v) {
+			keys, err := expandDynamicBlocks(m, vs)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, uk := range keys {
+				unknownKeys = append(unknownKeys, k+"."+uk)
+			}
+		}
+	}
+
+	blocks, err := dynamicBlocks(raw)
+	if err != nil {
+		return nil, err
+	}
+	delete(raw, DynamicBlockKey)
+
+	for _, b := range blocks {
+		values, err := dynamicForEach(b.ForEach, vs)
+		if err != nil {
+			return nil, fmt.Errorf("dynamic %s: %s", b.Name, err)
+		}
+		if values == nil {
+			// The list isn't known yet, so neither are the blocks
+			unknownKeys = append(unknownKeys, b.Name)
+			continue
+		}
+
+		generated := make([]map[string]interface{}, 0, len(values))
+		for i, value := range values {
+			each := make(map[string]ast.Variable, len(vs)+2)
+			for k, v := range vs {
+				each[k] = v
+			}
+			each["each.value"] = ast.Variable{
+				Value: value,
+				Type:  ast.TypeString,
+			}
+			each["each.index"] = ast.Variable{
+				Value: i,
+				Type:  ast.TypeInt,
+			}
+
+			content := dynamicCopy(b.Content).(map[string]interface{})
+			keys, err := expandDynamicBlocks(content, each)
+			if err != nil {
+				return nil, err
+			}
+
+			w := &interpolationWalker{
+				F:       langEvalFunc(each),
+				Replace: true,
+			}
+			if err := reflectwalk.Walk(content, w); err != nil {
+				return nil, fmt.Errorf("dynamic %s: %s", b.Name, err)
+			}
+
+			keys = append(keys, w.unknownKeys...)
+			for _, uk := range keys {
+				unknownKeys = append(unknownKeys,
+					fmt.Sprintf("%s.%d.%s", b.Name, i, uk))
+			}
+
+			generated = append(generated, content)
+		}
+
+		raw[b.Name] = appendBlocks(raw[b.Name], generated)
+	}
+
+	return unknownKeys, nil
+}
+
+// dynamicForEach evaluates the for_each value of a dynamic block. The
+// result is nil if the list isn't known yet.
+func dynamicForEach(
+	v interface{}, vs map[string]ast.Variable) ([]string, error) {
+	var raw []interface{}
+	switch t := v.(type) {
+	case string:
+		raw = []interface{}{t}
+	case []interface{}:
+		raw = t
+	default:
+		return nil, fmt.Errorf("for_each must be a list, got %T", v)
+	}
+
+	fn := langEvalFunc(vs)
+	result := make([]string, 0, len(raw))
+	for _, elem := range raw {
+		s, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				"for_each elements must be strings, got %T", elem)
+		}
+
+		root, err := lang.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		if s, err = fn(root); err != nil {
+			return nil, err
+		}
+		if s == "" {
+			continue
+		}
+
+		for _, p := range strings.Split(s, InterpSplitDelim) {
+			if p == UnknownVariableValue {
+				return nil, nil
+			}
+
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+// dynamicCopy returns a deep copy of the content of a dynamic block.
+func dynamicCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			result[k] = dynamicCopy(v)
+		}
+
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, len(t))
+		for i, v := range t {
+			result[i] = dynamicCopy(v).(map[string]interface{})
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(t))
+		for i, v := range t {
+			result[i] = dynamicCopy(v)
+		}
+
+		return result
+	default:
+		return v
+	}
+}
+
+// appendBlocks appends generated blocks to any blocks that were
+// written out statically.
+func appendBlocks(
+	existing interface{},
+	blocks []map[string]interface{}) interface{} {
+	switch t := existing.(type) {
+	case []interface{}:
+		for _, b := range blocks {
+			t = append(t, b)
+		}
+
+		return t
+	default:
+		return append(dynamicMaps(existing), blocks...)
+	}
+}
+
+// validateDynamicBlocks checks that all the dynamic blocks within raw
+// can be parsed, and that "each" variables are only used within the
+// content of dynamic blocks.
+func validateDynamicBlocks(raw map[string]interface{}) error {
+	var errs []error
+	var walk func(map[string]interface{}, bool)
+	walk = func(m map[string]interface{}, inDynamic bool) {
+		for k, v := range m {
+			if k == DynamicBlockKey {
+				continue
+			}
+
+			// Nested blocks are walked on their own below
+			if blocks := dynamicMaps(v); len(blocks) > 0 {
+				for _, b := range blocks {
+					walk(b, inDynamic)
+				}
+
+				continue
+			}
+
+			if inDynamic {
+				continue
+			}
+
+			w := &interpolationWalker{F: func(root ast.Node) (string, error) {
+				vars, err := DetectVariables(root)
+				if err != nil {
+					return "", err
+				}
+
+				for _, v := range vars {
+					if _, ok := v.(*EachVariable); ok {
+						errs = append(errs, fmt.Errorf(
+							"%s: %s can only be used within the content "+
+								"of a dynamic block", k, v.FullKey()))
+					}
+				}
+
+				return "", nil
+			}}
+			if err := reflectwalk.Walk(v, w); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		blocks, err := dynamicBlocks(m)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+
+		for _, b := range blocks {
+			walk(map[string]interface{}{"for_each": b.ForEach}, inDynamic)
+			walk(b.Content, true)
+		}
+	}
+	walk(raw, false)
+
+	if len(errs) > 0 {
+		return &multierror.Error{Errors: errs}
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config/lang/ast"
+)
+
+func TestExpandDynamicBlocks(t *testing.T) {
+	cases := []struct {
+		Input   map[string]interface{}
+		Vars    map[string]ast.Variable
+		Output  map[string]interface{}
+		Unknown []string
+		Error   bool
+	}{
+		// No dynamic blocks
+		{
+			map[string]interface{}{
+				"foo": "bar",
+			},
+			nil,
+			map[string]interface{}{
+				"foo": "bar",
+			},
+			nil,
+			false,
+		},
+
+		// Basic
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "${var.ports}",
+								"content": []map[string]interface{}{
+									map[string]interface{}{
+										"port":  "${each.value}",
+										"index": "${each.index}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			map[string]ast.Variable{
+				"var.ports": ast.Variable{
+					Value: "80" + InterpSplitDelim + "443",
+					Type:  ast.TypeString,
+				},
+			},
+			map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					map[string]interface{}{
+						"port":  "80",
+						"index": "0",
+					},
+					map[string]interface{}{
+						"port":  "443",
+						"index": "1",
+					},
+				},
+			},
+			nil,
+			false,
+		},
+
+		// Appends to static blocks
+		{
+			map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					map[string]interface{}{
+						"port": "22",
+					},
+				},
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": []interface{}{"80"},
+								"content": []map[string]interface{}{
+									map[string]interface{}{
+										"port": "${each.value}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			nil,
+			map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					map[string]interface{}{
+						"port": "22",
+					},
+					map[string]interface{}{
+						"port": "80",
+					},
+				},
+			},
+			nil,
+			false,
+		},
+
+		// Unknown list
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "${var.ports}",
+								"content": []map[string]interface{}{
+									map[string]interface{}{
+										"port": "${each.value}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			map[string]ast.Variable{
+				"var.ports": ast.Variable{
+					Value: UnknownVariableValue,
+					Type:  ast.TypeString,
+				},
+			},
+			map[string]interface{}{},
+			[]string{"ingress"},
+			false,
+		},
+
+		// Unknown value in the content
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": []interface{}{"80"},
+								"content": []map[string]interface{}{
+									map[string]interface{}{
+										"port": "${each.value}",
+										"cidr": "${var.cidr}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			map[string]ast.Variable{
+				"var.cidr": ast.Variable{
+					Value: UnknownVariableValue,
+					Type:  ast.TypeString,
+				},
+			},
+			map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					map[string]interface{}{
+						"port": "80",
+					},
+				},
+			},
+			[]string{"ingress.0.cidr"},
+			false,
+		},
+
+		// Missing content
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "80",
+							},
+						},
+					},
+				},
+			},
+			nil,
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		unknown, err := expandDynamicBlocks(tc.Input, tc.Vars)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if tc.Error {
+			continue
+		}
+
+		if !reflect.DeepEqual(tc.Input, tc.Output) {
+			t.Fatalf("%d: bad: %#v", i, tc.Input)
+		}
+		if !reflect.DeepEqual(unknown, tc.Unknown) {
+			t.Fatalf("%d: bad unknown: %#v", i, unknown)
+		}
+	}
+}
+
+func TestValidateDynamicBlocks(t *testing.T) {
+	cases := []struct {
+		Input map[string]interface{}
+		Error bool
+	}{
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "${var.ports}",
+								"content": []map[string]interface{}{
+									map[string]interface{}{
+										"port": "${each.value}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
+		// Each outside of a dynamic block
+		{
+			map[string]interface{}{
+				"port": "${each.value}",
+			},
+			true,
+		},
+
+		// Each in the list of a dynamic block
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "${each.value}",
+								"content": []map[string]interface{}{
+									map[string]interface{}{},
+								},
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+
+		// Unknown key
+		{
+			map[string]interface{}{
+				"dynamic": []map[string]interface{}{
+					map[string]interface{}{
+						"ingress": []map[string]interface{}{
+							map[string]interface{}{
+								"for_each": "80",
+								"iterator": "port",
+								"content": []map[string]interface{}{
+									map[string]interface{}{},
+								},
+							},
+						},
+					},
+				},
+			},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		err := validateDynamicBlocks(tc.Input)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+	}
+}
@@ -31,12 +31,40 @@ const (
 	CountValueIndex
 )
 
+// EachVariable is a variable for referencing the current element
+// within the content of a dynamic block, such as "${each.value}"
+type EachVariable struct {
+	Type EachValueType
+	key  string
+}
+
+// EachValueType is the type of the each variable that is referenced.
+type EachValueType byte
+
+const (
+	EachValueInvalid EachValueType = iota
+	EachValueIndex
+	EachValueValue
+)
+
+// A LocalVariable is a variable that references a named value from
+// a "locals" block, such as "${local.foo}"
+type LocalVariable struct {
+	Name string
+	key  string
+}
+
 // A ModuleVariable is a variable that is referencing the output
-// of a module, such as "${module.foo.bar}"
+// of a module, such as "${module.foo.bar}". Instances of a module with
+// a count are referenced by index, such as "${module.foo.0.bar}", or
+// all together with a splat, such as "${module.foo.*.bar}".
 type ModuleVariable struct {
 	Name  string
 	Field string
-	key   string
+	Multi bool // True if multi-variable: module.foo.*.bar
+	Index int  // Index for multi-variable: module.foo.1.bar == 1
+
+	key string
 }
 
 // A PathVariable is a variable that references path information about the
@@ -56,11 +84,14 @@ const (
 )
 
 // A ResourceVariable is a variable that is referencing the field
-// of a resource, such as "${aws_instance.foo.ami}"
+// of a resource, such as "${aws_instance.foo.ami}". The field "*"
+// references every attribute of the resource, such as
+// "${aws_instance.foo.*}" or "${aws_instance.foo.1.*}".
 type ResourceVariable struct {
-	Type  string // Resource type, i.e. "aws_instance"
-	Name  string // Resource name
-	Field string // Resource field
+	Mode  ResourceMode // Resource mode, data sources are "data.TYPE.NAME"
+	Type  string       // Resource type, i.e. "aws_instance"
+	Name  string       // Resource name
+	Field string       // Resource field
 
 	Multi bool // True if multi-variable: aws_instance.foo.*.id
 	Index int  // Index for multi-variable: aws_instance.foo.1.id == 1
@@ -89,12 +120,16 @@ type UserVariable struct {
 func NewInterpolatedVariable(v string) (InterpolatedVariable, error) {
 	if strings.HasPrefix(v, "count.") {
 		return NewCountVariable(v)
+	} else if strings.HasPrefix(v, "each.") {
+		return NewEachVariable(v)
 	} else if strings.HasPrefix(v, "path.") {
 		return NewPathVariable(v)
 	} else if strings.HasPrefix(v, "self.") {
 		return NewSelfVariable(v)
 	} else if strings.HasPrefix(v, "var.") {
 		return NewUserVariable(v)
+	} else if strings.HasPrefix(v, "local.") {
+		return NewLocalVariable(v)
 	} else if strings.HasPrefix(v, "module.") {
 		return NewModuleVariable(v)
 	} else {
@@ -120,6 +155,43 @@ func (c *CountVariable) FullKey() string {
 	return c.key
 }
 
+func NewEachVariable(key string) (*EachVariable, error) {
+	var fieldType EachValueType
+	parts := strings.SplitN(key, ".", 2)
+	switch parts[1] {
+	case "index":
+		fieldType = EachValueIndex
+	case "value":
+		fieldType = EachValueValue
+	}
+
+	return &EachVariable{
+		Type: fieldType,
+		key:  key,
+	}, nil
+}
+
+func (v *EachVariable) FullKey() string {
+	return v.key
+}
+
+func NewLocalVariable(key string) (*LocalVariable, error) {
+	name := key[len("local."):]
+	if name == "" || strings.Contains(name, ".") {
+		return nil, fmt.Errorf(
+			"%s: local variables must be two parts: local.name", key)
+	}
+
+	return &LocalVariable{
+		Name: name,
+		key:  key,
+	}, nil
+}
+
+func (v *LocalVariable) FullKey() string {
+	return v.key
+}
+
 func NewModuleVariable(key string) (*ModuleVariable, error) {
 	parts := strings.SplitN(key, ".", 3)
 	if len(parts) < 3 {
@@ -128,9 +200,35 @@ func NewModuleVariable(key string) (*ModuleVariable, error) {
 			key)
 	}
 
+	field := parts[2]
+	multi := false
+	var index int
+
+	if idx := strings.Index(field, "."); idx != -1 {
+		indexStr := field[:idx]
+		multi = indexStr == "*"
+		index = -1
+
+		if !multi {
+			indexInt, err := strconv.ParseInt(indexStr, 0, 0)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"%s: module instance index must be a number or *",
+					key)
+			}
+
+			multi = true
+			index = int(indexInt)
+		}
+
+		field = field[idx+1:]
+	}
+
 	return &ModuleVariable{
 		Name:  parts[1],
-		Field: parts[2],
+		Field: field,
+		Multi: multi,
+		Index: index,
 		key:   key,
 	}, nil
 }
@@ -162,8 +260,21 @@ func (v *PathVariable) FullKey() string {
 }
 
 func NewResourceVariable(key string) (*ResourceVariable, error) {
-	parts := strings.SplitN(key, ".", 3)
+	mode := ManagedResourceMode
+	rest := key
+	if strings.HasPrefix(key, "data.") {
+		mode = DataResourceMode
+		rest = key[len("data."):]
+	}
+
+	parts := strings.SplitN(rest, ".", 3)
 	if len(parts) < 3 {
+		if mode == DataResourceMode {
+			return nil, fmt.Errorf(
+				"%s: data source variables must be four parts: "+
+					"data.type.name.attr", key)
+		}
+
 		return nil, fmt.Errorf(
 			"%s: resource variables must be three parts: type.name.attr",
 			key)
@@ -191,7 +302,14 @@ func NewResourceVariable(key string) (*ResourceVariable, error) {
 		}
 	}
 
+	if field == ResourceAttributesField && multi && index == -1 {
+		return nil, fmt.Errorf(
+			"%s: all attributes can't be referenced with a splat, "+
+				"use an index such as type.name.0.*", key)
+	}
+
 	return &ResourceVariable{
+		Mode:  mode,
 		Type:  parts[0],
 		Name:  parts[1],
 		Field: field,
@@ -201,7 +319,21 @@ func NewResourceVariable(key string) (*ResourceVariable, error) {
 	}, nil
 }
 
+// ResourceAttributesField is the field of a ResourceVariable that
+// references every attribute of a resource.
+const ResourceAttributesField = "*"
+
+// AllAttributes returns true if this variable references every attribute
+// of the resource rather than a single field.
+func (v *ResourceVariable) AllAttributes() bool {
+	return v.Field == ResourceAttributesField
+}
+
 func (v *ResourceVariable) ResourceId() string {
+	if v.Mode == DataResourceMode {
+		return fmt.Sprintf("data.%s.%s", v.Type, v.Name)
+	}
+
 	return fmt.Sprintf("%s.%s", v.Type, v.Name)
 }
 
@@ -254,6 +386,13 @@ func (v *UserVariable) GoString() string {
 // DetectVariables takes an AST root and returns all the interpolated
 // variables that are detected in the AST tree.
 func DetectVariables(root ast.Node) ([]InterpolatedVariable, error) {
+	return detectVariables(root, nil)
+}
+
+// detectVariables detects the variables within root, ignoring the
+// names that are bound to the iteration variables of a for expression.
+func detectVariables(
+	root ast.Node, bound map[string]struct{}) ([]InterpolatedVariable, error) {
 	var result []InterpolatedVariable
 	var resultErr error
 
@@ -263,10 +402,58 @@ func DetectVariables(root ast.Node) ([]InterpolatedVariable, error) {
 			return n
 		}
 
+		// The results of a conditional aren't visited since only one
+		// of them is evaluated, but both may use variables.
+		if cn, ok := n.(*ast.Conditional); ok {
+			for _, child := range []ast.Node{cn.TrueExpr, cn.FalseExpr} {
+				vs, err := detectVariables(child, bound)
+				if err != nil {
+					resultErr = err
+					return n
+				}
+
+				result = append(result, vs...)
+			}
+
+			return n
+		}
+
+		// The body and condition of a for expression aren't visited,
+		// so detect the variables within them with the iteration
+		// variables bound.
+		if fn, ok := n.(*ast.For); ok {
+			inner := map[string]struct{}{fn.ValueVar: struct{}{}}
+			if fn.IndexVar != "" {
+				inner[fn.IndexVar] = struct{}{}
+			}
+			for k := range bound {
+				inner[k] = struct{}{}
+			}
+
+			for _, child := range []ast.Node{fn.Body, fn.Cond} {
+				if child == nil {
+					continue
+				}
+
+				vs, err := detectVariables(child, inner)
+				if err != nil {
+					resultErr = err
+					return n
+				}
+
+				result = append(result, vs...)
+			}
+
+			return n
+		}
+
 		vn, ok := n.(*ast.VariableAccess)
 		if !ok {
 			return n
 		}
+		if _, ok := bound[vn.Name]; ok {
+			return n
+		}
 
 		v, err := NewInterpolatedVariable(vn.Name)
 		if err != nil {
@@ -2,12 +2,29 @@ package config
 
 import (
 	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
+	"net"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/hashicorp/terraform/config/lang"
 	"github.com/hashicorp/terraform/config/lang/ast"
+	"github.com/mitchellh/go-homedir"
 )
 
 // Funcs is the mapping of built-in functions for configuration.
@@ -15,31 +32,408 @@ var Funcs map[string]ast.Function
 
 func init() {
 	Funcs = map[string]ast.Function{
-		"concat":  interpolationFuncConcat(),
-		"file":    interpolationFuncFile(),
-		"join":    interpolationFuncJoin(),
-		"element": interpolationFuncElement(),
-		"split":   interpolationFuncSplit(),
+		"abs":          interpolationFuncAbs(),
+		"base64decode": interpolationFuncBase64Decode(),
+		"base64encode": interpolationFuncBase64Encode(),
+		"base64gzip":   interpolationFuncBase64Gzip(),
+		"ceil":         interpolationFuncCeil(),
+		"cidrhost":     interpolationFuncCidrHost(),
+		"cidrsubnet":   interpolationFuncCidrSubnet(),
+		"coalesce":     interpolationFuncCoalesce(),
+		"compact":      interpolationFuncCompact(),
+		"concat":       interpolationFuncConcat(),
+		"empty":        interpolationFuncEmpty(),
+		"equal":        interpolationFuncEqual(),
+		"file":         interpolationFuncFile(),
+		"floor":        interpolationFuncFloor(),
+		"formatlist":   interpolationFuncFormatList(),
+		"index":        interpolationFuncIndex(),
+		"join":         interpolationFuncJoin(),
+		"jsondecode":   interpolationFuncJSONDecode(),
+		"jsonencode":   interpolationFuncJSONEncode(),
+		"length":       interpolationFuncLength(),
+		"lower":        interpolationFuncLower(),
+		"max":          interpolationFuncMax(),
+		"md5":          interpolationFuncMd5(),
+		"min":          interpolationFuncMin(),
+		"pathexpand":   interpolationFuncPathExpand(),
+		"regex":        interpolationFuncRegex(),
+		"regexall":     interpolationFuncRegexAll(),
+		"replace":      interpolationFuncReplace(),
+		"sha1":         interpolationFuncSha1(),
+		"sha256":       interpolationFuncSha256(),
+		"distinct":     interpolationFuncDistinct(),
+		"element":      interpolationFuncElement(),
+		"signum":       interpolationFuncSignum(),
+		"slice":        interpolationFuncSlice(),
+		"slug":         interpolationFuncSlug(),
+		"sort":         interpolationFuncSort(),
+		"split":        interpolationFuncSplit(),
+		"substr":       interpolationFuncSubstr(),
+		"templatefile": interpolationFuncTemplateFile(),
+		"timeadd":      interpolationFuncTimeAdd(),
+		"timestamp":    interpolationFuncTimestamp(),
+		"title":        interpolationFuncTitle(),
+		"trim":         interpolationFuncTrim(),
+		"trimprefix":   interpolationFuncTrimPrefix(),
+		"trimspace":    interpolationFuncTrimSpace(),
+		"trimsuffix":   interpolationFuncTrimSuffix(),
+		"upper":        interpolationFuncUpper(),
+		"uuid":         interpolationFuncUUID(),
+		"zipmap":       interpolationFuncZipMap(),
 	}
 }
 
-// interpolationFuncConcat implements the "concat" function that
-// concatenates multiple strings. This isn't actually necessary anymore
-// since our language supports string concat natively, but for backwards
-// compat we do this.
-func interpolationFuncConcat() ast.Function {
+// interpolationFuncAbs implements the "abs" function that returns the
+// absolute value of a number.
+func interpolationFuncAbs() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeFloat},
+		ReturnType: ast.TypeFloat,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return math.Abs(args[0].(float64)), nil
+		},
+	}
+}
+
+// interpolationFuncCeil implements the "ceil" function that rounds a
+// number up to the closest whole number.
+func interpolationFuncCeil() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeFloat},
+		ReturnType: ast.TypeInt,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return int(math.Ceil(args[0].(float64))), nil
+		},
+	}
+}
+
+// interpolationFuncFloor implements the "floor" function that rounds a
+// number down to the closest whole number.
+func interpolationFuncFloor() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeFloat},
+		ReturnType: ast.TypeInt,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return int(math.Floor(args[0].(float64))), nil
+		},
+	}
+}
+
+// interpolationFuncMax implements the "max" function that returns the
+// largest of its arguments.
+func interpolationFuncMax() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeFloat},
+		ReturnType:   ast.TypeFloat,
+		Variadic:     true,
+		VariadicType: ast.TypeFloat,
+		Callback: func(args []interface{}) (interface{}, error) {
+			result := args[0].(float64)
+			for _, arg := range args[1:] {
+				result = math.Max(result, arg.(float64))
+			}
+
+			return result, nil
+		},
+	}
+}
+
+// interpolationFuncMin implements the "min" function that returns the
+// smallest of its arguments.
+func interpolationFuncMin() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeFloat},
+		ReturnType:   ast.TypeFloat,
+		Variadic:     true,
+		VariadicType: ast.TypeFloat,
+		Callback: func(args []interface{}) (interface{}, error) {
+			result := args[0].(float64)
+			for _, arg := range args[1:] {
+				result = math.Min(result, arg.(float64))
+			}
+
+			return result, nil
+		},
+	}
+}
+
+// interpolationFuncSignum implements the "signum" function that returns
+// -1, 0 or 1 depending on the sign of a number.
+func interpolationFuncSignum() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeInt},
+		ReturnType: ast.TypeInt,
+		Callback: func(args []interface{}) (interface{}, error) {
+			switch n := args[0].(int); {
+			case n < 0:
+				return -1, nil
+			case n > 0:
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		},
+	}
+}
+
+// interpolationFuncBase64Encode implements the "base64encode" function that
+// encodes a string with base64.
+func interpolationFuncBase64Encode() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return base64.StdEncoding.EncodeToString([]byte(args[0].(string))), nil
+		},
+	}
+}
+
+// interpolationFuncBase64Decode implements the "base64decode" function that
+// decodes a base64 encoded string.
+func interpolationFuncBase64Decode() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			data, err := base64.StdEncoding.DecodeString(args[0].(string))
+			if err != nil {
+				return "", fmt.Errorf("failed to decode base64 data: %s", err)
+			}
+
+			return string(data), nil
+		},
+	}
+}
+
+// interpolationFuncBase64Gzip implements the "base64gzip" function that
+// gzip compresses a string and base64 encodes the result. This is useful
+// for values such as user data that have size limits but accept
+// compressed content.
+func interpolationFuncBase64Gzip() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			var b bytes.Buffer
+			w := gzip.NewWriter(&b)
+			if _, err := w.Write([]byte(args[0].(string))); err != nil {
+				return "", fmt.Errorf("error compressing: %s", err)
+			}
+			if err := w.Close(); err != nil {
+				return "", fmt.Errorf("error compressing: %s", err)
+			}
+
+			return base64.StdEncoding.EncodeToString(b.Bytes()), nil
+		},
+	}
+}
+
+// interpolationFuncCidrHost implements the "cidrhost" function that
+// calculates the IP address of the given host number within an IP
+// network address prefix.
+func interpolationFuncCidrHost() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeInt},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			_, network, err := net.ParseCIDR(args[0].(string))
+			if err != nil {
+				return "", fmt.Errorf("invalid CIDR expression: %s", err)
+			}
+
+			hostNum := args[1].(int)
+			ones, bits := network.Mask.Size()
+			if hostNum < 0 || !fitsInBits(hostNum, bits-ones) {
+				return "", fmt.Errorf(
+					"host number %d doesn't fit in prefix %s",
+					hostNum, network)
+			}
+
+			ip := ipToInt(network.IP)
+			ip.Add(ip, big.NewInt(int64(hostNum)))
+			return intToIP(ip, bits).String(), nil
+		},
+	}
+}
+
+// interpolationFuncCidrSubnet implements the "cidrsubnet" function that
+// calculates a subnet address within a given IP network address prefix.
+// The prefix is extended by newbits bits and netnum selects the subnet
+// within that extended prefix.
+func interpolationFuncCidrSubnet() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeInt, ast.TypeInt},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			_, network, err := net.ParseCIDR(args[0].(string))
+			if err != nil {
+				return "", fmt.Errorf("invalid CIDR expression: %s", err)
+			}
+
+			newBits := args[1].(int)
+			netNum := args[2].(int)
+			ones, bits := network.Mask.Size()
+			newOnes := ones + newBits
+			if newBits < 0 || newOnes > bits {
+				return "", fmt.Errorf(
+					"cannot extend prefix %s by %d bits", network, newBits)
+			}
+			if netNum < 0 || !fitsInBits(netNum, newBits) {
+				return "", fmt.Errorf(
+					"network number %d doesn't fit in %d bits",
+					netNum, newBits)
+			}
+
+			ip := ipToInt(network.IP)
+			num := big.NewInt(int64(netNum))
+			ip.Or(ip, num.Lsh(num, uint(bits-newOnes)))
+			result := &net.IPNet{
+				IP:   intToIP(ip, bits),
+				Mask: net.CIDRMask(newOnes, bits),
+			}
+
+			return result.String(), nil
+		},
+	}
+}
+
+// fitsInBits returns true if the non-negative number n can be represented
+// with the given number of bits.
+func fitsInBits(n int, bits int) bool {
+	if bits >= 63 {
+		return true
+	}
+
+	return n < (1 << uint(bits))
+}
+
+// ipToInt converts an IP address into an integer. IPv4 addresses are
+// converted from their 4-byte form.
+func ipToInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	return new(big.Int).SetBytes(ip)
+}
+
+// intToIP converts an integer back into an IP address of the given
+// number of bits (32 for IPv4, 128 for IPv6).
+func intToIP(n *big.Int, bits int) net.IP {
+	raw := n.Bytes()
+	ip := make(net.IP, bits/8)
+	copy(ip[len(ip)-len(raw):], raw)
+	return ip
+}
+
+// interpolationFuncCoalesce implements the "coalesce" function that
+// returns the first non-empty argument, or an empty string if all of
+// the arguments are empty.
+func interpolationFuncCoalesce() ast.Function {
 	return ast.Function{
 		ArgTypes:     []ast.Type{ast.TypeString},
 		ReturnType:   ast.TypeString,
 		Variadic:     true,
 		VariadicType: ast.TypeString,
 		Callback: func(args []interface{}) (interface{}, error) {
-			var b bytes.Buffer
-			for _, v := range args {
-				b.WriteString(v.(string))
+			for _, arg := range args {
+				if v := arg.(string); v != "" {
+					return v, nil
+				}
+			}
+
+			return "", nil
+		},
+	}
+}
+
+// interpolationFuncCompact implements the "compact" function that
+// removes empty elements from a list.
+func interpolationFuncCompact() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			parts := listStrings(args[0])
+			result := make([]string, 0, len(parts))
+			for _, part := range parts {
+				if part != "" {
+					result = append(result, part)
+				}
+			}
+
+			return stringsList(result), nil
+		},
+	}
+}
+
+// interpolationFuncConcat implements the "concat" function that
+// concatenates multiple lists into a single list. Strings are treated
+// as lists of one element; use native interpolation to concatenate
+// strings.
+func interpolationFuncConcat() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeList},
+		ReturnType:   ast.TypeList,
+		Variadic:     true,
+		VariadicType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			var result []ast.Variable
+			for _, arg := range args {
+				result = append(result, arg.([]ast.Variable)...)
+			}
+
+			return result, nil
+		},
+	}
+}
+
+// interpolationFuncDistinct implements the "distinct" function that
+// removes duplicate elements from a list, keeping the first occurrence
+// of each.
+func interpolationFuncDistinct() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			list := listStrings(args[0])
+			seen := make(map[string]struct{}, len(list))
+			result := make([]string, 0, len(list))
+			for _, v := range list {
+				if _, ok := seen[v]; ok {
+					continue
+				}
+
+				seen[v] = struct{}{}
+				result = append(result, v)
 			}
 
-			return b.String(), nil
+			return stringsList(result), nil
+		},
+	}
+}
+
+// interpolationFuncEmpty implements the "empty" function that returns
+// "true" if the given string is empty and "false" otherwise.
+func interpolationFuncEmpty() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strconv.FormatBool(args[0].(string) == ""), nil
+		},
+	}
+}
+
+// interpolationFuncEqual implements the "equal" function that returns
+// "true" if both strings are equal and "false" otherwise.
+func interpolationFuncEqual() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strconv.FormatBool(args[0].(string) == args[1].(string)), nil
 		},
 	}
 }
@@ -61,17 +455,89 @@ func interpolationFuncFile() ast.Function {
 	}
 }
 
+// interpolationFuncFormatList implements the "formatlist" function that
+// applies a format string to every element of one or more lists. Lists
+// given as multiple arguments are zipped together and must all be the
+// same length. Arguments with a single element, such as strings, are
+// repeated for every element.
+func interpolationFuncFormatList() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeString},
+		ReturnType:   ast.TypeList,
+		Variadic:     true,
+		VariadicType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			format := args[0].(string)
+
+			// Get the elements of every argument, verifying along
+			// the way that all the lists have the same length.
+			n := 1
+			lists := make([][]string, len(args)-1)
+			for i, arg := range args[1:] {
+				parts := listStrings(arg)
+				if len(parts) > 1 {
+					if n > 1 && n != len(parts) {
+						return nil, fmt.Errorf(
+							"formatlist: mismatched list lengths: %d != %d",
+							n, len(parts))
+					}
+
+					n = len(parts)
+				}
+
+				lists[i] = parts
+			}
+
+			result := make([]string, n)
+			fmtArgs := make([]interface{}, len(lists))
+			for i := 0; i < n; i++ {
+				for j, parts := range lists {
+					if len(parts) == 1 {
+						fmtArgs[j] = parts[0]
+					} else {
+						fmtArgs[j] = parts[i]
+					}
+				}
+
+				result[i] = fmt.Sprintf(format, fmtArgs...)
+			}
+
+			return stringsList(result), nil
+		},
+	}
+}
+
+// interpolationFuncIndex implements the "index" function that returns
+// the position of the first occurrence of a value in a list. It is an
+// error if the value isn't found.
+func interpolationFuncIndex() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList, ast.TypeString},
+		ReturnType: ast.TypeInt,
+		Callback: func(args []interface{}) (interface{}, error) {
+			list := listStrings(args[0])
+			value := args[1].(string)
+			for i, v := range list {
+				if v == value {
+					return i, nil
+				}
+			}
+
+			return 0, fmt.Errorf("could not find '%s' in list", value)
+		},
+	}
+}
+
 // interpolationFuncJoin implements the "join" function that allows
-// multi-variable values to be joined by some character.
+// lists to be joined by some character.
 func interpolationFuncJoin() ast.Function {
 	return ast.Function{
-		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeList},
 		ReturnType: ast.TypeString,
 		Callback: func(args []interface{}) (interface{}, error) {
 			var list []string
 			for _, arg := range args[1:] {
-				parts := strings.Split(arg.(string), InterpSplitDelim)
-				list = append(list, parts...)
+				list = append(list, listStrings(arg)...)
 			}
 
 			return strings.Join(list, args[0].(string)), nil
@@ -79,28 +545,578 @@ func interpolationFuncJoin() ast.Function {
 	}
 }
 
+// interpolationFuncJSONEncode implements the "jsonencode" function that
+// encodes a value as JSON. Multi-variable values are encoded as an array
+// of strings and anything else is encoded as a string.
+func interpolationFuncJSONEncode() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			var v interface{} = args[0].(string)
+			if strings.Contains(v.(string), InterpSplitDelim) {
+				v = strings.Split(v.(string), InterpSplitDelim)
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode JSON data: %s", err)
+			}
+
+			return string(data), nil
+		},
+	}
+}
+
+// interpolationFuncJSONDecode implements the "jsondecode" function that
+// decodes a JSON document. Since interpolations only deal in strings and
+// lists, any further arguments are a path of object keys and array
+// indexes used to select a value within the document. The selected value
+// must be a primitive, which is returned as a string, or an array of
+// primitives, which is returned as a multi-variable value.
+func interpolationFuncJSONDecode() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeString},
+		ReturnType:   ast.TypeString,
+		Variadic:     true,
+		VariadicType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(args[0].(string)), &v); err != nil {
+				return "", fmt.Errorf("failed to decode JSON data: %s", err)
+			}
+
+			for _, raw := range args[1:] {
+				key := raw.(string)
+				switch current := v.(type) {
+				case map[string]interface{}:
+					next, ok := current[key]
+					if !ok {
+						return "", fmt.Errorf("key '%s' not found", key)
+					}
+					v = next
+				case []interface{}:
+					i, err := strconv.Atoi(key)
+					if err != nil || i < 0 || i >= len(current) {
+						return "", fmt.Errorf(
+							"invalid index '%s' for array of length %d",
+							key, len(current))
+					}
+					v = current[i]
+				default:
+					return "", fmt.Errorf(
+						"can't select '%s' from a primitive value", key)
+				}
+			}
+
+			if list, ok := v.([]interface{}); ok {
+				result := make([]string, len(list))
+				for i, elem := range list {
+					s, err := jsonPrimitiveString(elem)
+					if err != nil {
+						return "", err
+					}
+					result[i] = s
+				}
+
+				return strings.Join(result, InterpSplitDelim), nil
+			}
+
+			return jsonPrimitiveString(v)
+		},
+	}
+}
+
+// jsonPrimitiveString converts a decoded JSON primitive into its string
+// form for use in interpolations.
+func jsonPrimitiveString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf(
+			"JSON value must be a primitive or an array of primitives; " +
+				"use additional arguments to select a value within it")
+	}
+}
+
+// interpolationFuncLength implements the "length" function that returns
+// the number of elements in a multi-variable value, or the number of
+// characters in a string that isn't a multi-variable value.
+func interpolationFuncLength() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeInt,
+		Callback: func(args []interface{}) (interface{}, error) {
+			v := args[0].(string)
+			if !strings.Contains(v, InterpSplitDelim) {
+				return utf8.RuneCountInString(v), nil
+			}
+
+			return len(strings.Split(v, InterpSplitDelim)), nil
+		},
+	}
+}
+
+// interpolationFuncMd5 implements the "md5" function that returns the
+// hex encoded MD5 hash of a string.
+func interpolationFuncMd5() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			hash := md5.Sum([]byte(args[0].(string)))
+			return hex.EncodeToString(hash[:]), nil
+		},
+	}
+}
+
+// interpolationFuncSha1 implements the "sha1" function that returns the
+// hex encoded SHA-1 hash of a string.
+func interpolationFuncSha1() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			hash := sha1.Sum([]byte(args[0].(string)))
+			return hex.EncodeToString(hash[:]), nil
+		},
+	}
+}
+
+// interpolationFuncSha256 implements the "sha256" function that returns
+// the hex encoded SHA-256 hash of a string.
+func interpolationFuncSha256() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			hash := sha256.Sum256([]byte(args[0].(string)))
+			return hex.EncodeToString(hash[:]), nil
+		},
+	}
+}
+
+// interpolationFuncPathExpand implements the "pathexpand" function that
+// expands a leading "~" in a path to the current user's home directory.
+func interpolationFuncPathExpand() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return homedir.Expand(args[0].(string))
+		},
+	}
+}
+
+// interpolationFuncRegex implements the "regex" function that matches a
+// regular expression against a string. The result is a list containing
+// the value of each capture group in order, or just the matched text if
+// the expression has no capture groups. It is an error if there is no
+// match.
+func interpolationFuncRegex() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			re, err := regexp.Compile(args[0].(string))
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid regular expression %s: %s", args[0], err)
+			}
+
+			match := re.FindStringSubmatch(args[1].(string))
+			if match == nil {
+				return nil, fmt.Errorf(
+					"pattern %s did not match %q", args[0], args[1])
+			}
+
+			// Without capture groups the result is the whole match,
+			// otherwise it is only the captures.
+			if len(match) > 1 {
+				match = match[1:]
+			}
+
+			return stringsList(match), nil
+		},
+	}
+}
+
+// interpolationFuncRegexAll implements the "regexall" function that
+// returns a list of all the matches of a regular expression in a string.
+// If the expression has a capture group, the value of that group is
+// returned for each match instead of the whole match. Expressions with
+// more than one capture group aren't supported since lists can't be
+// nested.
+func interpolationFuncRegexAll() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			re, err := regexp.Compile(args[0].(string))
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid regular expression %s: %s", args[0], err)
+			}
+			if n := re.NumSubexp(); n > 1 {
+				return nil, fmt.Errorf(
+					"pattern %s must have at most one capture group, has %d",
+					args[0], n)
+			}
+
+			matches := re.FindAllStringSubmatch(args[1].(string), -1)
+			result := make([]string, len(matches))
+			for i, match := range matches {
+				result[i] = match[len(match)-1]
+			}
+
+			return stringsList(result), nil
+		},
+	}
+}
+
+// interpolationFuncReplace implements the "replace" function that
+// replaces all occurrences of a search string. If the search string is
+// wrapped in forward slashes it is treated as a regular expression, and
+// the replacement can then reference capture groups such as "$1".
+func interpolationFuncReplace() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			s := args[0].(string)
+			search := args[1].(string)
+			replace := args[2].(string)
+
+			if len(search) > 1 && search[0] == '/' && search[len(search)-1] == '/' {
+				re, err := regexp.Compile(search[1 : len(search)-1])
+				if err != nil {
+					return "", fmt.Errorf(
+						"invalid regular expression %s: %s", search, err)
+				}
+
+				return re.ReplaceAllString(s, replace), nil
+			}
+
+			return strings.Replace(s, search, replace, -1), nil
+		},
+	}
+}
+
+// interpolationFuncSlice implements the "slice" function that returns
+// the elements of a list from the start index (inclusive) to the end
+// index (exclusive).
+func interpolationFuncSlice() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList, ast.TypeInt, ast.TypeInt},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			list := args[0].([]ast.Variable)
+			start := args[1].(int)
+			end := args[2].(int)
+
+			if start < 0 {
+				return nil, fmt.Errorf("start index must not be negative, got %d", start)
+			}
+			if end > len(list) {
+				return nil, fmt.Errorf(
+					"end index must not be greater than the length of the list (%d), got %d",
+					len(list), end)
+			}
+			if start > end {
+				return nil, fmt.Errorf(
+					"start index (%d) must not be greater than end index (%d)",
+					start, end)
+			}
+
+			return list[start:end], nil
+		},
+	}
+}
+
+var (
+	slugSeparatorRegexp = regexp.MustCompile(`[\s_]+`)
+	slugInvalidRegexp   = regexp.MustCompile(`[^a-z0-9-]`)
+	slugHyphensRegexp   = regexp.MustCompile(`-{2,}`)
+)
+
+// interpolationFuncSlug implements the "slug" function that turns a
+// string into something safe to use in resource names: it is lowercased,
+// whitespace and underscores become hyphens and any other character that
+// isn't a letter, digit or hyphen is removed.
+func interpolationFuncSlug() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			v := strings.ToLower(args[0].(string))
+			v = slugSeparatorRegexp.ReplaceAllString(v, "-")
+			v = slugInvalidRegexp.ReplaceAllString(v, "")
+			v = slugHyphensRegexp.ReplaceAllString(v, "-")
+			return strings.Trim(v, "-"), nil
+		},
+	}
+}
+
+// interpolationFuncSort implements the "sort" function that sorts the
+// elements of a list lexicographically.
+func interpolationFuncSort() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			list := listStrings(args[0])
+			sort.Strings(list)
+			return stringsList(list), nil
+		},
+	}
+}
+
 // interpolationFuncSplit implements the "split" function that allows
-// strings to split into multi-variable values
+// strings to split into lists
 func interpolationFuncSplit() ast.Function {
 	return ast.Function{
 		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeList,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return stringsList(strings.Split(args[1].(string), args[0].(string))), nil
+		},
+	}
+}
+
+// interpolationFuncLower implements the "lower" function that converts
+// a string to lowercase.
+func interpolationFuncLower() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
 		ReturnType: ast.TypeString,
 		Callback: func(args []interface{}) (interface{}, error) {
-			return strings.Replace(args[1].(string), args[0].(string), InterpSplitDelim, -1), nil
+			return strings.ToLower(args[0].(string)), nil
 		},
 	}
 }
 
-// interpolationFuncLookup implements the "lookup" function that allows
-// dynamic lookups of map types within a Terraform configuration.
-func interpolationFuncLookup(vs map[string]ast.Variable) ast.Function {
+// interpolationFuncUpper implements the "upper" function that converts
+// a string to uppercase.
+func interpolationFuncUpper() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.ToUpper(args[0].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncSubstr implements the "substr" function that returns
+// up to length characters of a string starting at offset. A negative
+// offset counts from the end of the string and a length of -1 means
+// the rest of the string.
+func interpolationFuncSubstr() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeInt, ast.TypeInt},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			str := []rune(args[0].(string))
+			offset := args[1].(int)
+			length := args[2].(int)
+
+			if offset < 0 {
+				offset += len(str)
+			}
+			if offset < 0 || offset > len(str) {
+				return "", fmt.Errorf(
+					"offset %d is out of range for a string of length %d",
+					args[1].(int), len(str))
+			}
+
+			end := len(str)
+			switch {
+			case length == -1:
+			case length < 0:
+				return "", fmt.Errorf(
+					"length must be -1 or greater, got %d", length)
+			case offset+length < end:
+				end = offset + length
+			}
+
+			return string(str[offset:end]), nil
+		},
+	}
+}
+
+// interpolationFuncTemplateFile implements the "templatefile" function
+// that reads a file and renders it as a template. The remaining arguments
+// are pairs of variable names and values that are available within the
+// template, for example: templatefile("user_data.tpl", "port", "8080")
+// makes ${port} available. All the built-in functions are available
+// within the template as well.
+func interpolationFuncTemplateFile() ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeString},
+		ReturnType:   ast.TypeString,
+		Variadic:     true,
+		VariadicType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			path := args[0].(string)
+			pairs := args[1:]
+			if len(pairs)%2 != 0 {
+				return "", fmt.Errorf(
+					"templatefile: variables must be given as name/value pairs")
+			}
+
+			vars := make(map[string]ast.Variable, len(pairs)/2)
+			for i := 0; i < len(pairs); i += 2 {
+				vars[pairs[i].(string)] = ast.Variable{
+					Value: pairs[i+1].(string),
+					Type:  ast.TypeString,
+				}
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+
+			root, err := lang.Parse(string(data))
+			if err != nil {
+				return "", fmt.Errorf("error parsing template %s: %s", path, err)
+			}
+
+			out, t, err := lang.Eval(root, langEvalConfig(vars))
+			if err != nil {
+				return "", fmt.Errorf("error rendering template %s: %s", path, err)
+			}
+
+			return interpolationResultString(out, t), nil
+		},
+	}
+}
+
+// interpolationFuncTimestamp implements the "timestamp" function that
+// returns the current UTC time in RFC 3339 format. Like "uuid", the
+// result changes every time the function is evaluated.
+func interpolationFuncTimestamp() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return time.Now().UTC().Format(time.RFC3339), nil
+		},
+	}
+}
+
+// interpolationFuncTimeAdd implements the "timeadd" function that adds
+// a duration, such as "1h" or "-10m", to an RFC 3339 timestamp.
+func interpolationFuncTimeAdd() ast.Function {
 	return ast.Function{
 		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
 		ReturnType: ast.TypeString,
 		Callback: func(args []interface{}) (interface{}, error) {
+			ts, err := time.Parse(time.RFC3339, args[0].(string))
+			if err != nil {
+				return "", fmt.Errorf("invalid timestamp: %s", err)
+			}
+
+			d, err := time.ParseDuration(args[1].(string))
+			if err != nil {
+				return "", fmt.Errorf("invalid duration: %s", err)
+			}
+
+			return ts.Add(d).Format(time.RFC3339), nil
+		},
+	}
+}
+
+// interpolationFuncTitle implements the "title" function that capitalizes
+// the first letter of every word in a string.
+func interpolationFuncTitle() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.Title(args[0].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncTrim implements the "trim" function that removes all
+// leading and trailing characters contained in a cutset from a string.
+func interpolationFuncTrim() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.Trim(args[0].(string), args[1].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncTrimPrefix implements the "trimprefix" function that
+// removes a prefix from a string if it is present.
+func interpolationFuncTrimPrefix() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.TrimPrefix(args[0].(string), args[1].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncTrimSpace implements the "trimspace" function that
+// removes leading and trailing whitespace, including newlines, from a
+// string.
+func interpolationFuncTrimSpace() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.TrimSpace(args[0].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncTrimSuffix implements the "trimsuffix" function that
+// removes a suffix from a string if it is present.
+func interpolationFuncTrimSuffix() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			return strings.TrimSuffix(args[0].(string), args[1].(string)), nil
+		},
+	}
+}
+
+// interpolationFuncLookup implements the "lookup" function that allows
+// dynamic lookups of map types within a Terraform configuration. An
+// optional third argument is returned if the key isn't in the map.
+func interpolationFuncLookup(vs map[string]ast.Variable) ast.Function {
+	return ast.Function{
+		ArgTypes:     []ast.Type{ast.TypeString, ast.TypeString},
+		ReturnType:   ast.TypeString,
+		Variadic:     true,
+		VariadicType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			if len(args) > 3 {
+				return "", fmt.Errorf(
+					"lookup takes at most three arguments, got %d", len(args))
+			}
+
 			k := fmt.Sprintf("var.%s.%s", args[0].(string), args[1].(string))
 			v, ok := vs[k]
 			if !ok {
+				if len(args) == 3 {
+					return args[2].(string), nil
+				}
+
 				return "", fmt.Errorf(
 					"lookup in '%s' failed to find '%s'",
 					args[0].(string), args[1].(string))
@@ -118,22 +1134,133 @@ func interpolationFuncLookup(vs map[string]ast.Variable) ast.Function {
 
 // interpolationFuncElement implements the "element" function that allows
 // a specific index to be looked up in a multi-variable value. Note that this will
-// wrap if the index is larger than the number of elements in the multi-variable value.
+// wrap if the index is larger than the number of elements in the multi-variable value,
+// unless the optional third "strict" argument is true, in which case an
+// out-of-bounds index is an error. Negative indexes are always an error.
 func interpolationFuncElement() ast.Function {
 	return ast.Function{
-		ArgTypes:   []ast.Type{ast.TypeString, ast.TypeString},
-		ReturnType: ast.TypeString,
+		ArgTypes:     []ast.Type{ast.TypeList, ast.TypeString},
+		ReturnType:   ast.TypeString,
+		Variadic:     true,
+		VariadicType: ast.TypeString,
 		Callback: func(args []interface{}) (interface{}, error) {
-			list := strings.Split(args[0].(string), InterpSplitDelim)
+			if len(args) > 3 {
+				return "", fmt.Errorf(
+					"element takes at most three arguments, got %d", len(args))
+			}
+
+			strict := false
+			if len(args) == 3 {
+				var err error
+				strict, err = strconv.ParseBool(args[2].(string))
+				if err != nil {
+					return "", fmt.Errorf(
+						"invalid value for strict, got %s", args[2])
+				}
+			}
+
+			list := listStrings(args[0])
+			if len(list) == 0 {
+				return "", fmt.Errorf("element() may not be used with an empty list")
+			}
 
 			index, err := strconv.Atoi(args[1].(string))
 			if err != nil {
 				return "", fmt.Errorf(
 					"invalid number for index, got %s", args[1])
 			}
+			if index < 0 {
+				return "", fmt.Errorf(
+					"index must not be negative, got %d", index)
+			}
+			if strict && index >= len(list) {
+				return "", fmt.Errorf(
+					"index %d out of range for list of length %d",
+					index, len(list))
+			}
 
 			v := list[index%len(list)]
 			return v, nil
 		},
 	}
 }
+
+// interpolationFuncUUID implements the "uuid" function that generates
+// a random (version 4) UUID. A new value is generated every time the
+// function is evaluated, so the result changes on every plan.
+func interpolationFuncUUID() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			var b [16]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return "", fmt.Errorf("error generating uuid: %s", err)
+			}
+
+			// Set the version (4) and the variant (RFC 4122) bits
+			b[6] = (b[6] & 0x0f) | 0x40
+			b[8] = (b[8] & 0x3f) | 0x80
+
+			return fmt.Sprintf(
+				"%x-%x-%x-%x-%x",
+				b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+		},
+	}
+}
+
+// interpolationFuncZipMap implements the "zipmap" function that builds a
+// map from a list of keys and a list of values of the same length.
+// Interpolations can't return maps, so the map is returned encoded as a
+// JSON object, which can be read back with "jsondecode".
+func interpolationFuncZipMap() ast.Function {
+	return ast.Function{
+		ArgTypes:   []ast.Type{ast.TypeList, ast.TypeList},
+		ReturnType: ast.TypeString,
+		Callback: func(args []interface{}) (interface{}, error) {
+			keys := listStrings(args[0])
+			values := listStrings(args[1])
+			if len(keys) != len(values) {
+				return "", fmt.Errorf(
+					"number of keys (%d) does not match number of values (%d)",
+					len(keys), len(values))
+			}
+
+			result := make(map[string]string, len(keys))
+			for i, k := range keys {
+				result[k] = values[i]
+			}
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode JSON data: %s", err)
+			}
+
+			return string(data), nil
+		},
+	}
+}
+
+// listStrings returns the values of the elements of a list.
+func listStrings(v interface{}) []string {
+	list := v.([]ast.Variable)
+	result := make([]string, len(list))
+	for i, elem := range list {
+		result[i] = elem.Value.(string)
+	}
+
+	return result
+}
+
+// stringsList builds a list from the given strings.
+func stringsList(vs []string) []ast.Variable {
+	result := make([]ast.Variable, len(vs))
+	for i, v := range vs {
+		result[i] = ast.Variable{
+			Value: v,
+			Type:  ast.TypeString,
+		}
+	}
+
+	return result
+}
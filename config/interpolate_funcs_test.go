@@ -1,33 +1,1989 @@
 package config
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/config/lang"
 	"github.com/hashicorp/terraform/config/lang/ast"
+	"github.com/mitchellh/go-homedir"
 )
 
+func TestInterpolateFuncAbs(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${abs(0 - 5)}`,
+				"5",
+				false,
+			},
+
+			{
+				`${abs(1.5)}`,
+				"1.5",
+				false,
+			},
+
+			{
+				`${abs("-3")}`,
+				"3",
+				false,
+			},
+
+			{
+				`${abs(0)}`,
+				"0",
+				false,
+			},
+
+			// Not a number
+			{
+				`${abs("foo")}`,
+				nil,
+				true,
+			},
+
+			// No args
+			{
+				`${abs()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncCeil(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${ceil(1.2)}`,
+				"2",
+				false,
+			},
+
+			{
+				`${ceil(5)}`,
+				"5",
+				false,
+			},
+
+			{
+				`${ceil(0.0 - 1.5)}`,
+				"-1",
+				false,
+			},
+
+			// Derived from arithmetic
+			{
+				`${ceil(7.0 / 2)}`,
+				"4",
+				false,
+			},
+
+			// No args
+			{
+				`${ceil()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncFloor(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${floor(1.8)}`,
+				"1",
+				false,
+			},
+
+			{
+				`${floor(5)}`,
+				"5",
+				false,
+			},
+
+			{
+				`${floor(0.0 - 1.5)}`,
+				"-2",
+				false,
+			},
+
+			{
+				`${floor("2.5")}`,
+				"2",
+				false,
+			},
+
+			// No args
+			{
+				`${floor()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncMax(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${max(1, 5, 3)}`,
+				"5",
+				false,
+			},
+
+			{
+				`${max(1.5, 1.25)}`,
+				"1.5",
+				false,
+			},
+
+			{
+				`${max("2", 1)}`,
+				"2",
+				false,
+			},
+
+			{
+				`${max(4)}`,
+				"4",
+				false,
+			},
+
+			// No args
+			{
+				`${max()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncMin(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${min(3, 1, 5)}`,
+				"1",
+				false,
+			},
+
+			// Clamping a count
+			{
+				`${min("12", 10)}`,
+				"10",
+				false,
+			},
+
+			{
+				`${min(0 - 1, 0)}`,
+				"-1",
+				false,
+			},
+
+			// No args
+			{
+				`${min()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSignum(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.unset": ast.Variable{
+				Value: "0",
+				Type:  ast.TypeString,
+			},
+			"var.set": ast.Variable{
+				Value: "3",
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			{
+				`${signum(0 - 5)}`,
+				"-1",
+				false,
+			},
+
+			{
+				`${signum(0)}`,
+				"0",
+				false,
+			},
+
+			{
+				`${signum(15)}`,
+				"1",
+				false,
+			},
+
+			// Conditionally creating resources with count
+			{
+				`${signum(var.unset)}`,
+				"0",
+				false,
+			},
+
+			{
+				`${signum(var.set)}`,
+				"1",
+				false,
+			},
+
+			// Not a number
+			{
+				`${signum("foo")}`,
+				nil,
+				true,
+			},
+
+			// No args
+			{
+				`${signum()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncBase64Encode(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${base64encode("abc123!?$*&()'-=@~")}`,
+				"YWJjMTIzIT8kKiYoKSctPUB+",
+				false,
+			},
+
+			{
+				`${base64encode("")}`,
+				"",
+				false,
+			},
+
+			// No args
+			{
+				`${base64encode()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${base64encode("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncBase64Decode(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${base64decode("YWJjMTIzIT8kKiYoKSctPUB+")}`,
+				"abc123!?$*&()'-=@~",
+				false,
+			},
+
+			// Round trip
+			{
+				`${base64decode(base64encode("foo"))}`,
+				"foo",
+				false,
+			},
+
+			// Invalid base64 data
+			{
+				`${base64decode("this-is-an-invalid-base64-data")}`,
+				nil,
+				true,
+			},
+
+			// No args
+			{
+				`${base64decode()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncBase64Gzip(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// No args
+			{
+				`${base64gzip()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${base64gzip("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+
+	for _, input := range []string{"", "foo", "#!/bin/sh\necho hello\n"} {
+		ast, err := lang.Parse(fmt.Sprintf(`${base64gzip("%s")}`, input))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		out, _, err := lang.Eval(ast, langEvalConfig(nil))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		data, err := base64.StdEncoding.DecodeString(out.(string))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		actual, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(actual) != input {
+			t.Fatalf("bad: %q != %q", actual, input)
+		}
+	}
+}
+
+func TestInterpolateFuncCidrHost(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${cidrhost("192.168.1.0/24", 5)}`,
+				"192.168.1.5",
+				false,
+			},
+
+			// The network address doesn't need to be canonical
+			{
+				`${cidrhost("10.1.2.3/16", 258)}`,
+				"10.1.1.2",
+				false,
+			},
+
+			{
+				`${cidrhost("fd00:fd12:3456:7890::/56", 16)}`,
+				"fd00:fd12:3456:7800::10",
+				false,
+			},
+
+			// Host number too large for the prefix
+			{
+				`${cidrhost("192.168.1.0/30", 4)}`,
+				nil,
+				true,
+			},
+
+			// Negative host number
+			{
+				`${cidrhost("192.168.1.0/24", 0 - 1)}`,
+				nil,
+				true,
+			},
+
+			// Not a CIDR
+			{
+				`${cidrhost("not-a-cidr", 6)}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncCidrSubnet(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${cidrsubnet("192.168.2.0/20", 4, 6)}`,
+				"192.168.6.0/24",
+				false,
+			},
+
+			{
+				`${cidrsubnet("10.0.0.0/16", 8, 0)}`,
+				"10.0.0.0/24",
+				false,
+			},
+
+			{
+				`${cidrsubnet("10.0.0.0/16", 8, 255)}`,
+				"10.0.255.0/24",
+				false,
+			},
+
+			{
+				`${cidrsubnet("fe80::/48", 16, 6)}`,
+				"fe80:0:0:6::/64",
+				false,
+			},
+
+			// Netnum given as a string converts implicitly
+			{
+				`${cidrsubnet("10.0.0.0/16", 8, "3")}`,
+				"10.0.3.0/24",
+				false,
+			},
+
+			// Not enough bits left in the address
+			{
+				`${cidrsubnet("192.168.2.0/30", 4, 6)}`,
+				nil,
+				true,
+			},
+
+			// Network number too large for the new bits
+			{
+				`${cidrsubnet("10.0.0.0/16", 2, 4)}`,
+				nil,
+				true,
+			},
+
+			// Not a CIDR
+			{
+				`${cidrsubnet("not-a-cidr", 4, 6)}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncCoalesce(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.empty": ast.Variable{
+				Value: "",
+				Type:  ast.TypeString,
+			},
+			"var.name": ast.Variable{
+				Value: "explicit",
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			{
+				`${coalesce("first", "second", "third")}`,
+				"first",
+				false,
+			},
+
+			{
+				`${coalesce("", "second", "third")}`,
+				"second",
+				false,
+			},
+
+			{
+				`${coalesce(var.empty, "default")}`,
+				"default",
+				false,
+			},
+
+			{
+				`${coalesce(var.name, "default")}`,
+				"explicit",
+				false,
+			},
+
+			// All empty
+			{
+				`${coalesce("", "")}`,
+				"",
+				false,
+			},
+
+			{
+				`${coalesce("foo")}`,
+				"foo",
+				false,
+			},
+
+			// No args
+			{
+				`${coalesce()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncCompact(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Empty elements in the middle and at the ends
+			{
+				fmt.Sprintf(`${compact("%s")}`,
+					InterpSplitDelim+"a"+InterpSplitDelim+InterpSplitDelim+
+						"b"+InterpSplitDelim),
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Nothing to remove
+			{
+				fmt.Sprintf(`${compact("%s")}`,
+					"a"+InterpSplitDelim+"b"),
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Combined with split and join
+			{
+				`${join(",", compact(split(",", "sg-1,,sg-2,")))}`,
+				"sg-1,sg-2",
+				false,
+			},
+
+			// All empty
+			{
+				fmt.Sprintf(`${compact("%s")}`, InterpSplitDelim),
+				[]ast.Variable{},
+				false,
+			},
+
+			// No args
+			{
+				`${compact()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
 func TestInterpolateFuncConcat(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.list": ast.Variable{
+				Value: []ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				Type: ast.TypeList,
+			},
+		},
+		Cases: []testFunctionCase{
+			// Strings are lists of one element
+			{
+				`${concat("foo", "bar")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "foo", Type: ast.TypeString},
+					ast.Variable{Value: "bar", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			{
+				`${concat("foo")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "foo", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Lists
+			{
+				`${concat(split(",", "a,b"), split(",", "c"))}`,
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+					ast.Variable{Value: "c", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Elements containing the former list delimiter aren't split
+			{
+				`${join(",", concat(split(";", "a,b;c")))}`,
+				"a,b,c",
+				false,
+			},
+
+			{
+				`${length(concat(split(";", "a,b;c")))}`,
+				"2",
+				false,
+			},
+
+			// Used in a string
+			{
+				`${join("-", concat(var.list, "c"))}`,
+				"a-b-c",
+				false,
+			},
+
+			{
+				`${concat()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncDistinct(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Order of first occurrence is preserved
+			{
+				fmt.Sprintf(`${distinct("%s")}`,
+					"sg-b"+InterpSplitDelim+"sg-a"+InterpSplitDelim+
+						"sg-b"+InterpSplitDelim+"sg-a"),
+				[]ast.Variable{
+					ast.Variable{Value: "sg-b", Type: ast.TypeString},
+					ast.Variable{Value: "sg-a", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// No duplicates
+			{
+				fmt.Sprintf(`${distinct("%s")}`,
+					"a"+InterpSplitDelim+"b"),
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Concatenated lists
+			{
+				`${join(",", distinct(split(",", "subnet-1,subnet-2,subnet-1")))}`,
+				"subnet-1,subnet-2",
+				false,
+			},
+
+			// No args
+			{
+				`${distinct()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncEmpty(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${empty("")}`,
+				"true",
+				false,
+			},
+
+			{
+				`${empty("foo")}`,
+				"false",
+				false,
+			},
+
+			{
+				`${empty(" ")}`,
+				"false",
+				false,
+			},
+
+			// No args
+			{
+				`${empty()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${empty("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncEqual(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${equal("foo", "foo")}`,
+				"true",
+				false,
+			},
+
+			{
+				`${equal("foo", "bar")}`,
+				"false",
+				false,
+			},
+
+			{
+				`${equal("foo", "Foo")}`,
+				"false",
+				false,
+			},
+
+			{
+				`${equal("", "")}`,
+				"true",
+				false,
+			},
+
+			// Not enough args
+			{
+				`${equal("foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncFile(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	path := tf.Name()
+	tf.Write([]byte("foo"))
+	tf.Close()
+	defer os.Remove(path)
+
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				fmt.Sprintf(`${file("%s")}`, path),
+				"foo",
+				false,
+			},
+
+			// Invalid path
+			{
+				`${file("/i/dont/exist")}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${file("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncFormatList(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// No args
+			{
+				`${formatlist()}`,
+				nil,
+				true,
+			},
+
+			// Single list
+			{
+				fmt.Sprintf(`${formatlist("subnet-%%s", "%s")}`,
+					"foo"+InterpSplitDelim+"bar"),
+				[]ast.Variable{
+					ast.Variable{Value: "subnet-foo", Type: ast.TypeString},
+					ast.Variable{Value: "subnet-bar", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Hostnames to URLs
+			{
+				fmt.Sprintf(`${formatlist("https://%%s:%%s/", "%s", "8080")}`,
+					"a.example.com"+InterpSplitDelim+"b.example.com"),
+				[]ast.Variable{
+					ast.Variable{Value: "https://a.example.com:8080/", Type: ast.TypeString},
+					ast.Variable{Value: "https://b.example.com:8080/", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Single element is treated as a list of one
+			{
+				`${formatlist("subnet-%s", "foo")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "subnet-foo", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Zipped lists
+			{
+				fmt.Sprintf(`${formatlist("%%s=%%s", "%s", "%s")}`,
+					"a"+InterpSplitDelim+"b",
+					"1"+InterpSplitDelim+"2"),
+				[]ast.Variable{
+					ast.Variable{Value: "a=1", Type: ast.TypeString},
+					ast.Variable{Value: "b=2", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Non-list arguments are repeated
+			{
+				fmt.Sprintf(`${formatlist("%%s.%%s", "%s", "example.com")}`,
+					"a"+InterpSplitDelim+"b"),
+				[]ast.Variable{
+					ast.Variable{Value: "a.example.com", Type: ast.TypeString},
+					ast.Variable{Value: "b.example.com", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Mismatched list lengths
+			{
+				fmt.Sprintf(`${formatlist("%%s=%%s", "%s", "%s")}`,
+					"a"+InterpSplitDelim+"b",
+					"1"+InterpSplitDelim+"2"+InterpSplitDelim+"3"),
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncIndex(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				fmt.Sprintf(`${index("%s", "us-east-1b")}`,
+					"us-east-1a"+InterpSplitDelim+"us-east-1b"+InterpSplitDelim+"us-east-1c"),
+				"1",
+				false,
+			},
+
+			// First occurrence
+			{
+				fmt.Sprintf(`${index("%s", "a")}`,
+					"b"+InterpSplitDelim+"a"+InterpSplitDelim+"a"),
+				"1",
+				false,
+			},
+
+			{
+				`${index("foo", "foo")}`,
+				"0",
+				false,
+			},
+
+			// Can be used as an element index
+			{
+				fmt.Sprintf(`${element("%s", index("%s", "b"))}`,
+					"subnet-1"+InterpSplitDelim+"subnet-2",
+					"a"+InterpSplitDelim+"b"),
+				"subnet-2",
+				false,
+			},
+
+			// Not found
+			{
+				fmt.Sprintf(`${index("%s", "c")}`,
+					"a"+InterpSplitDelim+"b"),
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${index("foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncJoin(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${join(",")}`,
+				nil,
+				true,
+			},
+
+			{
+				`${join(",", "foo")}`,
+				"foo",
+				false,
+			},
+
+			/*
+				TODO
+				{
+					`${join(",", "foo", "bar")}`,
+					"foo,bar",
+					false,
+				},
+			*/
+
+			{
+				fmt.Sprintf(`${join(".", "%s")}`,
+					fmt.Sprintf(
+						"foo%sbar%sbaz",
+						InterpSplitDelim,
+						InterpSplitDelim)),
+				"foo.bar.baz",
+				false,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSort(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				fmt.Sprintf(`${sort("%s")}`,
+					"sg-c"+InterpSplitDelim+"sg-a"+InterpSplitDelim+"sg-b"),
+				[]ast.Variable{
+					ast.Variable{Value: "sg-a", Type: ast.TypeString},
+					ast.Variable{Value: "sg-b", Type: ast.TypeString},
+					ast.Variable{Value: "sg-c", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Sorting is lexical, not numeric
+			{
+				`${join(",", sort(split(",", "10,9,1")))}`,
+				"1,10,9",
+				false,
+			},
+
+			{
+				`${sort("foo")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "foo", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// No args
+			{
+				`${sort()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSplit(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${split(",")}`,
+				nil,
+				true,
+			},
+
+			{
+				`${split(",", "foo")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "foo", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			{
+				`${split(".", "foo.bar.baz")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "foo", Type: ast.TypeString},
+					ast.Variable{Value: "bar", Type: ast.TypeString},
+					ast.Variable{Value: "baz", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Used in a string
+			{
+				`foo-${split(".", "a.b")}`,
+				"foo-a" + InterpSplitDelim + "b",
+				false,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncJSONDecode(t *testing.T) {
+	manifest := `{
+		"name": "web",
+		"port": 8080,
+		"public": true,
+		"owner": null,
+		"zones": ["us-east-1a", "us-east-1b"],
+		"images": [{"id": "ami-1234"}, {"id": "ami-5678"}]
+	}`
+
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.manifest": ast.Variable{
+				Value: manifest,
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			{
+				`${jsondecode("\"hello\"")}`,
+				"hello",
+				false,
+			},
+
+			{
+				`${jsondecode(var.manifest, "name")}`,
+				"web",
+				false,
+			},
+
+			{
+				`${jsondecode(var.manifest, "port")}`,
+				"8080",
+				false,
+			},
+
+			{
+				`${jsondecode(var.manifest, "public")}`,
+				"true",
+				false,
+			},
+
+			{
+				`${jsondecode(var.manifest, "owner")}`,
+				"",
+				false,
+			},
+
+			// Arrays of primitives become lists
+			{
+				`${jsondecode(var.manifest, "zones")}`,
+				"us-east-1a" + InterpSplitDelim + "us-east-1b",
+				false,
+			},
+
+			// Nested values
+			{
+				`${jsondecode(var.manifest, "images", "1", "id")}`,
+				"ami-5678",
+				false,
+			},
+
+			// Round trip
+			{
+				`${join(",", jsondecode(jsonencode(split(",", "a,b"))))}`,
+				"a,b",
+				false,
+			},
+
+			// Objects can't be returned directly
+			{
+				`${jsondecode(var.manifest)}`,
+				nil,
+				true,
+			},
+
+			// Unknown key
+			{
+				`${jsondecode(var.manifest, "nope")}`,
+				nil,
+				true,
+			},
+
+			// Bad index
+			{
+				`${jsondecode(var.manifest, "zones", "2")}`,
+				nil,
+				true,
+			},
+
+			// Selecting within a primitive
+			{
+				`${jsondecode(var.manifest, "name", "foo")}`,
+				nil,
+				true,
+			},
+
+			// Invalid JSON
+			{
+				`${jsondecode("{")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncJSONEncode(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.list": ast.Variable{
+				Value: "arn:aws:s3:::a" + InterpSplitDelim + "arn:aws:s3:::b",
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			{
+				`${jsonencode("hello")}`,
+				`"hello"`,
+				false,
+			},
+
+			// Special characters are escaped
+			{
+				`${jsonencode("say \"hi\"\n")}`,
+				`"say \"hi\"\n"`,
+				false,
+			},
+
+			{
+				`${jsonencode("")}`,
+				`""`,
+				false,
+			},
+
+			// Lists become arrays
+			{
+				`${jsonencode(var.list)}`,
+				`["arn:aws:s3:::a","arn:aws:s3:::b"]`,
+				false,
+			},
+
+			{
+				`${jsonencode(split(",", "a,b,c"))}`,
+				`["a","b","c"]`,
+				false,
+			},
+
+			// No args
+			{
+				`${jsonencode()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncLength(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Lists
+			{
+				fmt.Sprintf(`${length("%s")}`,
+					"foo"+InterpSplitDelim+"bar"+InterpSplitDelim+"baz"),
+				"3",
+				false,
+			},
+
+			{
+				`${length(split(",", "a,b"))}`,
+				"2",
+				false,
+			},
+
+			// Strings
+			{
+				`${length("foo")}`,
+				"3",
+				false,
+			},
+
+			{
+				`${length("")}`,
+				"0",
+				false,
+			},
+
+			// The result can be used in math
+			{
+				`${length("foo") + 1}`,
+				"4",
+				false,
+			},
+
+			// No args
+			{
+				`${length()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${length("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncMd5(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${md5("tada")}`,
+				"ce47d07243bb6eaf5e1322c81baf9bbf",
+				false,
+			},
+
+			{
+				`${md5("")}`,
+				"d41d8cd98f00b204e9800998ecf8427e",
+				false,
+			},
+
+			// No args
+			{
+				`${md5()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSha1(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${sha1("test")}`,
+				"a94a8fe5ccb19ba61c4c0873d391e987982fbbd3",
+				false,
+			},
+
+			// No args
+			{
+				`${sha1()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSha256(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${sha256("test")}`,
+				"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				false,
+			},
+
+			// No args
+			{
+				`${sha256()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncPathExpand(t *testing.T) {
+	home, err := homedir.Dir()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${pathexpand("~/.ssh/id_rsa")}`,
+				filepath.Join(home, ".ssh", "id_rsa"),
+				false,
+			},
+
+			{
+				`${pathexpand("~")}`,
+				home,
+				false,
+			},
+
+			// Paths without a leading ~ are unchanged
+			{
+				`${pathexpand("/etc/resolv.conf")}`,
+				"/etc/resolv.conf",
+				false,
+			},
+
+			{
+				`${pathexpand("foo/~/bar")}`,
+				"foo/~/bar",
+				false,
+			},
+
+			// Other users' home directories aren't supported
+			{
+				`${pathexpand("~someone/foo")}`,
+				nil,
+				true,
+			},
+
+			// No args
+			{
+				`${pathexpand()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncRegex(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// No capture groups returns the whole match
+			{
+				`${regex("[a-z]+-[0-9]+", "ami-1234 ami-5678")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "ami-1234", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Capture groups
+			{
+				`${regex("^arn:aws:([^:]+):([^:]*):", "arn:aws:sqs:us-west-2:123456789012:queue")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "sqs", Type: ast.TypeString},
+					ast.Variable{Value: "us-west-2", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// A single capture used as a string
+			{
+				`region-${regex("^arn:aws:[^:]+:([^:]*):", "arn:aws:sqs:us-west-2:123456789012:queue")}`,
+				"region-us-west-2",
+				false,
+			},
+
+			{
+				`${element(regex("v([0-9]+)\.([0-9]+)", "app-v1.12-hvm"), 1)}`,
+				"12",
+				false,
+			},
+
+			// No match
+			{
+				`${regex("[0-9]+", "foo")}`,
+				nil,
+				true,
+			},
+
+			// Invalid pattern
+			{
+				`${regex("(", "foo")}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${regex("foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncRegexAll(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${regexall("[a-z]+-[0-9]+", "ami-1234 ami-5678")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "ami-1234", Type: ast.TypeString},
+					ast.Variable{Value: "ami-5678", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// A capture group returns its value for each match
+			{
+				`${regexall("ami-([0-9]+)", "ami-1234 ami-5678")}`,
+				[]ast.Variable{
+					ast.Variable{Value: "1234", Type: ast.TypeString},
+					ast.Variable{Value: "5678", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// No match is an empty list
+			{
+				`${regexall("[0-9]+", "foo")}`,
+				[]ast.Variable{},
+				false,
+			},
+
+			{
+				`${length(regexall("[0-9]+", "foo"))}`,
+				"0",
+				false,
+			},
+
+			// Too many capture groups
+			{
+				`${regexall("([a-z]+)-([0-9]+)", "ami-1234")}`,
+				nil,
+				true,
+			},
+
+			// Invalid pattern
+			{
+				`${regexall("(", "foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncReplace(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Regular search and replace
+			{
+				`${replace("hello", "hel", "bel")}`,
+				"bello",
+				false,
+			},
+
+			// All occurrences are replaced
+			{
+				`${replace("my.elb.name", ".", "-")}`,
+				"my-elb-name",
+				false,
+			},
+
+			// Search string doesn't match
+			{
+				`${replace("hello", "nope", "bel")}`,
+				"hello",
+				false,
+			},
+
+			// Regular expression
+			{
+				`${replace("hello", "/l/", "L")}`,
+				"heLLo",
+				false,
+			},
+
+			{
+				`${replace("helo", "/(l)/", "$1$1")}`,
+				"hello",
+				false,
+			},
+
+			// A single slash isn't a regular expression
+			{
+				`${replace("a/b", "/", "-")}`,
+				"a-b",
+				false,
+			},
+
+			// Bad regexp
+			{
+				`${replace("foo", "/(/", "")}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${replace("foo", "f")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSlice(t *testing.T) {
+	list := "a" + InterpSplitDelim + "b" + InterpSplitDelim + "c"
+
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.list": ast.Variable{
+				Value: list,
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			// First N elements
+			{
+				`${slice(var.list, 0, 2)}`,
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			{
+				`${slice(var.list, 1, 3)}`,
+				[]ast.Variable{
+					ast.Variable{Value: "b", Type: ast.TypeString},
+					ast.Variable{Value: "c", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			{
+				`${slice(var.list, 1, 2)}`,
+				[]ast.Variable{
+					ast.Variable{Value: "b", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// The whole list
+			{
+				`${slice(var.list, 0, length(var.list))}`,
+				[]ast.Variable{
+					ast.Variable{Value: "a", Type: ast.TypeString},
+					ast.Variable{Value: "b", Type: ast.TypeString},
+					ast.Variable{Value: "c", Type: ast.TypeString},
+				},
+				false,
+			},
+
+			// Empty
+			{
+				`${slice(var.list, 1, 1)}`,
+				[]ast.Variable{},
+				false,
+			},
+
+			// End out of range
+			{
+				`${slice(var.list, 0, 4)}`,
+				nil,
+				true,
+			},
+
+			// Start after end
+			{
+				`${slice(var.list, 2, 1)}`,
+				nil,
+				true,
+			},
+
+			// Negative start
+			{
+				`${slice(var.list, 0 - 1, 1)}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${slice(var.list, 0)}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSlug(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${slug("foo")}`,
+				"foo",
+				false,
+			},
+
+			// Mixed case
+			{
+				`${slug("FooBar")}`,
+				"foobar",
+				false,
+			},
+
+			// Spaces and underscores
+			{
+				`${slug("my web_server  group")}`,
+				"my-web-server-group",
+				false,
+			},
+
+			// Punctuation
+			{
+				`${slug("  Web (prod): v1.2!  ")}`,
+				"web-prod-v12",
+				false,
+			},
+
+			// No args
+			{
+				`${slug()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${slug("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncLower(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${lower("HELLO")}`,
+				"hello",
+				false,
+			},
+
+			{
+				`${lower("US-West-2")}`,
+				"us-west-2",
+				false,
+			},
+
+			{
+				`${lower("")}`,
+				"",
+				false,
+			},
+
+			// No args
+			{
+				`${lower()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${lower("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncUpper(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${upper("hello")}`,
+				"HELLO",
+				false,
+			},
+
+			{
+				`${upper("us-West-2")}`,
+				"US-WEST-2",
+				false,
+			},
+
+			{
+				`${upper("")}`,
+				"",
+				false,
+			},
+
+			// No args
+			{
+				`${upper()}`,
+				nil,
+				true,
+			},
+
+			// Too many args
+			{
+				`${upper("foo", "bar")}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncSubstr(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${substr("foobar", 0, 3)}`,
+				"foo",
+				false,
+			},
+
+			{
+				`${substr("foobar", 3, 3)}`,
+				"bar",
+				false,
+			},
+
+			// Rest of the string
+			{
+				`${substr("foobar", 2, 0 - 1)}`,
+				"obar",
+				false,
+			},
+
+			// Negative offsets count from the end
+			{
+				`${substr("foobar", 0 - 3, 2)}`,
+				"ba",
+				false,
+			},
+
+			{
+				`${substr("foobar", 0 - 6, 0 - 1)}`,
+				"foobar",
+				false,
+			},
+
+			// Lengths past the end are truncated, which allows using
+			// this to enforce name length limits
+			{
+				`${substr("short-name", 0, 32)}`,
+				"short-name",
+				false,
+			},
+
+			{
+				`${substr(sha1("test"), 0, 8)}`,
+				"a94a8fe5",
+				false,
+			},
+
+			// Multi-byte characters
+			{
+				`${substr("héllo", 1, 3)}`,
+				"éll",
+				false,
+			},
+
+			{
+				`${substr("foobar", 6, 1)}`,
+				"",
+				false,
+			},
+
+			// Offset out of range
+			{
+				`${substr("foobar", 7, 1)}`,
+				nil,
+				true,
+			},
+
+			{
+				`${substr("foobar", 0 - 7, 1)}`,
+				nil,
+				true,
+			},
+
+			// Bad length
+			{
+				`${substr("foobar", 0, 0 - 2)}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${substr("foobar", 0)}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncTemplateFile(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	path := tf.Name()
+	tf.Write([]byte("#!/bin/sh\nserve --name ${name} --port ${port} ${upper(name)}\n"))
+	tf.Close()
+	defer os.Remove(path)
+
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				fmt.Sprintf(
+					`${templatefile("%s", "name", "web", "port", "8080")}`, path),
+				"#!/bin/sh\nserve --name web --port 8080 WEB\n",
+				false,
+			},
+
+			// Unknown variable
+			{
+				fmt.Sprintf(`${templatefile("%s", "name", "web")}`, path),
+				nil,
+				true,
+			},
+
+			// Unpaired variable
+			{
+				fmt.Sprintf(
+					`${templatefile("%s", "name", "web", "port")}`, path),
+				nil,
+				true,
+			},
+
+			// Invalid path
+			{
+				`${templatefile("/i/dont/exist")}`,
+				nil,
+				true,
+			},
+
+			// No args
+			{
+				`${templatefile()}`,
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncTimestamp(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Too many args
+			{
+				`${timestamp("foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+
+	ast, err := lang.Parse(`${timestamp()}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	out, _, err := lang.Eval(ast, langEvalConfig(nil))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	after := time.Now().UTC()
+
+	ts, err := time.Parse(time.RFC3339, out.(string))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ts.Before(before) || ts.After(after) {
+		t.Fatalf("bad: %s not between %s and %s", ts, before, after)
+	}
+	if ts.Location() != time.UTC {
+		t.Fatalf("bad: %s", out)
+	}
+}
+
+func TestInterpolateFuncTimeAdd(t *testing.T) {
 	testFunction(t, testFunctionConfig{
 		Cases: []testFunctionCase{
 			{
-				`${concat("foo", "bar")}`,
-				"foobar",
+				`${timeadd("2015-03-01T12:00:00Z", "10m")}`,
+				"2015-03-01T12:10:00Z",
 				false,
 			},
 
 			{
-				`${concat("foo")}`,
-				"foo",
+				`${timeadd("2015-03-01T00:00:00Z", "-1h30m")}`,
+				"2015-02-28T22:30:00Z",
 				false,
 			},
 
+			// The offset of the timestamp is preserved
 			{
-				`${concat()}`,
+				`${timeadd("2015-03-01T12:00:00+02:00", "24h")}`,
+				"2015-03-02T12:00:00+02:00",
+				false,
+			},
+
+			// Invalid timestamp
+			{
+				`${timeadd("2015-03-01", "1h")}`,
+				nil,
+				true,
+			},
+
+			// Invalid duration
+			{
+				`${timeadd("2015-03-01T12:00:00Z", "1 day")}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${timeadd("2015-03-01T12:00:00Z")}`,
 				nil,
 				true,
 			},
@@ -35,34 +1991,38 @@ func TestInterpolateFuncConcat(t *testing.T) {
 	})
 }
 
-func TestInterpolateFuncFile(t *testing.T) {
-	tf, err := ioutil.TempFile("", "tf")
-	if err != nil {
-		t.Fatalf("err: %s", err)
-	}
-	path := tf.Name()
-	tf.Write([]byte("foo"))
-	tf.Close()
-	defer os.Remove(path)
-
+func TestInterpolateFuncTitle(t *testing.T) {
 	testFunction(t, testFunctionConfig{
 		Cases: []testFunctionCase{
 			{
-				fmt.Sprintf(`${file("%s")}`, path),
-				"foo",
+				`${title("hello world")}`,
+				"Hello World",
 				false,
 			},
 
-			// Invalid path
+			// Mixed case is preserved apart from the first letter
 			{
-				`${file("/i/dont/exist")}`,
+				`${title("hELLO wORLD")}`,
+				"HELLO WORLD",
+				false,
+			},
+
+			{
+				`${title("web-server group")}`,
+				"Web-Server Group",
+				false,
+			},
+
+			// No args
+			{
+				`${title()}`,
 				nil,
 				true,
 			},
 
 			// Too many args
 			{
-				`${file("foo", "bar")}`,
+				`${title("foo", "bar")}`,
 				nil,
 				true,
 			},
@@ -70,66 +2030,141 @@ func TestInterpolateFuncFile(t *testing.T) {
 	})
 }
 
-func TestInterpolateFuncJoin(t *testing.T) {
+func TestInterpolateFuncTrim(t *testing.T) {
 	testFunction(t, testFunctionConfig{
 		Cases: []testFunctionCase{
 			{
-				`${join(",")}`,
+				`${trim("--foo--", "-")}`,
+				"foo",
+				false,
+			},
+
+			// Every character of the cutset is trimmed
+			{
+				`${trim("?!hello?!", "!?")}`,
+				"hello",
+				false,
+			},
+
+			{
+				`${trim("foo", "x")}`,
+				"foo",
+				false,
+			},
+
+			// Not enough args
+			{
+				`${trim("foo")}`,
 				nil,
 				true,
 			},
+		},
+	})
+}
 
+func TestInterpolateFuncTrimPrefix(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
 			{
-				`${join(",", "foo")}`,
-				"foo",
+				`${trimprefix("arn:aws:iam::123", "arn:aws:")}`,
+				"iam::123",
 				false,
 			},
 
-			/*
-				TODO
-				{
-					`${join(",", "foo", "bar")}`,
-					"foo,bar",
-					false,
-				},
-			*/
+			// Only removed once
+			{
+				`${trimprefix("foofoobar", "foo")}`,
+				"foobar",
+				false,
+			},
 
+			// Not a prefix
 			{
-				fmt.Sprintf(`${join(".", "%s")}`,
-					fmt.Sprintf(
-						"foo%sbar%sbaz",
-						InterpSplitDelim,
-						InterpSplitDelim)),
-				"foo.bar.baz",
+				`${trimprefix("foobar", "bar")}`,
+				"foobar",
 				false,
 			},
+
+			// Not enough args
+			{
+				`${trimprefix("foo")}`,
+				nil,
+				true,
+			},
 		},
 	})
 }
 
-func TestInterpolateFuncSplit(t *testing.T) {
+func TestInterpolateFuncTrimSpace(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	path := tf.Name()
+	tf.Write([]byte("secret-token\n"))
+	tf.Close()
+	defer os.Remove(path)
+
 	testFunction(t, testFunctionConfig{
 		Cases: []testFunctionCase{
 			{
-				`${split(",")}`,
+				`${trimspace("  foo bar\n ")}`,
+				"foo bar",
+				false,
+			},
+
+			// Trailing newline from a file
+			{
+				fmt.Sprintf(`${trimspace(file("%s"))}`, path),
+				"secret-token",
+				false,
+			},
+
+			{
+				`${trimspace("foo")}`,
+				"foo",
+				false,
+			},
+
+			// No args
+			{
+				`${trimspace()}`,
 				nil,
 				true,
 			},
+		},
+	})
+}
+
+func TestInterpolateFuncTrimSuffix(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			{
+				`${trimsuffix("example.com.", ".")}`,
+				"example.com",
+				false,
+			},
 
+			// Only removed once
 			{
-				`${split(",", "foo")}`,
-				"foo",
+				`${trimsuffix("barbar", "bar")}`,
+				"bar",
 				false,
 			},
 
+			// Not a suffix
 			{
-				`${split(".", "foo.bar.baz")}`,
-				fmt.Sprintf(
-					"foo%sbar%sbaz",
-					InterpSplitDelim,
-					InterpSplitDelim),
+				`${trimsuffix("foobar", "foo")}`,
+				"foobar",
 				false,
 			},
+
+			// Not enough args
+			{
+				`${trimsuffix("foo")}`,
+				nil,
+				true,
+			},
 		},
 	})
 }
@@ -156,9 +2191,30 @@ func TestInterpolateFuncLookup(t *testing.T) {
 				true,
 			},
 
+			// Default is ignored if the key exists
+			{
+				`${lookup("foo", "bar", "qux")}`,
+				"baz",
+				false,
+			},
+
+			// Default is used if the key doesn't exist
+			{
+				`${lookup("foo", "baz", "qux")}`,
+				"qux",
+				false,
+			},
+
+			// Empty default
+			{
+				`${lookup("foo", "baz", "")}`,
+				"",
+				false,
+			},
+
 			// Too many args
 			{
-				`${lookup("foo", "bar", "baz")}`,
+				`${lookup("foo", "bar", "baz", "qux")}`,
 				nil,
 				true,
 			},
@@ -190,13 +2246,148 @@ func TestInterpolateFuncElement(t *testing.T) {
 				false,
 			},
 
-			// Too many args
+			// Non-numeric index
+			{
+				fmt.Sprintf(`${element("%s", "abc")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				nil,
+				true,
+			},
+
+			// Negative index
+			{
+				fmt.Sprintf(`${element("%s", "-1")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				nil,
+				true,
+			},
+
+			// Strict mode with an in-range index
+			{
+				fmt.Sprintf(`${element("%s", "1", "true")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				"baz",
+				false,
+			},
+
+			// Strict mode with an out-of-bounds index
+			{
+				fmt.Sprintf(`${element("%s", "2", "true")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				nil,
+				true,
+			},
+
+			// Strict mode disabled still wraps
+			{
+				fmt.Sprintf(`${element("%s", "2", "false")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				"foo",
+				false,
+			},
+
+			// Invalid strict value
 			{
 				fmt.Sprintf(`${element("%s", "0", "2")}`,
 					"foo"+InterpSplitDelim+"baz"),
 				nil,
 				true,
 			},
+
+			// Too many args
+			{
+				fmt.Sprintf(`${element("%s", "0", "true", "true")}`,
+					"foo"+InterpSplitDelim+"baz"),
+				nil,
+				true,
+			},
+		},
+	})
+}
+
+func TestInterpolateFuncUUID(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Cases: []testFunctionCase{
+			// Too many args
+			{
+				`${uuid("foo")}`,
+				nil,
+				true,
+			},
+		},
+	})
+
+	ast, err := lang.Parse(`${uuid()}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	re := regexp.MustCompile(
+		`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		out, _, err := lang.Eval(ast, langEvalConfig(nil))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		v := out.(string)
+		if !re.MatchString(v) {
+			t.Fatalf("bad: %s", v)
+		}
+		if _, ok := seen[v]; ok {
+			t.Fatalf("duplicate: %s", v)
+		}
+		seen[v] = struct{}{}
+	}
+}
+
+func TestInterpolateFuncZipMap(t *testing.T) {
+	testFunction(t, testFunctionConfig{
+		Vars: map[string]ast.Variable{
+			"var.names": ast.Variable{
+				Value: "web-1" + InterpSplitDelim + "web-0",
+				Type:  ast.TypeString,
+			},
+			"var.ips": ast.Variable{
+				Value: "10.0.0.2" + InterpSplitDelim + "10.0.0.1",
+				Type:  ast.TypeString,
+			},
+		},
+		Cases: []testFunctionCase{
+			// Keys are sorted in the result
+			{
+				`${zipmap(var.names, var.ips)}`,
+				`{"web-0":"10.0.0.1","web-1":"10.0.0.2"}`,
+				false,
+			},
+
+			{
+				`${zipmap("foo", "bar")}`,
+				`{"foo":"bar"}`,
+				false,
+			},
+
+			// Values can be read back
+			{
+				`${jsondecode(zipmap(var.names, var.ips), "web-1")}`,
+				"10.0.0.2",
+				false,
+			},
+
+			// Mismatched lengths
+			{
+				`${zipmap(var.names, "10.0.0.1")}`,
+				nil,
+				true,
+			},
+
+			// Not enough args
+			{
+				`${zipmap(var.names)}`,
+				nil,
+				true,
+			},
 		},
 	})
 }
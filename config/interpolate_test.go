@@ -21,6 +21,14 @@ func TestNewInterpolatedVariable(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"local.foo",
+			&LocalVariable{
+				Name: "foo",
+				key:  "local.foo",
+			},
+			false,
+		},
 		{
 			"module.foo.bar",
 			&ModuleVariable{
@@ -30,6 +38,28 @@ func TestNewInterpolatedVariable(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"module.foo.*.bar",
+			&ModuleVariable{
+				Name:  "foo",
+				Field: "bar",
+				Multi: true,
+				Index: -1,
+				key:   "module.foo.*.bar",
+			},
+			false,
+		},
+		{
+			"module.foo.2.bar",
+			&ModuleVariable{
+				Name:  "foo",
+				Field: "bar",
+				Multi: true,
+				Index: 2,
+				key:   "module.foo.2.bar",
+			},
+			false,
+		},
 		{
 			"count.index",
 			&CountVariable{
@@ -46,6 +76,14 @@ func TestNewInterpolatedVariable(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"each.value",
+			&EachVariable{
+				Type: EachValueValue,
+				key:  "each.value",
+			},
+			false,
+		},
 		{
 			"path.module",
 			&PathVariable{
@@ -75,6 +113,12 @@ func TestNewInterpolatedVariable(t *testing.T) {
 	}
 }
 
+func TestNewModuleVariable_badIndex(t *testing.T) {
+	if _, err := NewModuleVariable("module.foo.bar.baz"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func TestNewResourceVariable(t *testing.T) {
 	v, err := NewResourceVariable("foo.bar.baz")
 	if err != nil {
@@ -99,6 +143,61 @@ func TestNewResourceVariable(t *testing.T) {
 	}
 }
 
+func TestNewResourceVariable_data(t *testing.T) {
+	v, err := NewResourceVariable("data.foo.bar.baz")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if v.Mode != DataResourceMode {
+		t.Fatalf("bad: %#v", v)
+	}
+	if v.Type != "foo" {
+		t.Fatalf("bad: %#v", v)
+	}
+	if v.Name != "bar" {
+		t.Fatalf("bad: %#v", v)
+	}
+	if v.Field != "baz" {
+		t.Fatalf("bad: %#v", v)
+	}
+	if v.ResourceId() != "data.foo.bar" {
+		t.Fatalf("bad: %#v", v.ResourceId())
+	}
+	if v.FullKey() != "data.foo.bar.baz" {
+		t.Fatalf("bad: %#v", v)
+	}
+
+	if _, err := NewResourceVariable("data.foo.bar"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestNewResourceVariable_allAttributes(t *testing.T) {
+	v, err := NewResourceVariable("foo.bar.*")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !v.AllAttributes() {
+		t.Fatalf("bad: %#v", v)
+	}
+	if v.Multi {
+		t.Fatal("should not be multi")
+	}
+
+	v, err = NewResourceVariable("foo.bar.1.*")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !v.AllAttributes() || !v.Multi || v.Index != 1 {
+		t.Fatalf("bad: %#v", v)
+	}
+
+	if _, err := NewResourceVariable("foo.bar.*.*"); err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func TestNewUserVariable(t *testing.T) {
 	v, err := NewUserVariable("var.bar")
 	if err != nil {
@@ -219,6 +318,42 @@ func TestDetectVariables(t *testing.T) {
 				},
 			},
 		},
+
+		{
+			`${[for i, ip in var.ips : ip if element(var.roles, i) == var.role]}`,
+			[]InterpolatedVariable{
+				&UserVariable{
+					Name: "ips",
+					key:  "var.ips",
+				},
+				&UserVariable{
+					Name: "roles",
+					key:  "var.roles",
+				},
+				&UserVariable{
+					Name: "role",
+					key:  "var.role",
+				},
+			},
+		},
+
+		{
+			`${var.env == "prod" ? var.foo : var.bar}`,
+			[]InterpolatedVariable{
+				&UserVariable{
+					Name: "env",
+					key:  "var.env",
+				},
+				&UserVariable{
+					Name: "foo",
+					key:  "var.foo",
+				},
+				&UserVariable{
+					Name: "bar",
+					key:  "var.bar",
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
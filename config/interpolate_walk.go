@@ -11,9 +11,9 @@ import (
 )
 
 // InterpSplitDelim is the delimeter that is looked for to split when
-// it is returned. This is a comma right now but should eventually become
-// a value that a user is very unlikely to use (such as UUID).
-const InterpSplitDelim = `B780FFEC-B661-4EB8-9236-A01737AD98B6`
+// it is returned. Lists resulting from an interpolation are joined with
+// this so that they can be split back out within slices.
+const InterpSplitDelim = lang.ListDelim
 
 // interpolationWalker implements interfaces for the reflectwalk package
 // (github.com/mitchellh/reflectwalk) that can be used to automatically
@@ -39,6 +39,7 @@ type interpolationWalker struct {
 	csData      interface{}
 	sliceIndex  int
 	unknownKeys []string
+	nullKeys    []string
 }
 
 // interpolationWalkerFunc is the callback called by interpolationWalk.
@@ -148,6 +149,19 @@ func (w *interpolationWalker) Primitive(v reflect.Value) error {
 		// if the result contains any "UnknownVariableValue" which is
 		// set if it is computed. This behavior is different if we're
 		// splitting (in a SliceElem) or not.
+		// A null value removes the element as if it was never set
+		if replaceVal == NullVariableValue {
+			if w.loc == reflectwalk.SliceElem {
+				return fmt.Errorf(
+					"null can't be used as a list element in:\n\n%s",
+					v.String())
+			}
+
+			w.nullKeys = append(w.nullKeys, strings.Join(w.key, "."))
+			w.deleteCurrent()
+			return nil
+		}
+
 		remove := false
 		if w.loc == reflectwalk.SliceElem {
 			parts := strings.Split(replaceVal, InterpSplitDelim)
@@ -198,6 +212,12 @@ func (w *interpolationWalker) removeCurrent() {
 	// Append the key to the unknown keys
 	w.unknownKeys = append(w.unknownKeys, strings.Join(w.key, "."))
 
+	w.deleteCurrent()
+}
+
+// deleteCurrent deletes the key of the map that contains the current
+// value.
+func (w *interpolationWalker) deleteCurrent() {
 	for i := 1; i <= len(w.cs); i++ {
 		c := w.cs[len(w.cs)-i]
 		switch c.Kind() {
@@ -212,7 +232,7 @@ func (w *interpolationWalker) removeCurrent() {
 		}
 	}
 
-	panic("No container found for removeCurrent")
+	panic("No container found for deleteCurrent")
 }
 
 func (w *interpolationWalker) replaceCurrent(v reflect.Value) {
@@ -1,6 +1,7 @@
 package ast
 
-// ArithmeticOp is the operation to use for the math.
+// ArithmeticOp is the operation to use for the math. This includes
+// comparisons and boolean logic, which result in a bool.
 type ArithmeticOp int
 
 const (
@@ -10,4 +11,15 @@ const (
 	ArithmeticOpMul
 	ArithmeticOpDiv
 	ArithmeticOpMod
+
+	ArithmeticOpEqual
+	ArithmeticOpNotEqual
+	ArithmeticOpLessThan
+	ArithmeticOpLessThanOrEqual
+	ArithmeticOpGreaterThan
+	ArithmeticOpGreaterThanOrEqual
+
+	ArithmeticOpLogicalAnd
+	ArithmeticOpLogicalOr
+	ArithmeticOpLogicalNot
 )
@@ -44,6 +44,9 @@ type Visitor func(Node) Node
 //go:generate stringer -type=Type
 
 // Type is the type of any value.
+//
+// The Go type of a TypeList value is []Variable and of a TypeBool value
+// is bool. The only TypeNull value is nil, which means "unset".
 type Type uint32
 
 const (
@@ -51,4 +54,7 @@ const (
 	TypeString  Type = 1 << iota
 	TypeInt
 	TypeFloat
+	TypeList
+	TypeBool
+	TypeNull
 )
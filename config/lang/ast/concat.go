@@ -6,7 +6,9 @@ import (
 )
 
 // Concat represents a node where the result of two or more expressions are
-// concatenated. The result of all expressions must be a string.
+// concatenated. The result of all expressions must be a string, except
+// that a Concat of a single list expression is that list so that lists
+// can be the result of an interpolation.
 type Concat struct {
 	Exprs []Node
 	Posx  Pos
@@ -37,6 +39,16 @@ func (n *Concat) String() string {
 	return b.String()
 }
 
-func (n *Concat) Type(Scope) (Type, error) {
+func (n *Concat) Type(s Scope) (Type, error) {
+	if len(n.Exprs) == 1 {
+		t, err := n.Exprs[0].Type(s)
+		if err != nil {
+			return TypeInvalid, err
+		}
+		if t == TypeList {
+			return t, nil
+		}
+	}
+
 	return TypeString, nil
 }
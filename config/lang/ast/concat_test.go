@@ -14,3 +14,21 @@ func TestConcatType(t *testing.T) {
 		t.Fatalf("bad: %s", actual)
 	}
 }
+
+func TestConcatType_single(t *testing.T) {
+	c := &Concat{
+		Exprs: []Node{
+			&LiteralNode{
+				Value: []Variable{},
+				Typex: TypeList,
+			},
+		},
+	}
+	actual, err := c.Type(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != TypeList {
+		t.Fatalf("bad: %s", actual)
+	}
+}
@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// Conditional represents a node that results in one of two expressions
+// depending on the value of a condition: "cond ? true_val : false_val".
+//
+// Only the condition is visited by Accept, so that only the chosen result
+// is evaluated. Anything that needs to look inside the results must
+// handle Conditional directly.
+type Conditional struct {
+	CondExpr  Node
+	TrueExpr  Node
+	FalseExpr Node
+	Posx      Pos
+}
+
+func (n *Conditional) Accept(v Visitor) Node {
+	n.CondExpr = n.CondExpr.Accept(v)
+
+	return v(n)
+}
+
+func (n *Conditional) Pos() Pos {
+	return n.Posx
+}
+
+func (n *Conditional) GoString() string {
+	return fmt.Sprintf("*%#v", *n)
+}
+
+func (n *Conditional) String() string {
+	return fmt.Sprintf("Conditional(%s, %s, %s)", n.CondExpr, n.TrueExpr, n.FalseExpr)
+}
+
+func (n *Conditional) Type(s Scope) (Type, error) {
+	return n.TrueExpr.Type(s)
+}
@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// For represents a for expression that builds a list by evaluating an
+// expression for every element of another list:
+// "[for i, v in list : expr if cond]".
+//
+// Only the list is visited by Accept. The body and condition are
+// evaluated once per element with the iteration variables in scope, so
+// anything that needs to look inside them must handle For directly.
+type For struct {
+	// IndexVar is the name of the variable holding the index of the
+	// element, or empty if it isn't used. ValueVar is the name of the
+	// variable holding the element itself.
+	IndexVar string
+	ValueVar string
+
+	List Node
+	Body Node
+
+	// Cond filters the elements, if set. Only elements for which it is
+	// true are included in the result.
+	Cond Node
+
+	Posx Pos
+}
+
+func (n *For) Accept(v Visitor) Node {
+	n.List = n.List.Accept(v)
+
+	return v(n)
+}
+
+func (n *For) Pos() Pos {
+	return n.Posx
+}
+
+func (n *For) GoString() string {
+	return fmt.Sprintf("*%#v", *n)
+}
+
+func (n *For) String() string {
+	vars := n.ValueVar
+	if n.IndexVar != "" {
+		vars = n.IndexVar + ", " + vars
+	}
+
+	if n.Cond == nil {
+		return fmt.Sprintf("For(%s in %s: %s)", vars, n.List, n.Body)
+	}
+
+	return fmt.Sprintf("For(%s in %s: %s if %s)", vars, n.List, n.Body, n.Cond)
+}
+
+func (n *For) Type(Scope) (Type, error) {
+	return TypeList, nil
+}
+
+// Vars returns the variables that are in scope within the body and
+// condition, along with the given element and its index.
+func (n *For) Vars(index int, value Variable) map[string]Variable {
+	result := map[string]Variable{n.ValueVar: value}
+	if n.IndexVar != "" {
+		result[n.IndexVar] = Variable{Value: index, Type: TypeInt}
+	}
+
+	return result
+}
@@ -0,0 +1,20 @@
+package ast
+
+import (
+	"testing"
+)
+
+func TestForType(t *testing.T) {
+	c := &For{
+		ValueVar: "v",
+		List:     &VariableAccess{Name: "var.list"},
+		Body:     &VariableAccess{Name: "v"},
+	}
+	actual, err := c.Type(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != TypeList {
+		t.Fatalf("bad: %s", actual)
+	}
+}
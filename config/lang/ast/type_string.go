@@ -9,6 +9,9 @@ const (
 	_Type_name_1 = "TypeString"
 	_Type_name_2 = "TypeInt"
 	_Type_name_3 = "TypeFloat"
+	_Type_name_4 = "TypeList"
+	_Type_name_5 = "TypeBool"
+	_Type_name_6 = "TypeNull"
 )
 
 var (
@@ -16,6 +19,9 @@ var (
 	_Type_index_1 = [...]uint8{0, 10}
 	_Type_index_2 = [...]uint8{0, 7}
 	_Type_index_3 = [...]uint8{0, 9}
+	_Type_index_4 = [...]uint8{0, 8}
+	_Type_index_5 = [...]uint8{0, 8}
+	_Type_index_6 = [...]uint8{0, 8}
 )
 
 func (i Type) String() string {
@@ -28,6 +34,12 @@ func (i Type) String() string {
 		return _Type_name_2
 	case i == 8:
 		return _Type_name_3
+	case i == 16:
+		return _Type_name_4
+	case i == 32:
+		return _Type_name_5
+	case i == 64:
+		return _Type_name_6
 	default:
 		return fmt.Sprintf("Type(%d)", i)
 	}
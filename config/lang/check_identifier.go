@@ -35,6 +35,10 @@ func (c *IdentifierCheck) visit(raw ast.Node) ast.Node {
 		c.visitCall(n)
 	case *ast.VariableAccess:
 		c.visitVariableAccess(n)
+	case *ast.Conditional:
+		c.visitConditional(n)
+	case *ast.For:
+		c.visitFor(n)
 	case *ast.Concat:
 		// Ignore
 	case *ast.LiteralNode:
@@ -79,6 +83,36 @@ func (c *IdentifierCheck) visitVariableAccess(n *ast.VariableAccess) {
 	}
 }
 
+func (c *IdentifierCheck) visitConditional(n *ast.Conditional) {
+	// The results aren't visited as part of the tree since only one of
+	// them is evaluated, but both must be valid.
+	for _, child := range []ast.Node{n.TrueExpr, n.FalseExpr} {
+		child.Accept(c.visit)
+	}
+}
+
+func (c *IdentifierCheck) visitFor(n *ast.For) {
+	if n.IndexVar == n.ValueVar {
+		c.createErr(n, fmt.Sprintf(
+			"for: index and value variables must differ, got %s", n.ValueVar))
+		return
+	}
+
+	// The body and condition aren't visited as part of the tree since
+	// they can access the iteration variables, so check them here.
+	scope := c.Scope
+	defer func() { c.Scope = scope }()
+	c.Scope = &forScope{
+		Scope: scope,
+		Vars:  n.Vars(0, ast.Variable{Type: ast.TypeString}),
+	}
+	for _, child := range []ast.Node{n.Body, n.Cond} {
+		if child != nil {
+			child.Accept(c.visit)
+		}
+	}
+}
+
 func (c *IdentifierCheck) createErr(n ast.Node, str string) {
 	c.err = fmt.Errorf("%s: %s", n.Pos(), str)
 }
@@ -64,6 +64,12 @@ func (v *TypeCheck) visit(raw ast.Node) ast.Node {
 	case *ast.Concat:
 		tc := &typeCheckConcat{n}
 		result, err = tc.TypeCheck(v)
+	case *ast.Conditional:
+		tc := &typeCheckConditional{n}
+		result, err = tc.TypeCheck(v)
+	case *ast.For:
+		tc := &typeCheckFor{n}
+		result, err = tc.TypeCheck(v)
 	case *ast.LiteralNode:
 		tc := &typeCheckLiteral{n}
 		result, err = tc.TypeCheck(v)
@@ -100,47 +106,180 @@ func (tc *typeCheckArithmetic) TypeCheck(v *TypeCheck) (ast.Node, error) {
 		exprs[len(tc.n.Exprs)-1-i] = v.StackPop()
 	}
 
-	// Determine the resulting type we want
+	switch tc.n.Op {
+	case ast.ArithmeticOpEqual, ast.ArithmeticOpNotEqual,
+		ast.ArithmeticOpLessThan, ast.ArithmeticOpLessThanOrEqual,
+		ast.ArithmeticOpGreaterThan, ast.ArithmeticOpGreaterThanOrEqual:
+		return tc.checkComparison(v, exprs)
+	case ast.ArithmeticOpLogicalAnd, ast.ArithmeticOpLogicalOr,
+		ast.ArithmeticOpLogicalNot:
+		return tc.checkLogical(v, exprs)
+	}
+
+	// Determine the resulting type we want. If any operand is a float
+	// then the math is done with floats, otherwise it is done with ints.
+	// Strings, such as variables, are converted to the resulting type.
 	mathFunc := "__builtin_IntMath"
 	mathType := ast.TypeInt
-	switch v := exprs[0]; v {
-	case ast.TypeInt:
-		mathFunc = "__builtin_IntMath"
-		mathType = v
-	case ast.TypeFloat:
-		mathFunc = "__builtin_FloatMath"
-		mathType = v
-	default:
-		return nil, fmt.Errorf(
-			"Math operations can only be done with ints and floats, got %s",
-			v)
+	for _, v := range exprs {
+		switch v {
+		case ast.TypeInt, ast.TypeString:
+		case ast.TypeFloat:
+			mathFunc = "__builtin_FloatMath"
+			mathType = v
+		default:
+			return nil, fmt.Errorf(
+				"Math operations can only be done with ints and floats, got %s",
+				v)
+		}
 	}
 
 	// Verify the args
+	if err := tc.convertExprs(v, exprs, mathType); err != nil {
+		return nil, err
+	}
+
+	// Modulo doesn't work for floats
+	if mathType == ast.TypeFloat && tc.n.Op == ast.ArithmeticOpMod {
+		return nil, fmt.Errorf("modulo cannot be used with floats")
+	}
+
+	// Return type
+	v.StackPush(mathType)
+
+	return tc.call(mathFunc), nil
+}
+
+// checkComparison type checks a comparison, which always results in a
+// bool. Numbers are compared as numbers. If any operand is a string, such
+// as a variable, they're all compared as strings: ordering compares them
+// as numbers if they both are numbers and lexically otherwise, so
+// "a" < "b" works. Mixed operands for equality are compared as strings,
+// so "a" == 1 is false rather than an error. Bools can't be ordered.
+func (tc *typeCheckArithmetic) checkComparison(
+	v *TypeCheck, exprs []ast.Type) (ast.Node, error) {
+	ordering := tc.n.Op != ast.ArithmeticOpEqual &&
+		tc.n.Op != ast.ArithmeticOpNotEqual
+
+	var seen ast.Type
+	for _, t := range exprs {
+		switch t {
+		case ast.TypeList:
+			return nil, fmt.Errorf("lists cannot be compared")
+		case ast.TypeNull:
+			return nil, fmt.Errorf("null cannot be compared")
+		case ast.TypeBool:
+			if ordering {
+				return nil, fmt.Errorf("bools cannot be ordered")
+			}
+		}
+
+		seen |= t
+	}
+
+	// Determine the type we compare as
+	var cmpType ast.Type
+	switch {
+	case ordering && seen&ast.TypeString != 0:
+		cmpType = ast.TypeString
+	case ordering && seen&ast.TypeFloat != 0:
+		cmpType = ast.TypeFloat
+	case ordering:
+		cmpType = ast.TypeInt
+	case len(exprs) > 0 && seen == exprs[0]:
+		cmpType = seen
+	case seen&^(ast.TypeInt|ast.TypeFloat) == 0:
+		cmpType = ast.TypeFloat
+	default:
+		cmpType = ast.TypeString
+	}
+
+	if err := tc.convertExprs(v, exprs, cmpType); err != nil {
+		return nil, err
+	}
+
+	var cmpFunc string
+	switch cmpType {
+	case ast.TypeBool:
+		cmpFunc = "__builtin_BoolCompare"
+	case ast.TypeFloat:
+		cmpFunc = "__builtin_FloatCompare"
+	case ast.TypeInt:
+		cmpFunc = "__builtin_IntCompare"
+	case ast.TypeString:
+		cmpFunc = "__builtin_StringCompare"
+	}
+
+	v.StackPush(ast.TypeBool)
+
+	return tc.call(cmpFunc), nil
+}
+
+// checkLogical type checks boolean logic. All operands must be bools.
+// "&&" and "||" are replaced with conditionals so that the right operand
+// is only evaluated if the left one doesn't decide the result.
+func (tc *typeCheckArithmetic) checkLogical(
+	v *TypeCheck, exprs []ast.Type) (ast.Node, error) {
+	if err := tc.convertExprs(v, exprs, ast.TypeBool); err != nil {
+		return nil, err
+	}
+
+	v.StackPush(ast.TypeBool)
+
+	if tc.n.Op == ast.ArithmeticOpLogicalNot {
+		return tc.call("__builtin_Logical"), nil
+	}
+
+	// "a && b" is "a ? b : false" and "a || b" is "a ? true : b"
+	result := tc.n.Exprs[0]
+	for _, expr := range tc.n.Exprs[1:] {
+		decided := &ast.LiteralNode{
+			Value: tc.n.Op == ast.ArithmeticOpLogicalOr,
+			Typex: ast.TypeBool,
+			Posx:  tc.n.Pos(),
+		}
+
+		cn := &ast.Conditional{
+			CondExpr:  result,
+			TrueExpr:  expr,
+			FalseExpr: decided,
+			Posx:      tc.n.Pos(),
+		}
+		if tc.n.Op == ast.ArithmeticOpLogicalOr {
+			cn.TrueExpr, cn.FalseExpr = decided, expr
+		}
+
+		result = cn
+	}
+
+	return result, nil
+}
+
+// convertExprs verifies that all operands are of the given type,
+// inserting implicit conversions where possible.
+func (tc *typeCheckArithmetic) convertExprs(
+	v *TypeCheck, exprs []ast.Type, t ast.Type) error {
 	for i, arg := range exprs {
-		if arg != mathType {
-			cn := v.ImplicitConversion(exprs[i], mathType, tc.n.Exprs[i])
+		if arg != t {
+			cn := v.ImplicitConversion(arg, t, tc.n.Exprs[i])
 			if cn != nil {
 				tc.n.Exprs[i] = cn
 				continue
 			}
 
-			return nil, fmt.Errorf(
+			return fmt.Errorf(
 				"operand %d should be %s, got %s",
-				i+1, mathType, arg)
+				i+1, t, arg)
 		}
 	}
 
-	// Modulo doesn't work for floats
-	if mathType == ast.TypeFloat && tc.n.Op == ast.ArithmeticOpMod {
-		return nil, fmt.Errorf("modulo cannot be used with floats")
-	}
-
-	// Return type
-	v.StackPush(mathType)
+	return nil
+}
 
-	// Replace our node with a call to the proper function. This isn't
-	// type checked but we already verified types.
+// call replaces our node with a call to the given builtin function
+// with the operation as the first argument. This isn't type checked but
+// we already verified types.
+func (tc *typeCheckArithmetic) call(f string) ast.Node {
 	args := make([]ast.Node, len(tc.n.Exprs)+1)
 	args[0] = &ast.LiteralNode{
 		Value: tc.n.Op,
@@ -149,10 +288,10 @@ func (tc *typeCheckArithmetic) TypeCheck(v *TypeCheck) (ast.Node, error) {
 	}
 	copy(args[1:], tc.n.Exprs)
 	return &ast.Call{
-		Func: mathFunc,
+		Func: f,
 		Args: args,
 		Posx: tc.n.Pos(),
-	}, nil
+	}
 }
 
 type typeCheckCall struct {
@@ -225,8 +364,19 @@ func (tc *typeCheckConcat) TypeCheck(v *TypeCheck) (ast.Node, error) {
 		types[len(n.Exprs)-1-i] = v.StackPop()
 	}
 
+	// A single list or null expression stays as it is so that an
+	// interpolation can result in a list or null.
+	if len(types) == 1 && (types[0] == ast.TypeList || types[0] == ast.TypeNull) {
+		v.StackPush(types[0])
+		return n, nil
+	}
+
 	// All concat args must be strings, so validate that
 	for i, t := range types {
+		if t == ast.TypeNull {
+			return nil, fmt.Errorf("null can't be used within a string")
+		}
+
 		if t != ast.TypeString {
 			cn := v.ImplicitConversion(t, ast.TypeString, n.Exprs[i])
 			if cn != nil {
@@ -245,6 +395,137 @@ func (tc *typeCheckConcat) TypeCheck(v *TypeCheck) (ast.Node, error) {
 	return n, nil
 }
 
+type typeCheckConditional struct {
+	n *ast.Conditional
+}
+
+func (tc *typeCheckConditional) TypeCheck(v *TypeCheck) (ast.Node, error) {
+	n := tc.n
+
+	// The condition is the only child on the stack
+	condType := v.StackPop()
+
+	// The condition must be a bool
+	if condType != ast.TypeBool {
+		cn := v.ImplicitConversion(condType, ast.TypeBool, n.CondExpr)
+		if cn == nil {
+			return nil, fmt.Errorf(
+				"condition must be a bool, got %s", condType)
+		}
+
+		n.CondExpr = cn
+	}
+
+	// The results aren't visited as part of the tree since only one of
+	// them is evaluated, so we check them here. Errors are recorded on v
+	// directly.
+	n.TrueExpr = n.TrueExpr.Accept(v.visit)
+	if v.err != nil {
+		return n, nil
+	}
+	trueType := v.StackPop()
+
+	n.FalseExpr = n.FalseExpr.Accept(v.visit)
+	if v.err != nil {
+		return n, nil
+	}
+	falseType := v.StackPop()
+
+	// Both results must have the same type. If they don't, we try
+	// to convert them both to a string. Either result may be null,
+	// which has the type of the other.
+	resultType := trueType
+	if trueType == ast.TypeNull {
+		resultType = falseType
+	} else if falseType != ast.TypeNull && trueType != falseType {
+		resultType = ast.TypeString
+		exprs := []*ast.Node{&n.TrueExpr, &n.FalseExpr}
+		for i, t := range []ast.Type{trueType, falseType} {
+			if t == ast.TypeString {
+				continue
+			}
+
+			cn := v.ImplicitConversion(t, ast.TypeString, *exprs[i])
+			if cn == nil {
+				return nil, fmt.Errorf(
+					"true and false results must have the same type, "+
+						"got %s and %s", trueType, falseType)
+			}
+
+			*exprs[i] = cn
+		}
+	}
+
+	v.StackPush(resultType)
+
+	return n, nil
+}
+
+type typeCheckFor struct {
+	n *ast.For
+}
+
+func (tc *typeCheckFor) TypeCheck(v *TypeCheck) (ast.Node, error) {
+	n := tc.n
+
+	// The list is the only child on the stack
+	listType := v.StackPop()
+	if listType != ast.TypeList {
+		cn := v.ImplicitConversion(listType, ast.TypeList, n.List)
+		if cn == nil {
+			return nil, fmt.Errorf(
+				"for: expected a list, got %s", listType)
+		}
+
+		n.List = cn
+	}
+
+	// The body and condition aren't visited as part of the tree since
+	// they can access the iteration variables, so we check them here
+	// within a scope that has those. Errors are recorded on v directly.
+	scope := v.Scope
+	defer func() { v.Scope = scope }()
+	v.Scope = &forScope{
+		Scope: scope,
+		Vars:  n.Vars(0, ast.Variable{Type: ast.TypeString}),
+	}
+
+	// The elements of the resulting list are always strings
+	n.Body = n.Body.Accept(v.visit)
+	if v.err != nil {
+		return n, nil
+	}
+	if t := v.StackPop(); t != ast.TypeString {
+		cn := v.ImplicitConversion(t, ast.TypeString, n.Body)
+		if cn == nil {
+			return nil, fmt.Errorf(
+				"for: result must be a string, got %s", t)
+		}
+
+		n.Body = cn
+	}
+
+	if n.Cond != nil {
+		n.Cond = n.Cond.Accept(v.visit)
+		if v.err != nil {
+			return n, nil
+		}
+		if t := v.StackPop(); t != ast.TypeBool {
+			cn := v.ImplicitConversion(t, ast.TypeBool, n.Cond)
+			if cn == nil {
+				return nil, fmt.Errorf(
+					"for: condition must be a bool, got %s", t)
+			}
+
+			n.Cond = cn
+		}
+	}
+
+	v.StackPush(ast.TypeList)
+
+	return n, nil
+}
+
 type typeCheckLiteral struct {
 	n *ast.LiteralNode
 }
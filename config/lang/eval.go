@@ -32,6 +32,9 @@ func Eval(root ast.Node, config *EvalConfig) (interface{}, ast.Type, error) {
 	}
 	scope := registerBuiltins(config.GlobalScope)
 	implicitMap := map[ast.Type]map[ast.Type]string{
+		ast.TypeBool: {
+			ast.TypeString: "__builtin_BoolToString",
+		},
 		ast.TypeFloat: {
 			ast.TypeInt:    "__builtin_FloatToInt",
 			ast.TypeString: "__builtin_FloatToString",
@@ -40,8 +43,14 @@ func Eval(root ast.Node, config *EvalConfig) (interface{}, ast.Type, error) {
 			ast.TypeFloat:  "__builtin_IntToFloat",
 			ast.TypeString: "__builtin_IntToString",
 		},
+		ast.TypeList: {
+			ast.TypeString: "__builtin_ListToString",
+		},
 		ast.TypeString: {
-			ast.TypeInt: "__builtin_StringToInt",
+			ast.TypeBool:  "__builtin_StringToBool",
+			ast.TypeInt:   "__builtin_StringToInt",
+			ast.TypeFloat: "__builtin_StringToFloat",
+			ast.TypeList:  "__builtin_StringToList",
 		},
 	}
 
@@ -104,6 +113,10 @@ func (v *evalVisitor) Visit(root ast.Node) (interface{}, ast.Type, error) {
 	v.Stack.Reset()
 	v.err = nil
 
+	if resultErr != nil {
+		return nil, ast.TypeInvalid, resultErr
+	}
+
 	t, err := result.Type(v.Scope)
 	if err != nil {
 		return nil, ast.TypeInvalid, err
@@ -144,6 +157,10 @@ func evalNode(raw ast.Node) (EvalNode, error) {
 		return &evalCall{n}, nil
 	case *ast.Concat:
 		return &evalConcat{n}, nil
+	case *ast.Conditional:
+		return &evalConditional{n}, nil
+	case *ast.For:
+		return &evalFor{n}, nil
 	case *ast.LiteralNode:
 		return &evalLiteralNode{n}, nil
 	case *ast.VariableAccess:
@@ -169,9 +186,16 @@ func (v *evalCall) Eval(s ast.Scope, stack *ast.Stack) (interface{}, ast.Type, e
 	}
 
 	// The arguments are on the stack in reverse order, so pop them off.
+	// The type checker doesn't allow null arguments, but a conditional
+	// can still result in null.
 	args := make([]interface{}, len(v.Args))
 	for i, _ := range v.Args {
 		node := stack.Pop().(*ast.LiteralNode)
+		if node.Typex == ast.TypeNull {
+			return nil, ast.TypeInvalid, fmt.Errorf(
+				"%s: argument %d is null", v.Func, len(v.Args)-i)
+		}
+
 		args[len(v.Args)-1-i] = node.Value
 	}
 
@@ -194,14 +218,97 @@ func (v *evalConcat) Eval(s ast.Scope, stack *ast.Stack) (interface{}, ast.Type,
 		nodes = append(nodes, stack.Pop().(*ast.LiteralNode))
 	}
 
+	// A single list or null expression is passed through as-is so that
+	// an interpolation can result in a list or null.
+	if len(nodes) == 1 &&
+		(nodes[0].Typex == ast.TypeList || nodes[0].Typex == ast.TypeNull) {
+		return nodes[0].Value, nodes[0].Typex, nil
+	}
+
 	var buf bytes.Buffer
 	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i].Typex == ast.TypeNull {
+			return nil, ast.TypeInvalid, fmt.Errorf(
+				"null can't be used within a string")
+		}
+
 		buf.WriteString(nodes[i].Value.(string))
 	}
 
 	return buf.String(), ast.TypeString, nil
 }
 
+type evalConditional struct{ *ast.Conditional }
+
+func (v *evalConditional) Eval(s ast.Scope, stack *ast.Stack) (interface{}, ast.Type, error) {
+	// Only the condition is on the stack. Only the chosen result is
+	// evaluated, so the other may be something that would fail, such
+	// as reading a file that doesn't exist.
+	cond := stack.Pop().(*ast.LiteralNode)
+
+	result := v.FalseExpr
+	if cond.Value.(bool) {
+		result = v.TrueExpr
+	}
+
+	ev := &evalVisitor{Scope: s}
+	return ev.Visit(result)
+}
+
+type evalFor struct{ *ast.For }
+
+func (v *evalFor) Eval(s ast.Scope, stack *ast.Stack) (interface{}, ast.Type, error) {
+	// Only the list is on the stack. The body and condition are
+	// evaluated for every element with the iteration variables in scope.
+	list := stack.Pop().(*ast.LiteralNode).Value.([]ast.Variable)
+	result := make([]ast.Variable, 0, len(list))
+	for i, elem := range list {
+		ev := &evalVisitor{Scope: &forScope{
+			Scope: s,
+			Vars:  v.Vars(i, elem),
+		}}
+
+		if v.Cond != nil {
+			out, _, err := ev.Visit(v.Cond)
+			if err != nil {
+				return nil, ast.TypeInvalid, err
+			}
+			if !out.(bool) {
+				continue
+			}
+		}
+
+		out, t, err := ev.Visit(v.Body)
+		if err != nil {
+			return nil, ast.TypeInvalid, err
+		}
+		if t == ast.TypeNull {
+			return nil, ast.TypeInvalid, fmt.Errorf(
+				"for: list elements can't be null")
+		}
+
+		result = append(result, ast.Variable{Value: out, Type: t})
+	}
+
+	return result, ast.TypeList, nil
+}
+
+// forScope is the scope of the body and condition of a for expression.
+// It has the iteration variables in addition to everything in the
+// enclosing scope.
+type forScope struct {
+	ast.Scope
+	Vars map[string]ast.Variable
+}
+
+func (s *forScope) LookupVar(n string) (ast.Variable, bool) {
+	if v, ok := s.Vars[n]; ok {
+		return v, true
+	}
+
+	return s.Scope.LookupVar(n)
+}
+
 type evalLiteralNode struct{ *ast.LiteralNode }
 
 func (v *evalLiteralNode) Eval(ast.Scope, *ast.Stack) (interface{}, ast.Type, error) {
@@ -3,6 +3,7 @@ package lang
 import (
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config/lang/ast"
@@ -107,10 +108,81 @@ func TestEval(t *testing.T) {
 			"foo ${42+2*2}",
 			nil,
 			false,
+			"foo 46",
+			ast.TypeString,
+		},
+
+		{
+			"foo ${(42+2)*2}",
+			nil,
+			false,
 			"foo 88",
 			ast.TypeString,
 		},
 
+		{
+			"foo ${10-4/2-1}",
+			nil,
+			false,
+			"foo 7",
+			ast.TypeString,
+		},
+
+		{
+			"foo ${-2*3}",
+			nil,
+			false,
+			"foo -6",
+			ast.TypeString,
+		},
+
+		{
+			"foo ${1+0.5}",
+			nil,
+			false,
+			"foo 1.5",
+			ast.TypeString,
+		},
+
+		{
+			"foo ${5%0}",
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			"foo ${1/0}",
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			"foo ${1.0/0}",
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			"foo ${bar*2}",
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "21",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"foo 42",
+			ast.TypeString,
+		},
+
 		{
 			"foo ${42+(2*2)}",
 			nil,
@@ -208,6 +280,607 @@ func TestEval(t *testing.T) {
 			"foo 42",
 			ast.TypeString,
 		},
+
+		{
+			`foo ${foo("42.5")}`,
+			&ast.BasicScope{
+				FuncMap: map[string]ast.Function{
+					"foo": ast.Function{
+						ArgTypes:   []ast.Type{ast.TypeFloat},
+						ReturnType: ast.TypeString,
+						Callback: func(args []interface{}) (interface{}, error) {
+							return strconv.FormatFloat(args[0].(float64), 'f', 2, 64), nil
+						},
+					},
+				},
+			},
+			false,
+			"foo 42.50",
+			ast.TypeString,
+		},
+
+		{
+			`${1 < 2}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${2 <= 1}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${1.5 > 1}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${2 >= 2}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${1 + 1 == 2}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${"a" != "b"}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${true == false}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${!true}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${true && !false}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${false || 1 > 2}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${true || false && false}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		// The right operand is only evaluated if needed
+		{
+			`${false && 1/0 == 1}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${true || 1/0 == 1}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${true && 1/0 == 1}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		// Mixed types are compared as strings for equality
+		{
+			`${"a" == 1}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${"a" != 1}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${true == 1}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${1 == 1.0}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		// Strings are ordered as numbers if they both are numbers and
+		// lexically otherwise
+		{
+			`${"a" < "b"}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${bar > "x"}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "y",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${bar >= 9}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "10",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${"a" < 1}`,
+			nil,
+			false,
+			"false",
+			ast.TypeString,
+		},
+
+		{
+			`${1.5 <= 2}`,
+			nil,
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${bar == 2 ? "two" : "other"}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "2",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"two",
+			ast.TypeString,
+		},
+
+		{
+			`${bar > 1 && bar < 3}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "2",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"true",
+			ast.TypeString,
+		},
+
+		{
+			`${true < false}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`${true ? "foo" : "bar"}`,
+			nil,
+			false,
+			"foo",
+			ast.TypeString,
+		},
+
+		{
+			`${false ? "foo" : "bar"}`,
+			nil,
+			false,
+			"bar",
+			ast.TypeString,
+		},
+
+		{
+			`${bar ? 1 + 1 : 0}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "true",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			false,
+			"2",
+			ast.TypeString,
+		},
+
+		{
+			`${true ? 42 : "foo"}`,
+			nil,
+			false,
+			"42",
+			ast.TypeString,
+		},
+
+		// Only the chosen result is evaluated
+		{
+			`${true ? 1 : 1/0}`,
+			nil,
+			false,
+			"1",
+			ast.TypeString,
+		},
+
+		{
+			`${false ? 1/0 : 2}`,
+			nil,
+			false,
+			"2",
+			ast.TypeString,
+		},
+
+		{
+			`${true ? 1 : 1/0}${false ? 1/0 : 2}`,
+			nil,
+			false,
+			"12",
+			ast.TypeString,
+		},
+
+		{
+			`${false ? 1 : 1/0}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		// Both results must still be valid
+		{
+			`${true ? 1 : baz}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`${bar ? "foo" : "bar"}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: "yes",
+						Type:  ast.TypeString,
+					},
+				},
+			},
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			"${bar}",
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			[]ast.Variable{
+				ast.Variable{Value: "foo", Type: ast.TypeString},
+				ast.Variable{Value: "bar", Type: ast.TypeString},
+			},
+			ast.TypeList,
+		},
+
+		{
+			"foo ${bar}",
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			"foo foo" + ListDelim + "bar",
+			ast.TypeString,
+		},
+
+		{
+			`${foo("a` + ListDelim + `b")}`,
+			&ast.BasicScope{
+				FuncMap: map[string]ast.Function{
+					"foo": ast.Function{
+						ArgTypes:   []ast.Type{ast.TypeList},
+						ReturnType: ast.TypeInt,
+						Callback: func(args []interface{}) (interface{}, error) {
+							return len(args[0].([]ast.Variable)), nil
+						},
+					},
+				},
+			},
+			false,
+			"2",
+			ast.TypeString,
+		},
+
+		{
+			`${[for v in bar : "${v}-1"]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			[]ast.Variable{
+				ast.Variable{Value: "foo-1", Type: ast.TypeString},
+				ast.Variable{Value: "bar-1", Type: ast.TypeString},
+				ast.Variable{Value: "baz-1", Type: ast.TypeString},
+			},
+			ast.TypeList,
+		},
+
+		{
+			`${[for i, v in bar : i if v != "bar"]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			[]ast.Variable{
+				ast.Variable{Value: "0", Type: ast.TypeString},
+				ast.Variable{Value: "2", Type: ast.TypeString},
+			},
+			ast.TypeList,
+		},
+
+		{
+			`${[for v in "a` + ListDelim + `b" : [for w in bar : "${v}${w}" if w == "foo"]]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			[]ast.Variable{
+				ast.Variable{Value: "afoo", Type: ast.TypeString},
+				ast.Variable{Value: "bfoo", Type: ast.TypeString},
+			},
+			ast.TypeList,
+		},
+
+		{
+			`${[for v in bar : v if false]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			false,
+			[]ast.Variable{},
+			ast.TypeList,
+		},
+
+		{
+			`${[for v in bar : w]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`${[for v in bar : v if v]}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`${[for v in bar : v]}-${v}`,
+			&ast.BasicScope{
+				VarMap: map[string]ast.Variable{
+					"bar": ast.Variable{
+						Value: []ast.Variable{
+							ast.Variable{Value: "foo", Type: ast.TypeString},
+							ast.Variable{Value: "bar", Type: ast.TypeString},
+							ast.Variable{Value: "baz", Type: ast.TypeString},
+						},
+						Type: ast.TypeList,
+					},
+				},
+			},
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+		{
+			"${null}",
+			nil,
+			false,
+			nil,
+			ast.TypeNull,
+		},
+
+		{
+			`${true ? null : "foo"}`,
+			nil,
+			false,
+			nil,
+			ast.TypeNull,
+		},
+
+		{
+			`${false ? null : "foo"}`,
+			nil,
+			false,
+			"foo",
+			ast.TypeString,
+		},
+
+		{
+			"foo ${null}",
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`foo ${true ? null : "bar"}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
+
+		{
+			`${null == "foo"}`,
+			nil,
+			true,
+			nil,
+			ast.TypeInvalid,
+		},
 	}
 
 	for _, tc := range cases {
@@ -228,3 +901,22 @@ func TestEval(t *testing.T) {
 		}
 	}
 }
+
+func TestEval_divideByZero(t *testing.T) {
+	// Ints and floats fail the same way, rather than floats resulting
+	// in "+Inf".
+	for _, input := range []string{"${1/0}", "${1.0/0}", "${1.5/0.0}"} {
+		node, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Error: %s\n\nInput: %s", err, input)
+		}
+
+		_, _, err = Eval(node, nil)
+		if err == nil {
+			t.Fatalf("should error\n\nInput: %s", input)
+		}
+		if !strings.Contains(err.Error(), "divide by zero") {
+			t.Fatalf("Bad: %s\n\nInput: %s", err, input)
+		}
+	}
+}
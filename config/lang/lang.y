@@ -15,19 +15,31 @@ import (
     node     ast.Node
     nodeList []ast.Node
     str      string
+    strList  []string
     token    *parserToken
 }
 
 %token  <str> PROGRAM_BRACKET_LEFT PROGRAM_BRACKET_RIGHT
 %token  <str> PROGRAM_STRING_START PROGRAM_STRING_END
-%token  <str> PAREN_LEFT PAREN_RIGHT COMMA
+%token  <str> PAREN_LEFT PAREN_RIGHT COMMA QUESTION COLON
+%token  <str> SQUARE_BRACKET_RIGHT FOR IN IF
 
-%token <token> ARITH_OP IDENTIFIER INTEGER FLOAT STRING
+%token <token> ARITH_OP ARITH_OP_MUL IDENTIFIER INTEGER FLOAT STRING BOOL
+%token <token> EQUALITY_OP COMPARISON_OP AND_OP OR_OP BANG
+%token <token> SQUARE_BRACKET_LEFT NULL
 
 %type <node> expr interpolation literal literalModeTop literalModeValue
 %type <nodeList> args
+%type <strList> forVars
 
+%right QUESTION COLON
+%left OR_OP
+%left AND_OP
+%left EQUALITY_OP
+%left COMPARISON_OP
 %left ARITH_OP
+%left ARITH_OP_MUL
+%right UNARY
 
 %%
 
@@ -122,6 +134,31 @@ expr:
             Posx:  $1.Pos,
         }
     }
+|   BOOL
+    {
+        $$ = &ast.LiteralNode{
+            Value: $1.Value.(bool),
+            Typex:  ast.TypeBool,
+            Posx:  $1.Pos,
+        }
+    }
+|   NULL
+    {
+        $$ = &ast.LiteralNode{
+            Value: nil,
+            Typex:  ast.TypeNull,
+            Posx:  $1.Pos,
+        }
+    }
+|   expr QUESTION expr COLON expr
+    {
+        $$ = &ast.Conditional{
+            CondExpr:  $1,
+            TrueExpr:  $3,
+            FalseExpr: $5,
+            Posx:      $1.Pos(),
+        }
+    }
 |   expr ARITH_OP expr
     {
         $$ = &ast.Arithmetic{
@@ -130,6 +167,73 @@ expr:
             Posx:  $1.Pos(),
         }
     }
+|   expr ARITH_OP_MUL expr
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $2.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$1, $3},
+            Posx:  $1.Pos(),
+        }
+    }
+|   expr EQUALITY_OP expr
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $2.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$1, $3},
+            Posx:  $1.Pos(),
+        }
+    }
+|   expr COMPARISON_OP expr
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $2.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$1, $3},
+            Posx:  $1.Pos(),
+        }
+    }
+|   expr AND_OP expr
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $2.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$1, $3},
+            Posx:  $1.Pos(),
+        }
+    }
+|   expr OR_OP expr
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $2.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$1, $3},
+            Posx:  $1.Pos(),
+        }
+    }
+|   BANG expr %prec UNARY
+    {
+        $$ = &ast.Arithmetic{
+            Op:    $1.Value.(ast.ArithmeticOp),
+            Exprs: []ast.Node{$2},
+            Posx:  $1.Pos,
+        }
+    }
+|   ARITH_OP expr %prec UNARY
+    {
+        // Unary plus is a no-op and unary minus is subtraction from zero
+        $$ = $2
+        if $1.Value.(ast.ArithmeticOp) == ast.ArithmeticOpSub {
+            $$ = &ast.Arithmetic{
+                Op: ast.ArithmeticOpSub,
+                Exprs: []ast.Node{
+                    &ast.LiteralNode{
+                        Value: 0,
+                        Typex: ast.TypeInt,
+                        Posx:  $1.Pos,
+                    },
+                    $2,
+                },
+                Posx: $1.Pos,
+            }
+        }
+    }
 |   IDENTIFIER
     {
         $$ = &ast.VariableAccess{Name: $1.Value.(string), Posx: $1.Pos}
@@ -138,6 +242,37 @@ expr:
     {
         $$ = &ast.Call{Func: $1.Value.(string), Args: $3, Posx: $1.Pos}
     }
+|   SQUARE_BRACKET_LEFT FOR forVars IN expr COLON expr SQUARE_BRACKET_RIGHT
+    {
+        $$ = &ast.For{
+            IndexVar: $3[0],
+            ValueVar: $3[1],
+            List:     $5,
+            Body:     $7,
+            Posx:     $1.Pos,
+        }
+    }
+|   SQUARE_BRACKET_LEFT FOR forVars IN expr COLON expr IF expr SQUARE_BRACKET_RIGHT
+    {
+        $$ = &ast.For{
+            IndexVar: $3[0],
+            ValueVar: $3[1],
+            List:     $5,
+            Body:     $7,
+            Cond:     $9,
+            Posx:     $1.Pos,
+        }
+    }
+
+forVars:
+    IDENTIFIER
+    {
+        $$ = []string{"", $1.Value.(string)}
+    }
+|   IDENTIFIER COMMA IDENTIFIER
+    {
+        $$ = []string{$1.Value.(string), $3.Value.(string)}
+    }
 
 args:
 	{
@@ -168,6 +168,64 @@ func (x *parserLex) lexModeInterpolation(yylval *parserSymType) int {
 			return PAREN_RIGHT
 		case ',':
 			return COMMA
+		case '?':
+			return QUESTION
+		case '[':
+			yylval.token = &parserToken{}
+			return SQUARE_BRACKET_LEFT
+		case ']':
+			return SQUARE_BRACKET_RIGHT
+		case ':':
+			return COLON
+		case '=':
+			if x.next() != '=' {
+				x.Error("expected '==', got '='")
+				return lexEOF
+			}
+
+			yylval.token = &parserToken{Value: ast.ArithmeticOpEqual}
+			return EQUALITY_OP
+		case '!':
+			if x.peek() == '=' {
+				x.next()
+				yylval.token = &parserToken{Value: ast.ArithmeticOpNotEqual}
+				return EQUALITY_OP
+			}
+
+			yylval.token = &parserToken{Value: ast.ArithmeticOpLogicalNot}
+			return BANG
+		case '<':
+			yylval.token = &parserToken{Value: ast.ArithmeticOpLessThan}
+			if x.peek() == '=' {
+				x.next()
+				yylval.token.Value = ast.ArithmeticOpLessThanOrEqual
+			}
+
+			return COMPARISON_OP
+		case '>':
+			yylval.token = &parserToken{Value: ast.ArithmeticOpGreaterThan}
+			if x.peek() == '=' {
+				x.next()
+				yylval.token.Value = ast.ArithmeticOpGreaterThanOrEqual
+			}
+
+			return COMPARISON_OP
+		case '&':
+			if x.next() != '&' {
+				x.Error("expected '&&', got '&'")
+				return lexEOF
+			}
+
+			yylval.token = &parserToken{Value: ast.ArithmeticOpLogicalAnd}
+			return AND_OP
+		case '|':
+			if x.next() != '|' {
+				x.Error("expected '||', got '|'")
+				return lexEOF
+			}
+
+			yylval.token = &parserToken{Value: ast.ArithmeticOpLogicalOr}
+			return OR_OP
 		case '+':
 			yylval.token = &parserToken{Value: ast.ArithmeticOpAdd}
 			return ARITH_OP
@@ -176,13 +234,13 @@ func (x *parserLex) lexModeInterpolation(yylval *parserSymType) int {
 			return ARITH_OP
 		case '*':
 			yylval.token = &parserToken{Value: ast.ArithmeticOpMul}
-			return ARITH_OP
+			return ARITH_OP_MUL
 		case '/':
 			yylval.token = &parserToken{Value: ast.ArithmeticOpDiv}
-			return ARITH_OP
+			return ARITH_OP_MUL
 		case '%':
 			yylval.token = &parserToken{Value: ast.ArithmeticOpMod}
-			return ARITH_OP
+			return ARITH_OP_MUL
 		default:
 			x.backup()
 			return x.lexId(yylval)
@@ -192,12 +250,21 @@ func (x *parserLex) lexModeInterpolation(yylval *parserSymType) int {
 
 func (x *parserLex) lexId(yylval *parserSymType) int {
 	var b bytes.Buffer
+	var last rune
 	for {
 		c := x.next()
 		if c == lexEOF {
 			break
 		}
 
+		// A '*' is only part of an ID as a splat, such as "foo.*.bar".
+		// Otherwise it is a multiplication: "var.foo*2".
+		if c == '*' && last != '.' {
+			x.backup()
+			break
+		}
+		last = c
+
 		// If this isn't a character we want in an ID, return out.
 		// One day we should make this a regexp.
 		if c != '_' &&
@@ -216,6 +283,23 @@ func (x *parserLex) lexId(yylval *parserSymType) int {
 		}
 	}
 
+	// The boolean and null literals and the keywords of for expressions
+	// look like identifiers
+	switch v := b.String(); v {
+	case "true", "false":
+		yylval.token = &parserToken{Value: v == "true"}
+		return BOOL
+	case "null":
+		yylval.token = &parserToken{}
+		return NULL
+	case "for":
+		return FOR
+	case "in":
+		return IN
+	case "if":
+		return IF
+	}
+
 	yylval.token = &parserToken{Value: b.String()}
 	return IDENTIFIER
 }
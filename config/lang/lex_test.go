@@ -72,6 +72,22 @@ func TestLex(t *testing.T) {
 				PROGRAM_BRACKET_RIGHT, lexEOF},
 		},
 
+		{
+			"${bar(42*1)}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				IDENTIFIER, PAREN_LEFT,
+				INTEGER, ARITH_OP_MUL, INTEGER,
+				PAREN_RIGHT,
+				PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${var.foo*2}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				IDENTIFIER, ARITH_OP_MUL, INTEGER,
+				PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
 		{
 			"${bar(3.14159)}",
 			[]int{PROGRAM_BRACKET_LEFT,
@@ -106,6 +122,48 @@ func TestLex(t *testing.T) {
 				PROGRAM_BRACKET_RIGHT, lexEOF},
 		},
 
+		{
+			"${var.foo ? 1 : 2}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				IDENTIFIER, QUESTION, INTEGER, COLON, INTEGER,
+				PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${a == b != c < d <= e > f >= g}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				IDENTIFIER, EQUALITY_OP, IDENTIFIER, EQUALITY_OP,
+				IDENTIFIER, COMPARISON_OP, IDENTIFIER, COMPARISON_OP,
+				IDENTIFIER, COMPARISON_OP, IDENTIFIER, COMPARISON_OP,
+				IDENTIFIER, PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${!a && b || c}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				BANG, IDENTIFIER, AND_OP, IDENTIFIER, OR_OP, IDENTIFIER,
+				PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${true}",
+			[]int{PROGRAM_BRACKET_LEFT, BOOL, PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${null}",
+			[]int{PROGRAM_BRACKET_LEFT, NULL, PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
+		{
+			"${[for i, v in var.foo : v if i > 0]}",
+			[]int{PROGRAM_BRACKET_LEFT,
+				SQUARE_BRACKET_LEFT, FOR, IDENTIFIER, COMMA, IDENTIFIER,
+				IN, IDENTIFIER, COLON, IDENTIFIER, IF, IDENTIFIER,
+				COMPARISON_OP, INTEGER, SQUARE_BRACKET_RIGHT,
+				PROGRAM_BRACKET_RIGHT, lexEOF},
+		},
+
 		{
 			`foo ${"${var.foo}"}`,
 			[]int{STRING, PROGRAM_BRACKET_LEFT,
@@ -183,6 +183,120 @@ func TestParse(t *testing.T) {
 			},
 		},
 
+		{
+			"${true ? 1 : 2+3}",
+			false,
+			&ast.Concat{
+				Posx: ast.Pos{Column: 3, Line: 1},
+				Exprs: []ast.Node{
+					&ast.Conditional{
+						CondExpr: &ast.LiteralNode{
+							Value: true,
+							Typex: ast.TypeBool,
+							Posx:  ast.Pos{Column: 3, Line: 1},
+						},
+						TrueExpr: &ast.LiteralNode{
+							Value: 1,
+							Typex: ast.TypeInt,
+							Posx:  ast.Pos{Column: 9, Line: 1},
+						},
+						FalseExpr: &ast.Arithmetic{
+							Op: ast.ArithmeticOpAdd,
+							Exprs: []ast.Node{
+								&ast.LiteralNode{
+									Value: 2,
+									Typex: ast.TypeInt,
+									Posx:  ast.Pos{Column: 13, Line: 1},
+								},
+								&ast.LiteralNode{
+									Value: 3,
+									Typex: ast.TypeInt,
+									Posx:  ast.Pos{Column: 16, Line: 1},
+								},
+							},
+							Posx: ast.Pos{Column: 13, Line: 1},
+						},
+						Posx: ast.Pos{Column: 3, Line: 1},
+					},
+				},
+			},
+		},
+
+		{
+			"${[for v in var.foo : v if v != \"\"]}",
+			false,
+			&ast.Concat{
+				Posx: ast.Pos{Column: 3, Line: 1},
+				Exprs: []ast.Node{
+					&ast.For{
+						ValueVar: "v",
+						List: &ast.VariableAccess{
+							Name: "var.foo",
+							Posx: ast.Pos{Column: 12, Line: 1},
+						},
+						Body: &ast.VariableAccess{
+							Name: "v",
+							Posx: ast.Pos{Column: 22, Line: 1},
+						},
+						Cond: &ast.Arithmetic{
+							Op: ast.ArithmeticOpNotEqual,
+							Exprs: []ast.Node{
+								&ast.VariableAccess{
+									Name: "v",
+									Posx: ast.Pos{Column: 27, Line: 1},
+								},
+								&ast.LiteralNode{
+									Value: "",
+									Typex: ast.TypeString,
+									Posx:  ast.Pos{Column: 32, Line: 1},
+								},
+							},
+							Posx: ast.Pos{Column: 27, Line: 1},
+						},
+						Posx: ast.Pos{Column: 3, Line: 1},
+					},
+				},
+			},
+		},
+
+		{
+			"${[for i, v in var.foo : i]}",
+			false,
+			&ast.Concat{
+				Posx: ast.Pos{Column: 3, Line: 1},
+				Exprs: []ast.Node{
+					&ast.For{
+						IndexVar: "i",
+						ValueVar: "v",
+						List: &ast.VariableAccess{
+							Name: "var.foo",
+							Posx: ast.Pos{Column: 15, Line: 1},
+						},
+						Body: &ast.VariableAccess{
+							Name: "i",
+							Posx: ast.Pos{Column: 25, Line: 1},
+						},
+						Posx: ast.Pos{Column: 3, Line: 1},
+					},
+				},
+			},
+		},
+
+		{
+			"${null}",
+			false,
+			&ast.Concat{
+				Posx: ast.Pos{Column: 3, Line: 1},
+				Exprs: []ast.Node{
+					&ast.LiteralNode{
+						Value: nil,
+						Typex: ast.TypeNull,
+						Posx:  ast.Pos{Column: 3, Line: 1},
+					},
+				},
+			},
+		},
+
 		{
 			"${foo()}",
 			false,
@@ -314,6 +428,30 @@ func TestParse(t *testing.T) {
 			true,
 			nil,
 		},
+
+		{
+			"${true ? 1}",
+			true,
+			nil,
+		},
+
+		{
+			"${1 = 1}",
+			true,
+			nil,
+		},
+
+		{
+			"${true & false}",
+			true,
+			nil,
+		},
+
+		{
+			"${[for v in var.foo]}",
+			true,
+			nil,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1,9 +1,12 @@
+// Code generated by goyacc -p parser -o y.go lang.y. DO NOT EDIT.
+
 //line lang.y:6
 package lang
 
 import __yyfmt__ "fmt"
 
 //line lang.y:6
+
 import (
 	"github.com/hashicorp/terraform/config/lang/ast"
 )
@@ -14,6 +17,7 @@ type parserSymType struct {
 	node     ast.Node
 	nodeList []ast.Node
 	str      string
+	strList  []string
 	token    *parserToken
 }
 
@@ -24,13 +28,32 @@ const PROGRAM_STRING_END = 57349
 const PAREN_LEFT = 57350
 const PAREN_RIGHT = 57351
 const COMMA = 57352
-const ARITH_OP = 57353
-const IDENTIFIER = 57354
-const INTEGER = 57355
-const FLOAT = 57356
-const STRING = 57357
-
-var parserToknames = []string{
+const QUESTION = 57353
+const COLON = 57354
+const SQUARE_BRACKET_RIGHT = 57355
+const FOR = 57356
+const IN = 57357
+const IF = 57358
+const ARITH_OP = 57359
+const ARITH_OP_MUL = 57360
+const IDENTIFIER = 57361
+const INTEGER = 57362
+const FLOAT = 57363
+const STRING = 57364
+const BOOL = 57365
+const EQUALITY_OP = 57366
+const COMPARISON_OP = 57367
+const AND_OP = 57368
+const OR_OP = 57369
+const BANG = 57370
+const SQUARE_BRACKET_LEFT = 57371
+const NULL = 57372
+const UNARY = 57373
+
+var parserToknames = [...]string{
+	"$end",
+	"error",
+	"$unk",
 	"PROGRAM_BRACKET_LEFT",
 	"PROGRAM_BRACKET_RIGHT",
 	"PROGRAM_STRING_START",
@@ -38,104 +61,169 @@ var parserToknames = []string{
 	"PAREN_LEFT",
 	"PAREN_RIGHT",
 	"COMMA",
+	"QUESTION",
+	"COLON",
+	"SQUARE_BRACKET_RIGHT",
+	"FOR",
+	"IN",
+	"IF",
 	"ARITH_OP",
+	"ARITH_OP_MUL",
 	"IDENTIFIER",
 	"INTEGER",
 	"FLOAT",
 	"STRING",
+	"BOOL",
+	"EQUALITY_OP",
+	"COMPARISON_OP",
+	"AND_OP",
+	"OR_OP",
+	"BANG",
+	"SQUARE_BRACKET_LEFT",
+	"NULL",
+	"UNARY",
 }
-var parserStatenames = []string{}
+
+var parserStatenames = [...]string{}
 
 const parserEofCode = 1
 const parserErrCode = 2
-const parserMaxDepth = 200
+const parserInitialStackSize = 16
 
-//line lang.y:165
+//line lang.y:300
 
 //line yacctab:1
-var parserExca = []int{
+var parserExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
 }
 
-const parserNprod = 19
 const parserPrivate = 57344
 
-var parserTokenNames []string
-var parserStates []string
-
-const parserLast = 30
-
-var parserAct = []int{
-
-	9, 20, 16, 16, 7, 7, 3, 18, 10, 8,
-	1, 17, 14, 12, 13, 6, 6, 19, 8, 22,
-	15, 23, 24, 11, 2, 25, 16, 21, 4, 5,
+const parserLast = 135
+
+var parserAct = [...]int8{
+	9, 22, 23, 7, 53, 22, 23, 23, 24, 25,
+	26, 28, 24, 25, 22, 23, 48, 29, 30, 44,
+	32, 6, 33, 34, 35, 36, 37, 38, 39, 21,
+	49, 56, 42, 31, 57, 22, 23, 1, 3, 46,
+	47, 8, 24, 25, 26, 27, 50, 7, 51, 52,
+	8, 10, 43, 22, 23, 55, 11, 2, 58, 41,
+	17, 25, 18, 12, 13, 6, 14, 4, 21, 5,
+	59, 16, 19, 15, 22, 23, 0, 0, 0, 21,
+	54, 24, 25, 26, 27, 22, 23, 0, 0, 0,
+	21, 45, 24, 25, 26, 27, 22, 23, 0, 40,
+	0, 21, 0, 24, 25, 26, 27, 22, 23, 0,
+	0, 0, 20, 21, 24, 25, 26, 27, 21, 22,
+	23, 0, 0, 0, 22, 23, 24, 25, 26, 27,
+	0, 24, 25, 26, 27,
 }
-var parserPact = []int{
 
-	1, -1000, 1, -1000, -1000, -1000, -1000, 0, -1000, 15,
-	0, 1, -1000, -1000, -1, -1000, 0, -8, 0, -1000,
-	-1000, 12, -9, -1000, 0, -9,
+var parserPact = [...]int16{
+	-1, -1000, -1, -1000, -1000, -1000, -1000, 43, -1000, 107,
+	43, -1, -1000, -1000, -1000, -1000, 43, 43, 25, 6,
+	-1000, 43, 43, 43, 43, 43, 43, 43, 90, -1000,
+	-1000, 43, 0, 79, -11, -1000, 36, -3, -12, -16,
+	-1000, 30, 102, 1, 20, 43, -1000, 43, 43, -15,
+	102, 102, 68, -1000, 43, 18, -1000, 43, 57, -1000,
 }
-var parserPgo = []int{
 
-	0, 0, 29, 28, 23, 6, 27, 10,
+var parserPgo = [...]int8{
+	0, 0, 69, 67, 56, 38, 59, 52, 37,
 }
-var parserR1 = []int{
 
-	0, 7, 7, 4, 4, 5, 5, 2, 1, 1,
-	1, 1, 1, 1, 1, 6, 6, 6, 3,
+var parserR1 = [...]int8{
+	0, 8, 8, 4, 4, 5, 5, 2, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 7, 7, 6,
+	6, 6, 3,
 }
-var parserR2 = []int{
 
+var parserR2 = [...]int8{
 	0, 0, 1, 1, 2, 1, 1, 3, 3, 1,
-	1, 1, 3, 1, 4, 0, 3, 1, 1,
+	1, 1, 1, 1, 5, 3, 3, 3, 3, 3,
+	3, 2, 2, 1, 4, 8, 10, 1, 3, 0,
+	3, 1, 1,
 }
-var parserChk = []int{
 
-	-1000, -7, -4, -5, -3, -2, 15, 4, -5, -1,
-	8, -4, 13, 14, 12, 5, 11, -1, 8, -1,
-	9, -6, -1, 9, 10, -1,
+var parserChk = [...]int16{
+	-1000, -8, -4, -5, -3, -2, 22, 4, -5, -1,
+	8, -4, 20, 21, 23, 30, 28, 17, 19, 29,
+	5, 11, 17, 18, 24, 25, 26, 27, -1, -1,
+	-1, 8, 14, -1, -1, -1, -1, -1, -1, -1,
+	9, -6, -1, -7, 19, 12, 9, 10, 15, 10,
+	-1, -1, -1, 19, 12, -1, 13, 16, -1, 13,
 }
-var parserDef = []int{
 
-	1, -2, 2, 3, 5, 6, 18, 0, 4, 0,
-	0, 9, 10, 11, 13, 7, 0, 0, 15, 12,
-	8, 0, 17, 14, 0, 16,
+var parserDef = [...]int8{
+	1, -2, 2, 3, 5, 6, 32, 0, 4, 0,
+	0, 9, 10, 11, 12, 13, 0, 0, 23, 0,
+	7, 0, 0, 0, 0, 0, 0, 0, 0, 21,
+	22, 29, 0, 0, 15, 16, 17, 18, 19, 20,
+	8, 0, 31, 0, 27, 0, 24, 0, 0, 0,
+	14, 30, 0, 28, 0, 0, 25, 0, 0, 26,
 }
-var parserTok1 = []int{
 
+var parserTok1 = [...]int8{
 	1,
 }
-var parserTok2 = []int{
 
+var parserTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14, 15,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
+	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 }
-var parserTok3 = []int{
+
+var parserTok3 = [...]int8{
 	0,
 }
 
+var parserErrorMessages = [...]struct {
+	state int
+	token int
+	msg   string
+}{}
+
 //line yaccpar:1
 
 /*	parser for yacc output	*/
 
-var parserDebug = 0
+var (
+	parserDebug        = 0
+	parserErrorVerbose = false
+)
 
 type parserLexer interface {
 	Lex(lval *parserSymType) int
 	Error(s string)
 }
 
+type parserParser interface {
+	Parse(parserLexer) int
+	Lookahead() int
+}
+
+type parserParserImpl struct {
+	lval  parserSymType
+	stack [parserInitialStackSize]parserSymType
+	char  int
+}
+
+func (p *parserParserImpl) Lookahead() int {
+	return p.char
+}
+
+func parserNewParser() parserParser {
+	return &parserParserImpl{}
+}
+
 const parserFlag = -1000
 
 func parserTokname(c int) string {
-	// 4 is TOKSTART above
-	if c >= 4 && c-4 < len(parserToknames) {
-		if parserToknames[c-4] != "" {
-			return parserToknames[c-4]
+	if c >= 1 && c-1 < len(parserToknames) {
+		if parserToknames[c-1] != "" {
+			return parserToknames[c-1]
 		}
 	}
 	return __yyfmt__.Sprintf("tok-%v", c)
@@ -150,51 +238,127 @@ func parserStatname(s int) string {
 	return __yyfmt__.Sprintf("state-%v", s)
 }
 
-func parserlex1(lex parserLexer, lval *parserSymType) int {
-	c := 0
-	char := lex.Lex(lval)
+func parserErrorMessage(state, lookAhead int) string {
+	const TOKSTART = 4
+
+	if !parserErrorVerbose {
+		return "syntax error"
+	}
+
+	for _, e := range parserErrorMessages {
+		if e.state == state && e.token == lookAhead {
+			return "syntax error: " + e.msg
+		}
+	}
+
+	res := "syntax error: unexpected " + parserTokname(lookAhead)
+
+	// To match Bison, suggest at most four expected tokens.
+	expected := make([]int, 0, 4)
+
+	// Look for shiftable tokens.
+	base := int(parserPact[state])
+	for tok := TOKSTART; tok-1 < len(parserToknames); tok++ {
+		if n := base + tok; n >= 0 && n < parserLast && int(parserChk[int(parserAct[n])]) == tok {
+			if len(expected) == cap(expected) {
+				return res
+			}
+			expected = append(expected, tok)
+		}
+	}
+
+	if parserDef[state] == -2 {
+		i := 0
+		for parserExca[i] != -1 || int(parserExca[i+1]) != state {
+			i += 2
+		}
+
+		// Look for tokens that we accept or reduce.
+		for i += 2; parserExca[i] >= 0; i += 2 {
+			tok := int(parserExca[i])
+			if tok < TOKSTART || parserExca[i+1] == 0 {
+				continue
+			}
+			if len(expected) == cap(expected) {
+				return res
+			}
+			expected = append(expected, tok)
+		}
+
+		// If the default action is to accept or reduce, give up.
+		if parserExca[i+1] != 0 {
+			return res
+		}
+	}
+
+	for i, tok := range expected {
+		if i == 0 {
+			res += ", expecting "
+		} else {
+			res += " or "
+		}
+		res += parserTokname(tok)
+	}
+	return res
+}
+
+func parserlex1(lex parserLexer, lval *parserSymType) (char, token int) {
+	token = 0
+	char = lex.Lex(lval)
 	if char <= 0 {
-		c = parserTok1[0]
+		token = int(parserTok1[0])
 		goto out
 	}
 	if char < len(parserTok1) {
-		c = parserTok1[char]
+		token = int(parserTok1[char])
 		goto out
 	}
 	if char >= parserPrivate {
 		if char < parserPrivate+len(parserTok2) {
-			c = parserTok2[char-parserPrivate]
+			token = int(parserTok2[char-parserPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(parserTok3); i += 2 {
-		c = parserTok3[i+0]
-		if c == char {
-			c = parserTok3[i+1]
+		token = int(parserTok3[i+0])
+		if token == char {
+			token = int(parserTok3[i+1])
 			goto out
 		}
 	}
 
 out:
-	if c == 0 {
-		c = parserTok2[1] /* unknown char */
+	if token == 0 {
+		token = int(parserTok2[1]) /* unknown char */
 	}
 	if parserDebug >= 3 {
-		__yyfmt__.Printf("lex %s(%d)\n", parserTokname(c), uint(char))
+		__yyfmt__.Printf("lex %s(%d)\n", parserTokname(token), uint(char))
 	}
-	return c
+	return char, token
 }
 
 func parserParse(parserlex parserLexer) int {
+	return parserNewParser().Parse(parserlex)
+}
+
+func (parserrcvr *parserParserImpl) Parse(parserlex parserLexer) int {
 	var parsern int
-	var parserlval parserSymType
 	var parserVAL parserSymType
-	parserS := make([]parserSymType, parserMaxDepth)
+	var parserDollar []parserSymType
+	_ = parserDollar // silence set and not used
+	parserS := parserrcvr.stack[:]
 
 	Nerrs := 0   /* number of errors */
 	Errflag := 0 /* error recovery flag */
 	parserstate := 0
-	parserchar := -1
+	parserrcvr.char = -1
+	parsertoken := -1 // parserrcvr.char translated into internal numbering
+	defer func() {
+		// Make sure we report no lookahead when not parsing.
+		parserstate = -1
+		parserrcvr.char = -1
+		parsertoken = -1
+	}()
 	parserp := -1
 	goto parserstack
 
@@ -207,7 +371,7 @@ ret1:
 parserstack:
 	/* put a state and value onto the stack */
 	if parserDebug >= 4 {
-		__yyfmt__.Printf("char %v in %v\n", parserTokname(parserchar), parserStatname(parserstate))
+		__yyfmt__.Printf("char %v in %v\n", parserTokname(parsertoken), parserStatname(parserstate))
 	}
 
 	parserp++
@@ -220,21 +384,22 @@ parserstack:
 	parserS[parserp].yys = parserstate
 
 parsernewstate:
-	parsern = parserPact[parserstate]
+	parsern = int(parserPact[parserstate])
 	if parsern <= parserFlag {
 		goto parserdefault /* simple state */
 	}
-	if parserchar < 0 {
-		parserchar = parserlex1(parserlex, &parserlval)
+	if parserrcvr.char < 0 {
+		parserrcvr.char, parsertoken = parserlex1(parserlex, &parserrcvr.lval)
 	}
-	parsern += parserchar
+	parsern += parsertoken
 	if parsern < 0 || parsern >= parserLast {
 		goto parserdefault
 	}
-	parsern = parserAct[parsern]
-	if parserChk[parsern] == parserchar { /* valid shift */
-		parserchar = -1
-		parserVAL = parserlval
+	parsern = int(parserAct[parsern])
+	if int(parserChk[parsern]) == parsertoken { /* valid shift */
+		parserrcvr.char = -1
+		parsertoken = -1
+		parserVAL = parserrcvr.lval
 		parserstate = parsern
 		if Errflag > 0 {
 			Errflag--
@@ -244,27 +409,27 @@ parsernewstate:
 
 parserdefault:
 	/* default state action */
-	parsern = parserDef[parserstate]
+	parsern = int(parserDef[parserstate])
 	if parsern == -2 {
-		if parserchar < 0 {
-			parserchar = parserlex1(parserlex, &parserlval)
+		if parserrcvr.char < 0 {
+			parserrcvr.char, parsertoken = parserlex1(parserlex, &parserrcvr.lval)
 		}
 
 		/* look through exception table */
 		xi := 0
 		for {
-			if parserExca[xi+0] == -1 && parserExca[xi+1] == parserstate {
+			if parserExca[xi+0] == -1 && int(parserExca[xi+1]) == parserstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			parsern = parserExca[xi+0]
-			if parsern < 0 || parsern == parserchar {
+			parsern = int(parserExca[xi+0])
+			if parsern < 0 || parsern == parsertoken {
 				break
 			}
 		}
-		parsern = parserExca[xi+1]
+		parsern = int(parserExca[xi+1])
 		if parsern < 0 {
 			goto ret0
 		}
@@ -273,11 +438,11 @@ parserdefault:
 		/* error ... attempt to resume parsing */
 		switch Errflag {
 		case 0: /* brand new error */
-			parserlex.Error("syntax error")
+			parserlex.Error(parserErrorMessage(parserstate, parsertoken))
 			Nerrs++
 			if parserDebug >= 1 {
 				__yyfmt__.Printf("%s", parserStatname(parserstate))
-				__yyfmt__.Printf(" saw %s\n", parserTokname(parserchar))
+				__yyfmt__.Printf(" saw %s\n", parserTokname(parsertoken))
 			}
 			fallthrough
 
@@ -286,10 +451,10 @@ parserdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for parserp >= 0 {
-				parsern = parserPact[parserS[parserp].yys] + parserErrCode
+				parsern = int(parserPact[parserS[parserp].yys]) + parserErrCode
 				if parsern >= 0 && parsern < parserLast {
-					parserstate = parserAct[parsern] /* simulate a shift of "error" */
-					if parserChk[parserstate] == parserErrCode {
+					parserstate = int(parserAct[parsern]) /* simulate a shift of "error" */
+					if int(parserChk[parserstate]) == parserErrCode {
 						goto parserstack
 					}
 				}
@@ -305,12 +470,13 @@ parserdefault:
 
 		case 3: /* no shift yet; clobber input char */
 			if parserDebug >= 2 {
-				__yyfmt__.Printf("error recovery discards %s\n", parserTokname(parserchar))
+				__yyfmt__.Printf("error recovery discards %s\n", parserTokname(parsertoken))
 			}
-			if parserchar == parserEofCode {
+			if parsertoken == parserEofCode {
 				goto ret1
 			}
-			parserchar = -1
+			parserrcvr.char = -1
+			parsertoken = -1
 			goto parsernewstate /* try again in the same state */
 		}
 	}
@@ -324,27 +490,35 @@ parserdefault:
 	parserpt := parserp
 	_ = parserpt // guard against "declared and not used"
 
-	parserp -= parserR2[parsern]
+	parserp -= int(parserR2[parsern])
+	// parserp is now the index of $0. Perform the default action. Iff the
+	// reduced production is ε, $1 is possibly out of range.
+	if parserp+1 >= len(parserS) {
+		nyys := make([]parserSymType, len(parserS)*2)
+		copy(nyys, parserS)
+		parserS = nyys
+	}
 	parserVAL = parserS[parserp+1]
 
 	/* consult goto table to find next state */
-	parsern = parserR1[parsern]
-	parserg := parserPgo[parsern]
+	parsern = int(parserR1[parsern])
+	parserg := int(parserPgo[parsern])
 	parserj := parserg + parserS[parserp].yys + 1
 
 	if parserj >= parserLast {
-		parserstate = parserAct[parserg]
+		parserstate = int(parserAct[parserg])
 	} else {
-		parserstate = parserAct[parserj]
-		if parserChk[parserstate] != -parsern {
-			parserstate = parserAct[parserg]
+		parserstate = int(parserAct[parserj])
+		if int(parserChk[parserstate]) != -parsern {
+			parserstate = int(parserAct[parserg])
 		}
 	}
 	// dummy call; replaced with literal code
 	switch parsernt {
 
 	case 1:
-		//line lang.y:35
+		parserDollar = parserS[parserpt-0 : parserpt+1]
+//line lang.y:47
 		{
 			parserResult = &ast.LiteralNode{
 				Value: "",
@@ -353,9 +527,10 @@ parserdefault:
 			}
 		}
 	case 2:
-		//line lang.y:43
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:55
 		{
-			parserResult = parserS[parserpt-0].node
+			parserResult = parserDollar[1].node
 
 			// We want to make sure that the top value is always a Concat
 			// so that the return value is always a string type from an
@@ -365,28 +540,30 @@ parserdefault:
 			// because functionally the AST is the same, but we do that because
 			// it makes for an easy literal check later (to check if a string
 			// has any interpolations).
-			if _, ok := parserS[parserpt-0].node.(*ast.Concat); !ok {
-				if n, ok := parserS[parserpt-0].node.(*ast.LiteralNode); !ok || n.Typex != ast.TypeString {
+			if _, ok := parserDollar[1].node.(*ast.Concat); !ok {
+				if n, ok := parserDollar[1].node.(*ast.LiteralNode); !ok || n.Typex != ast.TypeString {
 					parserResult = &ast.Concat{
-						Exprs: []ast.Node{parserS[parserpt-0].node},
-						Posx:  parserS[parserpt-0].node.Pos(),
+						Exprs: []ast.Node{parserDollar[1].node},
+						Posx:  parserDollar[1].node.Pos(),
 					}
 				}
 			}
 		}
 	case 3:
-		//line lang.y:66
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:78
 		{
-			parserVAL.node = parserS[parserpt-0].node
+			parserVAL.node = parserDollar[1].node
 		}
 	case 4:
-		//line lang.y:70
+		parserDollar = parserS[parserpt-2 : parserpt+1]
+//line lang.y:82
 		{
 			var result []ast.Node
-			if c, ok := parserS[parserpt-1].node.(*ast.Concat); ok {
-				result = append(c.Exprs, parserS[parserpt-0].node)
+			if c, ok := parserDollar[1].node.(*ast.Concat); ok {
+				result = append(c.Exprs, parserDollar[2].node)
 			} else {
-				result = []ast.Node{parserS[parserpt-1].node, parserS[parserpt-0].node}
+				result = []ast.Node{parserDollar[1].node, parserDollar[2].node}
 			}
 
 			parserVAL.node = &ast.Concat{
@@ -395,89 +572,252 @@ parserdefault:
 			}
 		}
 	case 5:
-		//line lang.y:86
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:98
 		{
-			parserVAL.node = parserS[parserpt-0].node
+			parserVAL.node = parserDollar[1].node
 		}
 	case 6:
-		//line lang.y:90
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:102
 		{
-			parserVAL.node = parserS[parserpt-0].node
+			parserVAL.node = parserDollar[1].node
 		}
 	case 7:
-		//line lang.y:96
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:108
 		{
-			parserVAL.node = parserS[parserpt-1].node
+			parserVAL.node = parserDollar[2].node
 		}
 	case 8:
-		//line lang.y:102
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:114
 		{
-			parserVAL.node = parserS[parserpt-1].node
+			parserVAL.node = parserDollar[2].node
 		}
 	case 9:
-		//line lang.y:106
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:118
 		{
-			parserVAL.node = parserS[parserpt-0].node
+			parserVAL.node = parserDollar[1].node
 		}
 	case 10:
-		//line lang.y:110
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:122
 		{
 			parserVAL.node = &ast.LiteralNode{
-				Value: parserS[parserpt-0].token.Value.(int),
+				Value: parserDollar[1].token.Value.(int),
 				Typex: ast.TypeInt,
-				Posx:  parserS[parserpt-0].token.Pos,
+				Posx:  parserDollar[1].token.Pos,
 			}
 		}
 	case 11:
-		//line lang.y:118
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:130
 		{
 			parserVAL.node = &ast.LiteralNode{
-				Value: parserS[parserpt-0].token.Value.(float64),
+				Value: parserDollar[1].token.Value.(float64),
 				Typex: ast.TypeFloat,
-				Posx:  parserS[parserpt-0].token.Pos,
+				Posx:  parserDollar[1].token.Pos,
 			}
 		}
 	case 12:
-		//line lang.y:126
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:138
 		{
-			parserVAL.node = &ast.Arithmetic{
-				Op:    parserS[parserpt-1].token.Value.(ast.ArithmeticOp),
-				Exprs: []ast.Node{parserS[parserpt-2].node, parserS[parserpt-0].node},
-				Posx:  parserS[parserpt-2].node.Pos(),
+			parserVAL.node = &ast.LiteralNode{
+				Value: parserDollar[1].token.Value.(bool),
+				Typex: ast.TypeBool,
+				Posx:  parserDollar[1].token.Pos,
 			}
 		}
 	case 13:
-		//line lang.y:134
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:146
 		{
-			parserVAL.node = &ast.VariableAccess{Name: parserS[parserpt-0].token.Value.(string), Posx: parserS[parserpt-0].token.Pos}
+			parserVAL.node = &ast.LiteralNode{
+				Value: nil,
+				Typex: ast.TypeNull,
+				Posx:  parserDollar[1].token.Pos,
+			}
 		}
 	case 14:
-		//line lang.y:138
+		parserDollar = parserS[parserpt-5 : parserpt+1]
+//line lang.y:154
 		{
-			parserVAL.node = &ast.Call{Func: parserS[parserpt-3].token.Value.(string), Args: parserS[parserpt-1].nodeList, Posx: parserS[parserpt-3].token.Pos}
+			parserVAL.node = &ast.Conditional{
+				CondExpr:  parserDollar[1].node,
+				TrueExpr:  parserDollar[3].node,
+				FalseExpr: parserDollar[5].node,
+				Posx:      parserDollar[1].node.Pos(),
+			}
 		}
 	case 15:
-		//line lang.y:143
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:163
 		{
-			parserVAL.nodeList = nil
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
 		}
 	case 16:
-		//line lang.y:147
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:171
 		{
-			parserVAL.nodeList = append(parserS[parserpt-2].nodeList, parserS[parserpt-0].node)
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
 		}
 	case 17:
-		//line lang.y:151
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:179
 		{
-			parserVAL.nodeList = append(parserVAL.nodeList, parserS[parserpt-0].node)
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
 		}
 	case 18:
-		//line lang.y:157
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:187
+		{
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
+		}
+	case 19:
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:195
+		{
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
+		}
+	case 20:
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:203
+		{
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[2].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[1].node, parserDollar[3].node},
+				Posx:  parserDollar[1].node.Pos(),
+			}
+		}
+	case 21:
+		parserDollar = parserS[parserpt-2 : parserpt+1]
+//line lang.y:211
+		{
+			parserVAL.node = &ast.Arithmetic{
+				Op:    parserDollar[1].token.Value.(ast.ArithmeticOp),
+				Exprs: []ast.Node{parserDollar[2].node},
+				Posx:  parserDollar[1].token.Pos,
+			}
+		}
+	case 22:
+		parserDollar = parserS[parserpt-2 : parserpt+1]
+//line lang.y:219
+		{
+			// Unary plus is a no-op and unary minus is subtraction from zero
+			parserVAL.node = parserDollar[2].node
+			if parserDollar[1].token.Value.(ast.ArithmeticOp) == ast.ArithmeticOpSub {
+				parserVAL.node = &ast.Arithmetic{
+					Op: ast.ArithmeticOpSub,
+					Exprs: []ast.Node{
+						&ast.LiteralNode{
+							Value: 0,
+							Typex: ast.TypeInt,
+							Posx:  parserDollar[1].token.Pos,
+						},
+						parserDollar[2].node,
+					},
+					Posx: parserDollar[1].token.Pos,
+				}
+			}
+		}
+	case 23:
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:238
+		{
+			parserVAL.node = &ast.VariableAccess{Name: parserDollar[1].token.Value.(string), Posx: parserDollar[1].token.Pos}
+		}
+	case 24:
+		parserDollar = parserS[parserpt-4 : parserpt+1]
+//line lang.y:242
+		{
+			parserVAL.node = &ast.Call{Func: parserDollar[1].token.Value.(string), Args: parserDollar[3].nodeList, Posx: parserDollar[1].token.Pos}
+		}
+	case 25:
+		parserDollar = parserS[parserpt-8 : parserpt+1]
+//line lang.y:246
+		{
+			parserVAL.node = &ast.For{
+				IndexVar: parserDollar[3].strList[0],
+				ValueVar: parserDollar[3].strList[1],
+				List:     parserDollar[5].node,
+				Body:     parserDollar[7].node,
+				Posx:     parserDollar[1].token.Pos,
+			}
+		}
+	case 26:
+		parserDollar = parserS[parserpt-10 : parserpt+1]
+//line lang.y:256
+		{
+			parserVAL.node = &ast.For{
+				IndexVar: parserDollar[3].strList[0],
+				ValueVar: parserDollar[3].strList[1],
+				List:     parserDollar[5].node,
+				Body:     parserDollar[7].node,
+				Cond:     parserDollar[9].node,
+				Posx:     parserDollar[1].token.Pos,
+			}
+		}
+	case 27:
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:269
+		{
+			parserVAL.strList = []string{"", parserDollar[1].token.Value.(string)}
+		}
+	case 28:
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:273
+		{
+			parserVAL.strList = []string{parserDollar[1].token.Value.(string), parserDollar[3].token.Value.(string)}
+		}
+	case 29:
+		parserDollar = parserS[parserpt-0 : parserpt+1]
+//line lang.y:278
+		{
+			parserVAL.nodeList = nil
+		}
+	case 30:
+		parserDollar = parserS[parserpt-3 : parserpt+1]
+//line lang.y:282
+		{
+			parserVAL.nodeList = append(parserDollar[1].nodeList, parserDollar[3].node)
+		}
+	case 31:
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:286
+		{
+			parserVAL.nodeList = append(parserVAL.nodeList, parserDollar[1].node)
+		}
+	case 32:
+		parserDollar = parserS[parserpt-1 : parserpt+1]
+//line lang.y:292
 		{
 			parserVAL.node = &ast.LiteralNode{
-				Value: parserS[parserpt-0].token.Value.(string),
+				Value: parserDollar[1].token.Value.(string),
 				Typex: ast.TypeString,
-				Posx:  parserS[parserpt-0].token.Pos,
+				Posx:  parserDollar[1].token.Pos,
 			}
 		}
 	}
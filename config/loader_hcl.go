@@ -1,11 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
 
 	"github.com/hashicorp/hcl"
 	hclobj "github.com/hashicorp/hcl/hcl"
+	"github.com/mitchellh/mapstructure"
 )
 
 // hclConfigurable is an implementation of configurable that knows
@@ -17,6 +22,8 @@ type hclConfigurable struct {
 
 func (t *hclConfigurable) Config() (*Config, error) {
 	validKeys := map[string]struct{}{
+		"data":     struct{}{},
+		"locals":   struct{}{},
 		"module":   struct{}{},
 		"output":   struct{}{},
 		"provider": struct{}{},
@@ -24,9 +31,16 @@ func (t *hclConfigurable) Config() (*Config, error) {
 		"variable": struct{}{},
 	}
 
+	type hclVariableValidation struct {
+		Condition    string
+		ErrorMessage string `hcl:"error_message"`
+	}
+
 	type hclVariable struct {
 		Default     interface{}
 		Description string
+		Validation  []*hclVariableValidation
+		Type        interface{}
 		Fields      []string `hcl:",decodedFields"`
 	}
 
@@ -66,10 +80,50 @@ func (t *hclConfigurable) Config() (*Config, error) {
 				Description: v.Description,
 			}
 
+			// An object type is a mapping of attribute names to types
+			if v.Type != nil {
+				attrs, err := loadVariableAttributes(v.Type)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading type for variable %s: %s", k, err)
+				}
+
+				newVar.Attributes = attrs
+			}
+
+			for _, vv := range v.Validation {
+				raw := make(map[string]interface{})
+				if vv.Condition != "" {
+					raw["condition"] = vv.Condition
+				}
+
+				rc, err := NewRawConfig(raw)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading validation for variable %s: %s",
+						k, err)
+				}
+
+				newVar.Validations = append(newVar.Validations,
+					&VariableValidation{
+						RawConfig:    rc,
+						ErrorMessage: vv.ErrorMessage,
+					})
+			}
+
 			config.Variables = append(config.Variables, newVar)
 		}
 	}
 
+	// Build the locals
+	if locals := t.Object.Get("locals", false); locals != nil {
+		var err error
+		config.Locals, err = loadLocalsHcl(locals)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build the modules
 	if modules := t.Object.Get("module", false); modules != nil {
 		var err error
@@ -91,10 +145,21 @@ func (t *hclConfigurable) Config() (*Config, error) {
 	// Build the resources
 	if resources := t.Object.Get("resource", false); resources != nil {
 		var err error
-		config.Resources, err = loadResourcesHcl(resources)
+		config.Resources, err = loadResourcesHcl(
+			resources, ManagedResourceMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Build the data sources, which are resources that are only read
+	if data := t.Object.Get("data", false); data != nil {
+		dataResources, err := loadResourcesHcl(data, DataResourceMode)
 		if err != nil {
 			return nil, err
 		}
+
+		config.Resources = append(config.Resources, dataResources...)
 	}
 
 	// Build the outputs
@@ -131,8 +196,16 @@ func loadFileHcl(root string) (configurable, []string, error) {
 			"Error reading %s: %s", root, err)
 	}
 
+	// Expand any heredocs into plain strings, since the HCL parser
+	// doesn't know about them.
+	src, err := expandHeredocs(string(d))
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"Error parsing %s: %s", root, err)
+	}
+
 	// Parse it
-	obj, err = hcl.Parse(string(d))
+	obj, err = hcl.Parse(src)
 	if err != nil {
 		return nil, nil, fmt.Errorf(
 			"Error parsing %s: %s", root, err)
@@ -187,6 +260,188 @@ func loadFileHcl(root string) (configurable, []string, error) {
 	return result, nil, nil
 }
 
+// expandHeredocs rewrites heredoc values in the HCL source into
+// regular double-quoted strings. A heredoc begins with "<<" and an
+// identifier at the end of a line, and ends at the first following
+// line that contains only that identifier:
+//
+//	user_data = <<EOF
+//	#!/bin/bash
+//	echo "hello"
+//	EOF
+//
+// The resulting string keeps the newlines of the body, except for the
+// one directly before the closing identifier. Blank lines are added
+// after the string so that line numbers in parse errors still match
+// the original file.
+func expandHeredocs(src string) (string, error) {
+	var buf bytes.Buffer
+	line := 1
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+		case c == '"':
+			// Copy strings verbatim so "<<" within them is left alone
+			end := skipString(src, i)
+			line += strings.Count(src[i:end], "\n")
+			buf.WriteString(src[i:end])
+			i = end - 1
+			continue
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				end = len(src) - i
+			}
+			buf.WriteString(src[i : i+end])
+			i += end - 1
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				end = len(src) - i
+			} else {
+				end += 4
+			}
+			line += strings.Count(src[i:i+end], "\n")
+			buf.WriteString(src[i : i+end])
+			i += end - 1
+			continue
+		case strings.HasPrefix(src[i:], "<<"):
+			ident, start := heredocIdent(src, i+2)
+			if ident == "" {
+				break
+			}
+
+			value, end, ok := heredocBody(src, start, ident)
+			if !ok {
+				return "", fmt.Errorf(
+					"Line %d: heredoc not terminated, expected %q",
+					line, ident)
+			}
+
+			lines := strings.Count(src[i:end], "\n")
+			line += lines
+			buf.WriteString(heredocQuote(value))
+			buf.WriteString(strings.Repeat("\n", lines))
+			i = end - 1
+			continue
+		}
+
+		buf.WriteByte(c)
+	}
+
+	return buf.String(), nil
+}
+
+// heredocIdent reads the identifier of a heredoc anchor beginning at
+// index i. It returns the identifier and the index of the first line of
+// the body, or an empty identifier if this isn't a heredoc anchor.
+func heredocIdent(src string, i int) (string, int) {
+	end := i
+	for end < len(src) {
+		r := rune(src[end])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		end++
+	}
+	if end == i {
+		return "", 0
+	}
+
+	ident := src[i:end]
+	rest := src[end:]
+	nl := strings.IndexByte(rest, '\n')
+	if nl == -1 || strings.TrimSpace(rest[:nl]) != "" {
+		return "", 0
+	}
+
+	return ident, end + nl + 1
+}
+
+// heredocBody collects the lines of a heredoc starting at index i until
+// the line containing only ident. It returns the body and the index
+// just past the closing identifier.
+func heredocBody(src string, i int, ident string) (string, int, bool) {
+	var lines []string
+	for i <= len(src) {
+		end := strings.IndexByte(src[i:], '\n')
+		if end == -1 {
+			end = len(src) - i
+		}
+
+		l := src[i : i+end]
+		if strings.TrimSpace(l) == ident {
+			return strings.Join(lines, "\n"), i + len(strings.TrimRight(l, " \t\r")), true
+		}
+
+		// Files with CRLF line endings keep the "\r" before each newline,
+		// but it isn't part of the body.
+		lines = append(lines, strings.TrimSuffix(l, "\r"))
+		i += end + 1
+	}
+
+	return "", 0, false
+}
+
+// heredocQuote turns the body of a heredoc into a double-quoted HCL
+// string. Quotes inside of interpolations are left as-is since the
+// HCL lexer doesn't end a string within "${}".
+func heredocQuote(v string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	depth := 0
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case strings.HasPrefix(v[i:], "${"):
+			depth++
+			buf.WriteString("${")
+			i++
+			continue
+		case c == '}' && depth > 0:
+			depth--
+		case c == '\n':
+			buf.WriteString("\\n")
+			continue
+		case c == '\\' && depth == 0:
+			buf.WriteString("\\\\")
+			continue
+		case c == '"' && depth == 0:
+			buf.WriteString("\\\"")
+			continue
+		}
+
+		buf.WriteByte(c)
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
+
+// skipString returns the index just past the double-quoted string that
+// starts at index i.
+func skipString(src string, i int) int {
+	depth := 0
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case src[j] == '\\':
+			j++
+		case strings.HasPrefix(src[j:], "${"):
+			depth++
+			j++
+		case src[j] == '}' && depth > 0:
+			depth--
+		case src[j] == '"' && depth == 0:
+			return j + 1
+		}
+	}
+
+	return len(src)
+}
+
 // Given a handle to a HCL object, this recurses into the structure
 // and pulls out a list of modules.
 //
@@ -225,6 +480,8 @@ func loadModulesHcl(os *hclobj.Object) ([]*Module, error) {
 
 		// Remove the fields we handle specially
 		delete(config, "source")
+		delete(config, "count")
+		delete(config, "depends_on")
 
 		rawConfig, err := NewRawConfig(config)
 		if err != nil {
@@ -235,6 +492,27 @@ func loadModulesHcl(os *hclobj.Object) ([]*Module, error) {
 		}
 
 		// If we have a count, then figure it out
+		var countConfig *RawConfig
+		if o := obj.Get("count", false); o != nil {
+			var count string
+			err = hcl.DecodeObject(&count, o)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Error parsing count for %s: %s",
+					k,
+					err)
+			}
+
+			countConfig, err = NewRawConfig(map[string]interface{}{
+				"count": count,
+			})
+			if err != nil {
+				return nil, err
+			}
+			countConfig.Key = "count"
+		}
+
+		// Read the source of the module
 		var source string
 		if o := obj.Get("source", false); o != nil {
 			err = hcl.DecodeObject(&source, o)
@@ -246,10 +524,109 @@ func loadModulesHcl(os *hclobj.Object) ([]*Module, error) {
 			}
 		}
 
+		// If we have depends fields, then add those in
+		var dependsOn []string
+		if o := obj.Get("depends_on", false); o != nil {
+			err := hcl.DecodeObject(&dependsOn, o)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Error reading depends_on for %s: %s",
+					k,
+					err)
+			}
+		}
+
 		result = append(result, &Module{
 			Name:      k,
 			Source:    source,
 			RawConfig: rawConfig,
+			RawCount:  countConfig,
+			DependsOn: dependsOn,
+		})
+	}
+
+	return result, nil
+}
+
+// loadVariableAttributes loads the attributes of an object type from
+// the decoded "type" of a variable.
+func loadVariableAttributes(raw interface{}) ([]*VariableAttribute, error) {
+	var m map[string]string
+	switch t := raw.(type) {
+	case []map[string]interface{}:
+		merged := make(map[string]interface{})
+		for _, part := range t {
+			for k, v := range part {
+				merged[k] = v
+			}
+		}
+
+		raw = merged
+	}
+	if err := mapstructure.WeakDecode(raw, &m); err != nil {
+		return nil, fmt.Errorf(
+			"must be a mapping of attribute names to types: %s", err)
+	}
+
+	names := make([]string, 0, len(m))
+	for k, _ := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	result := make([]*VariableAttribute, 0, len(names))
+	for _, k := range names {
+		a, err := ParseVariableAttribute(k, m[k])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+// loadLocalsHcl recurses into the given HCL object and turns
+// it into a list of locals. Every key within every "locals" block
+// is a separate local.
+func loadLocalsHcl(os *hclobj.Object) ([]*Local, error) {
+	objects := make(map[string]*hclobj.Object)
+
+	// Iterate over all the "locals" blocks and get the keys along with
+	// their raw values. We'll parse those later.
+	for _, o1 := range os.Elem(false) {
+		for _, o2 := range o1.Elem(true) {
+			objects[o2.Key] = o2
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	result := make([]*Local, 0, len(objects))
+	for n, o := range objects {
+		var value interface{}
+
+		if err := hcl.DecodeObject(&value, o); err != nil {
+			return nil, err
+		}
+
+		rawConfig, err := NewRawConfig(map[string]interface{}{
+			"value": value,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Error reading config for local %s: %s",
+				n,
+				err)
+		}
+
+		result = append(result, &Local{
+			Name:      n,
+			RawConfig: rawConfig,
 		})
 	}
 
@@ -302,13 +679,15 @@ func loadOutputsHcl(os *hclobj.Object) ([]*Output, error) {
 // LoadProvidersHcl recurses into the given HCL object and turns
 // it into a mapping of provider configs.
 func loadProvidersHcl(os *hclobj.Object) ([]*ProviderConfig, error) {
-	objects := make(map[string]*hclobj.Object)
+	var objects []*hclobj.Object
 
 	// Iterate over all the "provider" blocks and get the keys along with
-	// their raw configuration objects. We'll parse those later.
+	// their raw configuration objects. We'll parse those later. The same
+	// provider can be configured more than once with an alias, so these
+	// aren't unique by key.
 	for _, o1 := range os.Elem(false) {
 		for _, o2 := range o1.Elem(true) {
-			objects[o2.Key] = o2
+			objects = append(objects, o2)
 		}
 	}
 
@@ -318,23 +697,38 @@ func loadProvidersHcl(os *hclobj.Object) ([]*ProviderConfig, error) {
 
 	// Go through each object and turn it into an actual result.
 	result := make([]*ProviderConfig, 0, len(objects))
-	for n, o := range objects {
+	for _, o := range objects {
 		var config map[string]interface{}
 
 		if err := hcl.DecodeObject(&config, o); err != nil {
 			return nil, err
 		}
 
+		delete(config, "alias")
+
 		rawConfig, err := NewRawConfig(config)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"Error reading config for provider config %s: %s",
-				n,
+				o.Key,
 				err)
 		}
 
+		// If we have an alias field, then add those in
+		var alias string
+		if a := o.Get("alias", false); a != nil {
+			err := hcl.DecodeObject(&alias, a)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Error reading alias for provider[%s]: %s",
+					o.Key,
+					err)
+			}
+		}
+
 		result = append(result, &ProviderConfig{
-			Name:      n,
+			Name:      o.Key,
+			Alias:     alias,
 			RawConfig: rawConfig,
 		})
 	}
@@ -343,12 +737,13 @@ func loadProvidersHcl(os *hclobj.Object) ([]*ProviderConfig, error) {
 }
 
 // Given a handle to a HCL object, this recurses into the structure
-// and pulls out a list of resources.
+// and pulls out a list of resources with the given mode.
 //
 // The resulting resources may not be unique, but each resource
 // represents exactly one resource definition in the HCL configuration.
 // We leave it up to another pass to merge them together.
-func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
+func loadResourcesHcl(
+	os *hclobj.Object, mode ResourceMode) ([]*Resource, error) {
 	var allTypes []*hclobj.Object
 
 	// HCL object iteration is really nasty. Below is likely to make
@@ -380,6 +775,19 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 		for _, obj := range t.Elem(true) {
 			k := obj.Key
 
+			// Data sources are only read, so the parts of a resource
+			// that deal with creating and destroying it don't apply.
+			if mode == DataResourceMode {
+				for _, key := range []string{
+					"connection", "lifecycle", "provisioner"} {
+					if obj.Get(key, false) != nil {
+						return nil, fmt.Errorf(
+							"data.%s[%s]: data sources can't have a %s block",
+							t.Key, k, key)
+					}
+				}
+			}
+
 			var config map[string]interface{}
 			if err := hcl.DecodeObject(&config, obj); err != nil {
 				return nil, fmt.Errorf(
@@ -394,6 +802,7 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 			delete(config, "count")
 			delete(config, "depends_on")
 			delete(config, "provisioner")
+			delete(config, "provider")
 			delete(config, "lifecycle")
 
 			rawConfig, err := NewRawConfig(config)
@@ -438,10 +847,25 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 				}
 			}
 
+			// If we have an explicit provider configuration, such as an
+			// aliased one, then parse it out
+			var provider string
+			if o := obj.Get("provider", false); o != nil {
+				err := hcl.DecodeObject(&provider, o)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Error reading provider for %s[%s]: %s",
+						t.Key,
+						k,
+						err)
+				}
+			}
+
 			// If we have connection info, then parse those out
 			var connInfo map[string]interface{}
 			if o := obj.Get("connection", false); o != nil {
-				err := hcl.DecodeObject(&connInfo, o)
+				var err error
+				connInfo, err = loadBlockHcl(o)
 				if err != nil {
 					return nil, fmt.Errorf(
 						"Error reading connection info for %s[%s]: %s",
@@ -469,7 +893,11 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 			// destroying the existing instance
 			var lifecycle ResourceLifecycle
 			if o := obj.Get("lifecycle", false); o != nil {
-				err = hcl.DecodeObject(&lifecycle, o)
+				var raw map[string]interface{}
+				raw, err = loadBlockHcl(o)
+				if err == nil {
+					lifecycle, err = decodeLifecycle(raw)
+				}
 				if err != nil {
 					return nil, fmt.Errorf(
 						"Error parsing lifecycle for %s[%s]: %s",
@@ -480,6 +908,7 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 			}
 
 			result = append(result, &Resource{
+				Mode:         mode,
 				Name:         k,
 				Type:         t.Key,
 				RawCount:     countConfig,
@@ -487,6 +916,7 @@ func loadResourcesHcl(os *hclobj.Object) ([]*Resource, error) {
 				Provisioners: provisioners,
 				DependsOn:    dependsOn,
 				Lifecycle:    lifecycle,
+				Provider:     provider,
 			})
 		}
 	}
@@ -515,16 +945,37 @@ func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]
 	//     }
 	//   ]
 	//
+	// JSON can also repeat a type of provisioner with a list of
+	// configurations, so types holds the type of each object in pos
+	// since the elements of such a list have no key of their own:
+	//
+	//   {
+	//     "shell": [{ ... }, { ... }]
+	//   }
+	//
+	types := make([]string, 0, cap(pos))
+	add := func(o *hclobj.Object) {
+		if o.Type != hclobj.ValueTypeList {
+			types = append(types, o.Key)
+			pos = append(pos, o)
+			return
+		}
+
+		for _, elem := range o.Elem(true) {
+			types = append(types, o.Key)
+			pos = append(pos, elem)
+		}
+	}
 	for _, o1 := range os.Elem(false) {
 		for _, o2 := range o1.Elem(true) {
 
 			switch o1.Type {
 			case hclobj.ValueTypeList:
 				for _, o3 := range o2.Elem(true) {
-					pos = append(pos, o3)
+					add(o3)
 				}
 			case hclobj.ValueTypeObject:
-				pos = append(pos, o2)
+				add(o2)
 			}
 		}
 	}
@@ -535,7 +986,7 @@ func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]
 	}
 
 	result := make([]*Provisioner, 0, len(pos))
-	for _, po := range pos {
+	for i, po := range pos {
 		var config map[string]interface{}
 		if err := hcl.DecodeObject(&config, po); err != nil {
 			return nil, err
@@ -553,7 +1004,7 @@ func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]
 		// block that overrides the resource-level
 		var subConnInfo map[string]interface{}
 		if o := po.Get("connection", false); o != nil {
-			err := hcl.DecodeObject(&subConnInfo, o)
+			subConnInfo, err = loadBlockHcl(o)
 			if err != nil {
 				return nil, err
 			}
@@ -578,7 +1029,7 @@ func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]
 		}
 
 		result = append(result, &Provisioner{
-			Type:      po.Key,
+			Type:      types[i],
 			RawConfig: rawConfig,
 			ConnInfo:  connRaw,
 		})
@@ -587,6 +1038,52 @@ func loadProvisionersHcl(os *hclobj.Object, connInfo map[string]interface{}) ([]
 	return result, nil
 }
 
+// loadBlockHcl decodes a single block such as "connection" into a map.
+// In JSON the block can also be written as a list of objects, the same
+// way that HCL repeats blocks, in which case they're merged together.
+func loadBlockHcl(o *hclobj.Object) (map[string]interface{}, error) {
+	if o.Type != hclobj.ValueTypeList {
+		var result map[string]interface{}
+		if err := hcl.DecodeObject(&result, o); err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	result := make(map[string]interface{})
+	for _, elem := range o.Elem(true) {
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, elem); err != nil {
+			return nil, err
+		}
+
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+
+	return result, nil
+}
+
+// decodeLifecycle turns the decoded "lifecycle" block of a resource
+// into a ResourceLifecycle. Values are weakly decoded, since JSON
+// configurations often quote booleans.
+func decodeLifecycle(raw map[string]interface{}) (ResourceLifecycle, error) {
+	var result ResourceLifecycle
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "hcl",
+		WeaklyTypedInput: true,
+		Result:           &result,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = decoder.Decode(raw)
+	return result, err
+}
+
 /*
 func hclObjectMap(os *hclobj.Object) map[string]ast.ListNode {
 	objects := make(map[string][]*hclobj.Object)
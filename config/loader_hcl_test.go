@@ -1,9 +1,131 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
 func TestHCLConfigurableConfigurable(t *testing.T) {
 	var _ configurable = new(hclConfigurable)
 }
+
+func TestExpandHeredocs(t *testing.T) {
+	cases := []struct {
+		Input  string
+		Output string
+		Error  bool
+	}{
+		{
+			"foo = \"bar\"\n",
+			"foo = \"bar\"\n",
+			false,
+		},
+
+		{
+			"foo = <<EOF\nhello\nworld\nEOF\nbar = 1\n",
+			"foo = \"hello\\nworld\"\n\n\n\nbar = 1\n",
+			false,
+		},
+
+		{
+			"foo = <<EOF\necho \"a\\b\"\n  EOF\n",
+			"foo = \"echo \\\"a\\\\b\\\"\"\n\n\n",
+			false,
+		},
+
+		{
+			"foo = <<EOF\n${lookup(var.m, \"a\")}\nEOF\n",
+			"foo = \"${lookup(var.m, \"a\")}\"\n\n\n",
+			false,
+		},
+
+		// CRLF line endings
+		{
+			"foo = <<EOF\r\nhello\r\nworld\r\nEOF\r\nbar = 1\r\n",
+			"foo = \"hello\\nworld\"\n\n\n\r\nbar = 1\r\n",
+			false,
+		},
+
+		// Not a heredoc inside of strings or comments
+		{
+			"foo = \"<<EOF\"\n# <<EOF\n/* <<EOF */\n",
+			"foo = \"<<EOF\"\n# <<EOF\n/* <<EOF */\n",
+			false,
+		},
+
+		// Unterminated
+		{
+			"foo = <<EOF\nhello\n",
+			"",
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := expandHeredocs(tc.Input)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Output {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+func TestDecodeLifecycle(t *testing.T) {
+	cases := []struct {
+		Input  map[string]interface{}
+		Output ResourceLifecycle
+		Error  bool
+	}{
+		{
+			map[string]interface{}{},
+			ResourceLifecycle{},
+			false,
+		},
+
+		{
+			map[string]interface{}{
+				"create_before_destroy": "true",
+				"prevent_destroy":       true,
+			},
+			ResourceLifecycle{
+				CreateBeforeDestroy: true,
+				PreventDestroy:      true,
+			},
+			false,
+		},
+
+		{
+			map[string]interface{}{
+				"ignore_changes": []interface{}{"tags", "user_data"},
+			},
+			ResourceLifecycle{
+				IgnoreChanges: []string{"tags", "user_data"},
+			},
+			false,
+		},
+
+		{
+			map[string]interface{}{
+				"ignore_changes": map[string]interface{}{"tags": true},
+			},
+			ResourceLifecycle{},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		actual, err := decodeLifecycle(tc.Input)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if tc.Error {
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, tc.Output) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
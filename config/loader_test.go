@@ -383,6 +383,143 @@ func TestLoad_createBeforeDestroy(t *testing.T) {
 	}
 }
 
+func TestLoad_dataSource(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "data-source.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	if actual != strings.TrimSpace(dataSourceResourcesStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestLoad_dataSourceProvisioner(t *testing.T) {
+	_, err := Load(filepath.Join(fixtureDir, "data-source-provisioner.tf"))
+	if err == nil {
+		t.Fatal("should have error")
+	}
+}
+
+func TestLoad_providerAlias(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "provider-alias.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := providerConfigsStr(c.ProviderConfigs)
+	if actual != strings.TrimSpace(providerAliasStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	r := c.Resources[0]
+	if r.Provider != "aws.west" {
+		t.Fatalf("bad: %#v", r)
+	}
+	if _, ok := r.RawConfig.Raw["provider"]; ok {
+		t.Fatalf("provider should not be in the config: %#v", r.RawConfig.Raw)
+	}
+}
+
+func TestLoad_heredoc(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "heredoc.tf"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if c == nil {
+		t.Fatal("config should not be nil")
+	}
+
+	r := c.Resources[0]
+	expected := "#!/bin/bash\necho \"hello ${var.name}\" > /tmp/hello"
+	if actual := r.RawConfig.Raw["user_data"]; actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestLoad_connections_json(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "connection.tf.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	if actual != strings.TrimSpace(connectionResourcesStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	r := c.Resources[0]
+	p1 := r.Provisioners[0]
+	if p1.ConnInfo == nil || len(p1.ConnInfo.Raw) != 2 {
+		t.Fatalf("Bad: %#v", p1.ConnInfo)
+	}
+	if p1.ConnInfo.Raw["user"] != "nobody" {
+		t.Fatalf("Bad: %#v", p1.ConnInfo)
+	}
+
+	p2 := r.Provisioners[1]
+	if p2.ConnInfo == nil || len(p2.ConnInfo.Raw) != 2 {
+		t.Fatalf("Bad: %#v", p2.ConnInfo)
+	}
+	if p2.ConnInfo.Raw["user"] != "root" {
+		t.Fatalf("Bad: %#v", p2.ConnInfo)
+	}
+	if p2.RawConfig.Raw["path"] != "bar" {
+		t.Fatalf("Bad: %#v", p2.RawConfig)
+	}
+}
+
+func TestLoad_createBeforeDestroy_json(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "create-before-destroy.tf.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := resourcesStr(c.Resources)
+	if actual != strings.TrimSpace(createBeforeDestroyResourcesStr) {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	for _, r := range c.Resources {
+		expected := r.Name == "web"
+		if r.Lifecycle.CreateBeforeDestroy != expected {
+			t.Fatalf("Bad: %#v", r)
+		}
+	}
+}
+
+func TestLoad_heredoc_json(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "heredoc.tf.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := c.Resources[0]
+	expected := "#!/bin/bash\necho \"hello ${var.name}\" > /tmp/hello"
+	if actual := r.RawConfig.Raw["user_data"]; actual != expected {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestLoad_nestedBlocks_json(t *testing.T) {
+	c, err := Load(filepath.Join(fixtureDir, "nested-blocks.tf.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := c.Resources[0]
+	raw, ok := r.RawConfig.Raw["ebs_block_device"].([]interface{})
+	if !ok || len(raw) != 2 {
+		t.Fatalf("bad: %#v", r.RawConfig.Raw["ebs_block_device"])
+	}
+}
+
 func TestLoad_temporary_files(t *testing.T) {
 	_, err := LoadDir(filepath.Join(fixtureDir, "dir-temporary-files"))
 	if err == nil {
@@ -576,6 +713,20 @@ foo (required)
   <>
 `
 
+const providerAliasStr = `
+aws
+  region
+aws.west
+  region
+`
+
+const dataSourceResourcesStr = `
+aws_instance[web] (x1)
+  ami
+data.aws_ami[ubuntu] (x1)
+  name
+`
+
 const createBeforeDestroyResourcesStr = `
 aws_instance[bar] (x1)
   ami
@@ -52,6 +52,23 @@ func Merge(c1, c2 *Config) (*Config, error) {
 		}
 	}
 
+	// Locals
+	m1 = make([]merger, 0, len(c1.Locals))
+	m2 = make([]merger, 0, len(c2.Locals))
+	for _, v := range c1.Locals {
+		m1 = append(m1, v)
+	}
+	for _, v := range c2.Locals {
+		m2 = append(m2, v)
+	}
+	mresult = mergeSlice(m1, m2)
+	if len(mresult) > 0 {
+		c.Locals = make([]*Local, len(mresult))
+		for i, v := range mresult {
+			c.Locals[i] = v.(*Local)
+		}
+	}
+
 	// Outputs
 	m1 = make([]merger, 0, len(c1.Outputs))
 	m2 = make([]merger, 0, len(c2.Outputs))
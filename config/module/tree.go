@@ -68,12 +68,21 @@ func (t *Tree) Config() *config.Config {
 }
 
 // Child returns the child with the given path (by name).
+//
+// Path elements for modules with a count have the index of the
+// instance as a suffix, such as "foo.0". All instances share the
+// same tree, so the index is ignored.
 func (t *Tree) Child(path []string) *Tree {
 	if len(path) == 0 {
 		return t
 	}
 
-	c := t.Children()[path[0]]
+	name := path[0]
+	if idx := strings.Index(name, "."); idx != -1 {
+		name = name[:idx]
+	}
+
+	c := t.Children()[name]
 	if c == nil {
 		return nil
 	}
@@ -40,6 +40,13 @@ func TestTreeChild(t *testing.T) {
 	} else if c.Name() != "bar" {
 		t.Fatalf("bad: %#v", c.Name())
 	}
+
+	// Should be able to get a counted instance of a child
+	if c := tree.Child([]string{"foo.1", "bar"}); c == nil {
+		t.Fatal("should not be nil")
+	} else if c.Name() != "bar" {
+		t.Fatalf("bad: %#v", c.Name())
+	}
 }
 
 func TestTreeLoad(t *testing.T) {
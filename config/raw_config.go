@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"encoding/gob"
+	"strings"
 
 	"github.com/hashicorp/terraform/config/lang"
 	"github.com/hashicorp/terraform/config/lang/ast"
@@ -16,6 +17,11 @@ import (
 // unknown keys.
 const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
 
+// NullVariableValue is a sentinel value that an interpolation results
+// in when its value is null. RawConfig removes keys with this value
+// from the configuration, as if they were never set.
+const NullVariableValue = "3F0B6C2E-8D4A-4B71-9E35-1A7C5D2F8B90"
+
 // RawConfig is a structure that holds a piece of configuration
 // where te overall structure is unknown since it will be used
 // to configure a plugin or some other similar external component.
@@ -33,6 +39,7 @@ type RawConfig struct {
 
 	config      map[string]interface{}
 	unknownKeys []string
+	nullKeys    []string
 }
 
 // NewRawConfig creates a new RawConfig structure and populates the
@@ -81,15 +88,7 @@ func (r *RawConfig) Config() map[string]interface{} {
 //
 // If a variable key is missing, this will panic.
 func (r *RawConfig) Interpolate(vs map[string]ast.Variable) error {
-	config := langEvalConfig(vs)
-	return r.interpolate(func(root ast.Node) (string, error) {
-		out, _, err := lang.Eval(root, config)
-		if err != nil {
-			return "", err
-		}
-
-		return out.(string), nil
-	})
+	return r.interpolate(langEvalFunc(vs), vs)
 }
 
 // Merge merges another RawConfig into this one (overriding any conflicting
@@ -134,6 +133,19 @@ func (r *RawConfig) Merge(other *RawConfig) *RawConfig {
 		result.unknownKeys = append(result.unknownKeys, k)
 	}
 
+	// Build the null keys
+	nullKeys := make(map[string]struct{})
+	for _, k := range r.nullKeys {
+		nullKeys[k] = struct{}{}
+	}
+	for _, k := range other.nullKeys {
+		nullKeys[k] = struct{}{}
+	}
+
+	for k, _ := range nullKeys {
+		result.nullKeys = append(result.nullKeys, k)
+	}
+
 	return result
 }
 
@@ -168,20 +180,30 @@ func (r *RawConfig) init() error {
 	return nil
 }
 
-func (r *RawConfig) interpolate(fn interpolationWalkerFunc) error {
+// interpolate replaces all the interpolations in the configuration
+// with the result of fn. Dynamic blocks are expanded first, with their
+// content interpolated using the variables vs.
+func (r *RawConfig) interpolate(
+	fn interpolationWalkerFunc, vs map[string]ast.Variable) error {
 	config, err := copystructure.Copy(r.Raw)
 	if err != nil {
 		return err
 	}
 	r.config = config.(map[string]interface{})
 
+	unknownKeys, err := expandDynamicBlocks(r.config, vs)
+	if err != nil {
+		return err
+	}
+
 	w := &interpolationWalker{F: fn, Replace: true}
 	err = reflectwalk.Walk(r.config, w)
 	if err != nil {
 		return err
 	}
 
-	r.unknownKeys = w.unknownKeys
+	r.unknownKeys = append(w.unknownKeys, unknownKeys...)
+	r.nullKeys = w.nullKeys
 	return nil
 }
 
@@ -210,6 +232,12 @@ func (r *RawConfig) UnknownKeys() []string {
 	return r.unknownKeys
 }
 
+// NullKeys returns the keys of the configuration that were removed
+// because their interpolations resulted in null.
+func (r *RawConfig) NullKeys() []string {
+	return r.nullKeys
+}
+
 // See GobEncode
 func (r *RawConfig) GobDecode(b []byte) error {
 	var data gobRawConfig
@@ -247,7 +275,36 @@ type gobRawConfig struct {
 	Raw map[string]interface{}
 }
 
+// interpolationResultString converts the result of evaluating an
+// interpolation into the string stored in the configuration. Lists are
+// joined with InterpSplitDelim so that they're split again if they're
+// within a slice.
+func interpolationResultString(v interface{}, t ast.Type) string {
+	switch t {
+	case ast.TypeList:
+		return strings.Join(listStrings(v), InterpSplitDelim)
+	case ast.TypeNull:
+		return NullVariableValue
+	default:
+		return v.(string)
+	}
+}
+
 // langEvalConfig returns the evaluation configuration we use to execute.
+// langEvalFunc returns an interpolationWalkerFunc that evaluates
+// interpolations with the given variables.
+func langEvalFunc(vs map[string]ast.Variable) interpolationWalkerFunc {
+	config := langEvalConfig(vs)
+	return func(root ast.Node) (string, error) {
+		out, t, err := lang.Eval(root, config)
+		if err != nil {
+			return "", err
+		}
+
+		return interpolationResultString(out, t), nil
+	}
+}
+
 func langEvalConfig(vs map[string]ast.Variable) *lang.EvalConfig {
 	funcMap := make(map[string]ast.Function)
 	for k, v := range Funcs {
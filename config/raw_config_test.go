@@ -114,6 +114,76 @@ func TestRawConfig_double(t *testing.T) {
 	}
 }
 
+func TestRawConfig_list(t *testing.T) {
+	raw := map[string]interface{}{
+		"foo": []interface{}{"${var.bar}", "qux"},
+	}
+
+	rc, err := NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vars := map[string]ast.Variable{
+		"var.bar": ast.Variable{
+			Value: []ast.Variable{
+				ast.Variable{Value: "a,b", Type: ast.TypeString},
+				ast.Variable{Value: "c", Type: ast.TypeString},
+			},
+			Type: ast.TypeList,
+		},
+	}
+	if err := rc.Interpolate(vars); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := rc.Config()
+	expected := map[string]interface{}{
+		"foo": []interface{}{"a,b", "c", "qux"},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestRawConfig_for(t *testing.T) {
+	raw := map[string]interface{}{
+		"foo": []interface{}{
+			`${[for i, ip in var.ips : ip if element(var.roles, i) == "db"]}`,
+		},
+	}
+
+	rc, err := NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vars := map[string]ast.Variable{
+		"var.ips": ast.Variable{
+			Value: "10.0.0.1" + InterpSplitDelim + "10.0.0.2" +
+				InterpSplitDelim + "10.0.0.3",
+			Type: ast.TypeString,
+		},
+		"var.roles": ast.Variable{
+			Value: "db" + InterpSplitDelim + "web" + InterpSplitDelim + "db",
+			Type:  ast.TypeString,
+		},
+	}
+	if err := rc.Interpolate(vars); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := rc.Config()
+	expected := map[string]interface{}{
+		"foo": []interface{}{"10.0.0.1", "10.0.0.3"},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestRawConfig_merge(t *testing.T) {
 	raw1 := map[string]interface{}{
 		"foo": "${var.foo}",
@@ -195,6 +265,51 @@ func TestRawConfig_merge(t *testing.T) {
 	}
 }
 
+func TestRawConfig_null(t *testing.T) {
+	raw := map[string]interface{}{
+		"foo": `${var.bar == "" ? null : var.bar}`,
+		"baz": "${null}",
+		"qux": "quux",
+	}
+
+	rc, err := NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	vars := map[string]ast.Variable{
+		"var.bar": ast.Variable{
+			Value: "",
+			Type:  ast.TypeString,
+		},
+	}
+	if err := rc.Interpolate(vars); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := rc.Config()
+	expected := map[string]interface{}{
+		"qux": "quux",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if len(rc.UnknownKeys()) > 0 {
+		t.Fatalf("bad: %#v", rc.UnknownKeys())
+	}
+
+	// Null can't be a list element
+	rc, err = NewRawConfig(map[string]interface{}{
+		"foo": []interface{}{"${null}"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := rc.Interpolate(nil); err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func TestRawConfig_syntax(t *testing.T) {
 	raw := map[string]interface{}{
 		"foo": "${var",
@@ -0,0 +1,7 @@
+data "aws_ami" "ubuntu" {
+    name = "ubuntu"
+
+    provisioner "shell" {
+        path = "foo"
+    }
+}
@@ -0,0 +1,6 @@
+resource "aws_instance" "web" {
+    user_data = <<EOF
+#!/bin/bash
+echo "hello ${var.name}" > /tmp/hello
+EOF
+}
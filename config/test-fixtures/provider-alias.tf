@@ -0,0 +1,13 @@
+provider "aws" {
+    region = "us-east-1"
+}
+
+provider "aws" {
+    alias = "west"
+    region = "us-west-2"
+}
+
+resource "aws_instance" "web" {
+    provider = "aws.west"
+    ami = "foo"
+}
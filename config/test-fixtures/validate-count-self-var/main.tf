@@ -0,0 +1,3 @@
+resource "aws_instance" "web" {
+    count = "${aws_instance.web.0.bar}"
+}
@@ -0,0 +1,8 @@
+locals {
+    name = "web-${count.index}"
+}
+
+resource "aws_instance" "web" {
+    count = 2
+    name  = "${local.name}"
+}
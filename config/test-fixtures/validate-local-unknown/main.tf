@@ -0,0 +1,7 @@
+locals {
+    name = "web"
+}
+
+resource "aws_instance" "web" {
+    name = "${local.nope}"
+}
@@ -0,0 +1,14 @@
+variable "env" {}
+
+locals {
+    name = "web-${var.env}"
+    tags = "${local.name}-tags"
+}
+
+resource "aws_instance" "web" {
+    tags = "${local.tags}"
+}
+
+output "name" {
+    value = "${local.name}"
+}
@@ -0,0 +1,8 @@
+module "foo" {
+    source = "./foo"
+    count  = 2
+}
+
+resource "aws_instance" "web" {
+    name = "${module.foo.name}"
+}
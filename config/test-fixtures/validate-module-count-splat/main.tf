@@ -0,0 +1,9 @@
+module "foo" {
+    source = "./foo"
+    count  = 2
+}
+
+resource "aws_instance" "web" {
+    names = "${join(",", module.foo.*.name)}"
+    first = "${module.foo.0.name}"
+}
@@ -0,0 +1,8 @@
+variable "count" {
+    default = "2"
+}
+
+module "foo" {
+    source = "./foo"
+    count  = "${var.count}"
+}
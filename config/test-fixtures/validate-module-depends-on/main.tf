@@ -0,0 +1,10 @@
+resource "aws_vpc" "main" {}
+
+module "network" {
+    source = "./network"
+}
+
+module "app" {
+    source     = "./app"
+    depends_on = ["aws_vpc.main", "module.network"]
+}
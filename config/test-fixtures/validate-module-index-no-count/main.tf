@@ -0,0 +1,7 @@
+module "foo" {
+    source = "./foo"
+}
+
+resource "aws_instance" "web" {
+    name = "${module.foo.0.name}"
+}
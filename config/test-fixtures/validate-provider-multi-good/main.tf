@@ -0,0 +1,12 @@
+provider "aws" {
+    region = "us-east-1"
+}
+
+provider "aws" {
+    alias = "west"
+    region = "us-west-2"
+}
+
+resource "aws_instance" "web" {
+    provider = "aws.west"
+}
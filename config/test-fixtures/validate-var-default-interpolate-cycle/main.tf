@@ -0,0 +1,7 @@
+variable "foo" {
+  default = "${var.bar}"
+}
+
+variable "bar" {
+  default = "${var.foo}"
+}
@@ -0,0 +1,13 @@
+variable "env" {}
+
+variable "region" {
+  default = "us-east-1"
+}
+
+variable "prefix" {
+  default = "${var.env}-${var.region}"
+}
+
+variable "name" {
+  default = "${upper(var.prefix)}"
+}
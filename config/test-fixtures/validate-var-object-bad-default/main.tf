@@ -0,0 +1,10 @@
+variable "subnet" {
+  type {
+    cidr_block = "string"
+    public     = "bool"
+  }
+
+  default {
+    public = "maybe"
+  }
+}
@@ -0,0 +1,12 @@
+variable "subnet" {
+  type {
+    cidr_block = "string"
+    public     = "bool"
+    name       = "optional(string)"
+  }
+
+  default {
+    cidr_block = "10.0.1.0/24"
+    public     = "true"
+  }
+}
@@ -0,0 +1,8 @@
+variable "cidr" {
+  validation {
+    condition     = "${var.cidr != var.other}"
+    error_message = "cidr must differ from other"
+  }
+}
+
+variable "other" {}
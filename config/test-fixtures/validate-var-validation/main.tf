@@ -0,0 +1,8 @@
+variable "cidr" {
+  default = "10.0.0.0/16"
+
+  validation {
+    condition     = "${length(regexall("^[0-9.]+/[0-9]+$", var.cidr)) > 0}"
+    error_message = "cidr must be in CIDR notation"
+  }
+}
@@ -0,0 +1,130 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError is the error returned by Validate for each cycle in the
+// graph.
+type CycleError struct {
+	// Path is the vertices of the cycle in order: each vertex depends on
+	// the next one, and the last vertex depends on the first. A vertex
+	// that depends on itself is a Path of just that vertex.
+	Path []Vertex
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Path) == 1 {
+		return fmt.Sprintf("Self reference: %s", VertexName(e.Path[0]))
+	}
+
+	names := make([]string, 0, len(e.Path)+1)
+	for _, v := range e.Path {
+		names = append(names, VertexName(v))
+	}
+	names = append(names, names[0])
+
+	return fmt.Sprintf("Cycle: %s", strings.Join(names, " -> "))
+}
+
+// Edges returns the edges that make up the cycle, in the order of Path.
+// The last edge goes from the last vertex back to the first.
+func (e *CycleError) Edges() []Edge {
+	result := make([]Edge, len(e.Path))
+	for i, v := range e.Path {
+		result[i] = BasicEdge(v, e.Path[(i+1)%len(e.Path)])
+	}
+
+	return result
+}
+
+// Cycles returns the cycles in the graph, sorted by their error message.
+// For each strongly connected component, this is the shortest cycle
+// through the vertex with the lowest name, so that the same graph always
+// reports the same cycles.
+func (g *AcyclicGraph) Cycles() []*CycleError {
+	var result []*CycleError
+	for _, scc := range StronglyConnected(&g.Graph) {
+		if len(scc) > 1 {
+			result = append(result, &CycleError{Path: cyclePath(&g.Graph, scc)})
+		}
+	}
+
+	for _, e := range g.Edges() {
+		if e.Source() == e.Target() {
+			result = append(result, &CycleError{Path: []Vertex{e.Source()}})
+		}
+	}
+
+	sort.Sort(byCycleError(result))
+	return result
+}
+
+// cyclePath returns the shortest path from the first vertex of the strongly
+// connected component scc, by name, back to itself.
+func cyclePath(g *Graph, scc []Vertex) []Vertex {
+	component := new(Set)
+	for _, v := range scc {
+		component.Add(v)
+	}
+
+	sorted := make([]Vertex, len(scc))
+	copy(sorted, scc)
+	sort.Sort(byVertexName(sorted))
+	start := sorted[0]
+
+	// Breadth-first search within the component. prev tracks how we got
+	// to each vertex so we can build the path once we're back at start.
+	prev := make(map[Vertex]Vertex)
+	queue := []Vertex{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+
+		targets := make([]Vertex, 0, g.DownEdges(v).Len())
+		for _, raw := range g.DownEdges(v).List() {
+			targets = append(targets, raw.(Vertex))
+		}
+		sort.Sort(byVertexName(targets))
+
+		for _, t := range targets {
+			if !component.Include(t) {
+				continue
+			}
+
+			if t == start {
+				path := []Vertex{v}
+				for v != start {
+					v = prev[v]
+					path = append(path, v)
+				}
+
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+
+				return path
+			}
+
+			if _, ok := prev[t]; ok {
+				continue
+			}
+
+			prev[t] = v
+			queue = append(queue, t)
+		}
+	}
+
+	// Every vertex of a strongly connected component can reach every
+	// other, so this is unreachable for a component with a cycle.
+	return sorted
+}
+
+// byCycleError implements sort.Interface to sort cycles by their message.
+type byCycleError []*CycleError
+
+func (s byCycleError) Len() int           { return len(s) }
+func (s byCycleError) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byCycleError) Less(i, j int) bool { return s[i].Error() < s[j].Error() }
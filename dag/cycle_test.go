@@ -0,0 +1,77 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAcyclicGraphCycles(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(4, 1))
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 1))
+	g.Connect(BasicEdge(4, 4))
+
+	cycles := g.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+
+	expected := []string{
+		"Cycle: 1 -> 2 -> 3 -> 1",
+		"Self reference: 4",
+	}
+	for i, c := range cycles {
+		if c.Error() != expected[i] {
+			t.Fatalf("%d: bad: %s", i, c.Error())
+		}
+	}
+
+	edges := cycles[0].Edges()
+	actual := make([][2]Vertex, len(edges))
+	for i, e := range edges {
+		actual[i] = [2]Vertex{e.Source(), e.Target()}
+	}
+	expectedEdges := [][2]Vertex{{1, 2}, {2, 3}, {3, 1}}
+	if !reflect.DeepEqual(actual, expectedEdges) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestAcyclicGraphCycles_shortest(t *testing.T) {
+	var g AcyclicGraph
+	g.Add("a")
+	g.Add("b")
+	g.Add("c")
+	g.Add("d")
+	g.Connect(BasicEdge("a", "b"))
+	g.Connect(BasicEdge("b", "c"))
+	g.Connect(BasicEdge("c", "a"))
+	g.Connect(BasicEdge("a", "d"))
+	g.Connect(BasicEdge("d", "a"))
+
+	cycles := g.Cycles()
+	if len(cycles) != 1 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+
+	if actual := cycles[0].Error(); actual != "Cycle: a -> d -> a" {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestAcyclicGraphCycles_none(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(1, 2))
+
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Fatalf("bad: %#v", cycles)
+	}
+}
@@ -2,8 +2,8 @@ package dag
 
 import (
 	"fmt"
-	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -17,6 +17,54 @@ type AcyclicGraph struct {
 // WalkFunc is the callback used for walking the graph.
 type WalkFunc func(Vertex) error
 
+// WalkOpts are the options for walking the graph with WalkWithOpts.
+type WalkOpts struct {
+	// Parallelism is the maximum number of vertices whose callback is
+	// running at the same time. If this is zero, there is no limit and
+	// every vertex is visited as soon as its dependencies are done.
+	Parallelism int
+
+	// StopCh, if non-nil, stops the walk when it is closed. Vertices
+	// that haven't started yet are skipped, and the walk returns once the
+	// callbacks already running have finished. Skipped vertices aren't
+	// reported as errors.
+	StopCh <-chan struct{}
+
+	// Hook, if non-nil, is notified as each vertex is visited or skipped.
+	Hook WalkHook
+}
+
+// WalkHook is notified of the progress of a walk, such as to report it
+// to the user or to time each vertex. Vertices are visited in parallel,
+// so the methods must be safe to call concurrently.
+type WalkHook interface {
+	// VertexStarted is called right before the callback for a vertex.
+	VertexStarted(Vertex)
+
+	// VertexFinished is called after the callback for a vertex returns,
+	// with the error it returned, if any.
+	VertexFinished(Vertex, error)
+
+	// VertexSkipped is called for a vertex that isn't visited because
+	// one of its dependencies failed or was skipped, or because the
+	// walk was stopped.
+	VertexSkipped(Vertex)
+}
+
+// Subgraph is like Graph.Subgraph, but returns an AcyclicGraph.
+func (g *AcyclicGraph) Subgraph(f func(Vertex) bool, opts *SubgraphOpts) *AcyclicGraph {
+	result := new(AcyclicGraph)
+	g.Graph.subgraphTo(&result.Graph, f, opts)
+	return result
+}
+
+// Copy returns an independent copy of the graph. See Graph.Copy.
+func (g *AcyclicGraph) Copy() *AcyclicGraph {
+	result := new(AcyclicGraph)
+	g.Graph.copyTo(&result.Graph)
+	return result
+}
+
 // Root returns the root of the DAG, or an error.
 //
 // Complexity: O(V)
@@ -41,50 +89,134 @@ func (g *AcyclicGraph) Root() (Vertex, error) {
 }
 
 // Validate validates the DAG. A DAG is valid if it has a single root
-// with no cycles.
+// with no cycles. Each cycle is reported as a *CycleError.
 func (g *AcyclicGraph) Validate() error {
 	if _, err := g.Root(); err != nil {
 		return err
 	}
 
-	// Look for cycles of more than 1 component
 	var err error
-	var cycles [][]Vertex
-	for _, cycle := range StronglyConnected(&g.Graph) {
-		if len(cycle) > 1 {
-			cycles = append(cycles, cycle)
-		}
+	for _, cycle := range g.Cycles() {
+		err = multierror.Append(err, cycle)
 	}
-	if len(cycles) > 0 {
-		for _, cycle := range cycles {
-			cycleStr := make([]string, len(cycle))
-			for j, vertex := range cycle {
-				cycleStr[j] = VertexName(vertex)
-			}
 
-			err = multierror.Append(err, fmt.Errorf(
-				"Cycle: %s", strings.Join(cycleStr, ", ")))
+	return err
+}
+
+// TransitiveReduction performs the transitive reduction of the graph in
+// place. The transitive reduction has as few edges as possible while
+// keeping the same reachability as the original graph. For example, if
+// A depends on B, B depends on C, and A also depends on C directly, the
+// edge from A to C is removed since A already depends on C through B.
+//
+// The graph must be valid for this to behave properly. If Validate
+// returns an error, the result is undefined.
+//
+// Complexity: O(V(V+E))
+func (g *AcyclicGraph) TransitiveReduction() {
+	for _, u := range g.Vertices() {
+		uTargets := g.DownEdges(u)
+		if uTargets.Len() < 2 {
+			continue
+		}
+
+		// Any direct dependency of u that another dependency of u also
+		// depends on is redundant.
+		deps := make([]Vertex, 0, uTargets.Len())
+		for _, raw := range uTargets.List() {
+			deps = append(deps, raw.(Vertex))
+		}
+		for _, raw := range g.Ancestors(deps...).List() {
+			if uTargets.Include(raw) {
+				g.RemoveEdge(BasicEdge(u, raw.(Vertex)))
+			}
 		}
 	}
+}
+
+// Ancestors returns the set of every vertex that the given vertices depend
+// on, directly or through other vertices. The given vertices themselves
+// are only included if another one of them depends on them.
+//
+// Complexity: O(V+E)
+func (g *AcyclicGraph) Ancestors(vs ...Vertex) *Set {
+	return g.reachable(vs, g.DownEdges)
+}
 
-	// Look for cycles to self
-	for _, e := range g.Edges() {
-		if e.Source() == e.Target() {
-			err = multierror.Append(err, fmt.Errorf(
-				"Self reference: %s", VertexName(e.Source())))
+// Descendants returns the set of every vertex that depends on the given
+// vertices, directly or through other vertices. This answers what would
+// be affected by a change to them. The given vertices themselves are only
+// included if another one of them depends on them.
+//
+// Complexity: O(V+E)
+func (g *AcyclicGraph) Descendants(vs ...Vertex) *Set {
+	return g.reachable(vs, g.UpEdges)
+}
+
+func (g *AcyclicGraph) reachable(vs []Vertex, next func(Vertex) *Set) *Set {
+	var start []Vertex
+	for _, v := range vs {
+		for _, raw := range next(v).List() {
+			start = append(start, raw.(Vertex))
 		}
 	}
 
-	return err
+	result := new(Set)
+	g.Graph.depthFirstWalk(start, next, func(v Vertex) error {
+		result.Add(v)
+		return nil
+	})
+
+	return result
 }
 
 // Walk walks the graph, calling your callback as each node is visited.
 // This will walk nodes in parallel if it can. Because the walk is done
 // in parallel, the error returned will be a multierror.
+//
+// If the callback for a vertex returns an error, everything that depends
+// on it is skipped, transitively: a vertex is skipped if any of its
+// dependencies failed or were skipped themselves.
 func (g *AcyclicGraph) Walk(cb WalkFunc) error {
+	return g.WalkWithOpts(cb, nil)
+}
+
+// WalkWithOpts is like Walk but with the given options. If opts is nil,
+// this is the same as Walk.
+func (g *AcyclicGraph) WalkWithOpts(cb WalkFunc, opts *WalkOpts) error {
+	if opts == nil {
+		opts = new(WalkOpts)
+	}
+
+	// The semaphore limiting how many callbacks run at once, if any
+	var sem chan struct{}
+	if opts.Parallelism > 0 {
+		sem = make(chan struct{}, opts.Parallelism)
+	}
+
 	// Cache the vertices since we use it multiple times
 	vertices := g.Vertices()
 
+	// The graph must not be modified while we walk it, since the walk
+	// is based on the vertices and edges as they are now. If it is,
+	// nothing new is started and the walk returns an error.
+	version := atomic.LoadUint32(&g.version)
+	var modifiedOnce sync.Once
+	var modifiedErr error
+	checkModified := func(v Vertex) bool {
+		if !g.modifiedSince(version) {
+			return false
+		}
+
+		modifiedOnce.Do(func() {
+			modifiedErr = fmt.Errorf(
+				"graph was modified while walking %s: vertices "+
+					"and edges can't be added or removed during a walk",
+				VertexName(v))
+		})
+		return true
+	}
+
 	// Build the waitgroup that signals when we're done
 	var wg sync.WaitGroup
 	wg.Add(len(vertices))
@@ -145,12 +277,51 @@ func (g *AcyclicGraph) Walk(cb WalkFunc) error {
 			defer wg.Done()
 
 			var err error
-			if ready := <-readyCh; ready {
-				err = cb(v)
+			ready := <-readyCh
+			if ready {
+				if sem != nil {
+					sem <- struct{}{}
+				}
+
+				// Don't start anything new if we've been stopped. The
+				// vertex counts as failed so that its dependents are
+				// skipped as well.
+				select {
+				case <-opts.StopCh:
+					ready = false
+				default:
+					if checkModified(v) {
+						ready = false
+						break
+					}
+
+					if opts.Hook != nil {
+						opts.Hook.VertexStarted(v)
+					}
+
+					err = cb(v)
+
+					if opts.Hook != nil {
+						opts.Hook.VertexFinished(v, err)
+					}
+
+					checkModified(v)
+				}
+
+				if sem != nil {
+					<-sem
+				}
+			}
+
+			if !ready && opts.Hook != nil {
+				opts.Hook.VertexSkipped(v)
 			}
 
 			errLock.Lock()
 			defer errLock.Unlock()
+			if !ready {
+				errMap[v] = true
+			}
 			if err != nil {
 				errMap[v] = true
 				errs = multierror.Append(errs, err)
@@ -159,5 +330,54 @@ func (g *AcyclicGraph) Walk(cb WalkFunc) error {
 	}
 
 	<-doneCh
+	if modifiedErr != nil {
+		errs = multierror.Append(errs, modifiedErr)
+	}
+
 	return errs
 }
+
+// WalkSubset walks only the given target vertices and everything they
+// depend on, calling your callback as each node is visited. Vertices
+// outside of that set are never visited. The walk otherwise behaves
+// exactly like Walk: it is done in parallel where possible and the
+// error returned will be a multierror.
+func (g *AcyclicGraph) WalkSubset(targets []Vertex, cb WalkFunc) error {
+	return g.WalkSubsetWithOpts(targets, cb, nil)
+}
+
+// WalkSubsetWithOpts is like WalkSubset but with the given options, the
+// same as WalkWithOpts. If opts is nil, this is the same as WalkSubset.
+func (g *AcyclicGraph) WalkSubsetWithOpts(
+	targets []Vertex, cb WalkFunc, opts *WalkOpts) error {
+	// Build the subgraph of the targets and all their dependencies,
+	// then walk it.
+	set := new(Set)
+	for _, v := range targets {
+		set.Add(v)
+	}
+
+	match := func(v Vertex) bool {
+		return set.Include(v)
+	}
+
+	sub := g.Subgraph(match, &SubgraphOpts{Ancestors: true})
+	return sub.WalkWithOpts(cb, opts)
+}
+
+// DepthFirstWalk does a depth-first walk of the graph starting from the
+// vertices in start, following each vertex to the vertices it depends on.
+// Each vertex is visited once, before its dependencies, and dependencies
+// are visited in order of VertexName so the walk is deterministic.
+//
+// Unlike Walk, this is done serially. If the callback returns an error,
+// the walk stops and that error is returned.
+func (g *AcyclicGraph) DepthFirstWalk(start []Vertex, cb WalkFunc) error {
+	return g.Graph.depthFirstWalk(start, g.DownEdges, cb)
+}
+
+// ReverseDepthFirstWalk is like DepthFirstWalk, except it follows each
+// vertex to the vertices that depend on it.
+func (g *AcyclicGraph) ReverseDepthFirstWalk(start []Vertex, cb WalkFunc) error {
+	return g.Graph.depthFirstWalk(start, g.UpEdges, cb)
+}
@@ -3,8 +3,11 @@ package dag
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestAcyclicGraphRoot(t *testing.T) {
@@ -87,6 +90,207 @@ func TestAcyclicGraphValidate_cycleSelf(t *testing.T) {
 	}
 }
 
+func TestAcyclicGraphTransitiveReduction(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(1, 4))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(3, 4))
+
+	g.TransitiveReduction()
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testGraphTransitiveReductionStr)
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestAcyclicGraphTransitiveReduction_diamond(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(1, 4))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(3, 4))
+
+	g.TransitiveReduction()
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testGraphTransitiveReductionDiamondStr)
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+}
+
+func TestAcyclicGraphAncestors(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(5, 4))
+
+	actual := testSetInts(g.Ancestors(2))
+	if !reflect.DeepEqual(actual, []int{3, 4}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	actual = testSetInts(g.Ancestors(1, 3))
+	if !reflect.DeepEqual(actual, []int{2, 3, 4}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	if s := g.Ancestors(4); s.Len() != 0 {
+		t.Fatalf("bad: %#v", s.List())
+	}
+}
+
+func TestAcyclicGraphDescendants(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(5, 4))
+
+	actual := testSetInts(g.Descendants(3))
+	if !reflect.DeepEqual(actual, []int{1, 2}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	actual = testSetInts(g.Descendants(4))
+	if !reflect.DeepEqual(actual, []int{1, 2, 3, 5}) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	if s := g.Descendants(1); s.Len() != 0 {
+		t.Fatalf("bad: %#v", s.List())
+	}
+}
+
+func TestAcyclicGraphDepthFirstWalk(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(5, 3))
+
+	var visits []Vertex
+	err := g.DepthFirstWalk([]Vertex{1}, func(v Vertex) error {
+		visits = append(visits, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []Vertex{1, 2, 4, 3}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphDepthFirstWalk_error(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+
+	var visits []Vertex
+	err := g.DepthFirstWalk([]Vertex{1}, func(v Vertex) error {
+		visits = append(visits, v)
+		if v == 2 {
+			return fmt.Errorf("error")
+		}
+
+		return nil
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	expected := []Vertex{1, 2}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphReverseDepthFirstWalk(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(5, 3))
+
+	var visits []Vertex
+	err := g.ReverseDepthFirstWalk([]Vertex{4, 5}, func(v Vertex) error {
+		visits = append(visits, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []Vertex{4, 2, 1, 3, 5}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphCopy(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(2, 1))
+
+	c := g.Copy()
+	c.Add(3)
+	c.Connect(BasicEdge(3, 2))
+	if err := c.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if g.HasVertex(3) {
+		t.Fatal("original should not have vertex")
+	}
+	if root, err := g.Root(); err != nil || root != 2 {
+		t.Fatalf("bad: %#v %s", root, err)
+	}
+}
+
 func TestAcyclicGraphWalk(t *testing.T) {
 	var g AcyclicGraph
 	g.Add(1)
@@ -156,3 +360,447 @@ func TestAcyclicGraphWalk_error(t *testing.T) {
 
 	t.Fatalf("bad: %#v", visits)
 }
+
+func TestAcyclicGraphWalk_errorTransitive(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(2, 1))
+
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.Walk(func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+		visits = append(visits, v)
+
+		if v == 1 {
+			return fmt.Errorf("error")
+		}
+
+		return nil
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	// 3 is skipped since 2 was skipped because 1 failed
+	expected := []Vertex{1}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalkWithOpts_hookSkippedTransitive(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(2, 1))
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(4, 3))
+
+	hook := new(testWalkHook)
+	err := g.WalkWithOpts(func(v Vertex) error {
+		if v == 1 {
+			return fmt.Errorf("error")
+		}
+
+		return nil
+	}, &WalkOpts{Hook: hook})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	// Everything after 1 is skipped, not just what depends on it directly
+	actual := strings.Join(hook.Sorted(), "\n")
+	expected := strings.TrimSpace(testWalkHookSkippedTransitiveStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestAcyclicGraphWalkWithOpts_parallelism(t *testing.T) {
+	var g AcyclicGraph
+	for i := 0; i < 20; i++ {
+		g.Add(i)
+	}
+
+	var lock sync.Mutex
+	var running, max, visits int
+	err := g.WalkWithOpts(func(v Vertex) error {
+		lock.Lock()
+		running++
+		visits++
+		if running > max {
+			max = running
+		}
+		lock.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return nil
+	}, &WalkOpts{Parallelism: 3})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if visits != 20 {
+		t.Fatalf("bad: %d", visits)
+	}
+	if max > 3 {
+		t.Fatalf("too many running at once: %d", max)
+	}
+}
+
+func TestAcyclicGraphWalkWithOpts_stop(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(2, 1))
+
+	stopCh := make(chan struct{})
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.WalkWithOpts(func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+		visits = append(visits, v)
+
+		// Stop the walk after the first vertex
+		close(stopCh)
+		return nil
+	}, &WalkOpts{StopCh: stopCh})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []Vertex{1}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalk_modified(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(2, 1))
+
+	var visits []Vertex
+	err := g.Walk(func(v Vertex) error {
+		visits = append(visits, v)
+		if v == 1 {
+			g.Add(3)
+		}
+
+		return nil
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "graph was modified while walking 1") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	expected := []Vertex{1}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalk_modifiedOutside(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(2, 1))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var visits []Vertex
+	var lock sync.Mutex
+	errCh := make(chan error)
+	go func() {
+		errCh <- g.Walk(func(v Vertex) error {
+			lock.Lock()
+			visits = append(visits, v)
+			lock.Unlock()
+
+			if v == 1 {
+				close(started)
+				<-release
+			}
+
+			return nil
+		})
+	}()
+
+	// Modify the graph from outside of any callback
+	<-started
+	g.Add(3)
+	close(release)
+
+	err := <-errCh
+	if err == nil {
+		t.Fatal("should error")
+	}
+	if !strings.Contains(err.Error(), "graph was modified") {
+		t.Fatalf("bad: %s", err)
+	}
+
+	expected := []Vertex{1}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalk_unmodified(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(2, 1))
+
+	// Adding what is already there and removing what isn't there
+	// doesn't change the graph, so the walk goes on.
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.Walk(func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+		visits = append(visits, v)
+
+		g.Add(v)
+		g.Connect(BasicEdge(2, 1))
+		g.RemoveEdge(BasicEdge(1, 2))
+		g.Remove(42)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []Vertex{1, 2}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalkWithOpts_hook(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(2, 1))
+	g.Connect(BasicEdge(4, 1))
+
+	hook := new(testWalkHook)
+	err := g.WalkWithOpts(func(v Vertex) error {
+		if v == 2 {
+			return fmt.Errorf("error")
+		}
+
+		return nil
+	}, &WalkOpts{Hook: hook})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	actual := strings.Join(hook.Sorted(), "\n")
+	expected := strings.TrimSpace(testWalkHookStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestAcyclicGraphWalkSubset(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(5, 4))
+	g.Connect(BasicEdge(5, 3))
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(4, 1))
+
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.WalkSubset([]Vertex{3}, func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+		visits = append(visits, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []Vertex{2, 3}
+	if !reflect.DeepEqual(visits, expected) {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+func TestAcyclicGraphWalkSubsetWithOpts_parallelism(t *testing.T) {
+	var g AcyclicGraph
+	g.Add("target")
+	for i := 0; i < 20; i++ {
+		g.Add(i)
+		g.Connect(BasicEdge("target", i))
+	}
+	g.Add("other")
+
+	var lock sync.Mutex
+	var running, max, visits int
+	err := g.WalkSubsetWithOpts([]Vertex{"target"}, func(v Vertex) error {
+		lock.Lock()
+		running++
+		visits++
+		if running > max {
+			max = running
+		}
+		lock.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return nil
+	}, &WalkOpts{Parallelism: 3})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if visits != 21 {
+		t.Fatalf("bad: %d", visits)
+	}
+	if max > 3 {
+		t.Fatalf("too many running at once: %d", max)
+	}
+}
+
+func TestAcyclicGraphWalkSubset_error(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(3, 1))
+	g.Connect(BasicEdge(2, 1))
+
+	var visits []Vertex
+	var lock sync.Mutex
+	err := g.WalkSubset([]Vertex{3}, func(v Vertex) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if v == 1 {
+			return fmt.Errorf("error")
+		}
+
+		visits = append(visits, v)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	if len(visits) != 0 {
+		t.Fatalf("bad: %#v", visits)
+	}
+}
+
+const testGraphTransitiveReductionStr = `
+1
+  2
+2
+  3
+3
+  4
+4
+`
+
+const testGraphTransitiveReductionDiamondStr = `
+1
+  2
+  3
+2
+  4
+3
+  4
+4
+`
+
+// testWalkHook is a WalkHook that records the calls made to it.
+type testWalkHook struct {
+	sync.Mutex
+	Calls []string
+}
+
+func (h *testWalkHook) VertexStarted(v Vertex) {
+	h.record("started %s", VertexName(v))
+}
+
+func (h *testWalkHook) VertexFinished(v Vertex, err error) {
+	h.record("finished %s: %v", VertexName(v), err)
+}
+
+func (h *testWalkHook) VertexSkipped(v Vertex) {
+	h.record("skipped %s", VertexName(v))
+}
+
+func (h *testWalkHook) record(format string, args ...interface{}) {
+	h.Lock()
+	defer h.Unlock()
+	h.Calls = append(h.Calls, fmt.Sprintf(format, args...))
+}
+
+// Sorted returns the calls sorted, since vertices are walked in parallel.
+func (h *testWalkHook) Sorted() []string {
+	h.Lock()
+	defer h.Unlock()
+	result := make([]string, len(h.Calls))
+	copy(result, h.Calls)
+	sort.Strings(result)
+	return result
+}
+
+const testWalkHookStr = `
+finished 1: <nil>
+finished 2: error
+finished 4: <nil>
+skipped 3
+started 1
+started 2
+started 4
+`
+
+const testWalkHookSkippedTransitiveStr = `
+finished 1: error
+skipped 2
+skipped 3
+skipped 4
+started 1
+`
+
+// testSetInts returns the elements of a set of ints, sorted.
+func testSetInts(s *Set) []int {
+	result := make([]int, 0, s.Len())
+	for _, v := range s.List() {
+		result = append(result, v.(int))
+	}
+	sort.Ints(result)
+
+	return result
+}
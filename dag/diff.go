@@ -0,0 +1,121 @@
+package dag
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// GraphDiff is the difference between two graphs. Vertices from the two
+// graphs are usually different values, such as when the graphs were
+// built separately, so they're compared by VertexName.
+type GraphDiff struct {
+	AddedVertices   []string
+	RemovedVertices []string
+	AddedEdges      []DiffEdge
+	RemovedEdges    []DiffEdge
+}
+
+// DiffEdge is an edge in a GraphDiff, by the names of its vertices.
+type DiffEdge struct {
+	Source string
+	Target string
+}
+
+func (e DiffEdge) String() string {
+	return fmt.Sprintf("%s -> %s", e.Source, e.Target)
+}
+
+// Diff returns the changes to get from graph a to graph b. Everything in
+// the result is sorted, so the same two graphs always give the same diff.
+func Diff(a, b *Graph) *GraphDiff {
+	aVertices, aEdges := diffNames(a)
+	bVertices, bEdges := diffNames(b)
+
+	result := new(GraphDiff)
+	for n := range bVertices {
+		if !aVertices[n] {
+			result.AddedVertices = append(result.AddedVertices, n)
+		}
+	}
+	for n := range aVertices {
+		if !bVertices[n] {
+			result.RemovedVertices = append(result.RemovedVertices, n)
+		}
+	}
+	for e := range bEdges {
+		if !aEdges[e] {
+			result.AddedEdges = append(result.AddedEdges, e)
+		}
+	}
+	for e := range aEdges {
+		if !bEdges[e] {
+			result.RemovedEdges = append(result.RemovedEdges, e)
+		}
+	}
+
+	sort.Strings(result.AddedVertices)
+	sort.Strings(result.RemovedVertices)
+	sort.Sort(byDiffEdge(result.AddedEdges))
+	sort.Sort(byDiffEdge(result.RemovedEdges))
+	return result
+}
+
+// Empty returns true if the graphs are the same.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedVertices) == 0 &&
+		len(d.RemovedVertices) == 0 &&
+		len(d.AddedEdges) == 0 &&
+		len(d.RemovedEdges) == 0
+}
+
+// String outputs the diff with a line for each change, prefixed with "+"
+// for additions and "-" for removals. Vertices come before edges.
+func (d *GraphDiff) String() string {
+	var buf bytes.Buffer
+	for _, n := range d.RemovedVertices {
+		buf.WriteString(fmt.Sprintf("- %s\n", n))
+	}
+	for _, n := range d.AddedVertices {
+		buf.WriteString(fmt.Sprintf("+ %s\n", n))
+	}
+	for _, e := range d.RemovedEdges {
+		buf.WriteString(fmt.Sprintf("- %s\n", e))
+	}
+	for _, e := range d.AddedEdges {
+		buf.WriteString(fmt.Sprintf("+ %s\n", e))
+	}
+
+	return buf.String()
+}
+
+// diffNames returns the set of vertex names and edges by name of g.
+func diffNames(g *Graph) (map[string]bool, map[DiffEdge]bool) {
+	vertices := make(map[string]bool)
+	for _, v := range g.Vertices() {
+		vertices[VertexName(v)] = true
+	}
+
+	edges := make(map[DiffEdge]bool)
+	for _, e := range g.Edges() {
+		edges[DiffEdge{
+			Source: VertexName(e.Source()),
+			Target: VertexName(e.Target()),
+		}] = true
+	}
+
+	return vertices, edges
+}
+
+// byDiffEdge implements sort.Interface to sort edges by source, then target.
+type byDiffEdge []DiffEdge
+
+func (s byDiffEdge) Len() int      { return len(s) }
+func (s byDiffEdge) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDiffEdge) Less(i, j int) bool {
+	if s[i].Source != s[j].Source {
+		return s[i].Source < s[j].Source
+	}
+
+	return s[i].Target < s[j].Target
+}
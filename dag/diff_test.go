@@ -0,0 +1,57 @@
+package dag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	var a Graph
+	a.Add("a")
+	a.Add("b")
+	a.Add("c")
+	a.Connect(BasicEdge("a", "b"))
+	a.Connect(BasicEdge("b", "c"))
+
+	var b Graph
+	b.Add("a")
+	b.Add("b")
+	b.Add("d")
+	b.Connect(BasicEdge("a", "b"))
+	b.Connect(BasicEdge("a", "d"))
+
+	d := Diff(&a, &b)
+	if d.Empty() {
+		t.Fatal("should not be empty")
+	}
+
+	actual := strings.TrimSpace(d.String())
+	expected := strings.TrimSpace(testDiffStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestDiff_same(t *testing.T) {
+	// Graphs with different vertex values but the same names are the same
+	var a Graph
+	a1 := a.Add(&testDotVertex{Label: "1"})
+	a2 := a.Add(&testDotVertex{Label: "2"})
+	a.Connect(BasicEdge(a1, a2))
+
+	var b Graph
+	b1 := b.Add(&testDotVertex{Label: "1"})
+	b2 := b.Add(&testDotVertex{Label: "2"})
+	b.Connect(BasicEdge(b1, b2))
+
+	if d := Diff(&a, &b); !d.Empty() {
+		t.Fatalf("bad:\n\n%s", d)
+	}
+}
+
+const testDiffStr = `
+- c
++ d
+- b -> c
++ a -> d
+`
@@ -0,0 +1,170 @@
+package dag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DotOpts are the options for generating a dot formatted Graph.
+type DotOpts struct {
+	// Verbose draws vertices that don't implement Dotter as plain nodes,
+	// and draws the labels of edges made with LabeledEdge. Otherwise
+	// those vertices are left out, along with their edges.
+	Verbose bool
+
+	// MaxDepth is how many levels of subgraphs are drawn as clusters.
+	// A vertex with a subgraph deeper than this is drawn as a single
+	// node. Zero draws no subgraphs; a negative value draws all of them.
+	MaxDepth int
+}
+
+// Dotter can be implemented by a vertex to draw it in a dot graph. The
+// Dot method is given the name to use for the node and returns the dot
+// node statement, such as `"name" [shape=box];`. If it returns an empty
+// string, the vertex isn't drawn.
+type Dotter interface {
+	Dot(string) string
+}
+
+// DotSubgrapher can be implemented by a vertex that contains a graph of
+// its own, such as a module. When drawn, the vertex and its subgraph are
+// grouped into a cluster, and edges to and from the vertex are drawn to
+// the border of the cluster.
+type DotSubgrapher interface {
+	DotSubgraph() *Graph
+}
+
+// Dot returns the dot formatting of a visual representation of the graph.
+// The output is deterministic: vertices and edges are sorted by name.
+func (g *Graph) Dot(opts *DotOpts) []byte {
+	if opts == nil {
+		opts = new(DotOpts)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph {\n")
+	buf.WriteString("\tcompound = \"true\"\n")
+	buf.WriteString("\tnewrank = \"true\"\n")
+	g.writeDot(&buf, opts, "", 0, "\t")
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// writeDot writes the body of the graph. The names of the nodes start
+// with prefix so that they're unique across subgraphs.
+func (g *Graph) writeDot(
+	buf *bytes.Buffer, opts *DotOpts, prefix string, depth int, indent string) {
+	vertices := g.Vertices()
+	sort.Sort(byVertexName(vertices))
+
+	// Determine the node names of the vertices we draw, and which of
+	// those are drawn as clusters.
+	names := make(map[Vertex]string, len(vertices))
+	clusters := make(map[Vertex]*Graph)
+	for _, v := range vertices {
+		name := prefix + VertexName(v)
+		if d, ok := v.(Dotter); ok {
+			if d.Dot(name) == "" {
+				continue
+			}
+		} else if !opts.Verbose {
+			continue
+		}
+
+		names[v] = name
+		if opts.MaxDepth < 0 || depth < opts.MaxDepth {
+			if sg, ok := v.(DotSubgrapher); ok {
+				if sub := sg.DotSubgraph(); sub != nil {
+					clusters[v] = sub
+				}
+			}
+		}
+	}
+
+	for _, v := range vertices {
+		name, ok := names[v]
+		if !ok {
+			continue
+		}
+
+		sub, ok := clusters[v]
+		if !ok {
+			writeDotNode(buf, v, name, indent)
+			continue
+		}
+
+		buf.WriteString(fmt.Sprintf(
+			"%ssubgraph %q {\n", indent, dotClusterName(name)))
+		buf.WriteString(fmt.Sprintf(
+			"%s\tlabel = %q\n", indent, VertexName(v)))
+		writeDotNode(buf, v, name, indent+"\t")
+		sub.writeDot(buf, opts, name+"/", depth+1, indent+"\t")
+		buf.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	// Draw the edges between the vertices we drew. Edges to and from a
+	// cluster are clipped to its border.
+	for _, v := range vertices {
+		name, ok := names[v]
+		if !ok {
+			continue
+		}
+
+		targets := make([]Vertex, 0, g.DownEdges(v).Len())
+		for _, raw := range g.DownEdges(v).List() {
+			targets = append(targets, raw.(Vertex))
+		}
+		sort.Sort(byVertexName(targets))
+
+		for _, target := range targets {
+			targetName, ok := names[target]
+			if !ok {
+				continue
+			}
+
+			var attrs []string
+			if _, ok := clusters[v]; ok {
+				attrs = append(attrs, fmt.Sprintf(
+					"ltail = %q", dotClusterName(name)))
+			}
+			if _, ok := clusters[target]; ok {
+				attrs = append(attrs, fmt.Sprintf(
+					"lhead = %q", dotClusterName(targetName)))
+			}
+			if opts.Verbose {
+				if label := EdgeLabel(g.Edge(v, target)); label != nil {
+					attrs = append(attrs, fmt.Sprintf(
+						"label = %q", fmt.Sprint(label)))
+				}
+			}
+
+			buf.WriteString(fmt.Sprintf(
+				"%s%q -> %q", indent, name, targetName))
+			if len(attrs) > 0 {
+				buf.WriteString(fmt.Sprintf(
+					" [%s]", strings.Join(attrs, ", ")))
+			}
+			buf.WriteString("\n")
+		}
+	}
+}
+
+func writeDotNode(buf *bytes.Buffer, v Vertex, name, indent string) {
+	d, ok := v.(Dotter)
+	if !ok {
+		buf.WriteString(fmt.Sprintf("%s%q\n", indent, name))
+		return
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(d.Dot(name)))
+	for scanner.Scan() {
+		buf.WriteString(indent + scanner.Text() + "\n")
+	}
+}
+
+func dotClusterName(name string) string {
+	return "cluster_" + name
+}
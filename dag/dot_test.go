@@ -0,0 +1,104 @@
+package dag
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGraphDot(t *testing.T) {
+	var sub Graph
+	sub.Add(&testDotVertex{Label: "c"})
+
+	var g Graph
+	a := g.Add(&testDotVertex{Label: "a"})
+	b := g.Add(&testDotVertex{Label: "b", Sub: &sub})
+	g.Add(42)
+	g.Connect(BasicEdge(a, b))
+
+	cases := []struct {
+		Opts     *DotOpts
+		Expected string
+	}{
+		{nil, testGraphDotStr},
+		{&DotOpts{MaxDepth: -1}, testGraphDotClusterStr},
+		{&DotOpts{Verbose: true}, testGraphDotVerboseStr},
+	}
+
+	for i, tc := range cases {
+		actual := strings.TrimSpace(string(g.Dot(tc.Opts)))
+		expected := strings.TrimSpace(tc.Expected)
+		if actual != expected {
+			t.Fatalf("%d: bad:\n\n%s", i, actual)
+		}
+	}
+}
+
+func TestGraphDot_edgeLabel(t *testing.T) {
+	var g Graph
+	a := g.Add(&testDotVertex{Label: "a"})
+	b := g.Add(&testDotVertex{Label: "b"})
+	g.Connect(LabeledEdge(a, b, "ref"))
+
+	actual := string(g.Dot(&DotOpts{Verbose: true}))
+	if !strings.Contains(actual, `"a" -> "b" [label = "ref"]`) {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+
+	actual = string(g.Dot(nil))
+	if strings.Contains(actual, "ref") {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+type testDotVertex struct {
+	Label string
+	Sub   *Graph
+}
+
+func (v *testDotVertex) Name() string {
+	return v.Label
+}
+
+func (v *testDotVertex) Dot(name string) string {
+	return fmt.Sprintf("%q [label = %q];", name, v.Label)
+}
+
+func (v *testDotVertex) DotSubgraph() *Graph {
+	return v.Sub
+}
+
+const testGraphDotStr = `
+digraph {
+	compound = "true"
+	newrank = "true"
+	"a" [label = "a"];
+	"b" [label = "b"];
+	"a" -> "b"
+}
+`
+
+const testGraphDotClusterStr = `
+digraph {
+	compound = "true"
+	newrank = "true"
+	"a" [label = "a"];
+	subgraph "cluster_b" {
+		label = "b"
+		"b" [label = "b"];
+		"b/c" [label = "c"];
+	}
+	"a" -> "b" [lhead = "cluster_b"]
+}
+`
+
+const testGraphDotVerboseStr = `
+digraph {
+	compound = "true"
+	newrank = "true"
+	"42"
+	"a" [label = "a"];
+	"b" [label = "b"];
+	"a" -> "b"
+}
+`
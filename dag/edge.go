@@ -18,6 +18,30 @@ func BasicEdge(source, target Vertex) Edge {
 	return &basicEdge{S: source, T: target}
 }
 
+// LabeledEdge returns an Edge like BasicEdge that also carries a label.
+// The label can be a value of any type, such as one describing why the
+// edge was made, so that edges of different kinds can be told apart.
+// The label doesn't affect the identity of the edge: it is the same edge
+// as the BasicEdge with the same source and target.
+func LabeledEdge(source, target Vertex, label interface{}) Edge {
+	return &labeledEdge{basicEdge: basicEdge{S: source, T: target}, L: label}
+}
+
+// EdgeLabeler is implemented by edges that carry a label, such as those
+// created with LabeledEdge.
+type EdgeLabeler interface {
+	Label() interface{}
+}
+
+// EdgeLabel returns the label of the edge, or nil if it has none.
+func EdgeLabel(e Edge) interface{} {
+	if l, ok := e.(EdgeLabeler); ok {
+		return l.Label()
+	}
+
+	return nil
+}
+
 // basicEdge is a basic implementation of Edge that has the source and
 // target vertex.
 type basicEdge struct {
@@ -35,3 +59,13 @@ func (e *basicEdge) Source() Vertex {
 func (e *basicEdge) Target() Vertex {
 	return e.T
 }
+
+// labeledEdge is an Edge with a label.
+type labeledEdge struct {
+	basicEdge
+	L interface{}
+}
+
+func (e *labeledEdge) Label() interface{} {
+	return e.L
+}
@@ -24,3 +24,16 @@ func TestBasicEdgeHashcode_pointer(t *testing.T) {
 		t.Fatalf("bad")
 	}
 }
+
+func TestLabeledEdge(t *testing.T) {
+	e := LabeledEdge(1, 2, "foo")
+	if e.Hashcode() != BasicEdge(1, 2).Hashcode() {
+		t.Fatal("should have the same hashcode as the basic edge")
+	}
+	if label := EdgeLabel(e); label != "foo" {
+		t.Fatalf("bad: %#v", label)
+	}
+	if label := EdgeLabel(BasicEdge(1, 2)); label != nil {
+		t.Fatalf("bad: %#v", label)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // Graph is used to represent a dependency graph.
@@ -14,6 +15,10 @@ type Graph struct {
 	downEdges map[Vertex]*Set
 	upEdges   map[Vertex]*Set
 	once      sync.Once
+
+	// version is incremented every time the graph is modified, so that
+	// a walk can detect that the graph changed underneath it.
+	version uint32
 }
 
 // Vertex of the graph.
@@ -48,10 +53,93 @@ func (g *Graph) Edges() []Edge {
 	return result
 }
 
+// Edge returns the edge in the graph from source to target, or nil if
+// there is no such edge. This is how the label of an edge added with
+// LabeledEdge is found again.
+func (g *Graph) Edge(source, target Vertex) Edge {
+	g.once.Do(g.init)
+
+	raw, ok := g.edges.m[BasicEdge(source, target).Hashcode()]
+	if !ok {
+		return nil
+	}
+
+	return raw.(Edge)
+}
+
+// Copy returns an independent copy of the graph. The vertex and edge
+// values themselves are shared, but adding or removing vertices and
+// edges in the copy doesn't affect the original, and vice versa.
+func (g *Graph) Copy() *Graph {
+	result := new(Graph)
+	g.copyTo(result)
+	return result
+}
+
+// SubgraphOpts are the options for Subgraph.
+type SubgraphOpts struct {
+	// Ancestors includes every vertex that the matching vertices depend
+	// on, directly or through other vertices.
+	Ancestors bool
+
+	// Descendants includes every vertex that depends on the matching
+	// vertices, directly or through other vertices.
+	Descendants bool
+}
+
+// Subgraph returns a new graph with only the vertices for which f returns
+// true, along with the edges between them. With opts, the vertices they
+// depend on or that depend on them can be included as well. If opts is
+// nil, only the matching vertices are included. As with Copy, the vertex
+// and edge values are shared with the original graph.
+func (g *Graph) Subgraph(f func(Vertex) bool, opts *SubgraphOpts) *Graph {
+	result := new(Graph)
+	g.subgraphTo(result, f, opts)
+	return result
+}
+
+// VertexCount returns the number of vertices in the graph.
+//
+// Complexity: O(1)
+func (g *Graph) VertexCount() int {
+	return g.vertices.Len()
+}
+
+// EdgeCount returns the number of edges in the graph.
+//
+// Complexity: O(1)
+func (g *Graph) EdgeCount() int {
+	return g.edges.Len()
+}
+
+// HasVertex returns true if the given vertex is in the graph.
+//
+// Complexity: O(1)
+func (g *Graph) HasVertex(v Vertex) bool {
+	g.once.Do(g.init)
+	return g.vertices.Include(v)
+}
+
+// HasEdge returns true if the graph has an edge from the source to the
+// target of the given edge. Like Connect, this compares the vertices of
+// the edge and not the edge value itself.
+//
+// Complexity: O(1)
+func (g *Graph) HasEdge(e Edge) bool {
+	g.once.Do(g.init)
+	s, ok := g.downEdges[e.Source()]
+	return ok && s.Include(e.Target())
+}
+
 // Add adds a vertex to the graph. This is safe to call multiple time with
 // the same Vertex.
 func (g *Graph) Add(v Vertex) Vertex {
 	g.once.Do(g.init)
+	if g.vertices.Include(v) {
+		return v
+	}
+
+	g.modified()
 	g.vertices.Add(v)
 	return v
 }
@@ -59,6 +147,13 @@ func (g *Graph) Add(v Vertex) Vertex {
 // Remove removes a vertex from the graph. This will also remove any
 // edges with this vertex as a source or target.
 func (g *Graph) Remove(v Vertex) Vertex {
+	g.once.Do(g.init)
+	if !g.vertices.Include(v) {
+		return nil
+	}
+
+	g.modified()
+
 	// Delete the vertex itself
 	g.vertices.Delete(v)
 
@@ -82,13 +177,15 @@ func (g *Graph) Replace(original, replacement Vertex) bool {
 		return false
 	}
 
-	// Add our new vertex, then copy all the edges
+	// Add our new vertex, then copy all the edges, keeping their labels
 	g.Add(replacement)
 	for _, target := range g.DownEdges(original).List() {
-		g.Connect(BasicEdge(replacement, target))
+		label := EdgeLabel(g.Edge(original, target))
+		g.Connect(replaceEdge(replacement, target, label))
 	}
 	for _, source := range g.UpEdges(original).List() {
-		g.Connect(BasicEdge(source, replacement))
+		label := EdgeLabel(g.Edge(source, original))
+		g.Connect(replaceEdge(source, replacement, label))
 	}
 
 	// Remove our old vertex, which will also remove all the edges
@@ -101,6 +198,13 @@ func (g *Graph) Replace(original, replacement Vertex) bool {
 func (g *Graph) RemoveEdge(edge Edge) {
 	g.once.Do(g.init)
 
+	// Do we have this at all? If not, there's nothing to remove.
+	if s, ok := g.downEdges[edge.Source()]; !ok || !s.Include(edge.Target()) {
+		return
+	}
+
+	g.modified()
+
 	// Delete the edge from the set
 	g.edges.Delete(edge)
 
@@ -141,6 +245,7 @@ func (g *Graph) Connect(edge Edge) {
 	}
 
 	// Add the edge to the set
+	g.modified()
 	g.edges.Add(edge)
 
 	// Add the down edge
@@ -199,6 +304,108 @@ func (g *Graph) String() string {
 	return buf.String()
 }
 
+// replaceEdge returns an edge for Replace, keeping the label if any.
+func replaceEdge(source, target Vertex, label interface{}) Edge {
+	if label == nil {
+		return BasicEdge(source, target)
+	}
+
+	return LabeledEdge(source, target, label)
+}
+
+func (g *Graph) copyTo(dst *Graph) {
+	for _, v := range g.Vertices() {
+		dst.Add(v)
+	}
+	for _, e := range g.Edges() {
+		dst.Connect(e)
+	}
+}
+
+func (g *Graph) subgraphTo(dst *Graph, f func(Vertex) bool, opts *SubgraphOpts) {
+	if opts == nil {
+		opts = new(SubgraphOpts)
+	}
+
+	var start []Vertex
+	for _, v := range g.Vertices() {
+		if f(v) {
+			start = append(start, v)
+		}
+	}
+
+	include := new(Set)
+	add := func(v Vertex) error {
+		include.Add(v)
+		return nil
+	}
+	for _, v := range start {
+		add(v)
+	}
+	if opts.Ancestors {
+		g.depthFirstWalk(start, g.DownEdges, add)
+	}
+	if opts.Descendants {
+		g.depthFirstWalk(start, g.UpEdges, add)
+	}
+
+	for _, raw := range include.List() {
+		dst.Add(raw.(Vertex))
+	}
+	for _, e := range g.Edges() {
+		if include.Include(e.Source()) && include.Include(e.Target()) {
+			dst.Connect(e)
+		}
+	}
+}
+
+// depthFirstWalk visits the vertices reachable from start by following
+// next, in depth-first order. See AcyclicGraph.DepthFirstWalk.
+func (g *Graph) depthFirstWalk(
+	start []Vertex, next func(Vertex) *Set, cb WalkFunc) error {
+	seen := new(Set)
+
+	// The stack is popped from the end, so everything is pushed in
+	// reverse to visit it in order.
+	stack := make([]Vertex, 0, len(start))
+	for i := len(start) - 1; i >= 0; i-- {
+		stack = append(stack, start[i])
+	}
+
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen.Include(v) {
+			continue
+		}
+		seen.Add(v)
+
+		if err := cb(v); err != nil {
+			return err
+		}
+
+		targets := make([]Vertex, 0, next(v).Len())
+		for _, raw := range next(v).List() {
+			targets = append(targets, raw.(Vertex))
+		}
+		sort.Sort(sort.Reverse(byVertexName(targets)))
+		stack = append(stack, targets...)
+	}
+
+	return nil
+}
+
+// modified records that the graph was modified.
+func (g *Graph) modified() {
+	atomic.AddUint32(&g.version, 1)
+}
+
+// modifiedSince returns true if the graph was modified since it was at
+// the given version.
+func (g *Graph) modifiedSince(version uint32) bool {
+	return atomic.LoadUint32(&g.version) != version
+}
+
 func (g *Graph) init() {
 	g.vertices = new(Set)
 	g.edges = new(Set)
@@ -47,6 +47,110 @@ func TestGraph_remove(t *testing.T) {
 	}
 }
 
+func TestGraph_copy(t *testing.T) {
+	var g Graph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(1, 3))
+
+	c := g.Copy()
+	actual := strings.TrimSpace(c.String())
+	expected := strings.TrimSpace(testGraphBasicStr)
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	// Modify the copy
+	c.Add(4)
+	c.Connect(BasicEdge(2, 4))
+	c.RemoveEdge(BasicEdge(1, 3))
+	c.Remove(2)
+
+	// The original is unchanged
+	actual = strings.TrimSpace(g.String())
+	if actual != expected {
+		t.Fatalf("bad: %s", actual)
+	}
+	if g.HasVertex(4) || !g.HasEdge(BasicEdge(1, 3)) {
+		t.Fatalf("bad: %s", actual)
+	}
+
+	// Modifying the original doesn't change the copy
+	g.Connect(BasicEdge(3, 1))
+	if c.HasEdge(BasicEdge(3, 1)) {
+		t.Fatalf("bad: %s", c.String())
+	}
+}
+
+func TestGraph_counts(t *testing.T) {
+	var g Graph
+	if g.VertexCount() != 0 || g.EdgeCount() != 0 {
+		t.Fatalf("bad: %d %d", g.VertexCount(), g.EdgeCount())
+	}
+
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(3)
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(1, 3))
+	g.Connect(BasicEdge(2, 3))
+	if g.VertexCount() != 3 {
+		t.Fatalf("bad: %d", g.VertexCount())
+	}
+	if g.EdgeCount() != 2 {
+		t.Fatalf("bad: %d", g.EdgeCount())
+	}
+
+	g.RemoveEdge(BasicEdge(2, 3))
+	if g.EdgeCount() != 1 {
+		t.Fatalf("bad: %d", g.EdgeCount())
+	}
+
+	g.Remove(3)
+	if g.VertexCount() != 2 {
+		t.Fatalf("bad: %d", g.VertexCount())
+	}
+	if g.EdgeCount() != 0 {
+		t.Fatalf("bad: %d", g.EdgeCount())
+	}
+}
+
+func TestGraph_hasVertexEdge(t *testing.T) {
+	var g Graph
+	if g.HasVertex(1) {
+		t.Fatal("should not have vertex")
+	}
+	if g.HasEdge(BasicEdge(1, 2)) {
+		t.Fatal("should not have edge")
+	}
+
+	g.Add(1)
+	g.Add(2)
+	g.Connect(BasicEdge(1, 2))
+	if !g.HasVertex(1) || !g.HasVertex(2) {
+		t.Fatal("should have vertices")
+	}
+	if g.HasVertex(3) {
+		t.Fatal("should not have vertex")
+	}
+	if !g.HasEdge(BasicEdge(1, 2)) {
+		t.Fatal("should have edge")
+	}
+	if g.HasEdge(BasicEdge(2, 1)) {
+		t.Fatal("should not have reverse edge")
+	}
+
+	g.Remove(2)
+	if g.HasVertex(2) {
+		t.Fatal("should not have vertex")
+	}
+	if g.HasEdge(BasicEdge(1, 2)) {
+		t.Fatal("should not have edge")
+	}
+}
+
 func TestGraph_replace(t *testing.T) {
 	var g Graph
 	g.Add(1)
@@ -63,6 +167,78 @@ func TestGraph_replace(t *testing.T) {
 	}
 }
 
+func TestGraph_edgeLabel(t *testing.T) {
+	var g Graph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(LabeledEdge(1, 2, "foo"))
+	g.Connect(BasicEdge(2, 3))
+
+	if label := EdgeLabel(g.Edge(1, 2)); label != "foo" {
+		t.Fatalf("bad: %#v", label)
+	}
+	if e := g.Edge(2, 3); e == nil || EdgeLabel(e) != nil {
+		t.Fatalf("bad: %#v", e)
+	}
+	if e := g.Edge(1, 3); e != nil {
+		t.Fatalf("bad: %#v", e)
+	}
+
+	// Replacing a vertex keeps the labels of its edges
+	g.Replace(2, 42)
+	if label := EdgeLabel(g.Edge(1, 42)); label != "foo" {
+		t.Fatalf("bad: %#v", label)
+	}
+
+	// Removing the basic edge removes the labeled edge
+	g.RemoveEdge(BasicEdge(1, 42))
+	if g.Edge(1, 42) != nil || g.EdgeCount() != 1 {
+		t.Fatalf("bad: %s", g.String())
+	}
+}
+
+func TestGraph_subgraph(t *testing.T) {
+	var g Graph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Add(5)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(5, 4))
+
+	match := func(v Vertex) bool {
+		return v == 2 || v == 3
+	}
+
+	cases := []struct {
+		Opts     *SubgraphOpts
+		Expected string
+	}{
+		{nil, testGraphSubgraphStr},
+		{&SubgraphOpts{Ancestors: true}, testGraphSubgraphAncestorsStr},
+		{&SubgraphOpts{Descendants: true}, testGraphSubgraphDescendantsStr},
+	}
+
+	for i, tc := range cases {
+		sub := g.Subgraph(match, tc.Opts)
+
+		actual := strings.TrimSpace(sub.String())
+		expected := strings.TrimSpace(tc.Expected)
+		if actual != expected {
+			t.Fatalf("%d: bad: %s", i, actual)
+		}
+	}
+
+	// The original graph is unchanged
+	if g.VertexCount() != 5 || g.EdgeCount() != 4 {
+		t.Fatalf("bad: %s", g.String())
+	}
+}
+
 const testGraphBasicStr = `
 1
   3
@@ -88,3 +264,25 @@ const testGraphReplaceStr = `
 42
   3
 `
+
+const testGraphSubgraphStr = `
+2
+  3
+3
+`
+
+const testGraphSubgraphAncestorsStr = `
+2
+  3
+3
+  4
+4
+`
+
+const testGraphSubgraphDescendantsStr = `
+1
+  2
+2
+  3
+3
+`
@@ -4,104 +4,159 @@ package dag
 // within the Graph g. This information is primarily used by this package
 // for cycle detection, but strongly connected components have widespread
 // use.
+//
+// This is Tarjan's algorithm. It is iterative rather than recursive so
+// that large graphs don't grow the stack, and the accounting for each
+// vertex is kept in slices indexed by a number assigned to the vertex up
+// front, so that the only map lookups are while building the edge list.
+//
+// Complexity: O(V+E)
 func StronglyConnected(g *Graph) [][]Vertex {
-	vs := g.Vertices()
-	acct := sccAcct{
-		NextIndex:   1,
-		VertexIndex: make(map[Vertex]int, len(vs)),
-	}
-	for _, v := range vs {
-		// Recurse on any non-visited nodes
-		if acct.VertexIndex[v] == 0 {
-			stronglyConnected(&acct, g, v)
+	acct := newSCCAcct(g)
+	for v := range acct.Vertices {
+		if acct.Index[v] == 0 {
+			acct.strongConnect(v)
 		}
 	}
+
 	return acct.SCC
 }
 
-func stronglyConnected(acct *sccAcct, g *Graph, v Vertex) int {
-	// Initial vertex visit
-	index := acct.visit(v)
-	minIdx := index
-
-	for _, raw := range g.DownEdges(v).List() {
-		target := raw.(Vertex)
-		targetIdx := acct.VertexIndex[target]
-
-		// Recurse on successor if not yet visited
-		if targetIdx == 0 {
-			minIdx = min(minIdx, stronglyConnected(acct, g, target))
-		} else if acct.inStack(target) {
-			// Check if the vertex is in the stack
-			minIdx = min(minIdx, targetIdx)
-		}
+// sccAcct is used to pass around accounting information for
+// the StronglyConnectedComponents algorithm. Vertices are referred to
+// by their position in Vertices.
+type sccAcct struct {
+	Vertices []Vertex
+	Edges    [][]int
+
+	NextIndex int
+	Index     []int // order in which the vertex was visited; 0 if not yet
+	LowLink   []int // lowest index reachable from the vertex
+	OnStack   []bool
+	Stack     []int
+	SCC       [][]Vertex
+}
+
+// sccFrame is a frame of the call stack of the iterative walk: the vertex
+// and the position in its edge list to continue from.
+type sccFrame struct {
+	V    int
+	Next int
+}
+
+func newSCCAcct(g *Graph) *sccAcct {
+	vs := g.Vertices()
+	ids := make(map[Vertex]int, len(vs))
+	for i, v := range vs {
+		ids[v] = i
 	}
 
-	// Pop the strongly connected components off the stack if
-	// this is a root vertex
-	if index == minIdx {
-		var scc []Vertex
-		for {
-			v2 := acct.pop()
-			scc = append(scc, v2)
-			if v2 == v {
-				break
+	// Build the edge list, with the targets of every vertex sharing one
+	// backing slice. An edge can point at a vertex that was never added
+	// to the graph, so vertices are added as they're found.
+	targets := make([]int, 0, g.EdgeCount())
+	edges := make([][]int, 0, len(vs))
+	for i := 0; i < len(vs); i++ {
+		start := len(targets)
+		if s := g.DownEdges(vs[i]); s != nil {
+			for _, raw := range s.m {
+				id, ok := ids[raw]
+				if !ok {
+					id = len(vs)
+					ids[raw] = id
+					vs = append(vs, raw)
+				}
+
+				targets = append(targets, id)
 			}
 		}
 
-		acct.SCC = append(acct.SCC, scc)
+		edges = append(edges, targets[start:len(targets):len(targets)])
 	}
 
-	return minIdx
-}
-
-func min(a, b int) int {
-	if a <= b {
-		return a
+	return &sccAcct{
+		Vertices:  vs,
+		Edges:     edges,
+		NextIndex: 1,
+		Index:     make([]int, len(vs)),
+		LowLink:   make([]int, len(vs)),
+		OnStack:   make([]bool, len(vs)),
+		Stack:     make([]int, 0, len(vs)),
 	}
-	return b
 }
 
-// sccAcct is used ot pass around accounting information for
-// the StronglyConnectedComponents algorithm
-type sccAcct struct {
-	NextIndex   int
-	VertexIndex map[Vertex]int
-	Stack       []Vertex
-	SCC         [][]Vertex
+// strongConnect finds the strongly connected components reachable from
+// the vertex root.
+func (s *sccAcct) strongConnect(root int) {
+	s.visit(root)
+	calls := []sccFrame{{V: root}}
+	for len(calls) > 0 {
+		frame := &calls[len(calls)-1]
+		v := frame.V
+
+		// Continue with the next successor, if there is one
+		if frame.Next < len(s.Edges[v]) {
+			w := s.Edges[v][frame.Next]
+			frame.Next++
+
+			if s.Index[w] == 0 {
+				// Not yet visited, "recurse" into it
+				s.visit(w)
+				calls = append(calls, sccFrame{V: w})
+			} else if s.OnStack[w] {
+				s.LowLink[v] = min(s.LowLink[v], s.Index[w])
+			}
+
+			continue
+		}
+
+		// All successors are done. "Return" to the caller, which takes
+		// on our low link.
+		calls = calls[:len(calls)-1]
+		if len(calls) > 0 {
+			parent := calls[len(calls)-1].V
+			s.LowLink[parent] = min(s.LowLink[parent], s.LowLink[v])
+		}
+
+		// Pop the strongly connected components off the stack if
+		// this is a root vertex
+		if s.LowLink[v] == s.Index[v] {
+			var scc []Vertex
+			for {
+				w := s.pop()
+				scc = append(scc, s.Vertices[w])
+				if w == v {
+					break
+				}
+			}
+
+			s.SCC = append(s.SCC, scc)
+		}
+	}
 }
 
 // visit assigns an index and pushes a vertex onto the stack
-func (s *sccAcct) visit(v Vertex) int {
-	idx := s.NextIndex
-	s.VertexIndex[v] = idx
+func (s *sccAcct) visit(v int) {
+	s.Index[v] = s.NextIndex
+	s.LowLink[v] = s.NextIndex
 	s.NextIndex++
-	s.push(v)
-	return idx
-}
 
-// push adds a vertex to the stack
-func (s *sccAcct) push(n Vertex) {
-	s.Stack = append(s.Stack, n)
+	s.Stack = append(s.Stack, v)
+	s.OnStack[v] = true
 }
 
 // pop removes a vertex from the stack
-func (s *sccAcct) pop() Vertex {
+func (s *sccAcct) pop() int {
 	n := len(s.Stack)
-	if n == 0 {
-		return nil
-	}
-	vertex := s.Stack[n-1]
+	v := s.Stack[n-1]
 	s.Stack = s.Stack[:n-1]
-	return vertex
+	s.OnStack[v] = false
+	return v
 }
 
-// inStack checks if a vertex is in the stack
-func (s *sccAcct) inStack(needle Vertex) bool {
-	for _, n := range s.Stack {
-		if n == needle {
-			return true
-		}
+func min(a, b int) int {
+	if a <= b {
+		return a
 	}
-	return false
+	return b
 }
@@ -56,6 +56,55 @@ func TestGraphStronglyConnected_three(t *testing.T) {
 	}
 }
 
+func TestGraphStronglyConnected_deep(t *testing.T) {
+	// A long chain back to the start is one component, and deep enough
+	// that it would be a problem for a recursive implementation.
+	g := testGraphChain(100000)
+	g.Connect(BasicEdge(99999, 0))
+
+	scc := StronglyConnected(&g.Graph)
+	if len(scc) != 1 || len(scc[0]) != 100000 {
+		t.Fatalf("bad: %d", len(scc))
+	}
+}
+
+func BenchmarkStronglyConnected(b *testing.B) {
+	g := testGraphChain(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		StronglyConnected(&g.Graph)
+	}
+}
+
+func BenchmarkAcyclicGraphValidate(b *testing.B) {
+	g := testGraphChain(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := g.Validate(); err != nil {
+			b.Fatalf("err: %s", err)
+		}
+	}
+}
+
+// testGraphChain returns a graph of n vertices where each vertex depends
+// on the next one and on the one after that.
+func testGraphChain(n int) *AcyclicGraph {
+	g := new(AcyclicGraph)
+	for i := 0; i < n; i++ {
+		g.Add(i)
+	}
+	for i := 0; i < n-1; i++ {
+		g.Connect(BasicEdge(i, i+1))
+		if i < n-2 {
+			g.Connect(BasicEdge(i, i+2))
+		}
+	}
+
+	return g
+}
+
 func testSCCStr(list [][]Vertex) string {
 	var lines []string
 	for _, vs := range list {
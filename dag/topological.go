@@ -0,0 +1,82 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TopologicalSort returns the vertices of the graph ordered so that every
+// vertex comes after all of the vertices it depends on (the targets of its
+// edges). This is the order in which a serial Walk would visit them.
+//
+// The order is deterministic: when more than one vertex could come next,
+// they're ordered by VertexName. An error is returned if the graph has a
+// cycle.
+func (g *AcyclicGraph) TopologicalSort() ([]Vertex, error) {
+	return g.topologicalSort(false)
+}
+
+// ReverseTopologicalSort is like TopologicalSort, except every vertex comes
+// before all of the vertices it depends on, such as when destroying.
+func (g *AcyclicGraph) ReverseTopologicalSort() ([]Vertex, error) {
+	return g.topologicalSort(true)
+}
+
+func (g *AcyclicGraph) topologicalSort(reverse bool) ([]Vertex, error) {
+	// In the forward direction a vertex is ready once everything it depends
+	// on is done, and finishing it may make its dependents ready. In
+	// reverse, the edges are followed the other way.
+	deps, next := g.DownEdges, g.UpEdges
+	if reverse {
+		deps, next = g.UpEdges, g.DownEdges
+	}
+
+	vertices := g.Vertices()
+	remaining := make(map[Vertex]int, len(vertices))
+	var ready []Vertex
+	for _, v := range vertices {
+		remaining[v] = deps(v).Len()
+		if remaining[v] == 0 {
+			ready = append(ready, v)
+		}
+	}
+
+	result := make([]Vertex, 0, len(vertices))
+	for len(ready) > 0 {
+		sort.Sort(byVertexName(ready))
+		v := ready[0]
+		ready = ready[1:]
+		result = append(result, v)
+
+		for _, raw := range next(v).List() {
+			n := raw.(Vertex)
+			remaining[n]--
+			if remaining[n] == 0 {
+				ready = append(ready, n)
+			}
+		}
+	}
+
+	if len(result) != len(vertices) {
+		var names []string
+		for _, v := range vertices {
+			if remaining[v] > 0 {
+				names = append(names, VertexName(v))
+			}
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf(
+			"graph has a cycle between: %s", strings.Join(names, ", "))
+	}
+
+	return result, nil
+}
+
+// byVertexName implements sort.Interface to sort vertices by VertexName.
+type byVertexName []Vertex
+
+func (s byVertexName) Len() int           { return len(s) }
+func (s byVertexName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byVertexName) Less(i, j int) bool { return VertexName(s[i]) < VertexName(s[j]) }
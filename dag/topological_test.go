@@ -0,0 +1,67 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAcyclicGraphTopologicalSort(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(4, 3))
+	g.Connect(BasicEdge(4, 1))
+	g.Connect(BasicEdge(3, 2))
+
+	// Sort a few times since the vertices come from a map
+	for i := 0; i < 10; i++ {
+		actual, err := g.TopologicalSort()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		expected := []Vertex{1, 2, 3, 4}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("bad: %#v", actual)
+		}
+	}
+}
+
+func TestAcyclicGraphReverseTopologicalSort(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(4, 3))
+	g.Connect(BasicEdge(4, 1))
+	g.Connect(BasicEdge(3, 2))
+
+	for i := 0; i < 10; i++ {
+		actual, err := g.ReverseTopologicalSort()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		expected := []Vertex{4, 1, 3, 2}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("bad: %#v", actual)
+		}
+	}
+}
+
+func TestAcyclicGraphTopologicalSort_cycle(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(2, 1))
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(2, 3))
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatal("should error")
+	}
+}
@@ -0,0 +1,61 @@
+// Package customdiff provides helpers for building the CustomizeDiff
+// function of a schema.Resource out of smaller, common parts.
+package customdiff
+
+import (
+	"github.com/hashicorp/terraform/helper/multierror"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// All returns a CustomizeDiffFunc that runs all of the given functions,
+// even if some of them fail. All of the errors are returned together.
+func All(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		var err *multierror.Error
+		for _, f := range funcs {
+			if e := f(d, meta); e != nil {
+				err = multierror.ErrorAppend(err, e)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// Sequence returns a CustomizeDiffFunc that runs the given functions in
+// order, stopping at the first one that fails. This is useful when later
+// functions depend on the changes made by earlier ones.
+func Sequence(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		for _, f := range funcs {
+			if err := f(d, meta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// ResourceConditionFunc is a condition on the diff of a resource.
+type ResourceConditionFunc func(d *schema.ResourceDiff, meta interface{}) bool
+
+// ValueChangeConditionFunc is a condition on the change of a single key,
+// which is given the old and new value of the key.
+type ValueChangeConditionFunc func(old, new, meta interface{}) bool
+
+// If returns a CustomizeDiffFunc that only runs the given function if
+// the condition is true.
+func If(cond ResourceConditionFunc, f schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if cond(d, meta) {
+			return f(d, meta)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package customdiff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/multierror"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestAll(t *testing.T) {
+	var calls []string
+	f := All(
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "a")
+			return errors.New("a")
+		},
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "b")
+			return nil
+		},
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "c")
+			return errors.New("c")
+		},
+	)
+
+	_, err := testDiff(t, 1, 2, f)
+	if len(calls) != 3 {
+		t.Fatalf("bad: %#v", calls)
+	}
+
+	merr, ok := err.(*multierror.Error)
+	if !ok || len(merr.Errors) != 2 {
+		t.Fatalf("bad: %#v", err)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var calls []string
+	f := Sequence(
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "a")
+			return nil
+		},
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "b")
+			return errors.New("b")
+		},
+		func(d *schema.ResourceDiff, meta interface{}) error {
+			calls = append(calls, "c")
+			return nil
+		},
+	)
+
+	if _, err := testDiff(t, 1, 2, f); err == nil {
+		t.Fatal("should error")
+	}
+	if len(calls) != 2 {
+		t.Fatalf("bad: %#v", calls)
+	}
+}
+
+func TestIf(t *testing.T) {
+	for _, cond := range []bool{true, false} {
+		called := false
+		f := If(
+			func(d *schema.ResourceDiff, meta interface{}) bool {
+				return cond
+			},
+			func(d *schema.ResourceDiff, meta interface{}) error {
+				called = true
+				return nil
+			},
+		)
+
+		if _, err := testDiff(t, 1, 2, f); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if called != cond {
+			t.Fatalf("cond %t: called %t", cond, called)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package customdiff
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testDiff returns the diff of a resource with a single "size" attribute
+// going from the given old value to the given new value, with the given
+// CustomizeDiff function.
+func testDiff(
+	t *testing.T,
+	old, new int,
+	f schema.CustomizeDiffFunc) (*terraform.InstanceDiff, error) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+		CustomizeDiff: f,
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"size": new,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s := &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"size": strconv.Itoa(old),
+		},
+	}
+
+	return r.Diff(s, terraform.NewResourceConfig(raw), nil)
+}
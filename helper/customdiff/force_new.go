@@ -0,0 +1,45 @@
+package customdiff
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// ForceNewIf returns a CustomizeDiffFunc that requires a new resource for
+// a change of the given key if the condition is true. Nothing happens if
+// the key isn't changing.
+//
+// This is useful for keys that can only sometimes be updated in-place,
+// and which therefore can't set ForceNew in their schema.
+func ForceNewIf(key string, cond ResourceConditionFunc) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if d.HasChange(key) && cond(d, meta) {
+			return d.ForceNew(key)
+		}
+
+		return nil
+	}
+}
+
+// ForceNewIfChange returns a CustomizeDiffFunc that requires a new
+// resource for a change of the given key if the condition is true for
+// its old and new value, such as when the size of a volume shrinks:
+//
+//	customdiff.ForceNewIfChange("size", func(old, new, meta interface{}) bool {
+//		return new.(int) < old.(int)
+//	})
+//
+// The new value is the zero value of the key's type while it is computed.
+func ForceNewIfChange(key string, cond ValueChangeConditionFunc) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		if !d.HasChange(key) {
+			return nil
+		}
+
+		old, new := d.GetChange(key)
+		if cond(old, new, meta) {
+			return d.ForceNew(key)
+		}
+
+		return nil
+	}
+}
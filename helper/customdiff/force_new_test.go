@@ -0,0 +1,64 @@
+package customdiff
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestForceNewIf(t *testing.T) {
+	cases := []struct {
+		Old, New    int
+		Cond        bool
+		RequiresNew bool
+	}{
+		{1, 2, true, true},
+		{1, 2, false, false},
+
+		// No change
+		{1, 1, true, false},
+	}
+
+	for i, tc := range cases {
+		called := false
+		f := ForceNewIf("size", func(d *schema.ResourceDiff, meta interface{}) bool {
+			called = true
+			return tc.Cond
+		})
+
+		d, err := testDiff(t, tc.Old, tc.New, f)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if d.RequiresNew() != tc.RequiresNew {
+			t.Fatalf("%d: bad: %#v", i, d)
+		}
+		if called != (tc.Old != tc.New) {
+			t.Fatalf("%d: condition called: %t", i, called)
+		}
+	}
+}
+
+func TestForceNewIfChange(t *testing.T) {
+	shrinks := func(old, new, meta interface{}) bool {
+		return new.(int) < old.(int)
+	}
+
+	cases := []struct {
+		Old, New    int
+		RequiresNew bool
+	}{
+		{2, 1, true},
+		{1, 2, false},
+	}
+
+	for i, tc := range cases {
+		d, err := testDiff(t, tc.Old, tc.New, ForceNewIfChange("size", shrinks))
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if d.RequiresNew() != tc.RequiresNew {
+			t.Fatalf("%d: bad: %#v", i, d)
+		}
+	}
+}
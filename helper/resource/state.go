@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -28,7 +29,15 @@ type StateChangeConf struct {
 	Target         string           // Target state
 	Timeout        time.Duration    // The amount of time to wait before timeout
 	MinTimeout     time.Duration    // Smallest time to wait before refreshes
+	PollInterval   time.Duration    // Fixed time to wait between refreshes, instead of backing off
+	Jitter         time.Duration    // Largest random time added to each wait
 	NotFoundChecks int              // Number of times to allow not found
+
+	// ContinuousTargetOccurence is the number of times in a row that the
+	// target state must be seen before it's considered reached. This is
+	// useful for eventually consistent APIs, which can briefly report the
+	// target state before going back to a pending one. Defaults to 1.
+	ContinuousTargetOccurence int
 }
 
 // WaitForState watches an object and waits for it to achieve the state
@@ -38,12 +47,17 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 	log.Printf("[DEBUG] Waiting for state to become: %s", conf.Target)
 
 	notfoundTick := 0
+	targetOccurence := 0
 
 	// Set a default for times to check for not found
 	if conf.NotFoundChecks == 0 {
 		conf.NotFoundChecks = 20
 	}
 
+	if conf.ContinuousTargetOccurence == 0 {
+		conf.ContinuousTargetOccurence = 1
+	}
+
 	var result interface{}
 	var resulterr error
 
@@ -56,13 +70,22 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 
 		var err error
 		for tries := 0; ; tries++ {
-			// Wait between refreshes using an exponential backoff
-			wait := time.Duration(math.Pow(2, float64(tries))) *
-				100 * time.Millisecond
-			if wait < conf.MinTimeout {
-				wait = conf.MinTimeout
-			} else if wait > 10*time.Second {
-				wait = 10 * time.Second
+			// Wait between refreshes using an exponential backoff, unless
+			// a fixed interval is set
+			var wait time.Duration
+			if conf.PollInterval > 0 {
+				wait = conf.PollInterval
+			} else {
+				wait = time.Duration(math.Pow(2, float64(tries))) *
+					100 * time.Millisecond
+				if wait < conf.MinTimeout {
+					wait = conf.MinTimeout
+				} else if wait > 10*time.Second {
+					wait = 10 * time.Second
+				}
+			}
+			if conf.Jitter > 0 {
+				wait += time.Duration(rand.Int63n(int64(conf.Jitter)))
 			}
 
 			log.Printf("[TRACE] Waiting %s before next try", wait)
@@ -83,6 +106,7 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 			if result == nil {
 				// If we didn't find the resource, check if we have been
 				// not finding it for awhile, and if so, report an error.
+				targetOccurence = 0
 				notfoundTick += 1
 				if notfoundTick > conf.NotFoundChecks {
 					resulterr = errors.New("couldn't find resource")
@@ -93,9 +117,16 @@ func (conf *StateChangeConf) WaitForState() (interface{}, error) {
 				notfoundTick = 0
 
 				if currentState == conf.Target {
-					return
+					targetOccurence += 1
+					if targetOccurence >= conf.ContinuousTargetOccurence {
+						return
+					}
+
+					continue
 				}
 
+				targetOccurence = 0
+
 				found := false
 				for _, allowed := range conf.Pending {
 					if currentState == allowed {
@@ -25,6 +25,26 @@ func SuccessfulStateRefreshFunc() StateRefreshFunc {
 	}
 }
 
+// SequenceStateRefreshFunc returns a StateRefreshFunc that returns the
+// given states in order, and then the last one forever. An empty state
+// means the resource isn't found.
+func SequenceStateRefreshFunc(states ...string) (StateRefreshFunc, *int) {
+	calls := 0
+	return func() (interface{}, string, error) {
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+
+		if state == "" {
+			return nil, "", nil
+		}
+
+		return struct{}{}, state, nil
+	}, &calls
+}
+
 func TestWaitForState_timeout(t *testing.T) {
 	conf := &StateChangeConf{
 		Pending: []string{"pending", "incomplete"},
@@ -97,3 +117,71 @@ func TestWaitForState_failure(t *testing.T) {
 		t.Fatalf("should not return obj")
 	}
 }
+
+func TestWaitForState_continuousTargetOccurence(t *testing.T) {
+	f, calls := SequenceStateRefreshFunc(
+		"pending", "running", "pending", "running", "running", "running")
+	conf := &StateChangeConf{
+		Pending:                   []string{"pending"},
+		Target:                    "running",
+		Refresh:                   f,
+		Timeout:                   10 * time.Second,
+		PollInterval:              time.Millisecond,
+		ContinuousTargetOccurence: 3,
+	}
+
+	if _, err := conf.WaitForState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if *calls != 6 {
+		t.Fatalf("bad: %d", *calls)
+	}
+}
+
+func TestWaitForState_notFoundChecks(t *testing.T) {
+	f, calls := SequenceStateRefreshFunc("", "", "running")
+	conf := &StateChangeConf{
+		Pending:        []string{"pending"},
+		Target:         "running",
+		Refresh:        f,
+		Timeout:        10 * time.Second,
+		PollInterval:   time.Millisecond,
+		NotFoundChecks: 2,
+	}
+
+	if _, err := conf.WaitForState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if *calls != 3 {
+		t.Fatalf("bad: %d", *calls)
+	}
+
+	// Not found for too long
+	f, _ = SequenceStateRefreshFunc("", "", "", "running")
+	conf.Refresh = f
+	if _, err := conf.WaitForState(); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestWaitForState_pollInterval(t *testing.T) {
+	f, _ := SequenceStateRefreshFunc("pending", "pending", "running")
+	conf := &StateChangeConf{
+		Pending:      []string{"pending"},
+		Target:       "running",
+		Refresh:      f,
+		Timeout:      10 * time.Second,
+		PollInterval: 50 * time.Millisecond,
+		Jitter:       10 * time.Millisecond,
+	}
+
+	start := time.Now()
+	if _, err := conf.WaitForState(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Three waits of at least the interval each, without backing off
+	if d := time.Since(start); d < 150*time.Millisecond || d > 2*time.Second {
+		t.Fatalf("bad: %s", d)
+	}
+}
@@ -22,6 +22,10 @@ const TestEnvVar = "TF_ACC"
 // it was created.
 type TestCheckFunc func(*terraform.State) error
 
+// ImportStateCheckFunc is the check function for ImportState tests. It
+// is given the refreshed states returned by the importer.
+type ImportStateCheckFunc func([]*terraform.InstanceState) error
+
 // TestCase is a single acceptance test case used to test the apply/destroy
 // lifecycle of a resource in a specific configuration.
 //
@@ -68,6 +72,35 @@ type TestStep struct {
 
 	// Destroy will create a destroy plan if set to true.
 	Destroy bool
+
+	//---------------------------------------------------------------
+	// ImportState testing
+	//---------------------------------------------------------------
+
+	// ImportState, if true, will test the functionality of ImportState
+	// by importing the resource named by ResourceName. Config is ignored
+	// for these steps and the state is left untouched, so an import step
+	// must follow a step that created the resource.
+	ImportState bool
+
+	// ResourceName is the name of the resource in the state to import,
+	// such as "aws_instance.foo".
+	ResourceName string
+
+	// ImportStateId is the ID to import. If empty, the ID of the primary
+	// instance of ResourceName in the current state is used.
+	ImportStateId string
+
+	// ImportStateCheck, if non-nil, is called with the refreshed states
+	// returned by the import so that they can be inspected.
+	ImportStateCheck ImportStateCheckFunc
+
+	// ImportStateVerify, if true, will compare the attributes of the
+	// imported resource with the attributes in the current state.
+	// Attributes with a prefix in ImportStateVerifyIgnore are skipped,
+	// which is useful for values that can't be read back from the API.
+	ImportStateVerify       bool
+	ImportStateVerifyIgnore []string
 }
 
 // Test performs an acceptance test on a resource.
@@ -115,7 +148,11 @@ func Test(t TestT, c TestCase) {
 	for i, step := range c.Steps {
 		var err error
 		log.Printf("[WARN] Test: Executing step %d", i)
-		state, err = testStep(opts, state, step)
+		if step.ImportState {
+			state, err = testStepImportState(c.Providers, state, step)
+		} else {
+			state, err = testStep(opts, state, step)
+		}
 		if err != nil {
 			t.Error(fmt.Sprintf(
 				"Step %d error: %s", i, err))
@@ -123,10 +160,18 @@ func Test(t TestT, c TestCase) {
 		}
 	}
 
-	// If we have a state, then run the destroy
+	// If we have a state, then run the destroy using the config of the
+	// last step that had one. Import steps don't have a config.
 	if state != nil {
+		var lastConfig string
+		for _, step := range c.Steps {
+			if !step.ImportState {
+				lastConfig = step.Config
+			}
+		}
+
 		destroyStep := TestStep{
-			Config:  c.Steps[len(c.Steps)-1].Config,
+			Config:  lastConfig,
 			Check:   c.CheckDestroy,
 			Destroy: true,
 		}
@@ -146,6 +191,16 @@ func Test(t TestT, c TestCase) {
 	}
 }
 
+// ParallelTest performs an acceptance test on a resource, allowing
+// concurrency with other ParallelTest calls.
+//
+// Tests run in parallel must not share resource names, so they should
+// generally use randomized names in their configurations.
+func ParallelTest(t TestT, c TestCase) {
+	t.Parallel()
+	Test(t, c)
+}
+
 func testStep(
 	opts terraform.ContextOpts,
 	state *terraform.State,
@@ -291,6 +346,7 @@ type TestT interface {
 	Error(args ...interface{})
 	Fatal(args ...interface{})
 	Skip(args ...interface{})
+	Parallel()
 }
 
 // This is set to true by unit tests to alter some behavior
@@ -0,0 +1,175 @@
+package resource
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testStepImportState runs an ImportState TestStep. The state is never
+// modified by an import step; it is returned unchanged.
+func testStepImportState(
+	providers map[string]terraform.ResourceProvider,
+	state *terraform.State,
+	step TestStep) (*terraform.State, error) {
+	if step.ResourceName == "" {
+		return state, fmt.Errorf("ResourceName must be set for ImportState")
+	}
+
+	// Find the resource we're importing in the current state
+	var rs *terraform.ResourceState
+	if state != nil {
+		rs = state.RootModule().Resources[step.ResourceName]
+	}
+	if rs == nil {
+		return state, fmt.Errorf("Not found: %s", step.ResourceName)
+	}
+
+	id := step.ImportStateId
+	if id == "" {
+		if rs.Primary == nil {
+			return state, fmt.Errorf(
+				"No primary instance: %s", step.ResourceName)
+		}
+
+		id = rs.Primary.ID
+	}
+
+	pName := rs.Type
+	if idx := strings.IndexRune(pName, '_'); idx != -1 {
+		pName = pName[:idx]
+	}
+	p, ok := providers[pName]
+	if !ok {
+		return state, fmt.Errorf(
+			"Provider %q not found for %s", pName, step.ResourceName)
+	}
+
+	// Import!
+	log.Printf("[WARN] Test: Importing %s with ID %q", step.ResourceName, id)
+	info := &terraform.InstanceInfo{
+		Id:   step.ResourceName,
+		Type: rs.Type,
+	}
+	imported, err := p.ImportState(info, id)
+	if err != nil {
+		return state, fmt.Errorf("Error importing: %s", err)
+	}
+	if len(imported) == 0 {
+		return state, fmt.Errorf("Import of %q returned no resources", id)
+	}
+
+	// Refresh each imported resource so that it is fully populated, the
+	// same as it would be after a real import.
+	newStates := make([]*terraform.InstanceState, 0, len(imported))
+	for _, is := range imported {
+		refreshInfo := &terraform.InstanceInfo{
+			Id:   step.ResourceName,
+			Type: is.Ephemeral.Type,
+		}
+		if refreshInfo.Type == "" {
+			refreshInfo.Type = rs.Type
+		}
+
+		is, err := p.Refresh(refreshInfo, is)
+		if err != nil {
+			return state, fmt.Errorf("Error refreshing import: %s", err)
+		}
+		if is == nil || is.ID == "" {
+			return state, fmt.Errorf(
+				"Imported resource %q not found when refreshing", id)
+		}
+
+		newStates = append(newStates, is)
+	}
+
+	if step.ImportStateCheck != nil {
+		if err := step.ImportStateCheck(newStates); err != nil {
+			return state, fmt.Errorf("Check failed: %s", err)
+		}
+	}
+
+	if step.ImportStateVerify {
+		if err := testImportStateVerify(rs.Primary, newStates, step); err != nil {
+			return state, err
+		}
+	}
+
+	return state, nil
+}
+
+// testImportStateVerify compares the attributes of the imported instance
+// with the same ID as expected against the attributes of expected.
+func testImportStateVerify(
+	expected *terraform.InstanceState,
+	imported []*terraform.InstanceState,
+	step TestStep) error {
+	if expected == nil {
+		return fmt.Errorf("No primary instance: %s", step.ResourceName)
+	}
+
+	var actual *terraform.InstanceState
+	for _, is := range imported {
+		if is.ID == expected.ID {
+			actual = is
+			break
+		}
+	}
+	if actual == nil {
+		return fmt.Errorf(
+			"ImportStateVerify: no imported resource with ID %q",
+			expected.ID)
+	}
+
+	actualAttrs := testImportStateVerifyAttrs(actual.Attributes, step)
+	expectedAttrs := testImportStateVerifyAttrs(expected.Attributes, step)
+	if !reflect.DeepEqual(actualAttrs, expectedAttrs) {
+		var msg []string
+		for k, v := range expectedAttrs {
+			if av, ok := actualAttrs[k]; !ok || av != v {
+				msg = append(msg, fmt.Sprintf(
+					"  %s: expected %#v, got %#v", k, v, av))
+			}
+		}
+		for k, v := range actualAttrs {
+			if _, ok := expectedAttrs[k]; !ok {
+				msg = append(msg, fmt.Sprintf(
+					"  %s: unexpected value %#v", k, v))
+			}
+		}
+
+		sort.Strings(msg)
+
+		return fmt.Errorf(
+			"ImportStateVerify attributes not equivalent:\n\n%s",
+			strings.Join(msg, "\n"))
+	}
+
+	return nil
+}
+
+// testImportStateVerifyAttrs copies attrs, removing any keys that are
+// ignored by the step.
+func testImportStateVerifyAttrs(
+	attrs map[string]string, step TestStep) map[string]string {
+	result := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		ignored := false
+		for _, prefix := range step.ImportStateVerifyIgnore {
+			if strings.HasPrefix(k, prefix) {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			result[k] = v
+		}
+	}
+
+	return result
+}
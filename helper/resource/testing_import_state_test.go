@@ -0,0 +1,206 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestTestStepImportState(t *testing.T) {
+	mp := testProvider()
+	mp.ImportStateReturn = []*terraform.InstanceState{
+		&terraform.InstanceState{ID: "foo"},
+	}
+	mp.RefreshFn = func(
+		i *terraform.InstanceInfo,
+		s *terraform.InstanceState) (*terraform.InstanceState, error) {
+		return &terraform.InstanceState{
+			ID: s.ID,
+			Attributes: map[string]string{
+				"id":  s.ID,
+				"foo": "bar",
+			},
+		}, nil
+	}
+
+	checked := false
+	state := testImportStateState(map[string]string{
+		"id":  "foo",
+		"foo": "bar",
+	})
+	newState, err := testStepImportState(
+		map[string]terraform.ResourceProvider{"test": mp},
+		state,
+		TestStep{
+			ImportState:       true,
+			ResourceName:      "test_instance.foo",
+			ImportStateVerify: true,
+			ImportStateCheck: func(s []*terraform.InstanceState) error {
+				checked = true
+				if len(s) != 1 {
+					return fmt.Errorf("bad: %#v", s)
+				}
+
+				return nil
+			},
+		})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if newState != state {
+		t.Fatal("state should not be modified")
+	}
+	if !checked {
+		t.Fatal("ImportStateCheck should be called")
+	}
+	if mp.ImportStateID != "foo" {
+		t.Fatalf("bad: %#v", mp.ImportStateID)
+	}
+	if mp.ImportStateInfo.Type != "test_instance" {
+		t.Fatalf("bad: %#v", mp.ImportStateInfo)
+	}
+	if !mp.RefreshCalled {
+		t.Fatal("Refresh should be called")
+	}
+}
+
+func TestTestStepImportState_id(t *testing.T) {
+	mp := testProvider()
+	mp.ImportStateReturn = []*terraform.InstanceState{
+		&terraform.InstanceState{ID: "bar"},
+	}
+	mp.RefreshReturn = &terraform.InstanceState{ID: "bar"}
+
+	_, err := testStepImportState(
+		map[string]terraform.ResourceProvider{"test": mp},
+		testImportStateState(nil),
+		TestStep{
+			ImportState:   true,
+			ResourceName:  "test_instance.foo",
+			ImportStateId: "bar",
+		})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if mp.ImportStateID != "bar" {
+		t.Fatalf("bad: %#v", mp.ImportStateID)
+	}
+}
+
+func TestTestStepImportState_verify(t *testing.T) {
+	cases := []struct {
+		Attrs  map[string]string
+		Ignore []string
+		Err    string
+	}{
+		{
+			map[string]string{"id": "foo", "foo": "bar"},
+			nil,
+			"",
+		},
+
+		{
+			map[string]string{"id": "foo", "foo": "baz"},
+			nil,
+			`foo: expected "baz", got "bar"`,
+		},
+
+		{
+			map[string]string{"id": "foo", "foo": "baz"},
+			[]string{"foo"},
+			"",
+		},
+
+		{
+			map[string]string{"id": "foo", "foo": "bar", "password": "x"},
+			[]string{"password"},
+			"",
+		},
+
+		{
+			map[string]string{"id": "foo"},
+			nil,
+			`foo: unexpected value "bar"`,
+		},
+	}
+
+	for i, tc := range cases {
+		mp := testProvider()
+		mp.ImportStateReturn = []*terraform.InstanceState{
+			&terraform.InstanceState{ID: "foo"},
+		}
+		mp.RefreshReturn = &terraform.InstanceState{
+			ID: "foo",
+			Attributes: map[string]string{
+				"id":  "foo",
+				"foo": "bar",
+			},
+		}
+
+		_, err := testStepImportState(
+			map[string]terraform.ResourceProvider{"test": mp},
+			testImportStateState(tc.Attrs),
+			TestStep{
+				ImportState:             true,
+				ResourceName:            "test_instance.foo",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: tc.Ignore,
+			})
+		if (err != nil) != (tc.Err != "") {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), tc.Err) {
+			t.Fatalf("%d: bad: %s", i, err)
+		}
+	}
+}
+
+func TestTestStepImportState_notFound(t *testing.T) {
+	mp := testProvider()
+	mp.ImportStateReturn = []*terraform.InstanceState{
+		&terraform.InstanceState{ID: "foo"},
+	}
+
+	_, err := testStepImportState(
+		map[string]terraform.ResourceProvider{"test": mp},
+		testImportStateState(nil),
+		TestStep{
+			ImportState:  true,
+			ResourceName: "test_instance.foo",
+		})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	_, err = testStepImportState(
+		map[string]terraform.ResourceProvider{"test": mp},
+		testImportStateState(nil),
+		TestStep{
+			ImportState:  true,
+			ResourceName: "test_instance.bar",
+		})
+	if err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func testImportStateState(attrs map[string]string) *terraform.State {
+	return &terraform.State{
+		Modules: []*terraform.ModuleState{
+			&terraform.ModuleState{
+				Path: []string{"root"},
+				Resources: map[string]*terraform.ResourceState{
+					"test_instance.foo": &terraform.ResourceState{
+						Type: "test_instance",
+						Primary: &terraform.InstanceState{
+							ID:         "foo",
+							Attributes: attrs,
+						},
+					},
+				},
+			},
+		},
+	}
+}
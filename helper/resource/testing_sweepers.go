@@ -0,0 +1,156 @@
+package resource
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// SweeperFunc is the function called to remove any dangling resources
+// left behind by acceptance tests in the given region.
+type SweeperFunc func(region string) error
+
+// Sweeper removes resources leaked by acceptance tests for a single
+// resource type.
+type Sweeper struct {
+	// Name is the name of the sweeper, typically the resource type.
+	Name string
+
+	// Dependencies are the names of sweepers that must run before this
+	// one, such as sweepers for resources that reference the resources
+	// this sweeper removes.
+	Dependencies []string
+
+	// F is the function that does the sweeping.
+	F SweeperFunc
+}
+
+var sweeperFuncs map[string]*Sweeper
+
+// AddTestSweepers registers a Sweeper under the given name. This is
+// generally called from an init function in a provider's test files.
+// Registering two sweepers with the same name panics.
+func AddTestSweepers(name string, s *Sweeper) {
+	if sweeperFuncs == nil {
+		sweeperFuncs = make(map[string]*Sweeper)
+	}
+
+	if _, ok := sweeperFuncs[name]; ok {
+		panic(fmt.Sprintf("duplicate sweeper registered: %s", name))
+	}
+
+	sweeperFuncs[name] = s
+}
+
+// TestMain should be called from a provider's TestMain function:
+//
+//	func TestMain(m *testing.M) {
+//	    resource.TestMain(m)
+//	}
+//
+// When the -sweep flag is given a comma separated list of regions, the
+// registered sweepers are run for each region instead of the tests. The
+// -sweep-run flag limits the sweepers run to those whose names contain
+// one of the given comma separated values.
+func TestMain(m interface {
+	Run() int
+}) {
+	sweep := flag.String("sweep", "",
+		"comma separated list of regions to run the sweepers in")
+	sweepRun := flag.String("sweep-run", "",
+		"comma separated list of sweepers to run, defaults to all")
+	flag.Parse()
+
+	if *sweep == "" {
+		os.Exit(m.Run())
+	}
+
+	var filter []string
+	if *sweepRun != "" {
+		filter = strings.Split(*sweepRun, ",")
+	}
+
+	for _, region := range strings.Split(*sweep, ",") {
+		log.Printf("[DEBUG] Running sweepers for region (%s)", region)
+		if err := runSweepers(sweeperFuncs, region, filter); err != nil {
+			log.Printf("[ERR] Error running sweepers for region (%s): %s",
+				region, err)
+			os.Exit(1)
+		}
+	}
+
+	os.Exit(0)
+}
+
+// runSweepers runs the sweepers matching the filter in the given region,
+// running the dependencies of each sweeper first. Each sweeper is run at
+// most once.
+func runSweepers(
+	sweepers map[string]*Sweeper, region string, filter []string) error {
+	ran := make(map[string]bool)
+	for name := range sweepers {
+		if !sweeperMatches(name, filter) {
+			continue
+		}
+
+		if err := runSweeper(sweepers, name, region, ran, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runSweeper(
+	sweepers map[string]*Sweeper,
+	name, region string,
+	ran map[string]bool,
+	stack []string) error {
+	if ran[name] {
+		return nil
+	}
+
+	for _, n := range stack {
+		if n == name {
+			return fmt.Errorf(
+				"sweeper dependency cycle: %s -> %s",
+				strings.Join(stack, " -> "), name)
+		}
+	}
+
+	s, ok := sweepers[name]
+	if !ok {
+		return fmt.Errorf("sweeper not found: %s", name)
+	}
+
+	stack = append(stack, name)
+	for _, dep := range s.Dependencies {
+		if err := runSweeper(sweepers, dep, region, ran, stack); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Running sweeper (%s) in region (%s)", name, region)
+	ran[name] = true
+	if err := s.F(region); err != nil {
+		return fmt.Errorf("Error running sweeper %s: %s", name, err)
+	}
+
+	return nil
+}
+
+func sweeperMatches(name string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, f := range filter {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+
+	return false
+}
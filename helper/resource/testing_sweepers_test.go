@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRunSweepers(t *testing.T) {
+	var ran []string
+	sweeper := func(name string, deps ...string) *Sweeper {
+		return &Sweeper{
+			Name:         name,
+			Dependencies: deps,
+			F: func(region string) error {
+				if region != "us-east-1" {
+					return fmt.Errorf("bad region: %s", region)
+				}
+
+				ran = append(ran, name)
+				return nil
+			},
+		}
+	}
+
+	sweepers := map[string]*Sweeper{
+		"test_instance": sweeper("test_instance"),
+		"test_subnet":   sweeper("test_subnet", "test_instance"),
+		"test_network":  sweeper("test_network", "test_subnet", "test_instance"),
+		"other_thing":   sweeper("other_thing"),
+	}
+
+	if err := runSweepers(sweepers, "us-east-1", []string{"network"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"test_instance", "test_subnet", "test_network"}
+	if !reflect.DeepEqual(ran, expected) {
+		t.Fatalf("bad: %#v", ran)
+	}
+
+	ran = nil
+	if err := runSweepers(sweepers, "us-east-1", nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	sort.Strings(ran)
+	expected = []string{
+		"other_thing", "test_instance", "test_network", "test_subnet",
+	}
+	if !reflect.DeepEqual(ran, expected) {
+		t.Fatalf("bad: %#v", ran)
+	}
+}
+
+func TestRunSweepers_error(t *testing.T) {
+	f := func(string) error { return nil }
+	cases := []map[string]*Sweeper{
+		// Missing dependency
+		map[string]*Sweeper{
+			"a": &Sweeper{Name: "a", Dependencies: []string{"b"}, F: f},
+		},
+
+		// Dependency cycle
+		map[string]*Sweeper{
+			"a": &Sweeper{Name: "a", Dependencies: []string{"b"}, F: f},
+			"b": &Sweeper{Name: "b", Dependencies: []string{"a"}, F: f},
+		},
+
+		// Sweeper error
+		map[string]*Sweeper{
+			"a": &Sweeper{
+				Name: "a",
+				F:    func(string) error { return fmt.Errorf("error") },
+			},
+		},
+	}
+
+	for i, sweepers := range cases {
+		if err := runSweepers(sweepers, "us-east-1", nil); err == nil {
+			t.Fatalf("%d: should error", i)
+		}
+	}
+}
+
+func TestAddTestSweepers_duplicate(t *testing.T) {
+	defer func() {
+		sweeperFuncs = nil
+		if r := recover(); r == nil {
+			t.Fatal("should panic")
+		}
+	}()
+
+	s := &Sweeper{Name: "test_instance"}
+	AddTestSweepers("test_instance", s)
+	AddTestSweepers("test_instance", s)
+}
@@ -160,6 +160,15 @@ func TestTest_stepError(t *testing.T) {
 	}
 }
 
+func TestParallelTest(t *testing.T) {
+	mt := new(mockT)
+	ParallelTest(mt, TestCase{})
+
+	if !mt.ParallelCalled {
+		t.Fatal("Parallel() not called")
+	}
+}
+
 func TestComposeTestCheckFunc(t *testing.T) {
 	cases := []struct {
 		F      []TestCheckFunc
@@ -222,6 +231,8 @@ type mockT struct {
 	SkipCalled  bool
 	SkipArgs    []interface{}
 
+	ParallelCalled bool
+
 	f bool
 }
 
@@ -243,6 +254,10 @@ func (t *mockT) Skip(args ...interface{}) {
 	t.f = true
 }
 
+func (t *mockT) Parallel() {
+	t.ParallelCalled = true
+}
+
 func (t *mockT) failed() bool {
 	return t.f
 }
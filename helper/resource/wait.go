@@ -1,6 +1,8 @@
 package resource
 
 import (
+	"errors"
+	"sync"
 	"time"
 )
 
@@ -9,30 +11,56 @@ type RetryFunc func() error
 
 // Retry is a basic wrapper around StateChangeConf that will just retry
 // a function until it no longer returns an error.
+//
+// Errors are retried until the timeout, at which point the last one is
+// returned. Errors that won't go away by retrying, such as a permission
+// being denied, should be returned with NonRetryableError to stop right
+// away. RetryableError can be used to make it clear that an error is
+// expected to go away, such as with eventually consistent APIs.
 func Retry(timeout time.Duration, f RetryFunc) error {
-	var err error
+	// The refresh function may still be running when WaitForState times
+	// out, so the last error is guarded by a lock.
+	var resultErr error
+	var resultErrLock sync.Mutex
+
 	c := &StateChangeConf{
 		Pending:    []string{"error"},
 		Target:     "success",
 		Timeout:    timeout,
 		MinTimeout: 500 * time.Millisecond,
 		Refresh: func() (interface{}, string, error) {
-			err = f()
-			if err == nil {
-				return 42, "success", nil
-			}
+			err := f()
 
-			if rerr, ok := err.(RetryError); ok {
+			state := "error"
+			var quitErr error
+			switch rerr := err.(type) {
+			case nil:
+				state = "success"
+			case RetryError:
+				err = rerr.Err
+				state = "quit"
+				quitErr = err
+			case retryableError:
 				err = rerr.Err
-				return nil, "quit", err
 			}
 
-			return 42, "error", nil
+			resultErrLock.Lock()
+			resultErr = err
+			resultErrLock.Unlock()
+
+			if state == "quit" {
+				return nil, state, quitErr
+			}
+
+			return 42, state, nil
 		},
 	}
 
 	c.WaitForState()
-	return err
+
+	resultErrLock.Lock()
+	defer resultErrLock.Unlock()
+	return resultErr
 }
 
 // RetryError, if returned, will quit the retry immediately with the
@@ -44,3 +72,33 @@ type RetryError struct {
 func (e RetryError) Error() string {
 	return e.Err.Error()
 }
+
+// NonRetryableError returns an error that makes Retry quit right away
+// with the given error.
+func NonRetryableError(err error) error {
+	if err == nil {
+		err = errors.New("empty non-retryable error")
+	}
+
+	return RetryError{Err: err}
+}
+
+// RetryableError returns an error that makes Retry try again, which is
+// the same as returning the error itself. If the timeout is reached, the
+// given error is returned by Retry.
+func RetryableError(err error) error {
+	if err == nil {
+		err = errors.New("empty retryable error")
+	}
+
+	return retryableError{Err: err}
+}
+
+// retryableError is the error returned by RetryableError.
+type retryableError struct {
+	Err error
+}
+
+func (e retryableError) Error() string {
+	return e.Err.Error()
+}
@@ -2,6 +2,7 @@ package resource
 
 import (
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -60,3 +61,43 @@ func TestRetry_error(t *testing.T) {
 		t.Fatal("timeout")
 	}
 }
+
+func TestRetry_nonRetryableError(t *testing.T) {
+	t.Parallel()
+
+	var tries int32
+	expected := fmt.Errorf("permission denied")
+	f := func() error {
+		atomic.AddInt32(&tries, 1)
+		return NonRetryableError(expected)
+	}
+
+	err := Retry(1*time.Second, f)
+	if err != expected {
+		t.Fatalf("bad: %#v", err)
+	}
+	if n := atomic.LoadInt32(&tries); n != 1 {
+		t.Fatalf("bad: %d", n)
+	}
+}
+
+func TestRetry_retryableError(t *testing.T) {
+	t.Parallel()
+
+	// The function may still be called after Retry times out, so the
+	// tries are counted atomically.
+	var tries int32
+	expected := fmt.Errorf("not found yet")
+	f := func() error {
+		atomic.AddInt32(&tries, 1)
+		return RetryableError(expected)
+	}
+
+	err := Retry(2*time.Second, f)
+	if err != expected {
+		t.Fatalf("bad: %#v", err)
+	}
+	if n := atomic.LoadInt32(&tries); n < 2 {
+		t.Fatalf("bad: %d", n)
+	}
+}
@@ -3,6 +3,9 @@ package schema
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/mitchellh/mapstructure"
 )
 
 // FieldReaders are responsible for decoding fields out of data into
@@ -112,7 +115,11 @@ func addrToSchema(addr []string, schemaMap map[string]*Schema) []*Schema {
 			}
 		case TypeMap:
 			if len(addr) > 0 {
-				current = &Schema{Type: TypeString}
+				if elem, ok := current.Elem.(*Schema); ok {
+					current = elem
+				} else {
+					current = &Schema{Type: TypeString}
+				}
 			}
 		case typeObject:
 			// If we're already in the object, then we want to handle Sets
@@ -284,3 +291,32 @@ func stringToPrimitive(
 
 	return returnVal, nil
 }
+
+// mapValuesToPrimitive converts the values of a map that was read into
+// the type of the Elem of the map schema, if it has one. Values that
+// aren't known yet are left as they are.
+func mapValuesToPrimitive(m map[string]interface{}, schema *Schema) error {
+	elem, ok := schema.Elem.(*Schema)
+	if !ok {
+		return nil
+	}
+
+	for k, raw := range m {
+		var s string
+		if err := mapstructure.WeakDecode(raw, &s); err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+		if s == config.UnknownVariableValue {
+			continue
+		}
+
+		v, err := stringToPrimitive(s, false, elem)
+		if err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+
+		m[k] = v
+	}
+
+	return nil
+}
@@ -91,7 +91,7 @@ func (r *ConfigFieldReader) readField(
 	case TypeList:
 		return readListField(&nestedConfigFieldReader{r}, address, schema)
 	case TypeMap:
-		return r.readMap(k)
+		return r.readMap(k, schema)
 	case TypeSet:
 		result, _, err := r.readSet(address, schema)
 		return result, err
@@ -104,7 +104,8 @@ func (r *ConfigFieldReader) readField(
 	}
 }
 
-func (r *ConfigFieldReader) readMap(k string) (FieldReadResult, error) {
+func (r *ConfigFieldReader) readMap(
+	k string, schema *Schema) (FieldReadResult, error) {
 	mraw, ok := r.Config.Get(k)
 	if !ok {
 		return FieldReadResult{}, nil
@@ -125,11 +126,17 @@ func (r *ConfigFieldReader) readMap(k string) (FieldReadResult, error) {
 			}
 		}
 	case map[string]interface{}:
-		result = m
+		for k, v := range m {
+			result[k] = v
+		}
 	default:
 		panic(fmt.Sprintf("unknown type: %#v", mraw))
 	}
 
+	if err := mapValuesToPrimitive(result, schema); err != nil {
+		return FieldReadResult{}, err
+	}
+
 	return FieldReadResult{
 		Value:  result,
 		Exists: true,
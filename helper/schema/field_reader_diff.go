@@ -98,6 +98,10 @@ func (r *DiffFieldReader) readMap(
 		result[k] = v.New
 	}
 
+	if err := mapValuesToPrimitive(result, schema); err != nil {
+		return FieldReadResult{}, err
+	}
+
 	var resultVal interface{}
 	if resultSet {
 		resultVal = result
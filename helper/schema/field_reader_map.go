@@ -32,7 +32,7 @@ func (r *MapFieldReader) ReadField(address []string) (FieldReadResult, error) {
 	case TypeList:
 		return readListField(r, address, schema)
 	case TypeMap:
-		return r.readMap(k)
+		return r.readMap(k, schema)
 	case TypeSet:
 		return r.readSet(address, schema)
 	case typeObject:
@@ -42,7 +42,8 @@ func (r *MapFieldReader) ReadField(address []string) (FieldReadResult, error) {
 	}
 }
 
-func (r *MapFieldReader) readMap(k string) (FieldReadResult, error) {
+func (r *MapFieldReader) readMap(
+	k string, schema *Schema) (FieldReadResult, error) {
 	result := make(map[string]interface{})
 	resultSet := false
 
@@ -66,6 +67,10 @@ func (r *MapFieldReader) readMap(k string) (FieldReadResult, error) {
 		return true
 	})
 
+	if err := mapValuesToPrimitive(result, schema); err != nil {
+		return FieldReadResult{}, err
+	}
+
 	var resultVal interface{}
 	if resultSet {
 		resultVal = result
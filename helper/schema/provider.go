@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -33,12 +34,26 @@ type Provider struct {
 	// Diff, etc. to the proper resource.
 	ResourcesMap map[string]*Resource
 
+	// DataSourcesMap is the list of available data sources that this
+	// provider can read. Data sources use the Resource structure as well,
+	// but only implement Read, which must set the ID.
+	DataSourcesMap map[string]*Resource
+
 	// ConfigureFunc is a function for configuring the provider. If the
 	// provider doesn't need to be configured, this can be omitted.
 	//
 	// See the ConfigureFunc documentation for more information.
 	ConfigureFunc ConfigureFunc
 
+	// ValidateFunc is an optional function for validating the
+	// configuration of the provider as a whole, such as checking that
+	// mutually exclusive fields aren't set together. It is called during
+	// validation after the schema itself has validated, so it must not
+	// make any API calls.
+	//
+	// See the ProviderValidateFunc documentation for more information.
+	ValidateFunc ProviderValidateFunc
+
 	meta interface{}
 }
 
@@ -50,6 +65,14 @@ type Provider struct {
 // structure, etc.
 type ConfigureFunc func(*ResourceData) (interface{}, error)
 
+// ProviderValidateFunc is the function used to validate the configuration
+// of a Provider. It returns any warnings and errors with the configuration.
+//
+// Values that aren't known yet, such as those interpolated from
+// resources, are the zero value of their type, so these should be
+// skipped rather than reported as errors.
+type ProviderValidateFunc func(*ResourceData) ([]string, []error)
+
 // InternalValidate should be called to validate the structure
 // of the provider.
 //
@@ -71,6 +94,12 @@ func (p *Provider) InternalValidate() error {
 		}
 	}
 
+	for k, r := range p.DataSourcesMap {
+		if err := r.internalValidateDataSource(); err != nil {
+			return fmt.Errorf("data source %s: %s", k, err)
+		}
+	}
+
 	return nil
 }
 
@@ -96,7 +125,18 @@ func (p *Provider) Input(
 
 // Validate implementation of terraform.ResourceProvider interface.
 func (p *Provider) Validate(c *terraform.ResourceConfig) ([]string, []error) {
-	return schemaMap(p.Schema).Validate(c)
+	ws, es := schemaMap(p.Schema).Validate(c)
+	if len(es) > 0 || p.ValidateFunc == nil {
+		return ws, es
+	}
+
+	data, err := p.data(c, true)
+	if err != nil {
+		return ws, []error{err}
+	}
+
+	ws2, es2 := p.ValidateFunc(data)
+	return append(ws, ws2...), append(es, es2...)
 }
 
 // ValidateResource implementation of terraform.ResourceProvider interface.
@@ -111,6 +151,18 @@ func (p *Provider) ValidateResource(
 	return r.Validate(c)
 }
 
+// ValidateDataSource implementation of terraform.ResourceProvider interface.
+func (p *Provider) ValidateDataSource(
+	t string, c *terraform.ResourceConfig) ([]string, []error) {
+	r, ok := p.DataSourcesMap[t]
+	if !ok {
+		return nil, []error{fmt.Errorf(
+			"Provider doesn't support data source: %s", t)}
+	}
+
+	return schemaMap(r.Schema).Validate(c)
+}
+
 // Configure implementation of terraform.ResourceProvider interface.
 func (p *Provider) Configure(c *terraform.ResourceConfig) error {
 	// No configuration
@@ -118,16 +170,7 @@ func (p *Provider) Configure(c *terraform.ResourceConfig) error {
 		return nil
 	}
 
-	sm := schemaMap(p.Schema)
-
-	// Get a ResourceData for this configuration. To do this, we actually
-	// generate an intermediary "diff" although that is never exposed.
-	diff, err := sm.Diff(nil, c)
-	if err != nil {
-		return err
-	}
-
-	data, err := sm.Data(nil, diff)
+	data, err := p.data(c, false)
 	if err != nil {
 		return err
 	}
@@ -141,6 +184,33 @@ func (p *Provider) Configure(c *terraform.ResourceConfig) error {
 	return nil
 }
 
+// data returns a ResourceData for the given provider configuration. If
+// skipComputed is true, values that aren't known yet are left unset
+// rather than containing their raw interpolation.
+func (p *Provider) data(
+	c *terraform.ResourceConfig, skipComputed bool) (*ResourceData, error) {
+	sm := schemaMap(p.Schema)
+
+	// Get a ResourceData for this configuration. To do this, we actually
+	// generate an intermediary "diff" although that is never exposed.
+	diff, err := sm.Diff(nil, c, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if skipComputed && diff != nil {
+		for _, ck := range c.ComputedKeys {
+			for k := range diff.Attributes {
+				if k == ck || strings.HasPrefix(k, ck+".") {
+					delete(diff.Attributes, k)
+				}
+			}
+		}
+	}
+
+	return sm.Data(nil, diff)
+}
+
 // Apply implementation of terraform.ResourceProvider interface.
 func (p *Provider) Apply(
 	info *terraform.InstanceInfo,
@@ -164,7 +234,7 @@ func (p *Provider) Diff(
 		return nil, fmt.Errorf("unknown resource type: %s", info.Type)
 	}
 
-	return r.Diff(s, c)
+	return r.Diff(s, c, p.meta)
 }
 
 // Refresh implementation of terraform.ResourceProvider interface.
@@ -179,6 +249,30 @@ func (p *Provider) Refresh(
 	return r.Refresh(s, p.meta)
 }
 
+// ImportState implementation of terraform.ResourceProvider interface.
+func (p *Provider) ImportState(
+	info *terraform.InstanceInfo,
+	id string) ([]*terraform.InstanceState, error) {
+	r, ok := p.ResourcesMap[info.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type: %s", info.Type)
+	}
+
+	states, err := r.ImportState(id, p.meta)
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %s", info.Type, err)
+	}
+
+	// Only resources of another type have it set by the importer
+	for _, s := range states {
+		if s.Ephemeral.Type == "" {
+			s.Ephemeral.Type = info.Type
+		}
+	}
+
+	return states, nil
+}
+
 // Resources implementation of terraform.ResourceProvider interface.
 func (p *Provider) Resources() []terraform.ResourceType {
 	keys := make([]string, 0, len(p.ResourcesMap))
@@ -196,3 +290,45 @@ func (p *Provider) Resources() []terraform.ResourceType {
 
 	return result
 }
+
+// ReadDataDiff implementation of terraform.ResourceProvider interface.
+func (p *Provider) ReadDataDiff(
+	info *terraform.InstanceInfo,
+	c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	r, ok := p.DataSourcesMap[info.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source: %s", info.Type)
+	}
+
+	return schemaMap(r.Schema).Diff(nil, c, nil, p.meta)
+}
+
+// ReadDataApply implementation of terraform.ResourceProvider interface.
+func (p *Provider) ReadDataApply(
+	info *terraform.InstanceInfo,
+	d *terraform.InstanceDiff) (*terraform.InstanceState, error) {
+	r, ok := p.DataSourcesMap[info.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source: %s", info.Type)
+	}
+
+	return r.ReadDataApply(d, p.meta)
+}
+
+// DataSources implementation of terraform.ResourceProvider interface.
+func (p *Provider) DataSources() []terraform.DataSource {
+	keys := make([]string, 0, len(p.DataSourcesMap))
+	for k := range p.DataSourcesMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]terraform.DataSource, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, terraform.DataSource{
+			Name: k,
+		})
+	}
+
+	return result
+}
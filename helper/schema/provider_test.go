@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/config/lang/ast"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -117,6 +118,203 @@ func TestProviderResources(t *testing.T) {
 	}
 }
 
+func TestProviderDataSources(t *testing.T) {
+	cases := []struct {
+		P      *Provider
+		Result []terraform.DataSource
+	}{
+		{
+			P:      &Provider{},
+			Result: []terraform.DataSource{},
+		},
+
+		{
+			P: &Provider{
+				DataSourcesMap: map[string]*Resource{
+					"foo": nil,
+					"bar": nil,
+				},
+			},
+			Result: []terraform.DataSource{
+				terraform.DataSource{Name: "bar"},
+				terraform.DataSource{Name: "foo"},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := tc.P.DataSources()
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: %#v", i, actual)
+		}
+	}
+}
+
+func TestProviderInternalValidate_dataSource(t *testing.T) {
+	read := func(*ResourceData, interface{}) error { return nil }
+
+	cases := []struct {
+		R   *Resource
+		Err bool
+	}{
+		{
+			R:   &Resource{Read: read},
+			Err: false,
+		},
+
+		// Read is required
+		{
+			R:   &Resource{},
+			Err: true,
+		},
+
+		// Data sources can't be created
+		{
+			R: &Resource{
+				Read:   read,
+				Create: read,
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		p := &Provider{
+			DataSourcesMap: map[string]*Resource{"foo": tc.R},
+		}
+
+		err := p.InternalValidate()
+		if err != nil != tc.Err {
+			t.Fatalf("%d: bad: %s", i, err)
+		}
+	}
+}
+
+func TestProviderValidate(t *testing.T) {
+	validateFunc := func(d *ResourceData) ([]string, []error) {
+		_, okA := d.GetOk("a")
+		_, okB := d.GetOk("b")
+		if okA && okB {
+			return nil, []error{fmt.Errorf("only one of a or b can be set")}
+		}
+		if !okA && !okB {
+			return []string{"neither a nor b is set"}, nil
+		}
+
+		return nil, nil
+	}
+
+	cases := []struct {
+		P      *Provider
+		Config map[string]interface{}
+		Warn   bool
+		Err    bool
+	}{
+		{
+			P:      &Provider{},
+			Config: nil,
+		},
+
+		// Schema errors are returned before ValidateFunc is called
+		{
+			P: &Provider{
+				Schema: map[string]*Schema{
+					"a": &Schema{
+						Type:     TypeString,
+						Required: true,
+					},
+				},
+				ValidateFunc: func(*ResourceData) ([]string, []error) {
+					panic("should not be called")
+				},
+			},
+			Config: nil,
+			Err:    true,
+		},
+
+		{
+			P: &Provider{
+				Schema: map[string]*Schema{
+					"a": &Schema{Type: TypeString, Optional: true},
+					"b": &Schema{Type: TypeString, Optional: true},
+				},
+				ValidateFunc: validateFunc,
+			},
+			Config: map[string]interface{}{
+				"a": "foo",
+			},
+		},
+
+		{
+			P: &Provider{
+				Schema: map[string]*Schema{
+					"a": &Schema{Type: TypeString, Optional: true},
+					"b": &Schema{Type: TypeString, Optional: true},
+				},
+				ValidateFunc: validateFunc,
+			},
+			Config: map[string]interface{}{
+				"a": "foo",
+				"b": "bar",
+			},
+			Err: true,
+		},
+
+		{
+			P: &Provider{
+				Schema: map[string]*Schema{
+					"a": &Schema{Type: TypeString, Optional: true},
+					"b": &Schema{Type: TypeString, Optional: true},
+				},
+				ValidateFunc: validateFunc,
+			},
+			Config: nil,
+			Warn:   true,
+		},
+
+		// Computed values are zero
+		{
+			P: &Provider{
+				Schema: map[string]*Schema{
+					"a": &Schema{Type: TypeString, Optional: true},
+					"b": &Schema{Type: TypeString, Optional: true},
+				},
+				ValidateFunc: validateFunc,
+			},
+			Config: map[string]interface{}{
+				"a": "foo",
+				"b": "${var.foo}",
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if tc.Config != nil {
+			if err := c.Interpolate(map[string]ast.Variable{
+				"var.foo": ast.Variable{
+					Value: config.UnknownVariableValue,
+					Type:  ast.TypeString,
+				},
+			}); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		}
+
+		ws, es := tc.P.Validate(terraform.NewResourceConfig(c))
+		if (len(ws) > 0) != tc.Warn {
+			t.Fatalf("%d: %#v", i, ws)
+		}
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: %s", i, es)
+		}
+	}
+}
+
 func TestProviderValidateResource(t *testing.T) {
 	cases := []struct {
 		P      *Provider
@@ -168,3 +366,120 @@ func TestProviderMeta(t *testing.T) {
 		t.Fatalf("bad: %#v", v)
 	}
 }
+
+func TestProviderImportState(t *testing.T) {
+	rule := &Resource{
+		Schema: map[string]*Schema{
+			"group": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+	}
+
+	p := &Provider{
+		ResourcesMap: map[string]*Resource{
+			"foo": &Resource{},
+			"passthrough": &Resource{
+				Importer: &ResourceImporter{},
+			},
+			"group": &Resource{
+				Importer: &ResourceImporter{
+					State: func(d *ResourceData, m interface{}) ([]*ResourceData, error) {
+						r := rule.Data(nil)
+						r.SetId(d.Id() + "-rule")
+						r.SetType("rule")
+						r.Set("group", d.Id())
+
+						return []*ResourceData{d, r}, nil
+					},
+				},
+			},
+			"noid": &Resource{
+				Importer: &ResourceImporter{
+					State: func(d *ResourceData, m interface{}) ([]*ResourceData, error) {
+						d.SetId("")
+						return []*ResourceData{d}, nil
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		Type   string
+		Result []*terraform.InstanceState
+		Err    bool
+	}{
+		// Unknown type
+		{
+			Type: "unknown",
+			Err:  true,
+		},
+
+		// No importer
+		{
+			Type: "foo",
+			Err:  true,
+		},
+
+		// Default passthrough
+		{
+			Type: "passthrough",
+			Result: []*terraform.InstanceState{
+				&terraform.InstanceState{
+					ID: "bar",
+					Attributes: map[string]string{
+						"id": "bar",
+					},
+					Ephemeral: terraform.EphemeralState{
+						Type: "passthrough",
+					},
+				},
+			},
+		},
+
+		// Multiple resources
+		{
+			Type: "group",
+			Result: []*terraform.InstanceState{
+				&terraform.InstanceState{
+					ID: "bar",
+					Attributes: map[string]string{
+						"id": "bar",
+					},
+					Ephemeral: terraform.EphemeralState{
+						Type: "group",
+					},
+				},
+				&terraform.InstanceState{
+					ID: "bar-rule",
+					Attributes: map[string]string{
+						"id":    "bar-rule",
+						"group": "bar",
+					},
+					Ephemeral: terraform.EphemeralState{
+						Type: "rule",
+					},
+				},
+			},
+		},
+
+		// A result without an ID
+		{
+			Type: "noid",
+			Err:  true,
+		},
+	}
+
+	for i, tc := range cases {
+		info := &terraform.InstanceInfo{Type: tc.Type}
+		actual, err := p.ImportState(info, "bar")
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if !reflect.DeepEqual(actual, tc.Result) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
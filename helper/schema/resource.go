@@ -3,10 +3,16 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
 )
 
+// schemaVersionKey is the key within the Meta of a state that stores
+// the SchemaVersion of the resource that wrote it.
+const schemaVersionKey = "schema_version"
+
 // Resource represents a thing in Terraform that has a set of configurable
 // attributes and a lifecycle (create, read, update, delete).
 //
@@ -24,6 +30,20 @@ type Resource struct {
 	// resource.
 	Schema map[string]*Schema
 
+	// SchemaVersion is the version of the schema, which is stored in the
+	// state of every resource. It should be incremented whenever the
+	// way the attributes are stored changes, such as when an attribute
+	// is renamed or the hash function of a set changes, along with a
+	// MigrateState function to upgrade existing states.
+	//
+	// MigrateState is called with the version the state was written
+	// with whenever it is older than SchemaVersion, before the state is
+	// used for anything else. It must return the state upgraded to the
+	// current SchemaVersion, and may modify the state it is given. If it
+	// isn't set, then the state is only marked with the new version.
+	SchemaVersion int
+	MigrateState  StateMigrateFunc
+
 	// The functions below are the CRUD operations for this resource.
 	//
 	// The only optional operation is Update. If Update is not implemented,
@@ -52,6 +72,30 @@ type Resource struct {
 	Update UpdateFunc
 	Delete DeleteFunc
 	Exists ExistsFunc
+
+	// CustomizeDiff is called after the diff of the resource has been
+	// computed from the schema, and can change it through the
+	// *ResourceDiff. This is useful for changes that can't be described
+	// statically by the schema, such as a change that can only sometimes
+	// be made in-place, or a computed attribute whose new value depends
+	// on other attributes.
+	//
+	// If an error is returned, then the diff is rejected with that error.
+	// The function may be called more than once per diff, since the diff
+	// is computed again when a new resource is required. The interface{}
+	// parameter is the same as for the CRUD operations above.
+	CustomizeDiff CustomizeDiffFunc
+
+	// Timeouts are the default timeouts of the operations of this
+	// resource, which are available within them with ResourceData.Timeout.
+	// Users can override the timeouts that are set here with a
+	// "timeouts" block in the configuration of the resource.
+	Timeouts *ResourceTimeout
+
+	// Importer is used to import an existing resource into Terraform
+	// from its ID. If it isn't set, then the resource can't be imported.
+	// See ResourceImporter for more information.
+	Importer *ResourceImporter
 }
 
 // See Resource documentation.
@@ -69,11 +113,23 @@ type DeleteFunc func(*ResourceData, interface{}) error
 // See Resource documentation.
 type ExistsFunc func(*ResourceData, interface{}) (bool, error)
 
+// See Resource documentation.
+type StateMigrateFunc func(
+	int, *terraform.InstanceState, interface{}) (*terraform.InstanceState, error)
+
+// See Resource documentation.
+type CustomizeDiffFunc func(*ResourceDiff, interface{}) error
+
 // Apply creates, updates, and/or deletes a resource.
 func (r *Resource) Apply(
 	s *terraform.InstanceState,
 	d *terraform.InstanceDiff,
 	meta interface{}) (*terraform.InstanceState, error) {
+	s, err := r.migrateState(s, meta)
+	if err != nil {
+		return s, err
+	}
+
 	data, err := schemaMap(r.Schema).Data(s, d)
 	if err != nil {
 		return s, err
@@ -85,11 +141,19 @@ func (r *Resource) Apply(
 		s = new(terraform.InstanceState)
 	}
 
+	// The timeouts set in the configuration are in the diff, except for
+	// diffs that only destroy, where they are kept in the state.
+	timeoutMeta := d.Meta
+	if timeoutMeta == nil {
+		timeoutMeta = s.Meta
+	}
+	data.timeouts = r.Timeouts.timeouts(timeoutMeta)
+
 	if d.Destroy || d.RequiresNew() {
 		if s.ID != "" {
 			// Destroy the resource since it is created
 			if err := r.Delete(data, meta); err != nil {
-				return data.State(), err
+				return r.finalizeState(data.State(), timeoutMeta), err
 			}
 
 			// Make sure the ID is gone.
@@ -107,6 +171,7 @@ func (r *Resource) Apply(
 		if err != nil {
 			return nil, err
 		}
+		data.timeouts = r.Timeouts.timeouts(timeoutMeta)
 	}
 
 	err = nil
@@ -121,33 +186,134 @@ func (r *Resource) Apply(
 		err = r.Update(data, meta)
 	}
 
-	return data.State(), err
+	return r.finalizeState(data.State(), timeoutMeta), err
 }
 
 // Diff returns a diff of this resource and is API compatible with the
 // ResourceProvider interface.
 func (r *Resource) Diff(
 	s *terraform.InstanceState,
-	c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
-	return schemaMap(r.Schema).Diff(s, c)
+	c *terraform.ResourceConfig,
+	meta interface{}) (*terraform.InstanceDiff, error) {
+	s, err := r.migrateState(s, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	c, timeoutMeta, err := r.Timeouts.splitTimeouts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := schemaMap(r.Schema).Diff(s, c, r.CustomizeDiff, meta)
+	if diff != nil && len(timeoutMeta) > 0 {
+		diff.Meta = timeoutMeta
+	}
+
+	return diff, err
+}
+
+// Data returns a ResourceData for this resource with the given state,
+// which can be nil. This is mostly useful for importers that return
+// resources of other types than the one being imported.
+func (r *Resource) Data(s *terraform.InstanceState) *ResourceData {
+	data, err := schemaMap(r.Schema).Data(s, nil)
+	if err != nil {
+		// Data never returns an error today, so this shouldn't happen
+		panic(err)
+	}
+
+	var meta map[string]string
+	if s != nil {
+		meta = s.Meta
+	}
+	data.timeouts = r.Timeouts.timeouts(meta)
+
+	return data
+}
+
+// ImportState returns the state of the resources that result from
+// importing the resource with the given ID.
+func (r *Resource) ImportState(
+	id string,
+	meta interface{}) ([]*terraform.InstanceState, error) {
+	if r.Importer == nil {
+		return nil, errors.New("resource doesn't support importing")
+	}
+
+	f := r.Importer.State
+	if f == nil {
+		f = ImportStatePassthrough
+	}
+
+	data := r.Data(nil)
+	data.SetId(id)
+
+	results, err := f(data, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*terraform.InstanceState, 0, len(results))
+	for i, d := range results {
+		if d == nil {
+			return nil, fmt.Errorf("import result %d is nil", i)
+		}
+
+		state := d.State()
+		if state == nil {
+			return nil, fmt.Errorf("import result %d has no ID", i)
+		}
+
+		states = append(states, r.recordSchemaVersion(state))
+	}
+
+	return states, nil
 }
 
 // Validate validates the resource configuration against the schema.
 func (r *Resource) Validate(c *terraform.ResourceConfig) ([]string, []error) {
+	c, _, err := r.Timeouts.splitTimeouts(c)
+	if err != nil {
+		return nil, []error{err}
+	}
+
 	return schemaMap(r.Schema).Validate(c)
 }
 
+// ReadDataApply reads a data source with the given diff, which is against
+// an empty state, and returns its state. The Read function must set the
+// ID, otherwise the state is nil.
+func (r *Resource) ReadDataApply(
+	d *terraform.InstanceDiff,
+	meta interface{}) (*terraform.InstanceState, error) {
+	data, err := schemaMap(r.Schema).Data(nil, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.Read(data, meta)
+	state := data.State()
+	if state != nil && state.ID == "" {
+		state = nil
+	}
+
+	return r.recordSchemaVersion(state), err
+}
+
 // Refresh refreshes the state of the resource.
 func (r *Resource) Refresh(
 	s *terraform.InstanceState,
 	meta interface{}) (*terraform.InstanceState, error) {
+	s, err := r.migrateState(s, meta)
+	if err != nil {
+		return s, err
+	}
+
 	if r.Exists != nil {
 		// Make a copy of data so that if it is modified it doesn't
 		// affect our Read later.
-		data, err := schemaMap(r.Schema).Data(s, nil)
-		if err != nil {
-			return s, err
-		}
+		data := r.Data(s)
 
 		exists, err := r.Exists(data, meta)
 		if err != nil {
@@ -158,18 +324,95 @@ func (r *Resource) Refresh(
 		}
 	}
 
-	data, err := schemaMap(r.Schema).Data(s, nil)
-	if err != nil {
-		return s, err
-	}
-
+	data := r.Data(s)
 	err = r.Read(data, meta)
 	state := data.State()
 	if state != nil && state.ID == "" {
 		state = nil
 	}
 
-	return state, err
+	var timeoutMeta map[string]string
+	if s != nil {
+		timeoutMeta = s.Meta
+	}
+
+	return r.finalizeState(state, timeoutMeta), err
+}
+
+// migrateState upgrades the given state with MigrateState if it was
+// written with an older SchemaVersion than the current one.
+func (r *Resource) migrateState(
+	s *terraform.InstanceState,
+	meta interface{}) (*terraform.InstanceState, error) {
+	if s == nil || s.ID == "" {
+		return s, nil
+	}
+
+	version := 0
+	if raw, ok := s.Meta[schemaVersionKey]; ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return s, fmt.Errorf("invalid schema version %q: %s", raw, err)
+		}
+
+		version = v
+	}
+	if version >= r.SchemaVersion {
+		return s, nil
+	}
+
+	if r.MigrateState != nil {
+		migrated, err := r.MigrateState(version, s, meta)
+		if err != nil {
+			return s, fmt.Errorf(
+				"error migrating state from schema version %d to %d: %s",
+				version, r.SchemaVersion, err)
+		}
+
+		s = migrated
+	}
+
+	return r.recordSchemaVersion(s), nil
+}
+
+// finalizeState carries the timeouts that were set in the configuration
+// from the given Meta over to the new state, and records the current
+// SchemaVersion in it.
+func (r *Resource) finalizeState(
+	s *terraform.InstanceState,
+	meta map[string]string) *terraform.InstanceState {
+	if s == nil {
+		return nil
+	}
+
+	for k, v := range meta {
+		if !strings.HasPrefix(k, timeoutMetaPrefix) {
+			continue
+		}
+
+		if s.Meta == nil {
+			s.Meta = make(map[string]string)
+		}
+		s.Meta[k] = v
+	}
+
+	return r.recordSchemaVersion(s)
+}
+
+// recordSchemaVersion marks the given state as written with the
+// current SchemaVersion. Nothing is recorded for the initial version.
+func (r *Resource) recordSchemaVersion(
+	s *terraform.InstanceState) *terraform.InstanceState {
+	if s == nil || r.SchemaVersion == 0 {
+		return s
+	}
+
+	if s.Meta == nil {
+		s.Meta = make(map[string]string)
+	}
+	s.Meta[schemaVersionKey] = strconv.Itoa(r.SchemaVersion)
+
+	return s
 }
 
 // InternalValidate should be called to validate the structure
@@ -189,3 +432,21 @@ func (r *Resource) InternalValidate() error {
 
 	return schemaMap(r.Schema).InternalValidate()
 }
+
+// internalValidateDataSource is like InternalValidate, but for a
+// Resource that is used as a data source, which can only be read.
+func (r *Resource) internalValidateDataSource() error {
+	if err := r.InternalValidate(); err != nil {
+		return err
+	}
+
+	if r.Read == nil {
+		return errors.New("Read must be set")
+	}
+
+	if r.Create != nil || r.Update != nil || r.Delete != nil {
+		return errors.New("Create, Update and Delete must not be set")
+	}
+
+	return nil
+}
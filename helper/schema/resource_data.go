@@ -2,8 +2,10 @@ package schema
 
 import (
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -23,6 +25,9 @@ type ResourceData struct {
 	state  *terraform.InstanceState
 	diff   *terraform.InstanceDiff
 
+	// timeouts of the operations, see Timeout
+	timeouts map[string]time.Duration
+
 	// Don't set
 	multiReader *MultiLevelFieldReader
 	setWriter   *MapFieldWriter
@@ -193,6 +198,25 @@ func (d *ResourceData) SetConnInfo(v map[string]string) {
 	d.newState.Ephemeral.ConnInfo = v
 }
 
+// Timeout returns the timeout of the given operation, such as
+// TimeoutCreate. This is the timeout that is set in the configuration of
+// the resource, or else the default from the Timeouts of the resource.
+func (d *ResourceData) Timeout(key string) time.Duration {
+	if v, ok := d.timeouts[key]; ok {
+		return v
+	}
+
+	return defaultTimeout
+}
+
+// SetType sets the type of the resource. This is only needed by
+// importers that return resources of another type than the one being
+// imported.
+func (d *ResourceData) SetType(t string) {
+	d.once.Do(d.init)
+	d.newState.Ephemeral.Type = t
+}
+
 // State returns the new InstanceState after the diff and any Set
 // calls.
 func (d *ResourceData) State() *terraform.InstanceState {
@@ -233,6 +257,10 @@ func (d *ResourceData) State() *terraform.InstanceState {
 
 	result.Attributes = mapW.Map()
 	result.Ephemeral.ConnInfo = d.ConnInfo()
+	if d.newState != nil {
+		result.Ephemeral.Type = d.newState.Ephemeral.Type
+	}
+	result.Sensitive = d.sensitiveKeys(result.Attributes)
 
 	// TODO: This is hacky and we can remove this when we have a proper
 	// state writer. We should instead have a proper StateFieldWriter
@@ -254,6 +282,23 @@ func (d *ResourceData) State() *terraform.InstanceState {
 	return &result
 }
 
+// sensitiveKeys returns the sorted keys of the given attributes whose
+// schema, or the schema of anything that contains them, is Sensitive.
+func (d *ResourceData) sensitiveKeys(attrs map[string]string) []string {
+	var result []string
+	for k, _ := range attrs {
+		for _, schema := range addrToSchema(strings.Split(k, "."), d.schema) {
+			if schema.Sensitive {
+				result = append(result, k)
+				break
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
 func (d *ResourceData) init() {
 	// Initialize the field that will store our new state
 	var copyState terraform.InstanceState
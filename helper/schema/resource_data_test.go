@@ -688,6 +688,46 @@ func TestResourceDataGet(t *testing.T) {
 
 			Value: 33.0,
 		},
+
+		// #23 Map with typed values
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+				},
+			},
+
+			State: &terraform.InstanceState{
+				Attributes: map[string]string{
+					"ports.#":     "2",
+					"ports.http":  "80",
+					"ports.https": "443",
+				},
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"ports.#": &terraform.ResourceAttrDiff{
+						Old: "2",
+						New: "3",
+					},
+					"ports.ssh": &terraform.ResourceAttrDiff{
+						Old: "",
+						New: "22",
+					},
+				},
+			},
+
+			Key: "ports",
+
+			Value: map[string]interface{}{
+				"http":  80,
+				"https": 443,
+				"ssh":   22,
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -2695,6 +2735,77 @@ func TestResourceDataState(t *testing.T) {
 				},
 			},
 		},
+
+		// #28 Map with typed values
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+				},
+			},
+
+			State: nil,
+
+			Diff: nil,
+
+			Set: map[string]interface{}{
+				"ports": map[string]interface{}{
+					"http":  80,
+					"https": 443,
+				},
+			},
+
+			Result: &terraform.InstanceState{
+				Attributes: map[string]string{
+					"ports.#":     "2",
+					"ports.http":  "80",
+					"ports.https": "443",
+				},
+			},
+		},
+
+		// #29 Sensitive
+		{
+			Schema: map[string]*Schema{
+				"password": &Schema{
+					Type:      TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"keys": &Schema{
+					Type:      TypeList,
+					Optional:  true,
+					Sensitive: true,
+					Elem:      &Schema{Type: TypeString},
+				},
+				"user": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+
+			State: nil,
+
+			Diff: nil,
+
+			Set: map[string]interface{}{
+				"password": "secret",
+				"keys":     []interface{}{"foo"},
+				"user":     "root",
+			},
+
+			Result: &terraform.InstanceState{
+				Attributes: map[string]string{
+					"password": "secret",
+					"keys.#":   "1",
+					"keys.0":   "foo",
+					"user":     "root",
+				},
+				Sensitive: []string{"keys.#", "keys.0", "password"},
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -0,0 +1,203 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ResourceDiff is used to query and change the diff of a resource while
+// it is being computed. It is the argument received by the CustomizeDiff
+// function of a resource.
+//
+// Values are read the same way as with ResourceData, where the new value
+// of a key is the value that the diff will result in. Only keys that are
+// computed can be given a new value with SetNew or SetNewComputed, since
+// the values of all other keys come from the configuration.
+type ResourceDiff struct {
+	schema map[string]*Schema
+	state  *terraform.InstanceState
+	diff   *terraform.InstanceDiff
+	data   *ResourceData
+}
+
+// newResourceDiff returns a ResourceDiff that reads and changes the
+// given diff, which was computed from the state and the config.
+func newResourceDiff(
+	schema map[string]*Schema,
+	c *terraform.ResourceConfig,
+	s *terraform.InstanceState,
+	d *terraform.InstanceDiff) *ResourceDiff {
+	return &ResourceDiff{
+		schema: schema,
+		state:  s,
+		diff:   d,
+		data: &ResourceData{
+			schema: schema,
+			config: c,
+			state:  s,
+			diff:   d,
+		},
+	}
+}
+
+// Get returns the new value of the given key, as a result of the diff.
+//
+// See ResourceData.Get for more information.
+func (d *ResourceDiff) Get(key string) interface{} {
+	return d.data.Get(key)
+}
+
+// GetChange returns the old and new value of the given key.
+func (d *ResourceDiff) GetChange(key string) (interface{}, interface{}) {
+	return d.data.GetChange(key)
+}
+
+// GetOk returns the new value of the given key and whether or not it
+// has been set to a non-zero value.
+//
+// See ResourceData.GetOk for more information.
+func (d *ResourceDiff) GetOk(key string) (interface{}, bool) {
+	return d.data.GetOk(key)
+}
+
+// HasChange returns whether or not the given key has been changed.
+func (d *ResourceDiff) HasChange(key string) bool {
+	return d.data.HasChange(key)
+}
+
+// Id returns the ID of the resource, which is blank if the resource
+// doesn't exist yet.
+func (d *ResourceDiff) Id() string {
+	if d.state == nil {
+		return ""
+	}
+
+	return d.state.ID
+}
+
+// SetNew sets the new value of a computed key, such as a value that is
+// derived from other attributes in the configuration.
+func (d *ResourceDiff) SetNew(key string, value interface{}) error {
+	if err := d.checkKey(key, "SetNew"); err != nil {
+		return err
+	}
+
+	w := &MapFieldWriter{Schema: d.schema}
+	if err := w.WriteField(strings.Split(key, "."), value); err != nil {
+		return fmt.Errorf("SetNew: %s: %s", key, err)
+	}
+
+	d.clear(key)
+
+	var old map[string]string
+	if d.state != nil {
+		old = d.state.Attributes
+	}
+
+	values := w.Map()
+	for k, v := range values {
+		if o, ok := old[k]; ok && o == v {
+			continue
+		}
+
+		d.diff.Attributes[k] = &terraform.ResourceAttrDiff{
+			Old: old[k],
+			New: v,
+		}
+	}
+
+	// Anything that was set before but isn't anymore is removed
+	for k, v := range old {
+		if _, ok := values[k]; ok || !isKeyOrChild(k, key) {
+			continue
+		}
+
+		d.diff.Attributes[k] = &terraform.ResourceAttrDiff{
+			Old:        v,
+			NewRemoved: true,
+		}
+	}
+
+	return nil
+}
+
+// SetNewComputed marks a computed key as changing to a value that won't
+// be known until the resource is applied.
+func (d *ResourceDiff) SetNewComputed(key string) error {
+	if err := d.checkKey(key, "SetNewComputed"); err != nil {
+		return err
+	}
+
+	d.clear(key)
+
+	// Lists, sets and maps are computed by their count
+	k := key
+	if schemaL := addrToSchema(strings.Split(key, "."), d.schema); len(schemaL) > 0 {
+		switch schemaL[len(schemaL)-1].Type {
+		case TypeList, TypeSet, TypeMap:
+			k = key + ".#"
+		}
+	}
+
+	var old string
+	if d.state != nil {
+		old = d.state.Attributes[k]
+	}
+
+	d.diff.Attributes[k] = &terraform.ResourceAttrDiff{
+		Old:         old,
+		NewComputed: true,
+	}
+
+	return nil
+}
+
+// ForceNew marks the change of the given key as requiring a new resource,
+// which is useful when only some changes of a key can be updated in-place.
+// It is an error if the key isn't changing.
+func (d *ResourceDiff) ForceNew(key string) error {
+	if !d.HasChange(key) {
+		return fmt.Errorf("ForceNew: no changes for %s", key)
+	}
+
+	for k, attr := range d.diff.Attributes {
+		if attr != nil && isKeyOrChild(k, key) {
+			attr.RequiresNew = true
+		}
+	}
+
+	return nil
+}
+
+// checkKey verifies that the new value of the key can be set by the
+// CustomizeDiff function.
+func (d *ResourceDiff) checkKey(key, caller string) error {
+	parts := strings.Split(key, ".")
+	s, ok := d.schema[parts[0]]
+	if !ok || len(addrToSchema(parts, d.schema)) == 0 {
+		return fmt.Errorf("%s: invalid key: %s", caller, key)
+	}
+	if !s.Computed {
+		return fmt.Errorf(
+			"%s only operates on computed keys - %s is not one", caller, key)
+	}
+
+	return nil
+}
+
+// clear removes the diff of the given key and anything within it.
+func (d *ResourceDiff) clear(key string) {
+	for k, _ := range d.diff.Attributes {
+		if isKeyOrChild(k, key) {
+			delete(d.diff.Attributes, k)
+		}
+	}
+}
+
+// isKeyOrChild returns true if the flattened key k is key itself or
+// an element within it.
+func isKeyOrChild(k, key string) bool {
+	return k == key || strings.HasPrefix(k, key+".")
+}
@@ -0,0 +1,254 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResourceDiff(t *testing.T) {
+	sizeSchema := map[string]*Schema{
+		"size": &Schema{
+			Type:     TypeInt,
+			Optional: true,
+		},
+	}
+
+	computedSchema := map[string]*Schema{
+		"name": &Schema{
+			Type:     TypeString,
+			Optional: true,
+		},
+
+		"arn": &Schema{
+			Type:     TypeString,
+			Computed: true,
+		},
+
+		"tags": &Schema{
+			Type:     TypeList,
+			Computed: true,
+			Elem:     &Schema{Type: TypeString},
+		},
+	}
+
+	cases := []struct {
+		Schema map[string]*Schema
+		State  *terraform.InstanceState
+		Config map[string]interface{}
+		F      CustomizeDiffFunc
+		Diff   *terraform.InstanceDiff
+		Err    bool
+	}{
+		// Force a new resource only when shrinking
+		{
+			Schema: sizeSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"size": "2",
+				},
+			},
+			Config: map[string]interface{}{
+				"size": 1,
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				o, n := d.GetChange("size")
+				if n.(int) < o.(int) {
+					return d.ForceNew("size")
+				}
+
+				return nil
+			},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"size": &terraform.ResourceAttrDiff{
+						Old:         "2",
+						New:         "1",
+						RequiresNew: true,
+					},
+				},
+			},
+		},
+
+		// Growing is in-place
+		{
+			Schema: sizeSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"size": "2",
+				},
+			},
+			Config: map[string]interface{}{
+				"size": 3,
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				o, n := d.GetChange("size")
+				if n.(int) < o.(int) {
+					return d.ForceNew("size")
+				}
+
+				return nil
+			},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"size": &terraform.ResourceAttrDiff{
+						Old: "2",
+						New: "3",
+					},
+				},
+			},
+		},
+
+		// ForceNew without a change
+		{
+			Schema: sizeSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"size": "2",
+				},
+			},
+			Config: map[string]interface{}{
+				"size": 2,
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.ForceNew("size")
+			},
+			Err: true,
+		},
+
+		// Derived computed value
+		{
+			Schema: computedSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"name":   "foo",
+					"arn":    "arn:foo",
+					"tags.#": "0",
+				},
+			},
+			Config: map[string]interface{}{
+				"name": "bar",
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.SetNew("arn", "arn:"+d.Get("name").(string))
+			},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"name": &terraform.ResourceAttrDiff{
+						Old: "foo",
+						New: "bar",
+					},
+					"arn": &terraform.ResourceAttrDiff{
+						Old: "arn:foo",
+						New: "arn:bar",
+					},
+				},
+			},
+		},
+
+		// Computed list
+		{
+			Schema: computedSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"name":   "foo",
+					"tags.#": "1",
+					"tags.0": "foo",
+				},
+			},
+			Config: map[string]interface{}{
+				"name": "bar",
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.SetNewComputed("tags")
+			},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"name": &terraform.ResourceAttrDiff{
+						Old: "foo",
+						New: "bar",
+					},
+					"tags.#": &terraform.ResourceAttrDiff{
+						Old:         "1",
+						NewComputed: true,
+					},
+				},
+			},
+		},
+
+		// New list value
+		{
+			Schema: computedSchema,
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"tags.#": "2",
+					"tags.0": "foo",
+					"tags.1": "bar",
+				},
+			},
+			Config: map[string]interface{}{},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.SetNew("tags", []interface{}{"foo"})
+			},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"tags.#": &terraform.ResourceAttrDiff{
+						Old: "2",
+						New: "1",
+					},
+					"tags.1": &terraform.ResourceAttrDiff{
+						Old:        "bar",
+						NewRemoved: true,
+					},
+				},
+			},
+		},
+
+		// SetNew on a key that isn't computed
+		{
+			Schema: computedSchema,
+			Config: map[string]interface{}{
+				"name": "bar",
+			},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.SetNew("name", "baz")
+			},
+			Err: true,
+		},
+
+		// SetNew on a key that doesn't exist
+		{
+			Schema: computedSchema,
+			Config: map[string]interface{}{},
+			F: func(d *ResourceDiff, meta interface{}) error {
+				return d.SetNew("nope", "baz")
+			},
+			Err: true,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("#%d err: %s", i, err)
+		}
+
+		d, err := schemaMap(tc.Schema).Diff(
+			tc.State, terraform.NewResourceConfig(c), tc.F, nil)
+		if (err != nil) != tc.Err {
+			t.Fatalf("#%d err: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(tc.Diff, d) {
+			t.Fatalf("#%d: bad:\n\n%#v", i, d)
+		}
+	}
+}
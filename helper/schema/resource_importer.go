@@ -0,0 +1,33 @@
+package schema
+
+// ResourceImporter defines how a resource is imported into Terraform
+// from the ID of an existing resource.
+//
+// Importing only turns the ID into state: the state of every imported
+// resource is refreshed afterwards with the Read function of its
+// resource, so importers usually only need to set the ID.
+type ResourceImporter struct {
+	// State is called to turn the ID of the resource, which is already
+	// set on the given *ResourceData, into the data of one or more
+	// resources. If it isn't set, ImportStatePassthrough is used.
+	//
+	// Returning multiple resources is useful when a single remote object
+	// is managed by Terraform as many resources, such as a security group
+	// and each of its rules. Any resource of another type than the one
+	// being imported must be created with the Data function of its own
+	// Resource and have its type set with ResourceData.SetType.
+	//
+	// The interface{} parameter is the same as for the CRUD operations.
+	State StateFunc
+}
+
+// StateFunc is the function called to import a resource into the
+// Terraform state. See ResourceImporter for more information.
+type StateFunc func(*ResourceData, interface{}) ([]*ResourceData, error)
+
+// ImportStatePassthrough is a StateFunc that returns the given
+// *ResourceData as is, which is enough for any resource that can be
+// read from its ID alone.
+func ImportStatePassthrough(d *ResourceData, m interface{}) ([]*ResourceData, error) {
+	return []*ResourceData{d}, nil
+}
@@ -3,8 +3,11 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -57,6 +60,132 @@ func TestResourceApply_create(t *testing.T) {
 	}
 }
 
+func TestResourceApply_schemaVersion(t *testing.T) {
+	r := &Resource{
+		SchemaVersion: 2,
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+	}
+
+	r.Create = func(d *ResourceData, m interface{}) error {
+		d.SetId("foo")
+		return nil
+	}
+
+	var s *terraform.InstanceState = nil
+
+	d := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"foo": &terraform.ResourceAttrDiff{
+				New: "42",
+			},
+		},
+	}
+
+	actual, err := r.Apply(s, d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"id":  "foo",
+			"foo": "42",
+		},
+		Meta: map[string]string{
+			"schema_version": "2",
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestResourceApply_timeouts(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+		Timeouts: &ResourceTimeout{
+			Create: DefaultTimeout(10 * time.Minute),
+			Read:   DefaultTimeout(5 * time.Minute),
+		},
+	}
+
+	var create, read, remove time.Duration
+	r.Create = func(d *ResourceData, m interface{}) error {
+		create = d.Timeout(TimeoutCreate)
+		read = d.Timeout(TimeoutRead)
+		remove = d.Timeout(TimeoutDelete)
+		d.SetId("foo")
+		return nil
+	}
+
+	d := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"foo": &terraform.ResourceAttrDiff{
+				New: "42",
+			},
+		},
+		Meta: map[string]string{
+			"timeouts.create": "1h0m0s",
+		},
+	}
+
+	actual, err := r.Apply(nil, d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if create != time.Hour {
+		t.Fatalf("bad create timeout: %s", create)
+	}
+	if read != 5*time.Minute {
+		t.Fatalf("bad read timeout: %s", read)
+	}
+	if remove != 20*time.Minute {
+		t.Fatalf("bad delete timeout: %s", remove)
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"id":  "foo",
+			"foo": "42",
+		},
+		Meta: map[string]string{
+			"timeouts.create": "1h0m0s",
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// The configured timeouts are kept in the state for later operations
+	r.Read = func(d *ResourceData, m interface{}) error {
+		create = d.Timeout(TimeoutCreate)
+		return nil
+	}
+
+	create = 0
+	if _, err := r.Refresh(actual, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if create != time.Hour {
+		t.Fatalf("bad create timeout: %s", create)
+	}
+}
+
 func TestResourceApply_destroy(t *testing.T) {
 	r := &Resource{
 		Schema: map[string]*Schema{
@@ -303,6 +432,185 @@ func TestResourceApply_updateNoCallback(t *testing.T) {
 	}
 }
 
+func TestResourceDiff_customizeDiff(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+	}
+
+	var meta interface{}
+	r.CustomizeDiff = func(d *ResourceDiff, m interface{}) error {
+		meta = m
+		if d.Get("foo").(string) == "bad" {
+			return fmt.Errorf("foo can't be bad")
+		}
+
+		return nil
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"foo": "bar",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d, err := r.Diff(nil, terraform.NewResourceConfig(raw), 42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d == nil || d.Attributes["foo"].New != "bar" {
+		t.Fatalf("bad: %#v", d)
+	}
+	if meta != 42 {
+		t.Fatalf("bad: %#v", meta)
+	}
+
+	raw, err = config.NewRawConfig(map[string]interface{}{
+		"foo": "bad",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := r.Diff(nil, terraform.NewResourceConfig(raw), 42); err == nil {
+		t.Fatal("should error")
+	}
+}
+
+func TestResourceDiff_timeouts(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: &ResourceTimeout{
+			Create: DefaultTimeout(10 * time.Minute),
+		},
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"foo": "bar",
+		"timeouts": []map[string]interface{}{
+			map[string]interface{}{
+				"create": "1h",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d, err := r.Diff(nil, terraform.NewResourceConfig(raw), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"foo": &terraform.ResourceAttrDiff{
+				Old: "",
+				New: "bar",
+			},
+		},
+		Meta: map[string]string{
+			"timeouts.create": "1h0m0s",
+		},
+	}
+
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("bad: %#v", d)
+	}
+}
+
+func TestResourceValidate_timeouts(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+		},
+		Timeouts: &ResourceTimeout{
+			Create: DefaultTimeout(10 * time.Minute),
+		},
+	}
+
+	cases := []struct {
+		Timeouts map[string]interface{}
+		Err      bool
+	}{
+		// Valid
+		{
+			map[string]interface{}{
+				"create": "1h",
+			},
+			false,
+		},
+
+		// Interpolated values are checked once they're known
+		{
+			map[string]interface{}{
+				"create": "${var.timeout}",
+			},
+			false,
+		},
+
+		// No default for the operation
+		{
+			map[string]interface{}{
+				"delete": "1h",
+			},
+			true,
+		},
+
+		// Not a duration
+		{
+			map[string]interface{}{
+				"create": "forever",
+			},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"foo":      "bar",
+			"timeouts": []map[string]interface{}{tc.Timeouts},
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, es := r.Validate(terraform.NewResourceConfig(raw))
+		if (len(es) > 0) != tc.Err {
+			t.Fatalf("%d: %#v", i, es)
+		}
+	}
+
+	// The block is unknown for resources without timeouts
+	r.Timeouts = nil
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"timeouts": []map[string]interface{}{
+			map[string]interface{}{
+				"create": "1h",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, es := r.Validate(terraform.NewResourceConfig(raw)); len(es) == 0 {
+		t.Fatal("should error")
+	}
+}
+
 func TestResourceInternalValidate(t *testing.T) {
 	cases := []struct {
 		In  *Resource
@@ -379,6 +687,57 @@ func TestResourceRefresh(t *testing.T) {
 	}
 }
 
+func TestResourceReadDataApply(t *testing.T) {
+	r := &Resource{
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeString,
+				Required: true,
+			},
+
+			"bar": &Schema{
+				Type:     TypeString,
+				Computed: true,
+			},
+		},
+	}
+
+	r.Read = func(d *ResourceData, m interface{}) error {
+		if m != 42 {
+			return fmt.Errorf("meta not passed")
+		}
+
+		d.SetId(d.Get("foo").(string))
+		return d.Set("bar", "baz")
+	}
+
+	d := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"foo": &terraform.ResourceAttrDiff{
+				New: "qux",
+			},
+		},
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "qux",
+		Attributes: map[string]string{
+			"id":  "qux",
+			"foo": "qux",
+			"bar": "baz",
+		},
+	}
+
+	actual, err := r.ReadDataApply(d, 42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestResourceRefresh_delete(t *testing.T) {
 	r := &Resource{
 		Schema: map[string]*Schema{
@@ -478,3 +837,156 @@ func TestResourceRefresh_noExists(t *testing.T) {
 		t.Fatalf("should have no state")
 	}
 }
+
+func TestResourceRefresh_migrateState(t *testing.T) {
+	r := &Resource{
+		SchemaVersion: 2,
+		Schema: map[string]*Schema{
+			"newfoo": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+	}
+
+	r.Read = func(d *ResourceData, m interface{}) error {
+		return d.Set("newfoo", d.Get("newfoo").(int)+1)
+	}
+
+	r.MigrateState = func(
+		v int,
+		s *terraform.InstanceState,
+		meta interface{}) (*terraform.InstanceState, error) {
+		if v != 0 {
+			return nil, fmt.Errorf("bad version: %d", v)
+		}
+		if meta != 42 {
+			return nil, fmt.Errorf("meta not passed")
+		}
+
+		// Rename the attribute and multiply it by 10
+		oldfoo, err := strconv.Atoi(s.Attributes["oldfoo"])
+		if err != nil {
+			return nil, err
+		}
+		delete(s.Attributes, "oldfoo")
+		s.Attributes["newfoo"] = strconv.Itoa(oldfoo * 10)
+
+		return s, nil
+	}
+
+	s := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"oldfoo": "12",
+		},
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"id":     "bar",
+			"newfoo": "121",
+		},
+		Meta: map[string]string{
+			"schema_version": "2",
+		},
+	}
+
+	actual, err := r.Refresh(s, 42)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestResourceRefresh_migrateStateCurrent(t *testing.T) {
+	r := &Resource{
+		SchemaVersion: 2,
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+	}
+
+	r.Read = func(d *ResourceData, m interface{}) error {
+		return nil
+	}
+
+	r.MigrateState = func(
+		v int,
+		s *terraform.InstanceState,
+		meta interface{}) (*terraform.InstanceState, error) {
+		return nil, fmt.Errorf("should not be called")
+	}
+
+	s := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"foo": "12",
+		},
+		Meta: map[string]string{
+			"schema_version": "2",
+		},
+	}
+
+	expected := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"id":  "bar",
+			"foo": "12",
+		},
+		Meta: map[string]string{
+			"schema_version": "2",
+		},
+	}
+
+	actual, err := r.Refresh(s, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestResourceRefresh_migrateStateErr(t *testing.T) {
+	r := &Resource{
+		SchemaVersion: 1,
+		Schema: map[string]*Schema{
+			"foo": &Schema{
+				Type:     TypeInt,
+				Optional: true,
+			},
+		},
+	}
+
+	r.Read = func(d *ResourceData, m interface{}) error {
+		t.Fatal("Read should not be called")
+		return nil
+	}
+
+	r.MigrateState = func(
+		v int,
+		s *terraform.InstanceState,
+		meta interface{}) (*terraform.InstanceState, error) {
+		return nil, fmt.Errorf("migration failed")
+	}
+
+	s := &terraform.InstanceState{
+		ID: "bar",
+		Attributes: map[string]string{
+			"foo": "12",
+		},
+	}
+
+	if _, err := r.Refresh(s, nil); err == nil {
+		t.Fatal("should error")
+	}
+}
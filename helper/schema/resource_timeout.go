@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// The operations of a resource whose timeouts can be set, which are the
+// keys used with ResourceData.Timeout.
+const (
+	TimeoutCreate = "create"
+	TimeoutRead   = "read"
+	TimeoutUpdate = "update"
+	TimeoutDelete = "delete"
+)
+
+// TimeoutsConfigKey is the key of the block that overrides the timeouts
+// of a resource in its configuration, such as:
+//
+//	timeouts {
+//		create = "60m"
+//	}
+const TimeoutsConfigKey = "timeouts"
+
+// timeoutMetaPrefix prefixes the keys of timeouts that are set in the
+// configuration within the Meta of diffs and states.
+const timeoutMetaPrefix = "timeouts."
+
+// defaultTimeout is the timeout of operations that a resource doesn't
+// set a timeout for.
+const defaultTimeout = 20 * time.Minute
+
+// ResourceTimeout is the default timeout of each operation of a resource.
+// Only the timeouts that are set can be overridden in the configuration.
+type ResourceTimeout struct {
+	Create *time.Duration
+	Read   *time.Duration
+	Update *time.Duration
+	Delete *time.Duration
+}
+
+// DefaultTimeout is a helper that returns a pointer to the given duration,
+// for setting the fields of a ResourceTimeout.
+func DefaultTimeout(d time.Duration) *time.Duration {
+	return &d
+}
+
+// get returns the default timeout of the given operation, or nil if it
+// isn't set.
+func (t *ResourceTimeout) get(key string) *time.Duration {
+	if t == nil {
+		return nil
+	}
+
+	switch key {
+	case TimeoutCreate:
+		return t.Create
+	case TimeoutRead:
+		return t.Read
+	case TimeoutUpdate:
+		return t.Update
+	case TimeoutDelete:
+		return t.Delete
+	default:
+		return nil
+	}
+}
+
+// timeouts returns the timeout of each operation that has one, which is
+// the default of the resource unless it was set in the configuration.
+// The configured timeouts are read from the given Meta maps in order,
+// so later ones take precedence.
+func (t *ResourceTimeout) timeouts(metas ...map[string]string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, k := range []string{
+		TimeoutCreate, TimeoutRead, TimeoutUpdate, TimeoutDelete} {
+		if v := t.get(k); v != nil {
+			result[k] = *v
+		}
+	}
+
+	for _, meta := range metas {
+		for k, v := range meta {
+			if !strings.HasPrefix(k, timeoutMetaPrefix) {
+				continue
+			}
+
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				// Only valid durations are ever stored
+				continue
+			}
+
+			result[k[len(timeoutMetaPrefix):]] = d
+		}
+	}
+
+	return result
+}
+
+// splitTimeouts separates the timeouts block from the configuration of
+// a resource. It returns the configuration without the block, along with
+// the Meta keys and values of the timeouts that were set within it.
+func (t *ResourceTimeout) splitTimeouts(
+	c *terraform.ResourceConfig) (*terraform.ResourceConfig, map[string]string, error) {
+	if c == nil {
+		return nil, nil, nil
+	}
+
+	raw, ok := c.Get(TimeoutsConfigKey)
+	if !ok {
+		return c, nil, nil
+	}
+
+	var blocks []map[string]interface{}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		blocks = append(blocks, v)
+	case []map[string]interface{}:
+		blocks = v
+	case []interface{}:
+		for _, elem := range v {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf(
+					"%s: must be a block", TimeoutsConfigKey)
+			}
+
+			blocks = append(blocks, m)
+		}
+	default:
+		return nil, nil, fmt.Errorf(
+			"%s: must be a block", TimeoutsConfigKey)
+	}
+
+	meta := make(map[string]string)
+	for _, b := range blocks {
+		for k, v := range b {
+			if t.get(k) == nil {
+				return nil, nil, fmt.Errorf(
+					"%s: the timeout of %s can't be set for this resource",
+					TimeoutsConfigKey, k)
+			}
+
+			s, ok := v.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf(
+					"%s.%s: must be a duration string", TimeoutsConfigKey, k)
+			}
+			if strings.Contains(s, "${") {
+				// The value isn't known yet, so the default is used
+				// until it is
+				continue
+			}
+
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, nil, fmt.Errorf(
+					"%s.%s: %s", TimeoutsConfigKey, k, err)
+			}
+
+			meta[timeoutMetaPrefix+k] = d.String()
+		}
+	}
+
+	result := *c
+	result.Raw = withoutKey(c.Raw, TimeoutsConfigKey)
+	result.Config = withoutKey(c.Config, TimeoutsConfigKey)
+	result.ComputedKeys = nil
+	for _, k := range c.ComputedKeys {
+		if k != TimeoutsConfigKey && !strings.HasPrefix(k, TimeoutsConfigKey+".") {
+			result.ComputedKeys = append(result.ComputedKeys, k)
+		}
+	}
+
+	return &result, meta, nil
+}
+
+// withoutKey returns a copy of the given map without the given key.
+func withoutKey(m map[string]interface{}, key string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			result[k] = v
+		}
+	}
+
+	return result
+}
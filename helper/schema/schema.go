@@ -12,7 +12,9 @@
 package schema
 
 import (
+	"bufio"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"sort"
@@ -20,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/go-homedir"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -80,7 +83,10 @@ type Schema struct {
 	// The fields below relate to diffs.
 	//
 	// If Computed is true, then the result of this value is computed
-	// (unless specified by config) on creation.
+	// (unless specified by config) on creation. For a TypeList or TypeSet
+	// of resources, the optional fields of the elements are computed as
+	// well, so that Read can fill in the whole block even if only some of
+	// its fields are configured.
 	//
 	// If ForceNew is true, then a change in this resource necessitates
 	// the creation of a new resource.
@@ -93,14 +99,35 @@ type Schema struct {
 	ForceNew  bool
 	StateFunc SchemaStateFunc
 
+	// If Sensitive is true, then the value is hidden when it is shown
+	// in a plan or in the state, such as for passwords or private keys.
+	// It is still stored in the state so that changes can be detected.
+	Sensitive bool
+
+	// DiffSuppressFunc is called for each change of this value with the
+	// key, and the old and new values as they're stored in the state. If
+	// it returns true, the change is left out of the diff. This is useful
+	// for values that differ textually but mean the same thing, such as
+	// JSON documents or names that are case insensitive.
+	DiffSuppressFunc SchemaDiffSuppressFunc
+
 	// The following fields are only set for a TypeList or TypeSet Type.
 	//
 	// Elem must be either a *Schema or a *Resource only if the Type is
 	// TypeList, and represents what the element type is. If it is *Schema,
 	// the element type is just a simple value. If it is *Resource, the
 	// element type is a complex structure, potentially with its own lifecycle.
+	//
+	// Elem can also be set to a *Schema for a TypeMap to set the type of
+	// the values of the map, which are strings if it isn't set.
 	Elem interface{}
 
+	// MaxItems and MinItems constrain the number of elements of a list
+	// or set in the configuration, such as to require at least one
+	// nested block. They're only checked if they're greater than zero.
+	MaxItems int
+	MinItems int
+
 	// The following fields are only valid for a TypeSet type.
 	//
 	// Set defines a function to determine the unique ID of an item so that
@@ -113,6 +140,39 @@ type Schema struct {
 	//
 	// NOTE: This currently does not work.
 	ComputedWhen []string
+
+	// ValidateFunc allows individual fields to define arbitrary validation
+	// logic. It is given the configured value already decoded into the
+	// proper Go type for the schema Type along with the key, and can
+	// return warnings or errors based on inspection of that value.
+	//
+	// ValidateFunc currently only works for primitive types.
+	ValidateFunc SchemaValidateFunc
+
+	// The fields below constrain which other keys can be set along with
+	// this one. The keys are full paths from the root of the resource,
+	// such as "security_groups" or "ebs_block_device.0.device_name".
+	//
+	// ConflictsWith is a set of keys that can't be set if this one is.
+	//
+	// RequiredWith is a set of keys that must be set if this one is.
+	//
+	// ExactlyOneOf is a set of keys, usually including this one, of which
+	// exactly one must be set.
+	ConflictsWith []string
+	RequiredWith  []string
+	ExactlyOneOf  []string
+
+	// Deprecated and Removed mark a field that is going away, such as
+	// when it has been renamed. Their value is the message shown to users
+	// that set the field, which should say what to use instead.
+	//
+	// Setting a Deprecated field is a warning, while setting a Removed
+	// field is an error. Removed fields should stay in the schema as
+	// Optional for a while, so that users get the message rather than
+	// an error about an unknown key.
+	Deprecated string
+	Removed    string
 }
 
 // SchemaDefaultFunc is a function called to return a default value for
@@ -147,6 +207,110 @@ func MultiEnvDefaultFunc(ks []string, dv interface{}) SchemaDefaultFunc {
 	}
 }
 
+// FileDefaultFunc is a helper function that returns the contents of the
+// given file, with any surrounding whitespace removed, if it exists. The
+// path can begin with "~" for the home directory of the user. If the file
+// doesn't exist, the default value is returned.
+func FileDefaultFunc(path string, dv interface{}) SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return dv, nil
+			}
+
+			return nil, err
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+// CredentialsFileDefaultFunc is a helper function that returns the value
+// of a key within a section of an INI-style credentials file, such as:
+//
+//	[default]
+//	aws_access_key_id = foo
+//
+// The path can begin with "~" for the home directory of the user. If the
+// file, the section or the key doesn't exist, the default value is
+// returned.
+func CredentialsFileDefaultFunc(
+	path, section, key string, dv interface{}) SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return dv, nil
+			}
+
+			return nil, err
+		}
+		defer f.Close()
+
+		current := ""
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+
+			if line[0] == '[' && line[len(line)-1] == ']' {
+				current = strings.TrimSpace(line[1 : len(line)-1])
+				continue
+			}
+			if current != section {
+				continue
+			}
+
+			idx := strings.IndexByte(line, '=')
+			if idx == -1 {
+				continue
+			}
+			if strings.TrimSpace(line[:idx]) == key {
+				return strings.TrimSpace(line[idx+1:]), nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", path, err)
+		}
+
+		return dv, nil
+	}
+}
+
+// ChainDefaultFunc is a helper function that returns the value of the
+// first of the given functions that returns a value that isn't nil or
+// an empty string. This can be used to fall back from environment
+// variables to a credentials file, for example. If none of them return
+// a value, nil is returned.
+func ChainDefaultFunc(fs ...SchemaDefaultFunc) SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		for _, f := range fs {
+			v, err := f()
+			if err != nil {
+				return nil, err
+			}
+			if v != nil && v != "" {
+				return v, nil
+			}
+		}
+
+		return nil, nil
+	}
+}
+
 // SchemaSetFunc is a function that must return a unique ID for the given
 // element. This unique ID is used to store the element in a hash.
 type SchemaSetFunc func(interface{}) int
@@ -155,6 +319,16 @@ type SchemaSetFunc func(interface{}) int
 // to be stored in the state.
 type SchemaStateFunc func(interface{}) string
 
+// SchemaDiffSuppressFunc is a function used to ignore a change of a
+// value that is semantically the same. It is given the key, the old and
+// the new value, along with the data of the resource.
+type SchemaDiffSuppressFunc func(k, old, new string, d *ResourceData) bool
+
+// SchemaValidateFunc is a function used to validate a single field in the
+// schema. It is given the value and the key and returns any warnings and
+// errors found.
+type SchemaValidateFunc func(interface{}, string) ([]string, []error)
+
 func (s *Schema) GoString() string {
 	return fmt.Sprintf("*%#v", *s)
 }
@@ -183,6 +357,10 @@ func (s *Schema) finalizeDiff(
 		return d
 	}
 
+	if s.Sensitive {
+		d.Sensitive = true
+	}
+
 	if d.NewRemoved {
 		return d
 	}
@@ -225,10 +403,13 @@ func (m schemaMap) Data(
 }
 
 // Diff returns the diff for a resource given the schema map,
-// state, and configuration.
+// state, and configuration. If customizeDiff is set, it is called
+// with meta to change the diff once it has been computed.
 func (m schemaMap) Diff(
 	s *terraform.InstanceState,
-	c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	c *terraform.ResourceConfig,
+	customizeDiff CustomizeDiffFunc,
+	meta interface{}) (*terraform.InstanceDiff, error) {
 	result := new(terraform.InstanceDiff)
 	result.Attributes = make(map[string]*terraform.ResourceAttrDiff)
 
@@ -245,6 +426,15 @@ func (m schemaMap) Diff(
 		}
 	}
 
+	// Let the resource customize the diff, which can also force a new
+	// resource and so has to happen before we check for that.
+	if customizeDiff != nil {
+		rd := newResourceDiff(m, c, s, result)
+		if err := customizeDiff(rd, meta); err != nil {
+			return nil, err
+		}
+	}
+
 	// If the diff requires a new resource, then we recompute the diff
 	// so we have the complete new resource diff, and preserve the
 	// RequiresNew fields where necessary so the user knows exactly what
@@ -267,6 +457,14 @@ func (m schemaMap) Diff(
 			}
 		}
 
+		// Re-run the customization against the new diff
+		if customizeDiff != nil {
+			rd := newResourceDiff(m, c, nil, result2)
+			if err := customizeDiff(rd, meta); err != nil {
+				return nil, err
+			}
+		}
+
 		// Force all the fields to not force a new since we know what we
 		// want to force new.
 		for k, attr := range result2.Attributes {
@@ -449,6 +647,69 @@ func (m schemaMap) InternalValidate() error {
 				}
 			}
 		}
+
+		if v.MaxItems < 0 || v.MinItems < 0 {
+			return fmt.Errorf("%s: MaxItems and MinItems can't be negative", k)
+		}
+
+		if v.MaxItems > 0 || v.MinItems > 0 {
+			if v.Type != TypeList && v.Type != TypeSet {
+				return fmt.Errorf(
+					"%s: MaxItems and MinItems are only supported on lists or sets", k)
+			}
+
+			if v.MaxItems > 0 && v.MinItems > v.MaxItems {
+				return fmt.Errorf("%s: MinItems can't be greater than MaxItems", k)
+			}
+		}
+
+		if v.Type == TypeMap && v.Elem != nil {
+			t, ok := v.Elem.(*Schema)
+			if !ok {
+				return fmt.Errorf("%s: Elem must be a *Schema for maps", k)
+			}
+			if t.Computed || t.Optional || t.Required {
+				return fmt.Errorf("%s: Elem must have only Type set", k)
+			}
+
+			switch t.Type {
+			case TypeBool, TypeInt, TypeFloat, TypeString:
+			default:
+				return fmt.Errorf("%s: Elem must be a primitive for maps", k)
+			}
+		}
+
+		if v.ValidateFunc != nil {
+			switch v.Type {
+			case TypeList, TypeSet, TypeMap:
+				return fmt.Errorf(
+					"%s: ValidateFunc is only supported on primitives", k)
+			}
+		}
+
+		if len(v.ConflictsWith) > 0 && v.Required {
+			return fmt.Errorf("%s: ConflictsWith cannot be set with Required", k)
+		}
+
+		for _, ck := range v.ConflictsWith {
+			if ck == k {
+				return fmt.Errorf("%s: ConflictsWith cannot contain itself", k)
+			}
+		}
+
+		for _, rk := range v.RequiredWith {
+			if rk == k {
+				return fmt.Errorf("%s: RequiredWith cannot contain itself", k)
+			}
+		}
+
+		if len(v.ExactlyOneOf) > 0 && v.Required {
+			return fmt.Errorf("%s: ExactlyOneOf cannot be set with Required", k)
+		}
+
+		if v.Removed != "" && v.Required {
+			return fmt.Errorf("%s: Removed cannot be set with Required", k)
+		}
 	}
 
 	return nil
@@ -460,6 +721,12 @@ func (m schemaMap) diff(
 	diff *terraform.InstanceDiff,
 	d *ResourceData,
 	all bool) error {
+	// Diff into a separate structure first so that any changes the
+	// schema wants to suppress can be left out.
+	unsuppressed := &terraform.InstanceDiff{
+		Attributes: make(map[string]*terraform.ResourceAttrDiff),
+	}
+
 	var err error
 	switch schema.Type {
 	case TypeBool:
@@ -469,17 +736,28 @@ func (m schemaMap) diff(
 	case TypeFloat:
 		fallthrough
 	case TypeString:
-		err = m.diffString(k, schema, diff, d, all)
+		err = m.diffString(k, schema, unsuppressed, d, all)
 	case TypeList:
-		err = m.diffList(k, schema, diff, d, all)
+		err = m.diffList(k, schema, unsuppressed, d, all)
 	case TypeMap:
-		err = m.diffMap(k, schema, diff, d, all)
+		err = m.diffMap(k, schema, unsuppressed, d, all)
 	case TypeSet:
-		err = m.diffSet(k, schema, diff, d, all)
+		err = m.diffSet(k, schema, unsuppressed, d, all)
 	default:
 		err = fmt.Errorf("%s: unknown type %#v", k, schema.Type)
 	}
 
+	for attrK, attrV := range unsuppressed.Attributes {
+		if schema.DiffSuppressFunc != nil &&
+			attrV != nil &&
+			!attrV.NewComputed &&
+			schema.DiffSuppressFunc(attrK, attrV.Old, attrV.New, d) {
+			continue
+		}
+
+		diff.Attributes[attrK] = attrV
+	}
+
 	return err
 }
 
@@ -570,8 +848,9 @@ func (m schemaMap) diffList(
 	switch t := schema.Elem.(type) {
 	case *Resource:
 		// This is a complex resource
+		elem := computedElemSchema(schema, t)
 		for i := 0; i < maxLen; i++ {
-			for k2, schema := range t.Schema {
+			for k2, schema := range elem {
 				subK := fmt.Sprintf("%s.%d.%s", k, i, k2)
 				err := m.diff(subK, schema, diff, d, all)
 				if err != nil {
@@ -584,6 +863,7 @@ func (m schemaMap) diffList(
 		// the parent schema (the TypeList).
 		t2 := *t
 		t2.ForceNew = schema.ForceNew
+		t2.Sensitive = t.Sensitive || schema.Sensitive
 
 		// This is just a primitive element, so go through each and
 		// just diff each.
@@ -601,6 +881,33 @@ func (m schemaMap) diffList(
 	return nil
 }
 
+// computedElemSchema returns the schema to diff the elements of a list
+// or set of resources with. If the list or set is computed, the provider
+// can fill in the whole block, including defaults of optional fields
+// that the configuration doesn't set, so those fields are diffed as if
+// they were computed and keep the value that was read. If the list or
+// set is sensitive, so is everything within it.
+func computedElemSchema(schema *Schema, elem *Resource) map[string]*Schema {
+	if !schema.Computed && !schema.Sensitive {
+		return elem.Schema
+	}
+
+	result := make(map[string]*Schema, len(elem.Schema))
+	for k, v := range elem.Schema {
+		v2 := *v
+		if schema.Computed && v.Optional && !v.Computed {
+			v2.Computed = true
+		}
+		if schema.Sensitive {
+			v2.Sensitive = true
+		}
+
+		result[k] = &v2
+	}
+
+	return result
+}
+
 func (m schemaMap) diffMap(
 	k string,
 	schema *Schema,
@@ -612,10 +919,12 @@ func (m schemaMap) diffMap(
 	// First get all the values from the state
 	var stateMap, configMap map[string]string
 	o, n, _, _ := d.diffChange(k)
-	if err := mapstructure.WeakDecode(o, &stateMap); err != nil {
+	stateMap, err := mapValuesToString(o, schema)
+	if err != nil {
 		return fmt.Errorf("%s: %s", k, err)
 	}
-	if err := mapstructure.WeakDecode(n, &configMap); err != nil {
+	configMap, err = mapValuesToString(n, schema)
+	if err != nil {
 		return fmt.Errorf("%s: %s", k, err)
 	}
 
@@ -683,6 +992,51 @@ func (m schemaMap) diffMap(
 	return nil
 }
 
+// mapValuesToString turns the values of a map into strings the same way
+// that they're written to the state, according to the Elem of the map.
+func mapValuesToString(
+	raw interface{}, schema *Schema) (map[string]string, error) {
+	var result map[string]string
+	elem, ok := schema.Elem.(*Schema)
+	if !ok || (elem.Type != TypeBool && elem.Type != TypeFloat) {
+		err := mapstructure.WeakDecode(raw, &result)
+		return result, err
+	}
+
+	var values map[string]interface{}
+	if err := mapstructure.WeakDecode(raw, &values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return nil, nil
+	}
+
+	result = make(map[string]string, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			result[k] = s
+			continue
+		}
+
+		switch elem.Type {
+		case TypeBool:
+			var b bool
+			if err := mapstructure.WeakDecode(v, &b); err != nil {
+				return nil, err
+			}
+			result[k] = strconv.FormatBool(b)
+		case TypeFloat:
+			var f float64
+			if err := mapstructure.WeakDecode(v, &f); err != nil {
+				return nil, err
+			}
+			result[k] = strconv.FormatFloat(f, 'G', -1, 64)
+		}
+	}
+
+	return result, nil
+}
+
 func (m schemaMap) diffSet(
 	k string,
 	schema *Schema,
@@ -767,7 +1121,7 @@ func (m schemaMap) diffSet(
 		switch t := schema.Elem.(type) {
 		case *Resource:
 			// This is a complex resource
-			for k2, schema := range t.Schema {
+			for k2, schema := range computedElemSchema(schema, t) {
 				subK := fmt.Sprintf("%s.%d.%s", k, code, k2)
 				subK = strings.Replace(subK, "-", "~", -1)
 				err := m.diff(subK, schema, diff, d, true)
@@ -780,6 +1134,7 @@ func (m schemaMap) diffSet(
 			// the parent schema (the TypeSet).
 			t2 := *t
 			t2.ForceNew = schema.ForceNew
+			t2.Sensitive = t.Sensitive || schema.Sensitive
 
 			// This is just a primitive element, so go through each and
 			// just diff each.
@@ -871,6 +1226,28 @@ func (m schemaMap) validate(
 	schema *Schema,
 	c *terraform.ResourceConfig) ([]string, []error) {
 	raw, ok := c.Get(k)
+	if err := validateExactlyOneOf(k, schema, c); err != nil {
+		return nil, []error{err}
+	}
+
+	var ws []string
+	if ok {
+		if schema.Removed != "" {
+			return nil, []error{fmt.Errorf("%s: %s", k, schema.Removed)}
+		}
+		if schema.Deprecated != "" {
+			ws = append(ws, fmt.Sprintf(
+				"%s: deprecated: %s", k, schema.Deprecated))
+		}
+
+		if err := validateConflictsWith(k, schema, c); err != nil {
+			return nil, []error{err}
+		}
+		if err := validateRequiredWith(k, schema, c); err != nil {
+			return nil, []error{err}
+		}
+	}
+
 	if !ok && schema.DefaultFunc != nil {
 		// We have a dynamic default. Check if we have a value.
 		var err error
@@ -898,7 +1275,83 @@ func (m schemaMap) validate(
 			"%s: this field cannot be set", k)}
 	}
 
-	return m.validateType(k, raw, schema, c)
+	ws2, es := m.validateType(k, raw, schema, c)
+	return append(ws, ws2...), es
+}
+
+// validateConflictsWith checks that none of the keys that conflict with
+// the set key k are set as well.
+func validateConflictsWith(
+	k string,
+	schema *Schema,
+	c *terraform.ResourceConfig) error {
+	for _, ck := range schema.ConflictsWith {
+		if _, ok := c.Get(ck); ok {
+			return fmt.Errorf(
+				"%s: conflicts with %s", k, ck)
+		}
+	}
+
+	return nil
+}
+
+// validateRequiredWith checks that all of the keys that are required
+// along with the set key k are set as well.
+func validateRequiredWith(
+	k string,
+	schema *Schema,
+	c *terraform.ResourceConfig) error {
+	var missing []string
+	for _, rk := range schema.RequiredWith {
+		if _, ok := c.Get(rk); !ok {
+			missing = append(missing, rk)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"%s: %s must also be set", k, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// validateExactlyOneOf checks that exactly one of the keys of the
+// ExactlyOneOf set of k is set. If k is within the set, the check is only
+// done for the first key of the set so that errors aren't repeated for
+// every key within it.
+func validateExactlyOneOf(
+	k string,
+	schema *Schema,
+	c *terraform.ResourceConfig) error {
+	if len(schema.ExactlyOneOf) == 0 {
+		return nil
+	}
+	for i, ek := range schema.ExactlyOneOf {
+		if ek == k && i > 0 {
+			return nil
+		}
+	}
+
+	var set []string
+	for _, ek := range schema.ExactlyOneOf {
+		if _, ok := c.Get(ek); ok {
+			set = append(set, ek)
+		}
+	}
+
+	switch len(set) {
+	case 0:
+		return fmt.Errorf(
+			"one of %s must be set",
+			strings.Join(schema.ExactlyOneOf, ", "))
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf(
+			"only one of %s can be set, but %s were set",
+			strings.Join(schema.ExactlyOneOf, ", "),
+			strings.Join(set, ", "))
+	}
 }
 
 func (m schemaMap) validateList(
@@ -920,6 +1373,17 @@ func (m schemaMap) validateList(
 		raws[i] = rawV.Index(i).Interface()
 	}
 
+	if schema.MaxItems > 0 && len(raws) > schema.MaxItems {
+		return nil, []error{fmt.Errorf(
+			"%s: attribute supports %d item maximum, config has %d declared",
+			k, schema.MaxItems, len(raws))}
+	}
+	if schema.MinItems > 0 && len(raws) < schema.MinItems {
+		return nil, []error{fmt.Errorf(
+			"%s: attribute supports %d item as a minimum, config has %d declared",
+			k, schema.MinItems, len(raws))}
+	}
+
 	var ws []string
 	var es []error
 	for i, raw := range raws {
@@ -962,9 +1426,9 @@ func (m schemaMap) validateMap(
 			"%s: should be a map", k)}
 	}
 
-	// If it is not a slice, it is valid
+	// If it is not a slice, it is valid as long as its values are
 	if rawV.Kind() != reflect.Slice {
-		return nil, nil
+		return m.validateMapValues(k, rawV, schema, c)
 	}
 
 	// It is a slice, verify that all the elements are maps
@@ -973,15 +1437,46 @@ func (m schemaMap) validateMap(
 		raws[i] = rawV.Index(i).Interface()
 	}
 
+	var ws []string
+	var es []error
 	for _, raw := range raws {
 		v := reflect.ValueOf(raw)
 		if v.Kind() != reflect.Map {
 			return nil, []error{fmt.Errorf(
 				"%s: should be a map", k)}
 		}
+
+		ws2, es2 := m.validateMapValues(k, v, schema, c)
+		ws = append(ws, ws2...)
+		es = append(es, es2...)
 	}
 
-	return nil, nil
+	return ws, es
+}
+
+// validateMapValues validates the values of a map against the Elem
+// schema of the map, if it has one.
+func (m schemaMap) validateMapValues(
+	k string,
+	v reflect.Value,
+	schema *Schema,
+	c *terraform.ResourceConfig) ([]string, []error) {
+	elem, ok := schema.Elem.(*Schema)
+	if !ok {
+		return nil, nil
+	}
+
+	var ws []string
+	var es []error
+	for _, mk := range v.MapKeys() {
+		key := fmt.Sprintf("%s.%v", k, mk.Interface())
+		ws2, es2 := m.validatePrimitive(
+			key, v.MapIndex(mk).Interface(), elem, c)
+		ws = append(ws, ws2...)
+		es = append(es, es2...)
+	}
+
+	return ws, es
 }
 
 func (m schemaMap) validateObject(
@@ -1029,6 +1524,7 @@ func (m schemaMap) validatePrimitive(
 		return nil, nil
 	}
 
+	var decoded interface{}
 	switch schema.Type {
 	case TypeBool:
 		// Verify that we can parse this as the correct type
@@ -1036,28 +1532,36 @@ func (m schemaMap) validatePrimitive(
 		if err := mapstructure.WeakDecode(raw, &n); err != nil {
 			return nil, []error{err}
 		}
+		decoded = n
 	case TypeInt:
 		// Verify that we can parse this as an int
 		var n int
 		if err := mapstructure.WeakDecode(raw, &n); err != nil {
 			return nil, []error{err}
 		}
+		decoded = n
 	case TypeFloat:
 		// Verify that we can parse this as an int
 		var n float64
 		if err := mapstructure.WeakDecode(raw, &n); err != nil {
 			return nil, []error{err}
 		}
+		decoded = n
 	case TypeString:
 		// Verify that we can parse this as a string
 		var n string
 		if err := mapstructure.WeakDecode(raw, &n); err != nil {
 			return nil, []error{err}
 		}
+		decoded = n
 	default:
 		panic(fmt.Sprintf("Unknown validation type: %#v", schema.Type))
 	}
 
+	if schema.ValidateFunc != nil {
+		return schema.ValidateFunc(decoded, k)
+	}
+
 	return nil, nil
 }
 
@@ -3,8 +3,10 @@ package schema
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/config"
@@ -100,6 +102,123 @@ func TestMultiEnvDefaultFunc(t *testing.T) {
 	}
 }
 
+func TestFileDefaultFunc(t *testing.T) {
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString("foo\n"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	actual, err := FileDefaultFunc(f.Name(), "42")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != "foo" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// A file that doesn't exist returns the default
+	actual, err = FileDefaultFunc(f.Name()+".nope", "42")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != "42" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestCredentialsFileDefaultFunc(t *testing.T) {
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(testCredentialsFile)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	cases := []struct {
+		Section string
+		Key     string
+		Value   interface{}
+	}{
+		{"default", "access_key", "foo"},
+		{"default", "secret_key", "bar"},
+		{"other", "access_key", "baz"},
+		{"default", "nope", "42"},
+		{"nope", "access_key", "42"},
+	}
+
+	for i, tc := range cases {
+		actual, err := CredentialsFileDefaultFunc(
+			f.Name(), tc.Section, tc.Key, "42")()
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if actual != tc.Value {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+
+	// A file that doesn't exist returns the default
+	actual, err := CredentialsFileDefaultFunc(
+		f.Name()+".nope", "default", "access_key", "42")()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != "42" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestChainDefaultFunc(t *testing.T) {
+	key := "TF_TEST_CHAIN_DEFAULT_FUNC"
+	defer os.Unsetenv(key)
+
+	f := ChainDefaultFunc(
+		EnvDefaultFunc(key, nil),
+		func() (interface{}, error) { return "", nil },
+		func() (interface{}, error) { return "42", nil },
+	)
+
+	actual, err := f()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != "42" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	if err := os.Setenv(key, "foo"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual, err = f()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != "foo" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	// Errors are returned right away
+	f = ChainDefaultFunc(
+		func() (interface{}, error) { return nil, fmt.Errorf("err") },
+		func() (interface{}, error) { return "42", nil },
+	)
+	if _, err := f(); err == nil {
+		t.Fatal("should error")
+	}
+}
+
 func TestValueType_Zero(t *testing.T) {
 	cases := []struct {
 		Type  ValueType
@@ -2165,149 +2284,499 @@ func TestSchemaMap_Diff(t *testing.T) {
 
 			Err: false,
 		},
-	}
 
-	for i, tc := range cases {
-		c, err := config.NewRawConfig(tc.Config)
-		if err != nil {
-			t.Fatalf("#%d err: %s", i, err)
-		}
+		// #56 - Null is the same as unset
+		{
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Default:  "foo",
+				},
+			},
 
-		if len(tc.ConfigVariables) > 0 {
-			vars := make(map[string]ast.Variable)
-			for k, v := range tc.ConfigVariables {
-				vars[k] = ast.Variable{Value: v, Type: ast.TypeString}
-			}
+			State: nil,
 
-			if err := c.Interpolate(vars); err != nil {
-				t.Fatalf("#%d err: %s", i, err)
-			}
-		}
+			Config: map[string]interface{}{
+				"availability_zone": `${var.zone == "" ? null : var.zone}`,
+			},
 
-		d, err := schemaMap(tc.Schema).Diff(
-			tc.State, terraform.NewResourceConfig(c))
-		if (err != nil) != tc.Err {
-			t.Fatalf("#%d err: %s", i, err)
-		}
+			ConfigVariables: map[string]string{
+				"var.zone": "",
+			},
 
-		if !reflect.DeepEqual(tc.Diff, d) {
-			t.Fatalf("#%d: bad:\n\n%#v", i, d)
-		}
-	}
-}
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"availability_zone": &terraform.ResourceAttrDiff{
+						Old: "",
+						New: "foo",
+					},
+				},
+			},
 
-func TestSchemaMap_Input(t *testing.T) {
-	cases := map[string]struct {
-		Schema map[string]*Schema
-		Config map[string]interface{}
-		Input  map[string]string
-		Result map[string]interface{}
-		Err    bool
-	}{
-		/*
-		 * String decode
-		 */
+			Err: false,
+		},
 
-		"uses input on optional field with no config": {
+		// #57 - Suppressed diff
+		{
 			Schema: map[string]*Schema{
-				"availability_zone": &Schema{
+				"name": &Schema{
 					Type:     TypeString,
-					Optional: true,
+					Required: true,
+					ForceNew: true,
+					DiffSuppressFunc: func(k, o, n string, d *ResourceData) bool {
+						return strings.ToLower(o) == strings.ToLower(n)
+					},
 				},
 			},
 
-			Input: map[string]string{
-				"availability_zone": "foo",
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"name": "Foo",
+				},
 			},
 
-			Result: map[string]interface{}{
-				"availability_zone": "foo",
+			Config: map[string]interface{}{
+				"name": "foo",
 			},
 
+			Diff: nil,
+
 			Err: false,
 		},
 
-		"input ignored when config has a value": {
+		// #58 - Unsuppressed diff
+		{
 			Schema: map[string]*Schema{
-				"availability_zone": &Schema{
+				"name": &Schema{
 					Type:     TypeString,
-					Optional: true,
+					Required: true,
+					ForceNew: true,
+					DiffSuppressFunc: func(k, o, n string, d *ResourceData) bool {
+						return strings.ToLower(o) == strings.ToLower(n)
+					},
 				},
 			},
 
-			Config: map[string]interface{}{
-				"availability_zone": "bar",
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"name": "Foo",
+				},
 			},
 
-			Input: map[string]string{
-				"availability_zone": "foo",
+			Config: map[string]interface{}{
+				"name": "bar",
 			},
 
-			Result: map[string]interface{}{},
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"name": &terraform.ResourceAttrDiff{
+						Old:         "Foo",
+						New:         "bar",
+						RequiresNew: true,
+					},
+				},
+			},
 
 			Err: false,
 		},
 
-		"input ignored when schema has a default": {
+		// #59 - Suppressed list elements
+		{
 			Schema: map[string]*Schema{
-				"availability_zone": &Schema{
-					Type:     TypeString,
-					Default:  "foo",
+				"names": &Schema{
+					Type:     TypeList,
 					Optional: true,
+					Elem:     &Schema{Type: TypeString},
+					DiffSuppressFunc: func(k, o, n string, d *ResourceData) bool {
+						return strings.ToLower(o) == strings.ToLower(n)
+					},
 				},
 			},
 
-			Input: map[string]string{
-				"availability_zone": "bar",
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"names.#": "2",
+					"names.0": "Foo",
+					"names.1": "bar",
+				},
 			},
 
-			Result: map[string]interface{}{},
+			Config: map[string]interface{}{
+				"names": []interface{}{"foo", "baz"},
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"names.1": &terraform.ResourceAttrDiff{
+						Old: "bar",
+						New: "baz",
+					},
+				},
+			},
 
 			Err: false,
 		},
 
-		"input ignored when default function returns a value": {
+		// #60 - Map with typed values
+		{
 			Schema: map[string]*Schema{
-				"availability_zone": &Schema{
-					Type: TypeString,
-					DefaultFunc: func() (interface{}, error) {
-						return "foo", nil
-					},
+				"flags": &Schema{
+					Type:     TypeMap,
 					Optional: true,
+					Elem:     &Schema{Type: TypeBool},
 				},
 			},
 
-			Input: map[string]string{
-				"availability_zone": "bar",
+			State: &terraform.InstanceState{
+				Attributes: map[string]string{
+					"flags.#":   "2",
+					"flags.foo": "true",
+					"flags.bar": "false",
+				},
 			},
 
-			Result: map[string]interface{}{},
+			Config: map[string]interface{}{
+				"flags": []map[string]interface{}{
+					map[string]interface{}{
+						"foo": "1",
+						"bar": true,
+					},
+				},
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"flags.bar": &terraform.ResourceAttrDiff{
+						Old: "false",
+						New: "true",
+					},
+				},
+			},
 
 			Err: false,
 		},
 
-		"input used when default function returns nil": {
+		// #61 - Computed block with defaults filled in by the provider
+		{
 			Schema: map[string]*Schema{
-				"availability_zone": &Schema{
-					Type: TypeString,
-					DefaultFunc: func() (interface{}, error) {
-						return nil, nil
-					},
+				"block": &Schema{
+					Type:     TypeList,
 					Optional: true,
+					Computed: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"size": &Schema{
+								Type:     TypeInt,
+								Optional: true,
+							},
+							"type": &Schema{
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
 				},
 			},
 
-			Input: map[string]string{
-				"availability_zone": "bar",
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"block.#":      "1",
+					"block.0.size": "10",
+					"block.0.type": "standard",
+				},
 			},
 
-			Result: map[string]interface{}{
-				"availability_zone": "bar",
+			Config: map[string]interface{}{
+				"block": []map[string]interface{}{
+					map[string]interface{}{
+						"size": 10,
+					},
+				},
 			},
 
+			Diff: nil,
+
 			Err: false,
 		},
-	}
+
+		// #62 - Computed block that is being created
+		{
+			Schema: map[string]*Schema{
+				"block": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Computed: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"size": &Schema{
+								Type:     TypeInt,
+								Optional: true,
+							},
+							"type": &Schema{
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+
+			State: nil,
+
+			Config: map[string]interface{}{
+				"block": []map[string]interface{}{
+					map[string]interface{}{
+						"size": 10,
+					},
+				},
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"block.#": &terraform.ResourceAttrDiff{
+						Old: "0",
+						New: "1",
+					},
+					"block.0.size": &terraform.ResourceAttrDiff{
+						Old: "",
+						New: "10",
+					},
+					"block.0.type": &terraform.ResourceAttrDiff{
+						NewComputed: true,
+					},
+				},
+			},
+
+			Err: false,
+		},
+
+		// #63 - Changes within a computed block
+		{
+			Schema: map[string]*Schema{
+				"block": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Computed: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"size": &Schema{
+								Type:     TypeInt,
+								Optional: true,
+							},
+							"type": &Schema{
+								Type:     TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+
+			State: &terraform.InstanceState{
+				ID: "foo",
+				Attributes: map[string]string{
+					"block.#":      "1",
+					"block.0.size": "10",
+					"block.0.type": "standard",
+				},
+			},
+
+			Config: map[string]interface{}{
+				"block": []map[string]interface{}{
+					map[string]interface{}{
+						"size": 20,
+					},
+				},
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"block.0.size": &terraform.ResourceAttrDiff{
+						Old: "10",
+						New: "20",
+					},
+				},
+			},
+
+			Err: false,
+		},
+
+		// #64 - Sensitive
+		{
+			Schema: map[string]*Schema{
+				"password": &Schema{
+					Type:      TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+			},
+
+			State: nil,
+
+			Config: map[string]interface{}{
+				"password": "secret",
+			},
+
+			Diff: &terraform.InstanceDiff{
+				Attributes: map[string]*terraform.ResourceAttrDiff{
+					"password": &terraform.ResourceAttrDiff{
+						Old:       "",
+						New:       "secret",
+						Sensitive: true,
+					},
+				},
+			},
+
+			Err: false,
+		},
+	}
+
+	for i, tc := range cases {
+		c, err := config.NewRawConfig(tc.Config)
+		if err != nil {
+			t.Fatalf("#%d err: %s", i, err)
+		}
+
+		if len(tc.ConfigVariables) > 0 {
+			vars := make(map[string]ast.Variable)
+			for k, v := range tc.ConfigVariables {
+				vars[k] = ast.Variable{Value: v, Type: ast.TypeString}
+			}
+
+			if err := c.Interpolate(vars); err != nil {
+				t.Fatalf("#%d err: %s", i, err)
+			}
+		}
+
+		d, err := schemaMap(tc.Schema).Diff(
+			tc.State, terraform.NewResourceConfig(c), nil, nil)
+		if (err != nil) != tc.Err {
+			t.Fatalf("#%d err: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(tc.Diff, d) {
+			t.Fatalf("#%d: bad:\n\n%#v", i, d)
+		}
+	}
+}
+
+func TestSchemaMap_Input(t *testing.T) {
+	cases := map[string]struct {
+		Schema map[string]*Schema
+		Config map[string]interface{}
+		Input  map[string]string
+		Result map[string]interface{}
+		Err    bool
+	}{
+		/*
+		 * String decode
+		 */
+
+		"uses input on optional field with no config": {
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+
+			Input: map[string]string{
+				"availability_zone": "foo",
+			},
+
+			Result: map[string]interface{}{
+				"availability_zone": "foo",
+			},
+
+			Err: false,
+		},
+
+		"input ignored when config has a value": {
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+
+			Config: map[string]interface{}{
+				"availability_zone": "bar",
+			},
+
+			Input: map[string]string{
+				"availability_zone": "foo",
+			},
+
+			Result: map[string]interface{}{},
+
+			Err: false,
+		},
+
+		"input ignored when schema has a default": {
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type:     TypeString,
+					Default:  "foo",
+					Optional: true,
+				},
+			},
+
+			Input: map[string]string{
+				"availability_zone": "bar",
+			},
+
+			Result: map[string]interface{}{},
+
+			Err: false,
+		},
+
+		"input ignored when default function returns a value": {
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type: TypeString,
+					DefaultFunc: func() (interface{}, error) {
+						return "foo", nil
+					},
+					Optional: true,
+				},
+			},
+
+			Input: map[string]string{
+				"availability_zone": "bar",
+			},
+
+			Result: map[string]interface{}{},
+
+			Err: false,
+		},
+
+		"input used when default function returns nil": {
+			Schema: map[string]*Schema{
+				"availability_zone": &Schema{
+					Type: TypeString,
+					DefaultFunc: func() (interface{}, error) {
+						return nil, nil
+					},
+					Optional: true,
+				},
+			},
+
+			Input: map[string]string{
+				"availability_zone": "bar",
+			},
+
+			Result: map[string]interface{}{
+				"availability_zone": "bar",
+			},
+
+			Err: false,
+		},
+	}
 
 	for i, tc := range cases {
 		if tc.Config == nil {
@@ -2533,7 +3002,127 @@ func TestSchemaMap_InternalValidate(t *testing.T) {
 					},
 				},
 			},
-			false,
+			false,
+		},
+
+		// ValidateFunc on non-primitive
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeSet,
+					Required: true,
+					Elem:     &Schema{Type: TypeString},
+					Set: func(v interface{}) int {
+						return 0
+					},
+					ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+						return
+					},
+				},
+			},
+			true,
+		},
+
+		// Map with a complex Elem
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{},
+					},
+				},
+			},
+			true,
+		},
+
+		// Map with a typed Elem
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+				},
+			},
+			false,
+		},
+
+		// ConflictsWith on a required field
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:          TypeString,
+					Required:      true,
+					ConflictsWith: []string{"bar"},
+				},
+			},
+			true,
+		},
+
+		// ConflictsWith itself
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:          TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"foo"},
+				},
+			},
+			true,
+		},
+
+		// MaxItems on a list
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeString},
+					MaxItems: 1,
+					MinItems: 1,
+				},
+			},
+			false,
+		},
+
+		// MaxItems on a primitive
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					MaxItems: 1,
+				},
+			},
+			true,
+		},
+
+		// MinItems greater than MaxItems
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeString},
+					MaxItems: 1,
+					MinItems: 2,
+				},
+			},
+			true,
+		},
+
+		// Removed on a required field
+		{
+			map[string]*Schema{
+				"foo": &Schema{
+					Type:     TypeString,
+					Required: true,
+					Removed:  "Use bar instead",
+				},
+			},
+			true,
 		},
 	}
 
@@ -3006,6 +3595,363 @@ func TestSchemaMap_Validate(t *testing.T) {
 
 			Err: true,
 		},
+
+		// #23 ValidateFunc returns errors
+		{
+			Schema: map[string]*Schema{
+				"validate_me": &Schema{
+					Type:     TypeString,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+						return nil, []error{fmt.Errorf("something is not right here")}
+					},
+				},
+			},
+			Config: map[string]interface{}{
+				"validate_me": "invalid",
+			},
+			Err: true,
+		},
+
+		// #24 ValidateFunc returns warnings
+		{
+			Schema: map[string]*Schema{
+				"validate_me": &Schema{
+					Type:     TypeString,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+						return []string{"you might want to check this"}, nil
+					},
+				},
+			},
+			Config: map[string]interface{}{
+				"validate_me": "valid",
+			},
+			Warn: true,
+		},
+
+		// #25 ValidateFunc receives the decoded value and key
+		{
+			Schema: map[string]*Schema{
+				"number": &Schema{
+					Type:     TypeInt,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+						if k != "number" {
+							es = append(es, fmt.Errorf("bad key: %s", k))
+						}
+						if n, ok := v.(int); !ok || n != 42 {
+							es = append(es, fmt.Errorf("bad value: %#v", v))
+						}
+						return
+					},
+				},
+			},
+			Config: map[string]interface{}{
+				"number": "42",
+			},
+		},
+
+		// #26 ValidateFunc is skipped for computed values
+		{
+			Schema: map[string]*Schema{
+				"validate_me": &Schema{
+					Type:     TypeString,
+					Required: true,
+					ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+						return nil, []error{fmt.Errorf("should not be called")}
+					},
+				},
+			},
+			Config: map[string]interface{}{
+				"validate_me": "${var.foo}",
+			},
+			Vars: map[string]string{
+				"var.foo": config.UnknownVariableValue,
+			},
+		},
+
+		// #27 Map with values of the wrong type
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeMap,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+				},
+			},
+			Config: map[string]interface{}{
+				"ports": map[string]interface{}{
+					"http": "eighty",
+				},
+			},
+			Err: true,
+		},
+
+		// #28 ConflictsWith
+		{
+			Schema: map[string]*Schema{
+				"security_groups": &Schema{
+					Type:          TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"vpc_security_group_ids"},
+				},
+				"vpc_security_group_ids": &Schema{
+					Type:          TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"security_groups"},
+				},
+			},
+			Config: map[string]interface{}{
+				"security_groups":        "foo",
+				"vpc_security_group_ids": "bar",
+			},
+			Err: true,
+		},
+
+		// #29 ConflictsWith with only one set
+		{
+			Schema: map[string]*Schema{
+				"security_groups": &Schema{
+					Type:          TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"vpc_security_group_ids"},
+				},
+				"vpc_security_group_ids": &Schema{
+					Type:          TypeString,
+					Optional:      true,
+					ConflictsWith: []string{"security_groups"},
+				},
+			},
+			Config: map[string]interface{}{
+				"vpc_security_group_ids": "bar",
+			},
+		},
+
+		// #30 RequiredWith
+		{
+			Schema: map[string]*Schema{
+				"user": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					RequiredWith: []string{"password"},
+				},
+				"password": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+			Config: map[string]interface{}{
+				"user": "foo",
+			},
+			Err: true,
+		},
+
+		// #31 RequiredWith with both set
+		{
+			Schema: map[string]*Schema{
+				"user": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					RequiredWith: []string{"password"},
+				},
+				"password": &Schema{
+					Type:     TypeString,
+					Optional: true,
+				},
+			},
+			Config: map[string]interface{}{
+				"user":     "foo",
+				"password": "bar",
+			},
+		},
+
+		// #32 ExactlyOneOf with none set
+		{
+			Schema: map[string]*Schema{
+				"foo": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+				"bar": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+			},
+			Config: map[string]interface{}{},
+			Err:    true,
+		},
+
+		// #33 ExactlyOneOf with both set
+		{
+			Schema: map[string]*Schema{
+				"foo": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+				"bar": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+			},
+			Config: map[string]interface{}{
+				"foo": "a",
+				"bar": "b",
+			},
+			Err: true,
+		},
+
+		// #34 ExactlyOneOf with one set
+		{
+			Schema: map[string]*Schema{
+				"foo": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+				"bar": &Schema{
+					Type:         TypeString,
+					Optional:     true,
+					ExactlyOneOf: []string{"foo", "bar"},
+				},
+			},
+			Config: map[string]interface{}{
+				"bar": "b",
+			},
+		},
+
+		// #35 MaxItems on a list
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+					MaxItems: 2,
+				},
+			},
+			Config: map[string]interface{}{
+				"ports": []interface{}{80, 443, 8080},
+			},
+			Err: true,
+		},
+
+		// #36 MaxItems on a set of blocks
+		{
+			Schema: map[string]*Schema{
+				"ingress": &Schema{
+					Type:     TypeSet,
+					Optional: true,
+					Elem: &Resource{
+						Schema: map[string]*Schema{
+							"port": &Schema{
+								Type:     TypeInt,
+								Required: true,
+							},
+						},
+					},
+					Set:      func(a interface{}) int { return 0 },
+					MaxItems: 1,
+				},
+			},
+			Config: map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{"port": 80},
+					map[string]interface{}{"port": 443},
+				},
+			},
+			Err: true,
+		},
+
+		// #37 MinItems on a list
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+					MinItems: 2,
+				},
+			},
+			Config: map[string]interface{}{
+				"ports": []interface{}{80},
+			},
+			Err: true,
+		},
+
+		// #38 Within MinItems and MaxItems
+		{
+			Schema: map[string]*Schema{
+				"ports": &Schema{
+					Type:     TypeList,
+					Optional: true,
+					Elem:     &Schema{Type: TypeInt},
+					MinItems: 1,
+					MaxItems: 2,
+				},
+			},
+			Config: map[string]interface{}{
+				"ports": []interface{}{80, 443},
+			},
+		},
+
+		// #39 Deprecated field set
+		{
+			Schema: map[string]*Schema{
+				"old_news": &Schema{
+					Type:       TypeString,
+					Optional:   true,
+					Deprecated: "please use 'new_news' instead",
+				},
+			},
+			Config: map[string]interface{}{
+				"old_news": "extra extra!",
+			},
+			Warn: true,
+		},
+
+		// #40 Deprecated field not set
+		{
+			Schema: map[string]*Schema{
+				"old_news": &Schema{
+					Type:       TypeString,
+					Optional:   true,
+					Deprecated: "please use 'new_news' instead",
+				},
+			},
+			Config: map[string]interface{}{},
+		},
+
+		// #41 Removed field set
+		{
+			Schema: map[string]*Schema{
+				"long_gone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Removed:  "no longer supported by Cloud API",
+				},
+			},
+			Config: map[string]interface{}{
+				"long_gone": "still here!",
+			},
+			Err: true,
+		},
+
+		// #42 Removed field not set
+		{
+			Schema: map[string]*Schema{
+				"long_gone": &Schema{
+					Type:     TypeString,
+					Optional: true,
+					Removed:  "no longer supported by Cloud API",
+				},
+			},
+			Config: map[string]interface{}{},
+		},
 	}
 
 	for i, tc := range cases {
@@ -3042,3 +3988,13 @@ func TestSchemaMap_Validate(t *testing.T) {
 		}
 	}
 }
+
+const testCredentialsFile = `
+# Comments are ignored
+[default]
+access_key = foo
+secret_key=bar
+
+[other]
+access_key = baz
+`
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// SerializeValueForHash writes a deterministic representation of the
+// given value of the given schema to the buffer, for hashing the elements
+// of sets. Equal values always result in the same representation.
+func SerializeValueForHash(buf *bytes.Buffer, val interface{}, schema *Schema) {
+	if val == nil {
+		buf.WriteRune(';')
+		return
+	}
+
+	switch schema.Type {
+	case TypeBool:
+		if val.(bool) {
+			buf.WriteRune('1')
+		} else {
+			buf.WriteRune('0')
+		}
+	case TypeInt:
+		buf.WriteString(strconv.Itoa(val.(int)))
+	case TypeFloat:
+		buf.WriteString(strconv.FormatFloat(val.(float64), 'g', -1, 64))
+	case TypeString:
+		buf.WriteString(val.(string))
+	case TypeList:
+		buf.WriteRune('(')
+		for _, v := range val.([]interface{}) {
+			serializeElemForHash(buf, v, schema.Elem)
+		}
+		buf.WriteRune(')')
+	case TypeMap:
+		m := val.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k, _ := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteRune('[')
+		for _, k := range keys {
+			buf.WriteString(k)
+			buf.WriteRune(':')
+			buf.WriteString(fmt.Sprintf("%v", m[k]))
+			buf.WriteRune(';')
+		}
+		buf.WriteRune(']')
+	case TypeSet:
+		// The elements of a set are listed in a deterministic order
+		buf.WriteRune('{')
+		for _, v := range val.(*Set).List() {
+			serializeElemForHash(buf, v, schema.Elem)
+		}
+		buf.WriteRune('}')
+	default:
+		panic(fmt.Sprintf("unknown schema type %#v", schema.Type))
+	}
+
+	buf.WriteRune(';')
+}
+
+// SerializeResourceForHash writes a deterministic representation of the
+// given value of a nested resource to the buffer, for hashing the
+// elements of sets. Only the fields that can be set in the configuration
+// are included, since computed fields aren't known yet when the
+// configuration is diffed.
+func SerializeResourceForHash(buf *bytes.Buffer, val interface{}, resource *Resource) {
+	if val == nil {
+		return
+	}
+
+	m := val.(map[string]interface{})
+	keys := make([]string, 0, len(resource.Schema))
+	for k, _ := range resource.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		schema := resource.Schema[k]
+		if !schema.Required && !schema.Optional {
+			continue
+		}
+
+		buf.WriteString(k)
+		buf.WriteRune(':')
+		SerializeValueForHash(buf, m[k], schema)
+	}
+}
+
+// serializeElemForHash serializes an element of a list or set, whose
+// schema is either a *Schema or a *Resource.
+func serializeElemForHash(buf *bytes.Buffer, val interface{}, elem interface{}) {
+	switch t := elem.(type) {
+	case *Schema:
+		SerializeValueForHash(buf, val, t)
+	case *Resource:
+		buf.WriteRune('<')
+		SerializeResourceForHash(buf, val, t)
+		buf.WriteString(">;")
+	default:
+		panic(fmt.Sprintf("invalid element type %T", elem))
+	}
+}
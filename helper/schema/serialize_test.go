@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSerializeForHash(t *testing.T) {
+	type testCase struct {
+		Schema   interface{}
+		Value    interface{}
+		Expected string
+	}
+
+	tests := []testCase{
+		testCase{
+			Schema: &Schema{
+				Type: TypeInt,
+			},
+			Value:    0,
+			Expected: "0;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeInt,
+			},
+			Value:    200,
+			Expected: "200;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeBool,
+			},
+			Value:    true,
+			Expected: "1;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeFloat,
+			},
+			Value:    1.5,
+			Expected: "1.5;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeString,
+			},
+			Value:    "hello",
+			Expected: "hello;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeString,
+			},
+			Value:    nil,
+			Expected: ";",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeMap,
+			},
+			Value: map[string]interface{}{
+				"foo": "bar",
+				"baz": "qux",
+			},
+			Expected: "[baz:qux;foo:bar;];",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeList,
+				Elem: &Schema{
+					Type: TypeString,
+				},
+			},
+			Value:    []interface{}{"a", "b", "c"},
+			Expected: "(a;b;c;);",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeSet,
+				Elem: &Schema{
+					Type: TypeInt,
+				},
+			},
+			Value: NewSet(func(v interface{}) int {
+				return v.(int)
+			}, []interface{}{3, 1, 2}),
+			Expected: "{1;2;3;};",
+		},
+
+		testCase{
+			Schema: &Resource{
+				Schema: map[string]*Schema{
+					"name": &Schema{
+						Type:     TypeString,
+						Required: true,
+					},
+					"size": &Schema{
+						Type:     TypeInt,
+						Optional: true,
+					},
+					"id": &Schema{
+						Type:     TypeString,
+						Computed: true,
+					},
+				},
+			},
+			Value: map[string]interface{}{
+				"name": "foo",
+				"size": 42,
+				"id":   "computed",
+			},
+			Expected: "name:foo;size:42;",
+		},
+
+		testCase{
+			Schema: &Schema{
+				Type: TypeList,
+				Elem: &Resource{
+					Schema: map[string]*Schema{
+						"port": &Schema{
+							Type:     TypeInt,
+							Required: true,
+						},
+						"cidrs": &Schema{
+							Type:     TypeList,
+							Optional: true,
+							Elem: &Schema{
+								Type: TypeString,
+							},
+						},
+					},
+				},
+			},
+			Value: []interface{}{
+				map[string]interface{}{
+					"port":  80,
+					"cidrs": []interface{}{"10.0.0.0/8"},
+				},
+				map[string]interface{}{
+					"port": 443,
+				},
+			},
+			Expected: "(<cidrs:(10.0.0.0/8;);port:80;>;<cidrs:;port:443;>;);",
+		},
+	}
+
+	for i, test := range tests {
+		var buf bytes.Buffer
+		switch s := test.Schema.(type) {
+		case *Schema:
+			SerializeValueForHash(&buf, test.Value, s)
+		case *Resource:
+			SerializeResourceForHash(&buf, test.Value, s)
+		}
+
+		actual := buf.String()
+		if actual != test.Expected {
+			t.Fatalf("%d: got %q; want %q", i, actual, test.Expected)
+		}
+	}
+}
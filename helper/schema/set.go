@@ -1,12 +1,41 @@
 package schema
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"sort"
 	"sync"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
 )
 
+// HashString is a SchemaSetFunc for sets of strings.
+func HashString(v interface{}) int {
+	return hashcode.String(v.(string))
+}
+
+// HashSchema returns a SchemaSetFunc for sets whose elements have the
+// given schema, such as sets of ints.
+func HashSchema(schema *Schema) SchemaSetFunc {
+	return func(v interface{}) int {
+		var buf bytes.Buffer
+		SerializeValueForHash(&buf, v, schema)
+		return hashcode.String(buf.String())
+	}
+}
+
+// HashResource returns a SchemaSetFunc for sets of the given nested
+// resource, which hashes all the fields of an element that can be set
+// in the configuration.
+func HashResource(resource *Resource) SchemaSetFunc {
+	return func(v interface{}) int {
+		var buf bytes.Buffer
+		SerializeResourceForHash(&buf, v, resource)
+		return hashcode.String(buf.String())
+	}
+}
+
 // Set is a set data structure that is returned for elements of type
 // TypeSet.
 type Set struct {
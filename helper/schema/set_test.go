@@ -5,6 +5,56 @@ import (
 	"testing"
 )
 
+func TestHashResource(t *testing.T) {
+	f := HashResource(&Resource{
+		Schema: map[string]*Schema{
+			"port": &Schema{
+				Type:     TypeInt,
+				Required: true,
+			},
+			"protocol": &Schema{
+				Type:     TypeString,
+				Optional: true,
+			},
+			"id": &Schema{
+				Type:     TypeString,
+				Computed: true,
+			},
+		},
+	})
+
+	a := f(map[string]interface{}{
+		"port":     80,
+		"protocol": "tcp",
+	})
+	b := f(map[string]interface{}{
+		"port":     80,
+		"protocol": "tcp",
+		"id":       "computed",
+	})
+	c := f(map[string]interface{}{
+		"port":     80,
+		"protocol": "udp",
+	})
+
+	if a != b {
+		t.Fatalf("computed fields should be ignored: %d != %d", a, b)
+	}
+	if a == c {
+		t.Fatalf("different elements should differ: %d", a)
+	}
+}
+
+func TestHashSchema(t *testing.T) {
+	f := HashSchema(&Schema{Type: TypeInt})
+	if f(80) == f(443) {
+		t.Fatal("different elements should differ")
+	}
+	if f(80) != f(80) {
+		t.Fatal("equal elements should be equal")
+	}
+}
+
 func TestSetAdd(t *testing.T) {
 	s := &Set{F: testSetInt}
 	s.Add(1)
@@ -0,0 +1,153 @@
+// Package validation provides common functions for the ValidateFunc of a
+// schema.Schema, so that providers validate the same kinds of values in
+// the same way.
+package validation
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// IntBetween returns a SchemaValidateFunc which tests if the provided value
+// is of type int and is between min and max (inclusive).
+func IntBetween(min, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (ws []string, es []error) {
+		v, ok := i.(int)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %q to be int", k))
+			return
+		}
+
+		if v < min || v > max {
+			es = append(es, fmt.Errorf(
+				"%q must be between %d and %d, got %d", k, min, max, v))
+		}
+
+		return
+	}
+}
+
+// StringInSlice returns a SchemaValidateFunc which tests if the provided
+// value is of type string and matches a value in the valid slice. The
+// comparison ignores case if ignoreCase is true.
+func StringInSlice(valid []string, ignoreCase bool) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (ws []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %q to be string", k))
+			return
+		}
+
+		for _, str := range valid {
+			if v == str || (ignoreCase && strings.EqualFold(v, str)) {
+				return
+			}
+		}
+
+		es = append(es, fmt.Errorf(
+			"%q must be one of %s, got %q", k, strings.Join(valid, ", "), v))
+		return
+	}
+}
+
+// StringMatch returns a SchemaValidateFunc which tests if the provided
+// value is of type string and matches the given regular expression. If
+// message is empty, the error names the regular expression instead.
+func StringMatch(r *regexp.Regexp, message string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (ws []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %q to be string", k))
+			return
+		}
+
+		if !r.MatchString(v) {
+			if message != "" {
+				es = append(es, fmt.Errorf("%q %s, got %q", k, message, v))
+			} else {
+				es = append(es, fmt.Errorf(
+					"%q must match %q, got %q", k, r.String(), v))
+			}
+		}
+
+		return
+	}
+}
+
+// CIDRNetwork returns a SchemaValidateFunc which tests if the provided
+// value is of type string, is in valid CIDR network notation, and has a
+// prefix length between min and max (inclusive). The address must be the
+// network address, so "10.0.0.1/16" is invalid.
+func CIDRNetwork(min, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (ws []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %q to be string", k))
+			return
+		}
+
+		ip, ipnet, err := net.ParseCIDR(v)
+		if err != nil {
+			es = append(es, fmt.Errorf(
+				"%q must be a CIDR network such as \"10.0.0.0/16\", got %q",
+				k, v))
+			return
+		}
+
+		if !ip.Equal(ipnet.IP) {
+			es = append(es, fmt.Errorf(
+				"%q must be a network address such as %q, got %q",
+				k, ipnet.String(), v))
+			return
+		}
+
+		sigbits, _ := ipnet.Mask.Size()
+		if sigbits < min || sigbits > max {
+			es = append(es, fmt.Errorf(
+				"%q must have a prefix length between /%d and /%d, got %q",
+				k, min, max, v))
+		}
+
+		return
+	}
+}
+
+// arnRegexp matches "arn:partition:service:region:account:resource",
+// where region and account may be empty.
+var arnRegexp = regexp.MustCompile(
+	`^arn:[a-z0-9-]+:[a-z0-9-]+:[a-z0-9-]*:[0-9]*:.+$`)
+
+// ARN is a SchemaValidateFunc which tests if the provided value is of
+// type string and is an Amazon Resource Name, such as
+// "arn:aws:iam::123456789012:user/foo".
+func ARN(i interface{}, k string) (ws []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if !arnRegexp.MatchString(v) {
+		es = append(es, fmt.Errorf(
+			"%q must be an ARN such as "+
+				"\"arn:aws:iam::123456789012:user/foo\", got %q", k, v))
+	}
+
+	return
+}
+
+// NoZeroValues is a SchemaValidateFunc which tests if the provided value
+// is not the zero value of its type, such as "" for strings or 0 for
+// numbers.
+func NoZeroValues(i interface{}, k string) (ws []string, es []error) {
+	if i == nil || i == reflect.Zero(reflect.TypeOf(i)).Interface() {
+		es = append(es, fmt.Errorf("%q must not be empty", k))
+	}
+
+	return
+}
@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type testCase struct {
+	val         interface{}
+	f           schema.SchemaValidateFunc
+	expectedErr string
+}
+
+func TestValidationIntBetween(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: 1,
+			f:   IntBetween(1, 1),
+		},
+		{
+			val: 1,
+			f:   IntBetween(0, 2),
+		},
+		{
+			val:         1,
+			f:           IntBetween(2, 3),
+			expectedErr: `"test_property" must be between 2 and 3, got 1`,
+		},
+		{
+			val:         "1",
+			f:           IntBetween(2, 3),
+			expectedErr: `expected type of "test_property" to be int`,
+		},
+	})
+}
+
+func TestValidationStringInSlice(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "ValidValue",
+			f:   StringInSlice([]string{"ValidValue", "AnotherValidValue"}, false),
+		},
+		{
+			val: "VALIDVALUE",
+			f:   StringInSlice([]string{"ValidValue", "AnotherValidValue"}, true),
+		},
+		{
+			val:         "VALIDVALUE",
+			f:           StringInSlice([]string{"ValidValue", "AnotherValidValue"}, false),
+			expectedErr: `"test_property" must be one of ValidValue, AnotherValidValue, got "VALIDVALUE"`,
+		},
+		{
+			val:         1,
+			f:           StringInSlice([]string{"ValidValue"}, false),
+			expectedErr: `expected type of "test_property" to be string`,
+		},
+	})
+}
+
+func TestValidationStringMatch(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "foobar",
+			f:   StringMatch(regexp.MustCompile("^foo"), ""),
+		},
+		{
+			val:         "bar",
+			f:           StringMatch(regexp.MustCompile("^foo"), ""),
+			expectedErr: `"test_property" must match "^foo", got "bar"`,
+		},
+		{
+			val:         "bar",
+			f:           StringMatch(regexp.MustCompile("^foo"), "must start with foo"),
+			expectedErr: `"test_property" must start with foo, got "bar"`,
+		},
+	})
+}
+
+func TestValidationCIDRNetwork(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "10.1.0.0/16",
+			f:   CIDRNetwork(16, 28),
+		},
+		{
+			val:         "10.1.0.1/16",
+			f:           CIDRNetwork(16, 28),
+			expectedErr: `"test_property" must be a network address such as "10.1.0.0/16", got "10.1.0.1/16"`,
+		},
+		{
+			val:         "10.0.0.0/8",
+			f:           CIDRNetwork(16, 28),
+			expectedErr: `"test_property" must have a prefix length between /16 and /28, got "10.0.0.0/8"`,
+		},
+		{
+			val:         "10.0.0.0",
+			f:           CIDRNetwork(16, 28),
+			expectedErr: `"test_property" must be a CIDR network`,
+		},
+	})
+}
+
+func TestValidationARN(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "arn:aws:iam::123456789012:user/foo",
+			f:   ARN,
+		},
+		{
+			val: "arn:aws:s3:::my_bucket",
+			f:   ARN,
+		},
+		{
+			val: "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-1234",
+			f:   ARN,
+		},
+		{
+			val:         "arn:aws:iam::123456789012",
+			f:           ARN,
+			expectedErr: `"test_property" must be an ARN`,
+		},
+		{
+			val:         "my_bucket",
+			f:           ARN,
+			expectedErr: `"test_property" must be an ARN`,
+		},
+	})
+}
+
+func TestValidationNoZeroValues(t *testing.T) {
+	runTestCases(t, []testCase{
+		{val: "foo", f: NoZeroValues},
+		{val: 1, f: NoZeroValues},
+		{val: true, f: NoZeroValues},
+		{
+			val:         "",
+			f:           NoZeroValues,
+			expectedErr: `"test_property" must not be empty`,
+		},
+		{
+			val:         0,
+			f:           NoZeroValues,
+			expectedErr: `"test_property" must not be empty`,
+		},
+		{
+			val:         false,
+			f:           NoZeroValues,
+			expectedErr: `"test_property" must not be empty`,
+		},
+	})
+}
+
+func runTestCases(t *testing.T, cases []testCase) {
+	for i, tc := range cases {
+		_, es := tc.f(tc.val, "test_property")
+
+		if len(es) == 0 {
+			if tc.expectedErr != "" {
+				t.Fatalf("%d: expected error %q", i, tc.expectedErr)
+			}
+			continue
+		}
+
+		if tc.expectedErr == "" {
+			t.Fatalf("%d: unexpected errors: %s", i, es)
+		}
+		if !strings.Contains(es[0].Error(), tc.expectedErr) {
+			t.Fatalf("%d: expected error %q, got %q", i, tc.expectedErr, es[0])
+		}
+	}
+}
@@ -30,6 +30,7 @@ const (
 // ContextOpts are the user-configurable options to create a context with
 // NewContext.
 type ContextOpts struct {
+	Destroy      bool
 	Diff         *Diff
 	Hooks        []Hook
 	Module       *module.Tree
@@ -37,6 +38,7 @@ type ContextOpts struct {
 	State        *State
 	Providers    map[string]ResourceProviderFactory
 	Provisioners map[string]ResourceProvisionerFactory
+	Targets      []string
 	Variables    map[string]string
 
 	UIInput UIInput
@@ -55,13 +57,16 @@ type Context struct {
 	sh           *stopHook
 	state        *State
 	stateLock    sync.RWMutex
+	targets      []string
 	uiInput      UIInput
 	variables    map[string]string
 
 	l                   sync.Mutex // Lock acquired during any task
+	destroy             bool
 	parallelSem         Semaphore
 	providerInputConfig map[string]map[string]interface{}
 	runCh               <-chan struct{}
+	stopCh              chan struct{}
 }
 
 // NewContext creates a new Context structure.
@@ -98,9 +103,11 @@ func NewContext(opts *ContextOpts) *Context {
 		providers:    opts.Providers,
 		provisioners: opts.Provisioners,
 		state:        state,
+		targets:      opts.Targets,
 		uiInput:      opts.UIInput,
 		variables:    opts.Variables,
 
+		destroy:             opts.Destroy,
 		parallelSem:         NewSemaphore(par),
 		providerInputConfig: make(map[string]map[string]interface{}),
 		sh:                  sh,
@@ -132,6 +139,9 @@ func (c *Context) GraphBuilder() GraphBuilder {
 		Providers:    providers,
 		Provisioners: provisioners,
 		State:        c.state,
+		Targets:      c.targets,
+		Destroy:      c.destroy,
+		Variables:    c.variables,
 	}
 }
 
@@ -247,13 +257,19 @@ func (c *Context) Plan(opts *PlanOpts) (*Plan, error) {
 	defer c.releaseRun(v)
 
 	p := &Plan{
-		Module: c.module,
-		Vars:   c.variables,
-		State:  c.state,
+		Module:  c.module,
+		Vars:    c.variables,
+		State:   c.state,
+		Targets: c.targets,
 	}
 
+	// The graph for a destroy keeps what depends on the targets rather
+	// than what they depend on, for this plan and the apply after it.
+	c.destroy = opts != nil && opts.Destroy
+	p.Destroy = c.destroy
+
 	var operation walkOperation
-	if opts != nil && opts.Destroy {
+	if c.destroy {
 		operation = walkPlanDestroy
 	} else {
 		// Set our state to be something temporary. We do this so that
@@ -325,8 +341,14 @@ func (c *Context) Stop() {
 		return
 	}
 
-	// Tell the hook we want to stop
+	// Tell the hook we want to stop, and stop the walk from starting
+	// anything new.
 	c.sh.Stop()
+	select {
+	case <-c.stopCh:
+	default:
+		close(c.stopCh)
+	}
 
 	// Wait for us to stop
 	c.l.Unlock()
@@ -352,6 +374,9 @@ func (c *Context) Validate() ([]string, []error) {
 		if err := smcUserVariables(config, c.variables); len(err) > 0 {
 			errs = multierror.Append(errs, err...)
 		}
+		if err := smcVariableValidations(config, c.variables); len(err) > 0 {
+			errs = multierror.Append(errs, err...)
+		}
 	}
 
 	// Walk
@@ -379,6 +404,7 @@ func (c *Context) acquireRun() chan<- struct{} {
 
 	ch := make(chan struct{})
 	c.runCh = ch
+	c.stopCh = make(chan struct{})
 	return ch
 }
 
@@ -388,6 +414,7 @@ func (c *Context) releaseRun(ch chan<- struct{}) {
 
 	close(ch)
 	c.runCh = nil
+	c.stopCh = nil
 	c.sh.Reset()
 }
 
@@ -400,6 +427,10 @@ func (c *Context) walk(operation walkOperation) (*ContextGraphWalker, error) {
 
 	// Walk the graph
 	log.Printf("[INFO] Starting graph walk: %s", operation.String())
-	walker := &ContextGraphWalker{Context: c, Operation: operation}
+	walker := &ContextGraphWalker{
+		Context:   c,
+		Operation: operation,
+		StopCh:    c.stopCh,
+	}
 	return walker, graph.Walk(walker)
 }
@@ -40,6 +40,142 @@ func TestContext2Plan(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_targeted(t *testing.T) {
+	m := testModule(t, "plan-targeted")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Targets: []string{"aws_instance.bar"},
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(plan.String())
+	expected := strings.TrimSpace(testTerraformPlanTargetedStr)
+	if actual != expected {
+		t.Fatalf("bad:\n%s", actual)
+	}
+
+	if !reflect.DeepEqual(plan.Targets, []string{"aws_instance.bar"}) {
+		t.Fatalf("bad: %#v", plan.Targets)
+	}
+}
+
+func TestContext2Plan_preventDestroy_bad(t *testing.T) {
+	m := testModule(t, "plan-prevent-destroy-bad")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.foo": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "i-abc123",
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err == nil {
+		t.Fatalf("should fail, plan:\n%s", plan)
+	}
+	if !strings.Contains(err.Error(), "prevent_destroy") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
+func TestContext2Plan_preventDestroy_good(t *testing.T) {
+	m := testModule(t, "plan-prevent-destroy-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.foo": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "i-abc123",
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !plan.Diff.Empty() {
+		t.Fatalf("bad:\n%s", plan)
+	}
+}
+
+func TestContext2Plan_preventDestroy_destroyPlan(t *testing.T) {
+	m := testModule(t, "plan-prevent-destroy-good")
+	p := testProvider("aws")
+	p.DiffFn = testDiffFn
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.foo": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "i-abc123",
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	plan, err := ctx.Plan(&PlanOpts{Destroy: true})
+	if err == nil {
+		t.Fatalf("should fail, plan:\n%s", plan)
+	}
+	if !strings.Contains(err.Error(), "prevent_destroy") {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
 func TestContext2Plan_emptyDiff(t *testing.T) {
 	m := testModule(t, "plan-empty")
 	p := testProvider("aws")
@@ -1399,6 +1535,50 @@ func TestContext2Refresh(t *testing.T) {
 	}
 }
 
+func TestContext2Refresh_dataSource(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "refresh-data-source")
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	p.ReadDataDiffReturn = &InstanceDiff{
+		Attributes: map[string]*ResourceAttrDiff{
+			"foo": &ResourceAttrDiff{
+				New: "yes",
+			},
+		},
+	}
+	p.ReadDataApplyReturn = &InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"foo": "yes",
+		},
+	}
+
+	s, err := ctx.Refresh()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ReadDataDiffCalled {
+		t.Fatal("ReadDataDiff should be called")
+	}
+	if !p.ReadDataApplyCalled {
+		t.Fatal("ReadDataApply should be called")
+	}
+
+	rs := s.RootModule().Resources["data.aws_data_source.foo"]
+	if rs == nil {
+		t.Fatalf("bad: %s", s)
+	}
+	if !reflect.DeepEqual(rs.Primary, p.ReadDataApplyReturn) {
+		t.Fatalf("bad: %#v", rs.Primary)
+	}
+}
+
 func TestContext2Refresh_delete(t *testing.T) {
 	p := testProvider("aws")
 	m := testModule(t, "refresh-basic")
@@ -2337,6 +2517,81 @@ func TestContext2Validate_tainted(t *testing.T) {
 	}
 }
 
+func TestContext2Validate_varValidation(t *testing.T) {
+	m := testModule(t, "validate-var-validation")
+	p := testProvider("aws")
+	c := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	w, e := c.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) > 0 {
+		t.Fatalf("bad: %#v", e)
+	}
+
+	c = testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		Variables: map[string]string{
+			"instance_type": "x1.huge",
+		},
+	})
+
+	w, e = c.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) == 0 {
+		t.Fatalf("bad: %#v", e)
+	}
+}
+
+func TestContext2Validate_moduleVarObject(t *testing.T) {
+	m := testModule(t, "validate-module-var-object")
+	p := testProvider("aws")
+	c := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	w, e := c.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) == 0 {
+		t.Fatalf("bad: %#v", e)
+	}
+}
+
+func TestContext2Validate_moduleVarValidation(t *testing.T) {
+	m := testModule(t, "validate-module-var-validation")
+	p := testProvider("aws")
+	c := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	w, e := c.Validate()
+	if len(w) > 0 {
+		t.Fatalf("bad: %#v", w)
+	}
+	if len(e) == 0 {
+		t.Fatalf("bad: %#v", e)
+	}
+}
+
 func TestContext2Validate_varRef(t *testing.T) {
 	m := testModule(t, "validate-variable-ref")
 	p := testProvider("aws")
@@ -2733,6 +2988,73 @@ func TestContext2Apply(t *testing.T) {
 	}
 }
 
+func TestContext2Apply_dataSourceComputed(t *testing.T) {
+	m := testModule(t, "apply-data-source-computed")
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	p.ReadDataDiffFn = func(
+		info *InstanceInfo, c *ResourceConfig) (*InstanceDiff, error) {
+		diff := &InstanceDiff{
+			Attributes: map[string]*ResourceAttrDiff{
+				"foo": &ResourceAttrDiff{
+					NewComputed: true,
+				},
+			},
+		}
+		if v, ok := c.Config["foo"]; ok {
+			diff.Attributes["foo"] = &ResourceAttrDiff{New: v.(string)}
+		}
+
+		return diff, nil
+	}
+	p.ReadDataApplyFn = func(
+		info *InstanceInfo, d *InstanceDiff) (*InstanceState, error) {
+		return &InstanceState{
+			ID: "bar",
+			Attributes: map[string]string{
+				"foo": d.Attributes["foo"].New,
+			},
+		}, nil
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+
+	// The data source can't be read until aws_instance.foo is created
+	if _, err := ctx.Refresh(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.ReadDataApplyCalled {
+		t.Fatal("ReadDataApply should not be called")
+	}
+
+	plan, err := ctx.Plan(nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if plan.Diff.RootModule().Resources["data.aws_data_source.bar"] == nil {
+		t.Fatalf("bad: %s", plan)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !p.ReadDataApplyCalled {
+		t.Fatal("ReadDataApply should be called")
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testTerraformApplyDataSourceComputedStr)
+	if actual != expected {
+		t.Fatalf("bad: \n%s", actual)
+	}
+}
+
 func TestContext2Apply_emptyModule(t *testing.T) {
 	m := testModule(t, "apply-empty-module")
 	p := testProvider("aws")
@@ -3368,6 +3690,64 @@ func TestContext2Apply_multiProvider(t *testing.T) {
 	}
 }
 
+func TestContext2Apply_providerAlias(t *testing.T) {
+	m := testModule(t, "apply-provider-alias")
+
+	// Each provider configuration gets its own instance, so record the
+	// region that each resource was applied with.
+	var lock sync.Mutex
+	regions := make(map[string]string)
+	factory := func() (ResourceProvider, error) {
+		var region string
+		p := testProvider("aws")
+		p.DiffFn = testDiffFn
+		p.ConfigureFn = func(c *ResourceConfig) error {
+			region = c.Config["region"].(string)
+			return nil
+		}
+		p.ApplyFn = func(
+			info *InstanceInfo,
+			s *InstanceState,
+			d *InstanceDiff) (*InstanceState, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			regions[info.Id] = region
+			return testApplyFn(info, s, d)
+		}
+
+		return p, nil
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": factory,
+		},
+	})
+
+	if _, err := ctx.Plan(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, err := ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"aws_instance.foo": "east",
+		"aws_instance.bar": "west",
+	}
+	if !reflect.DeepEqual(regions, expected) {
+		t.Fatalf("bad: %#v", regions)
+	}
+
+	rs := state.RootModule().Resources["aws_instance.bar"]
+	if rs == nil || rs.Provider != "aws.west" {
+		t.Fatalf("bad: %#v", rs)
+	}
+}
+
 func TestContext2Apply_nilDiff(t *testing.T) {
 	m := testModule(t, "apply-good")
 	p := testProvider("aws")
@@ -4136,6 +4516,79 @@ func TestContext2Apply_destroy(t *testing.T) {
 	}
 }
 
+func TestContext2Apply_destroyTargetedPlanFile(t *testing.T) {
+	m := testModule(t, "plan-targeted")
+	p := testProvider("aws")
+	p.ApplyFn = testApplyFn
+	p.DiffFn = testDiffFn
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.foo": &ResourceState{
+						Type:    "aws_instance",
+						Primary: &InstanceState{ID: "foo"},
+					},
+					"aws_instance.bar": &ResourceState{
+						Type:         "aws_instance",
+						Dependencies: []string{"aws_instance.foo"},
+						Primary:      &InstanceState{ID: "bar"},
+					},
+					"aws_instance.baz": &ResourceState{
+						Type:    "aws_instance",
+						Primary: &InstanceState{ID: "baz"},
+					},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Module: m,
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+		State:   state,
+		Targets: []string{"aws_instance.foo"},
+	})
+
+	plan, err := ctx.Plan(&PlanOpts{Destroy: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Write and read the plan, as apply does with a plan file, so that
+	// the destroy must come from the plan rather than this context.
+	var buf bytes.Buffer
+	if err := WritePlan(plan, &buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	plan, err = ReadPlan(&buf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !plan.Destroy {
+		t.Fatal("plan should be a destroy")
+	}
+
+	ctx = plan.Context(&ContextOpts{
+		Providers: map[string]ResourceProviderFactory{
+			"aws": testProviderFuncFixed(p),
+		},
+	})
+	state, err = ctx.Apply()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// What depends on the target is destroyed along with it
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testTerraformApplyDestroyTargetedPlanFileStr)
+	if actual != expected {
+		t.Fatalf("bad: \n%s", actual)
+	}
+}
+
 func TestContext2Apply_destroyOutputs(t *testing.T) {
 	m := testModule(t, "apply-destroy-outputs")
 	h := new(HookRecordApplyOrder)
@@ -212,6 +212,8 @@ func (d *ModuleDiff) String() string {
 			crud = "DESTROY/CREATE"
 		} else if rdiff.Destroy {
 			crud = "DESTROY"
+		} else if strings.HasPrefix(name, "data.") {
+			crud = "READ"
 		} else if rdiff.RequiresNew() {
 			crud = "CREATE"
 		}
@@ -266,6 +268,11 @@ type InstanceDiff struct {
 	Attributes     map[string]*ResourceAttrDiff
 	Destroy        bool
 	DestroyTainted bool
+
+	// Meta is a simple key/value map that providers can use to pass
+	// data that isn't an attribute, such as timeouts, from the diff to
+	// the apply. It doesn't make the diff any less empty.
+	Meta map[string]string
 }
 
 // ResourceAttrDiff is the diff of a single attribute of a resource.
@@ -276,6 +283,7 @@ type ResourceAttrDiff struct {
 	NewRemoved  bool        // True if this attribute is being removed
 	NewExtra    interface{} // Extra information for the provider
 	RequiresNew bool        // True if change requires new resource
+	Sensitive   bool        // True if the values should not be shown
 	Type        DiffAttrType
 }
 
@@ -0,0 +1,33 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// EvalCheckPreventDestroy is an EvalNode implementation that returns an
+// error if a resource has PreventDestroy configured and the diff would
+// destroy the resource.
+type EvalCheckPreventDestroy struct {
+	Resource *config.Resource
+	Diff     **InstanceDiff
+}
+
+func (n *EvalCheckPreventDestroy) Eval(ctx EvalContext) (interface{}, error) {
+	if n.Diff == nil || *n.Diff == nil || n.Resource == nil {
+		return nil, nil
+	}
+
+	diff := *n.Diff
+	if diff.Destroy && n.Resource.Lifecycle.PreventDestroy {
+		return nil, fmt.Errorf(preventDestroyErrStr, n.Resource.Id())
+	}
+
+	return nil, nil
+}
+
+const preventDestroyErrStr = `%s: the plan would destroy this resource, ` +
+	`but it has lifecycle.prevent_destroy set to true. To continue, ` +
+	`either remove lifecycle.prevent_destroy or limit the plan to other ` +
+	`resources with the -target flag.`
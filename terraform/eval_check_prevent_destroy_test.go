@@ -0,0 +1,35 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestEvalCheckPreventDestroy(t *testing.T) {
+	cases := []struct {
+		Prevent bool
+		Diff    *InstanceDiff
+		Err     bool
+	}{
+		{true, nil, false},
+		{true, &InstanceDiff{}, false},
+		{true, &InstanceDiff{Destroy: true}, true},
+		{false, &InstanceDiff{Destroy: true}, false},
+	}
+
+	for i, tc := range cases {
+		r := &config.Resource{
+			Name:      "foo",
+			Type:      "aws_instance",
+			Lifecycle: config.ResourceLifecycle{PreventDestroy: tc.Prevent},
+		}
+
+		diff := tc.Diff
+		n := &EvalCheckPreventDestroy{Resource: r, Diff: &diff}
+		_, err := n.Eval(new(MockEvalContext))
+		if (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+	}
+}
@@ -1,9 +1,97 @@
 package terraform
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/hashicorp/terraform/config"
 )
 
+// EvalCountCheckComputed is an EvalNode that checks that the count of
+// a resource is known. The count can reference other resources and
+// modules, but only values of them that are known when planning.
+type EvalCountCheckComputed struct {
+	Resource *config.Resource
+}
+
+func (n *EvalCountCheckComputed) Eval(ctx EvalContext) (interface{}, error) {
+	// Counts that don't reference other resources or modules are
+	// always known, and any error in them is reported elsewhere.
+	dynamic := false
+	for _, v := range n.Resource.RawCount.Variables {
+		switch v.(type) {
+		case *config.ModuleVariable, *config.ResourceVariable:
+			dynamic = true
+		}
+	}
+	if !dynamic {
+		return nil, nil
+	}
+
+	// If the count isn't known, or the values it references don't
+	// exist yet, then it can't be computed.
+	if len(n.Resource.RawCount.UnknownKeys()) == 0 {
+		if _, err := n.Resource.Count(); err == nil {
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%s: count can't be computed because it depends on values that "+
+			"aren't known until they're applied. Apply the resources "+
+			"that the count depends on first, then apply again.",
+		n.Resource.Id())
+}
+
+// EvalCountFromState is an EvalNode that sets the count of a resource
+// whose count isn't known to the number of instances of the resource
+// in the state. This is used when the count depends on other resources,
+// but we're only working with what already exists, such as when
+// refreshing.
+type EvalCountFromState struct {
+	Resource *config.Resource
+}
+
+func (n *EvalCountFromState) Eval(ctx EvalContext) (interface{}, error) {
+	if len(n.Resource.RawCount.UnknownKeys()) == 0 {
+		return nil, nil
+	}
+
+	state, lock := ctx.State()
+
+	// Get a read lock so we can access this instance
+	lock.RLock()
+	defer lock.RUnlock()
+
+	count := 0
+	if mod := state.ModuleByPath(ctx.Path()); mod != nil {
+		id := n.Resource.Id()
+		for k, _ := range mod.Resources {
+			if k == id {
+				if count < 1 {
+					count = 1
+				}
+
+				continue
+			}
+
+			if !strings.HasPrefix(k, id+".") {
+				continue
+			}
+
+			idx, err := strconv.ParseInt(k[len(id)+1:], 0, 0)
+			if err == nil && int(idx) >= count {
+				count = int(idx) + 1
+			}
+		}
+	}
+
+	c := n.Resource.RawCount.Config()
+	c[n.Resource.RawCount.Key] = strconv.FormatInt(int64(count), 10)
+	return nil, nil
+}
+
 // EvalCountFixZeroOneBoundary is an EvalNode that fixes up the state
 // when there is a resource count with zero/one boundary, i.e. fixing
 // a resource named "aws_instance.foo" to "aws_instance.foo.0" and vice-versa.
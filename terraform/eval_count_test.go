@@ -0,0 +1,107 @@
+package terraform
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/config/lang/ast"
+)
+
+func TestEvalCountCheckComputed(t *testing.T) {
+	cases := []struct {
+		Count string
+		Value string
+		Err   bool
+	}{
+		{"2", "", false},
+		{"${aws_instance.foo.count}", "3", false},
+		{"${aws_instance.foo.count}", config.UnknownVariableValue, true},
+		{"${aws_instance.foo.count}", "", true},
+	}
+
+	for i, tc := range cases {
+		count, err := config.NewRawConfig(map[string]interface{}{
+			"count": tc.Count,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		count.Key = "count"
+
+		err = count.Interpolate(map[string]ast.Variable{
+			"aws_instance.foo.count": ast.Variable{
+				Value: tc.Value,
+				Type:  ast.TypeString,
+			},
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		n := &EvalCountCheckComputed{
+			Resource: &config.Resource{
+				Name:     "bar",
+				Type:     "aws_instance",
+				RawCount: count,
+			},
+		}
+		if _, err := n.Eval(new(MockEvalContext)); (err != nil) != tc.Err {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+	}
+}
+
+func TestEvalCountFromState(t *testing.T) {
+	count, err := config.NewRawConfig(map[string]interface{}{
+		"count": "${aws_instance.foo.count}",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	count.Key = "count"
+
+	err = count.Interpolate(map[string]ast.Variable{
+		"aws_instance.foo.count": ast.Variable{
+			Value: config.UnknownVariableValue,
+			Type:  ast.TypeString,
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx := new(MockEvalContext)
+	ctx.PathPath = rootModulePath
+	ctx.StateLock = new(sync.RWMutex)
+	ctx.StateState = &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.bar.0":  &ResourceState{},
+					"aws_instance.bar.2":  &ResourceState{},
+					"aws_instance.barbaz": &ResourceState{},
+				},
+			},
+		},
+	}
+
+	r := &config.Resource{
+		Name:     "bar",
+		Type:     "aws_instance",
+		RawCount: count,
+	}
+	n := &EvalCountFromState{Resource: r}
+	if _, err := n.Eval(ctx); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual, err := r.Count()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual != 3 {
+		t.Fatalf("bad: %d", actual)
+	}
+}
@@ -3,6 +3,9 @@ package terraform
 import (
 	"fmt"
 	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/config"
 )
 
 // EvalCompareDiff is an EvalNode implementation that compares two diffs
@@ -52,11 +55,16 @@ func (n *EvalCompareDiff) Eval(ctx EvalContext) (interface{}, error) {
 
 // EvalDiff is an EvalNode implementation that does a refresh for
 // a resource.
+//
+// If Resource is set, the changes to the attributes in its
+// lifecycle.ignore_changes are left out of the diff of an existing
+// resource.
 type EvalDiff struct {
 	Info        *InstanceInfo
 	Config      **ResourceConfig
 	Provider    *ResourceProvider
 	State       **InstanceState
+	Resource    *config.Resource
 	Output      **InstanceDiff
 	OutputState **InstanceState
 }
@@ -91,6 +99,12 @@ func (n *EvalDiff) Eval(ctx EvalContext) (interface{}, error) {
 		diff = new(InstanceDiff)
 	}
 
+	// Ignore the changes the configuration asks us to, unless we're
+	// creating the resource, in which case every attribute is needed.
+	if n.Resource != nil && state != nil && state.ID != "" {
+		diffIgnoreChanges(diff, n.Resource.Lifecycle.IgnoreChanges)
+	}
+
 	// Require a destroy if there is no ID and it requires new.
 	if diff.RequiresNew() && state != nil && state.ID != "" {
 		diff.Destroy = true
@@ -137,6 +151,24 @@ func (n *EvalDiff) Eval(ctx EvalContext) (interface{}, error) {
 	return nil, nil
 }
 
+// diffIgnoreChanges removes the changes to the given attributes from the
+// diff. An attribute that is a list, set or map is ignored along with all
+// of its elements, so "tags" ignores "tags.#" and "tags.Name".
+func diffIgnoreChanges(diff *InstanceDiff, ignore []string) {
+	if len(ignore) == 0 {
+		return
+	}
+
+	for k := range diff.Attributes {
+		for _, name := range ignore {
+			if k == name || strings.HasPrefix(k, name+".") {
+				delete(diff.Attributes, k)
+				break
+			}
+		}
+	}
+}
+
 // EvalDiffDestroy is an EvalNode implementation that returns a plain
 // destroy diff.
 type EvalDiffDestroy struct {
@@ -2,7 +2,10 @@ package terraform
 
 import (
 	"reflect"
+	"sort"
 	"testing"
+
+	"github.com/hashicorp/terraform/config"
 )
 
 func TestEvalFilterDiff(t *testing.T) {
@@ -76,3 +79,110 @@ func TestEvalFilterDiff(t *testing.T) {
 		}
 	}
 }
+
+func TestEvalDiff_ignoreChanges(t *testing.T) {
+	ctx := new(MockEvalContext)
+	r := &config.Resource{
+		Name: "foo",
+		Type: "aws_instance",
+		Lifecycle: config.ResourceLifecycle{
+			IgnoreChanges: []string{"tags", "ami"},
+		},
+	}
+
+	cases := []struct {
+		State  *InstanceState
+		Attrs  []string
+		Output []string
+	}{
+		// Ignored attributes of an existing resource are left out,
+		// including the elements of ignored maps.
+		{
+			&InstanceState{ID: "foo"},
+			[]string{"ami", "tags.#", "tags.Name", "tagsfoo", "size"},
+			[]string{"size", "tagsfoo"},
+		},
+
+		// A new resource needs all of its attributes
+		{
+			nil,
+			[]string{"ami", "tags.#"},
+			[]string{"ami", "id", "tags.#"},
+		},
+	}
+
+	for i, tc := range cases {
+		diff := &InstanceDiff{Attributes: make(map[string]*ResourceAttrDiff)}
+		for _, k := range tc.Attrs {
+			diff.Attributes[k] = &ResourceAttrDiff{New: "bar"}
+		}
+
+		var provider ResourceProvider = &MockResourceProvider{DiffReturn: diff}
+		var output *InstanceDiff
+		rc := new(ResourceConfig)
+		state := tc.State
+		n := &EvalDiff{
+			Info:     &InstanceInfo{Id: "aws_instance.foo"},
+			Config:   &rc,
+			Provider: &provider,
+			State:    &state,
+			Resource: r,
+			Output:   &output,
+		}
+		if _, err := n.Eval(ctx); err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		var actual []string
+		for k := range output.Attributes {
+			actual = append(actual, k)
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, tc.Output) {
+			t.Fatalf("%d: bad: %#v", i, actual)
+		}
+	}
+}
+
+// Ignoring the only attribute that forces a new resource means the
+// resource is no longer replaced.
+func TestEvalDiff_ignoreChangesRequiresNew(t *testing.T) {
+	ctx := new(MockEvalContext)
+	r := &config.Resource{
+		Name: "foo",
+		Type: "aws_instance",
+		Lifecycle: config.ResourceLifecycle{
+			IgnoreChanges: []string{"ami"},
+		},
+	}
+
+	var provider ResourceProvider = &MockResourceProvider{
+		DiffReturn: &InstanceDiff{
+			Attributes: map[string]*ResourceAttrDiff{
+				"ami": &ResourceAttrDiff{
+					Old:         "ami-old",
+					New:         "ami-new",
+					RequiresNew: true,
+				},
+			},
+		},
+	}
+	var output *InstanceDiff
+	rc := new(ResourceConfig)
+	state := &InstanceState{ID: "foo"}
+	n := &EvalDiff{
+		Info:     &InstanceInfo{Id: "aws_instance.foo"},
+		Config:   &rc,
+		Provider: &provider,
+		State:    &state,
+		Resource: r,
+		Output:   &output,
+	}
+	if _, err := n.Eval(ctx); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if output.Destroy || output.RequiresNew() || !output.Empty() {
+		t.Fatalf("bad: %#v", output)
+	}
+}
@@ -68,11 +68,12 @@ func (n *EvalGetProvider) Eval(ctx EvalContext) (interface{}, error) {
 }
 
 // EvalInputProvider is an EvalNode implementation that asks for input
-// for the given provider configurations.
+// for the given provider configurations. The configuration must be
+// interpolated so that the provider sees the values of any variables.
 type EvalInputProvider struct {
 	Name     string
 	Provider *ResourceProvider
-	Config   *config.RawConfig
+	Config   **ResourceConfig
 }
 
 func (n *EvalInputProvider) Eval(ctx EvalContext) (interface{}, error) {
@@ -81,8 +82,17 @@ func (n *EvalInputProvider) Eval(ctx EvalContext) (interface{}, error) {
 		return nil, nil
 	}
 
-	rc := NewResourceConfig(n.Config)
-	rc.Config = make(map[string]interface{})
+	// The provider may modify the configuration, so give it a copy
+	// and remember what was already set so we only save the input.
+	rc := &ResourceConfig{
+		ComputedKeys: (*n.Config).ComputedKeys,
+		NullKeys:     (*n.Config).NullKeys,
+		Raw:          (*n.Config).Raw,
+		Config:       make(map[string]interface{}),
+	}
+	for k, v := range (*n.Config).Config {
+		rc.Config[k] = v
+	}
 
 	// Wrap the input into a namespace
 	input := &PrefixUIInput{
@@ -101,11 +111,15 @@ func (n *EvalInputProvider) Eval(ctx EvalContext) (interface{}, error) {
 
 	// Set the input that we received so that child modules don't attempt
 	// to ask for input again.
-	if config != nil && len(config.Config) > 0 {
-		ctx.SetProviderInput(n.Name, config.Config)
-	} else {
-		ctx.SetProviderInput(n.Name, map[string]interface{}{})
+	result := make(map[string]interface{})
+	if config != nil {
+		for k, v := range config.Config {
+			if _, ok := (*n.Config).Config[k]; !ok {
+				result[k] = v
+			}
+		}
 	}
+	ctx.SetProviderInput(n.Name, result)
 
 	return nil, nil
 }
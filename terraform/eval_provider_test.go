@@ -70,3 +70,47 @@ func TestEvalGetProvider(t *testing.T) {
 		t.Fatalf("bad: %#v", ctx.ProviderName)
 	}
 }
+
+func TestEvalInputProvider_impl(t *testing.T) {
+	var _ EvalNode = new(EvalInputProvider)
+}
+
+func TestEvalInputProvider(t *testing.T) {
+	config := testResourceConfig(t, map[string]interface{}{
+		"foo": "bar",
+	})
+
+	var actual interface{}
+	var provider ResourceProvider = &MockResourceProvider{
+		InputFn: func(i UIInput, c *ResourceConfig) (*ResourceConfig, error) {
+			actual, _ = c.Get("foo")
+			c.Config["baz"] = "qux"
+			return c, nil
+		},
+	}
+	n := &EvalInputProvider{
+		Name:     "foo",
+		Provider: &provider,
+		Config:   &config,
+	}
+
+	ctx := &MockEvalContext{InputInput: new(MockUIInput)}
+	if _, err := n.Eval(ctx); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual != "bar" {
+		t.Fatalf("bad: %#v", actual)
+	}
+	if !ctx.SetProviderInputCalled {
+		t.Fatal("should be called")
+	}
+
+	expected := map[string]interface{}{"baz": "qux"}
+	if !reflect.DeepEqual(ctx.SetProviderInputConfig, expected) {
+		t.Fatalf("bad: %#v", ctx.SetProviderInputConfig)
+	}
+	if _, ok := config.Config["baz"]; ok {
+		t.Fatalf("config shouldn't be modified: %#v", config.Config)
+	}
+}
@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"fmt"
+)
+
+// EvalReadDataDiff is an EvalNode implementation that computes the diff
+// of reading a data source. Data sources are read from scratch each
+// time, so the diff is always against an empty state.
+type EvalReadDataDiff struct {
+	Info        *InstanceInfo
+	Config      **ResourceConfig
+	Provider    *ResourceProvider
+	Output      **InstanceDiff
+	OutputState **InstanceState
+}
+
+func (n *EvalReadDataDiff) Eval(ctx EvalContext) (interface{}, error) {
+	config := *n.Config
+	provider := *n.Provider
+
+	// Call pre-diff hook
+	err := ctx.Hook(func(h Hook) (HookAction, error) {
+		return h.PreDiff(n.Info, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := provider.ReadDataDiff(n.Info, config)
+	if err != nil {
+		return nil, err
+	}
+	if diff == nil {
+		diff = new(InstanceDiff)
+	}
+
+	// The ID of a data source isn't known until it is read
+	diff.init()
+	diff.Attributes["id"] = &ResourceAttrDiff{
+		NewComputed: true,
+		RequiresNew: true,
+		Type:        DiffAttrOutput,
+	}
+
+	// Call post-diff hook
+	err = ctx.Hook(func(h Hook) (HookAction, error) {
+		return h.PostDiff(n.Info, diff)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	*n.Output = diff
+
+	// The state until the data source is read has the values of the diff,
+	// so anything not known yet is computed.
+	if n.OutputState != nil {
+		*n.OutputState = new(InstanceState).MergeDiff(diff)
+	}
+
+	return nil, nil
+}
+
+// EvalReadDataApply is an EvalNode implementation that reads a data
+// source with the diff from EvalReadDataDiff. If the diff is a destroy,
+// the data source is removed from the state instead.
+type EvalReadDataApply struct {
+	Info     *InstanceInfo
+	Provider *ResourceProvider
+	Diff     **InstanceDiff
+	Output   **InstanceState
+}
+
+func (n *EvalReadDataApply) Eval(ctx EvalContext) (interface{}, error) {
+	diff := *n.Diff
+
+	// Data sources aren't destroyed, they're only forgotten
+	if diff != nil && diff.Destroy {
+		*n.Output = nil
+		return nil, nil
+	}
+
+	// Call pre-refresh hook
+	err := ctx.Hook(func(h Hook) (HookAction, error) {
+		return h.PreRefresh(n.Info, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	provider := *n.Provider
+	state, err := provider.ReadDataApply(n.Info, diff)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", n.Info.Id, err)
+	}
+	if state == nil || state.ID == "" {
+		return nil, fmt.Errorf(
+			"%s: reading the data source returned no result", n.Info.Id)
+	}
+
+	// Call post-refresh hook
+	err = ctx.Hook(func(h Hook) (HookAction, error) {
+		return h.PostRefresh(n.Info, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	*n.Output = state
+	return nil, nil
+}
@@ -0,0 +1,101 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestEvalReadDataDiff(t *testing.T) {
+	p := new(MockResourceProvider)
+	p.ReadDataDiffReturn = &InstanceDiff{
+		Attributes: map[string]*ResourceAttrDiff{
+			"foo": &ResourceAttrDiff{
+				New: "bar",
+			},
+		},
+	}
+
+	provider := ResourceProvider(p)
+	rc := testResourceConfig(t, map[string]interface{}{"foo": "bar"})
+	var diff *InstanceDiff
+	var state *InstanceState
+	n := &EvalReadDataDiff{
+		Info:        &InstanceInfo{Id: "data.aws_ami.foo", Type: "aws_ami"},
+		Config:      &rc,
+		Provider:    &provider,
+		Output:      &diff,
+		OutputState: &state,
+	}
+
+	if _, err := n.Eval(new(MockEvalContext)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !p.ReadDataDiffCalled {
+		t.Fatal("ReadDataDiff should be called")
+	}
+	if attr := diff.Attributes["id"]; attr == nil || !attr.NewComputed {
+		t.Fatalf("bad: %#v", diff)
+	}
+
+	expected := map[string]string{
+		"id":  config.UnknownVariableValue,
+		"foo": "bar",
+	}
+	if !reflect.DeepEqual(state.Attributes, expected) {
+		t.Fatalf("bad: %#v", state.Attributes)
+	}
+}
+
+func TestEvalReadDataApply(t *testing.T) {
+	p := new(MockResourceProvider)
+	p.ReadDataApplyReturn = &InstanceState{ID: "foo"}
+
+	provider := ResourceProvider(p)
+	diff := &InstanceDiff{}
+	var state *InstanceState
+	n := &EvalReadDataApply{
+		Info:     &InstanceInfo{Id: "data.aws_ami.foo", Type: "aws_ami"},
+		Provider: &provider,
+		Diff:     &diff,
+		Output:   &state,
+	}
+
+	if _, err := n.Eval(new(MockEvalContext)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !p.ReadDataApplyCalled {
+		t.Fatal("ReadDataApply should be called")
+	}
+	if !reflect.DeepEqual(state, p.ReadDataApplyReturn) {
+		t.Fatalf("bad: %#v", state)
+	}
+}
+
+func TestEvalReadDataApply_destroy(t *testing.T) {
+	p := new(MockResourceProvider)
+
+	provider := ResourceProvider(p)
+	diff := &InstanceDiff{Destroy: true}
+	state := &InstanceState{ID: "foo"}
+	n := &EvalReadDataApply{
+		Info:     &InstanceInfo{Id: "data.aws_ami.foo", Type: "aws_ami"},
+		Provider: &provider,
+		Diff:     &diff,
+		Output:   &state,
+	}
+
+	if _, err := n.Eval(new(MockEvalContext)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if p.ReadDataApplyCalled {
+		t.Fatal("ReadDataApply should not be called")
+	}
+	if state != nil {
+		t.Fatalf("bad: %#v", state)
+	}
+}
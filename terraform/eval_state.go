@@ -103,6 +103,7 @@ func (n *EvalUpdateStateHook) Eval(ctx EvalContext) (interface{}, error) {
 type EvalWriteState struct {
 	Name                string
 	ResourceType        string
+	ResourceProvider    string
 	Dependencies        []string
 	State               **InstanceState
 	Tainted             *bool
@@ -135,6 +136,7 @@ func (n *EvalWriteState) Eval(ctx EvalContext) (interface{}, error) {
 		mod.Resources[n.Name] = rs
 	}
 	rs.Type = n.ResourceType
+	rs.Provider = n.ResourceProvider
 	rs.Dependencies = n.Dependencies
 
 	if n.Tainted != nil && *n.Tainted {
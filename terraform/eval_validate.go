@@ -111,6 +111,7 @@ type EvalValidateResource struct {
 	Config       **ResourceConfig
 	ResourceName string
 	ResourceType string
+	ResourceMode config.ResourceMode
 }
 
 func (n *EvalValidateResource) Eval(ctx EvalContext) (interface{}, error) {
@@ -118,7 +119,14 @@ func (n *EvalValidateResource) Eval(ctx EvalContext) (interface{}, error) {
 
 	provider := *n.Provider
 	cfg := *n.Config
-	warns, errs := provider.ValidateResource(n.ResourceType, cfg)
+	var warns []string
+	var errs []error
+	switch n.ResourceMode {
+	case config.DataResourceMode:
+		warns, errs = provider.ValidateDataSource(n.ResourceType, cfg)
+	default:
+		warns, errs = provider.ValidateResource(n.ResourceType, cfg)
+	}
 
 	// If the resouce name doesn't match the name regular
 	// expression, show a warning.
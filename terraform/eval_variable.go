@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -28,7 +29,6 @@ type EvalVariableBlock struct {
 	Variables map[string]string
 }
 
-// TODO: test
 func (n *EvalVariableBlock) Eval(ctx EvalContext) (interface{}, error) {
 	// Clear out the existing mapping
 	for k, _ := range n.Variables {
@@ -38,6 +38,22 @@ func (n *EvalVariableBlock) Eval(ctx EvalContext) (interface{}, error) {
 	// Get our configuration
 	rc := *n.Config
 	for k, v := range rc.Config {
+		// Maps, such as the values of variables with an object type,
+		// are set per element.
+		if m, ok := variableBlockMap(v); ok {
+			for mk, mv := range m {
+				var vStr string
+				if err := mapstructure.WeakDecode(mv, &vStr); err != nil {
+					return nil, errwrap.Wrapf(fmt.Sprintf(
+						"%s.%s: error reading value: {{err}}", k, mk), err)
+				}
+
+				n.Variables[k+"."+mk] = vStr
+			}
+
+			continue
+		}
+
 		var vStr string
 		if err := mapstructure.WeakDecode(v, &vStr); err != nil {
 			return nil, errwrap.Wrapf(fmt.Sprintf(
@@ -46,7 +62,11 @@ func (n *EvalVariableBlock) Eval(ctx EvalContext) (interface{}, error) {
 
 		n.Variables[k] = vStr
 	}
-	for k, _ := range rc.Raw {
+
+	// Variables that are computed aren't in the configuration, so mark
+	// them as unknown. Variables that are null are left unset so that
+	// their default is used.
+	for _, k := range rc.ComputedKeys {
 		if _, ok := n.Variables[k]; !ok {
 			n.Variables[k] = config.UnknownVariableValue
 		}
@@ -54,3 +74,43 @@ func (n *EvalVariableBlock) Eval(ctx EvalContext) (interface{}, error) {
 
 	return nil, nil
 }
+
+// variableBlockMap returns the value of a variable as a map if it is
+// one. Maps can be written either as a block or an object, which HCL
+// decodes as a list of maps.
+func variableBlockMap(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case []map[string]interface{}:
+		result := make(map[string]interface{})
+		for _, m := range t {
+			for k, v := range m {
+				result[k] = v
+			}
+		}
+
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// EvalValidateVariables is an EvalNode implementation that checks the
+// values of the variables of a module against their validation rules.
+type EvalValidateVariables struct {
+	Config    *config.Config
+	Variables map[string]string
+}
+
+func (n *EvalValidateVariables) Eval(ctx EvalContext) (interface{}, error) {
+	if n.Config == nil {
+		return nil, nil
+	}
+
+	if errs := smcVariableValidations(n.Config, n.Variables); len(errs) > 0 {
+		return nil, &multierror.Error{Errors: errs}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+func TestEvalVariableBlock_impl(t *testing.T) {
+	var _ EvalNode = new(EvalVariableBlock)
+}
+
+func TestEvalVariableBlock_map(t *testing.T) {
+	rc := &ResourceConfig{
+		ComputedKeys: []string{"subnet.name"},
+		Raw: map[string]interface{}{
+			"foo": "bar",
+			"subnet": []map[string]interface{}{
+				map[string]interface{}{
+					"cidr_block": "10.0.1.0/24",
+					"public":     true,
+					"name":       "${var.unknown}",
+				},
+			},
+		},
+		Config: map[string]interface{}{
+			"foo": "bar",
+			"subnet": []map[string]interface{}{
+				map[string]interface{}{
+					"cidr_block": "10.0.1.0/24",
+					"public":     true,
+				},
+			},
+		},
+	}
+
+	vars := map[string]string{"old": "value"}
+	n := &EvalVariableBlock{Config: &rc, Variables: vars}
+	if _, err := n.Eval(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]string{
+		"foo":               "bar",
+		"subnet.cidr_block": "10.0.1.0/24",
+		"subnet.public":     "1",
+		"subnet.name":       config.UnknownVariableValue,
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Fatalf("bad: %#v", vars)
+	}
+}
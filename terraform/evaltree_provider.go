@@ -22,10 +22,14 @@ func ProviderEvalTree(n string, config *config.RawConfig) EvalNode {
 					Name:   n,
 					Output: &provider,
 				},
+				&EvalInterpolate{
+					Config: config,
+					Output: &resourceConfig,
+				},
 				&EvalInputProvider{
 					Name:     n,
 					Provider: &provider,
-					Config:   config,
+					Config:   &resourceConfig,
 				},
 			},
 		},
@@ -92,13 +92,15 @@ func (g *Graph) ConnectFrom(source string, target dag.Vertex) {
 
 // ConnectTo connects a vertex to a raw string of targets that are the
 // result of DependableName, and returns the list of targets that are missing.
+// Each edge is labeled with the name it was made for, which is shown by
+// verbose dot output.
 func (g *Graph) ConnectTo(v dag.Vertex, targets []string) []string {
 	g.once.Do(g.init)
 
 	var missing []string
 	for _, t := range targets {
 		if dest := g.dependableMap[t]; dest != nil {
-			g.Connect(dag.BasicEdge(v, dest))
+			g.Connect(dag.LabeledEdge(v, dest, t))
 		} else {
 			missing = append(missing, t)
 		}
@@ -115,8 +117,9 @@ func (g *Graph) Dependable(n string) dag.Vertex {
 }
 
 // Walk walks the graph with the given walker for callbacks. The graph
-// will be walked with full parallelism, so the walker should expect
-// to be called in concurrently.
+// will be walked in parallel, limited only by the options returned by the
+// walker's WalkOpts, so the walker should expect to be called
+// concurrently.
 func (g *Graph) Walk(walker GraphWalker) error {
 	return g.walk(walker)
 }
@@ -194,7 +197,7 @@ func (g *Graph) walk(walker GraphWalker) error {
 		return nil
 	}
 
-	return g.AcyclicGraph.Walk(walkFn)
+	return g.AcyclicGraph.WalkWithOpts(walkFn, walker.WalkOpts(g))
 }
 
 // GraphNodeDependable is an interface which says that a node can be
@@ -1,8 +1,11 @@
 package terraform
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/config/module"
 )
 
@@ -34,12 +37,34 @@ func (b *BasicGraphBuilder) Build(path []string) (*Graph, error) {
 	// Validate the graph structure
 	if err := g.Validate(); err != nil {
 		log.Printf("[ERROR] Graph validation failed. Graph:\n\n%s", g.String())
-		return nil, err
+		return nil, graphValidateError(path, err)
 	}
 
 	return g, nil
 }
 
+// graphValidateError prefixes each error from validating the graph of a
+// child module with the path of the module, so that cycles point at the
+// resources responsible even when other modules have the same names.
+func graphValidateError(path []string, err error) error {
+	if len(path) <= 1 {
+		return err
+	}
+
+	errs := []error{err}
+	if merr, ok := err.(*multierror.Error); ok {
+		errs = merr.Errors
+	}
+
+	var result error
+	for _, e := range errs {
+		result = multierror.Append(result, fmt.Errorf(
+			"module.%s: %s", strings.Join(path[1:], "."), e))
+	}
+
+	return result
+}
+
 // BuiltinGraphBuilder is responsible for building the complete graph that
 // Terraform uses for execution. It is an opinionated builder that defines
 // the step order required to build a complete graph as is used and expected
@@ -58,11 +83,23 @@ type BuiltinGraphBuilder struct {
 	// up by graph path.
 	State *State
 
+	// Variables are the user variables for the root module. These
+	// are used to compute the count of modules.
+	Variables map[string]string
+
 	// Providers is the list of providers supported.
 	Providers []string
 
 	// Provisioners is the list of provisioners supported.
 	Provisioners []string
+
+	// Targets is the list of resources to target. If empty, everything
+	// is targeted. See TargetsTransformer.
+	Targets []string
+
+	// Destroy is true if the graph is for destroying, which changes
+	// what is kept when targeting.
+	Destroy bool
 }
 
 // Build builds the graph according to the steps returned by Steps.
@@ -79,9 +116,12 @@ func (b *BuiltinGraphBuilder) Build(path []string) (*Graph, error) {
 func (b *BuiltinGraphBuilder) Steps() []GraphTransformer {
 	return []GraphTransformer{
 		// Create all our resources from the configuration and state
-		&ConfigTransformer{Module: b.Root},
+		&ConfigTransformer{Module: b.Root, Variables: b.Variables},
 		&OrphanTransformer{State: b.State, Module: b.Root},
 
+		// Only keep the targeted resources and what they need, if any
+		&TargetsTransformer{Targets: b.Targets, Destroy: b.Destroy},
+
 		// Provider-related transformations
 		&MissingProviderTransformer{Providers: b.Providers},
 		&ProviderTransformer{},
@@ -109,5 +149,8 @@ func (b *BuiltinGraphBuilder) Steps() []GraphTransformer {
 
 		// Make sure we create one root
 		&RootTransformer{},
+
+		// Remove the redundant edges so the graph is quicker to walk
+		&TransitiveReductionTransformer{},
 	}
 }
@@ -49,6 +49,29 @@ func TestBasicGraphBuilder_validate(t *testing.T) {
 	}
 }
 
+func TestBasicGraphBuilder_validateCycleModule(t *testing.T) {
+	b := &BasicGraphBuilder{
+		Steps: []GraphTransformer{
+			&testBasicGraphBuilderTransform{1},
+			&testBasicGraphBuilderTransform{2},
+			&testBasicGraphBuilderTransform{3},
+			&testBasicGraphBuilderConnect{3, 1},
+			&testBasicGraphBuilderConnect{1, 2},
+			&testBasicGraphBuilderConnect{2, 1},
+		},
+	}
+
+	_, err := b.Build([]string{"root", "child"})
+	if err == nil {
+		t.Fatal("should error")
+	}
+
+	expected := "module.child: Cycle: 1 -> 2 -> 1"
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("bad: %s", err)
+	}
+}
+
 func TestBuiltinGraphBuilder_impl(t *testing.T) {
 	var _ GraphBuilder = new(BuiltinGraphBuilder)
 }
@@ -118,6 +141,15 @@ func (t *testBasicGraphBuilderTransform) Transform(g *Graph) error {
 	return nil
 }
 
+type testBasicGraphBuilderConnect struct {
+	Source, Target dag.Vertex
+}
+
+func (t *testBasicGraphBuilderConnect) Transform(g *Graph) error {
+	g.Connect(dag.BasicEdge(t.Source, t.Target))
+	return nil
+}
+
 const testBasicGraphBuilderStr = `
 1
 `
@@ -125,14 +157,10 @@ const testBasicGraphBuilderStr = `
 const testBuiltinGraphBuilderBasicStr = `
 aws_instance.db
   aws_instance.db (destroy tainted)
-  provider.aws
 aws_instance.db (destroy tainted)
   aws_instance.web (destroy tainted)
-  provider.aws
 aws_instance.web
   aws_instance.db
-  aws_instance.web (destroy tainted)
-  provider.aws
 aws_instance.web (destroy tainted)
   provider.aws
 provider.aws
@@ -21,11 +21,48 @@ type graphNodeConfig interface {
 	GraphNodeDependent
 }
 
+// GraphNodeConfigLocal represents a local value within the configuration
+// graph. Locals are computed on demand when they're referenced, so this
+// node only exists to order the graph: anything referencing a local
+// depends on everything the local references.
+type GraphNodeConfigLocal struct {
+	Local *config.Local
+}
+
+func (n *GraphNodeConfigLocal) Name() string {
+	return fmt.Sprintf("local.%s", n.Local.Name)
+}
+
+func (n *GraphNodeConfigLocal) DependableName() []string {
+	return []string{n.Name()}
+}
+
+// GraphNodeTargetable impl.
+func (n *GraphNodeConfigLocal) TargetName() string {
+	return n.Name()
+}
+
+func (n *GraphNodeConfigLocal) DependentOn() []string {
+	vars := n.Local.RawConfig.Variables
+	result := make([]string, 0, len(vars))
+	for _, v := range vars {
+		if vn := varNameForVar(v); vn != "" {
+			result = append(result, vn)
+		}
+	}
+
+	return result
+}
+
 // GraphNodeConfigModule represents a module within the configuration graph.
 type GraphNodeConfigModule struct {
 	Path   []string
 	Module *config.Module
 	Tree   *module.Tree
+
+	// Count is the number of instances of the module. This is only
+	// used if the module has a count set.
+	Count int
 }
 
 func (n *GraphNodeConfigModule) DependableName() []string {
@@ -34,7 +71,9 @@ func (n *GraphNodeConfigModule) DependableName() []string {
 
 func (n *GraphNodeConfigModule) DependentOn() []string {
 	vars := n.Module.RawConfig.Variables
-	result := make([]string, 0, len(vars))
+	result := make([]string, len(n.Module.DependsOn),
+		len(vars)+len(n.Module.DependsOn))
+	copy(result, n.Module.DependsOn)
 	for _, v := range vars {
 		if vn := varNameForVar(v); vn != "" {
 			result = append(result, vn)
@@ -48,10 +87,44 @@ func (n *GraphNodeConfigModule) Name() string {
 	return fmt.Sprintf("module.%s", n.Module.Name)
 }
 
+// GraphNodeTargetable impl.
+func (n *GraphNodeConfigModule) TargetName() string {
+	return n.Name()
+}
+
 // GraphNodeExpandable
 func (n *GraphNodeConfigModule) Expand(b GraphBuilder) (GraphNodeSubgraph, error) {
+	if n.Module.RawCount == nil {
+		return n.expandInstance(b, n.Path, -1)
+	}
+
+	// With a count, each instance of the module gets its own path and
+	// graph. They're all put into a single subgraph at our own path.
+	parent := n.Path[:len(n.Path)-1]
+	graph := &Graph{Path: parent}
+	for i := 0; i < n.Count; i++ {
+		path := make([]string, len(parent), len(parent)+1)
+		copy(path, parent)
+		path = append(path, fmt.Sprintf("%s.%d", n.Module.Name, i))
+
+		expanded, err := n.expandInstance(b, path, i)
+		if err != nil {
+			return nil, err
+		}
+
+		graph.Add(expanded)
+	}
+
+	return &GraphNodeBasicSubgraph{
+		NameValue: fmt.Sprintf("%s (expanded)", n.Name()),
+		Graph:     graph,
+	}, nil
+}
+
+func (n *GraphNodeConfigModule) expandInstance(
+	b GraphBuilder, path []string, index int) (GraphNodeSubgraph, error) {
 	// Build the graph first
-	graph, err := b.Build(n.Path)
+	graph, err := b.Build(path)
 	if err != nil {
 		return nil, err
 	}
@@ -62,12 +135,23 @@ func (n *GraphNodeConfigModule) Expand(b GraphBuilder) (GraphNodeSubgraph, error
 		return nil, err
 	}
 
+	// Every instance interpolates its own copy of the configuration
+	// since they're walked in parallel.
+	inputConfig := n.Module.RawConfig
+	if index >= 0 {
+		inputConfig, err = config.NewRawConfig(inputConfig.Raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build the actual subgraph node
 	return &graphNodeModuleExpanded{
 		Original:    n,
 		Graph:       graph,
-		InputConfig: n.Module.RawConfig,
+		InputConfig: inputConfig,
 		Variables:   t.Variables,
+		Index:       index,
 	}, nil
 }
 
@@ -79,10 +163,10 @@ func (n *GraphNodeConfigModule) ProvidedBy() []string {
 	config := n.Tree.Config()
 	providers := make(map[string]struct{})
 	for _, p := range config.ProviderConfigs {
-		providers[p.Name] = struct{}{}
+		providers[p.FullName()] = struct{}{}
 	}
 	for _, r := range config.Resources {
-		providers[resourceProvider(r.Type)] = struct{}{}
+		providers[resourceProvider(r.Type, r.Provider)] = struct{}{}
 	}
 
 	// Turn the map into a string. This makes sure that the list is
@@ -109,6 +193,11 @@ func (n *GraphNodeConfigOutput) DependableName() []string {
 	return []string{n.Name()}
 }
 
+// GraphNodeTargetable impl.
+func (n *GraphNodeConfigOutput) TargetName() string {
+	return n.Name()
+}
+
 func (n *GraphNodeConfigOutput) DependentOn() []string {
 	vars := n.Output.RawConfig.Variables
 	result := make([]string, 0, len(vars))
@@ -144,7 +233,7 @@ type GraphNodeConfigProvider struct {
 }
 
 func (n *GraphNodeConfigProvider) Name() string {
-	return fmt.Sprintf("provider.%s", n.Provider.Name)
+	return fmt.Sprintf("provider.%s", n.Provider.FullName())
 }
 
 func (n *GraphNodeConfigProvider) DependableName() []string {
@@ -165,12 +254,12 @@ func (n *GraphNodeConfigProvider) DependentOn() []string {
 
 // GraphNodeEvalable impl.
 func (n *GraphNodeConfigProvider) EvalTree() EvalNode {
-	return ProviderEvalTree(n.Provider.Name, n.Provider.RawConfig)
+	return ProviderEvalTree(n.Provider.FullName(), n.Provider.RawConfig)
 }
 
 // GraphNodeProvider implementation
 func (n *GraphNodeConfigProvider) ProviderName() string {
-	return n.Provider.Name
+	return n.Provider.FullName()
 }
 
 // GraphNodeDotter impl.
@@ -191,6 +280,16 @@ type GraphNodeConfigResource struct {
 	// If this is set to anything other than destroyModeNone, then this
 	// resource represents a resource that will be destroyed in some way.
 	DestroyMode GraphNodeDestroyMode
+
+	// forceCBD is set if create before destroy was forced on for this
+	// resource even though the configuration doesn't enable it.
+	forceCBD bool
+}
+
+// createBeforeDestroy returns true if the resource is created before it
+// is destroyed, either because it is configured or because it was forced.
+func (n *GraphNodeConfigResource) createBeforeDestroy() bool {
+	return n.Resource.Lifecycle.CreateBeforeDestroy || n.forceCBD
 }
 
 func (n *GraphNodeConfigResource) DependableName() []string {
@@ -246,6 +345,11 @@ func (n *GraphNodeConfigResource) Name() string {
 	return result
 }
 
+// GraphNodeTargetable impl.
+func (n *GraphNodeConfigResource) TargetName() string {
+	return n.Resource.Id()
+}
+
 // GraphNodeDotter impl.
 func (n *GraphNodeConfigResource) Dot(name string) string {
 	if n.DestroyMode != DestroyNone {
@@ -277,8 +381,9 @@ func (n *GraphNodeConfigResource) DynamicExpand(ctx EvalContext) (*Graph, error)
 		fallthrough
 	case DestroyPrimary:
 		steps = append(steps, &ResourceCountTransformer{
-			Resource: n.Resource,
-			Destroy:  n.DestroyMode != DestroyNone,
+			Resource:            n.Resource,
+			Destroy:             n.DestroyMode != DestroyNone,
+			CreateBeforeDestroy: n.createBeforeDestroy(),
 		})
 	}
 
@@ -293,13 +398,13 @@ func (n *GraphNodeConfigResource) DynamicExpand(ctx EvalContext) (*Graph, error)
 			View:  n.Resource.Id(),
 		})
 
-		if n.Resource.Lifecycle.CreateBeforeDestroy {
+		if n.createBeforeDestroy() {
 			// If we're only destroying tainted resources, then we only
 			// want to find tainted resources and destroy them here.
 			steps = append(steps, &TaintedTransformer{
 				State:          state,
 				View:           n.Resource.Id(),
-				Deposed:        n.Resource.Lifecycle.CreateBeforeDestroy,
+				Deposed:        n.createBeforeDestroy(),
 				DeposedInclude: true,
 			})
 		}
@@ -309,7 +414,7 @@ func (n *GraphNodeConfigResource) DynamicExpand(ctx EvalContext) (*Graph, error)
 		steps = append(steps, &TaintedTransformer{
 			State:          state,
 			View:           n.Resource.Id(),
-			Deposed:        n.Resource.Lifecycle.CreateBeforeDestroy,
+			Deposed:        n.createBeforeDestroy(),
 			DeposedInclude: false,
 		})
 	}
@@ -331,6 +436,14 @@ func (n *GraphNodeConfigResource) EvalTree() EvalNode {
 				Ops:  []walkOperation{walkValidate},
 				Node: &EvalValidateCount{Resource: n.Resource},
 			},
+			&EvalOpFilter{
+				Ops:  []walkOperation{walkRefresh, walkPlanDestroy},
+				Node: &EvalCountFromState{Resource: n.Resource},
+			},
+			&EvalOpFilter{
+				Ops:  []walkOperation{walkPlan, walkApply},
+				Node: &EvalCountCheckComputed{Resource: n.Resource},
+			},
 			&EvalCountFixZeroOneBoundary{Resource: n.Resource},
 		},
 	}
@@ -338,7 +451,7 @@ func (n *GraphNodeConfigResource) EvalTree() EvalNode {
 
 // GraphNodeProviderConsumer
 func (n *GraphNodeConfigResource) ProvidedBy() []string {
-	return []string{resourceProvider(n.Resource.Type)}
+	return []string{resourceProvider(n.Resource.Type, n.Resource.Provider)}
 }
 
 // GraphNodeProvisionerConsumer
@@ -358,6 +471,12 @@ func (n *GraphNodeConfigResource) DestroyNode(mode GraphNodeDestroyMode) GraphNo
 		return nil
 	}
 
+	// Data sources are never destroyed, only removed from the state,
+	// which is done by the data source node itself.
+	if n.Resource.Mode == config.DataResourceMode {
+		return nil
+	}
+
 	result := &graphNodeResourceDestroy{
 		GraphNodeConfigResource: *n,
 		Original:                n,
@@ -380,10 +499,22 @@ func (n *graphNodeResourceDestroy) CreateBeforeDestroy() bool {
 	// state destroy node is the only destroy node that needs to be
 	// "shuffled" according to the CBD rules, since tainted resources
 	// don't have the same inverse dependencies.
-	return n.Original.Resource.Lifecycle.CreateBeforeDestroy &&
+	return n.Original.createBeforeDestroy() &&
 		n.DestroyMode == DestroyPrimary
 }
 
+// GraphNodeDestroyForceable impl.
+func (n *graphNodeResourceDestroy) ForceCreateBeforeDestroy() bool {
+	// The flag is set on the create node as well as on this one so
+	// that the create and the tainted destroy of the resource also depose
+	// the primary rather than destroying it first. The configuration
+	// itself is shared and left alone. Only the primary destroy is
+	// reordered, so only it reports that it was forced.
+	n.forceCBD = true
+	n.Original.forceCBD = true
+	return n.DestroyMode == DestroyPrimary
+}
+
 func (n *graphNodeResourceDestroy) CreateNode() dag.Vertex {
 	return n.Original
 }
@@ -498,9 +629,18 @@ type graphNodeModuleExpanded struct {
 	// be shared with ModuleInputTransformer in order to create a connection
 	// where the variables are set properly.
 	Variables map[string]string
+
+	// Index is the index of this instance of a module with a count,
+	// or -1 if the module doesn't have a count.
+	Index int
 }
 
 func (n *graphNodeModuleExpanded) Name() string {
+	if n.Index >= 0 {
+		return fmt.Sprintf(
+			"%s.%d (expanded)", dag.VertexName(n.Original), n.Index)
+	}
+
 	return fmt.Sprintf("%s (expanded)", dag.VertexName(n.Original))
 }
 
@@ -517,12 +657,27 @@ func (n *graphNodeModuleExpanded) Dot(name string) string {
 
 // GraphNodeEvalable impl.
 func (n *graphNodeModuleExpanded) EvalTree() EvalNode {
+	// The count index is available to the module inputs by giving
+	// the interpolation a resource for this instance.
+	var resource *Resource
+	if n.Index >= 0 {
+		resource = &Resource{CountIndex: n.Index}
+	}
+
+	// The variables are checked against the rules of the module's
+	// own configuration.
+	var childConfig *config.Config
+	if m, ok := n.Original.(*GraphNodeConfigModule); ok && m.Tree != nil {
+		childConfig = m.Tree.Config()
+	}
+
 	var resourceConfig *ResourceConfig
 	return &EvalSequence{
 		Nodes: []EvalNode{
 			&EvalInterpolate{
-				Config: n.InputConfig,
-				Output: &resourceConfig,
+				Config:   n.InputConfig,
+				Resource: resource,
+				Output:   &resourceConfig,
 			},
 
 			&EvalVariableBlock{
@@ -530,6 +685,11 @@ func (n *graphNodeModuleExpanded) EvalTree() EvalNode {
 				Variables: n.Variables,
 			},
 
+			&EvalValidateVariables{
+				Config:    childConfig,
+				Variables: n.Variables,
+			},
+
 			&EvalOpFilter{
 				Ops: []walkOperation{walkPlanDestroy},
 				Node: &EvalSequence{
@@ -546,3 +706,12 @@ func (n *graphNodeModuleExpanded) EvalTree() EvalNode {
 func (n *graphNodeModuleExpanded) Subgraph() *Graph {
 	return n.Graph
 }
+
+// dag.DotSubgrapher impl.
+func (n *graphNodeModuleExpanded) DotSubgraph() *dag.Graph {
+	if n.Graph == nil {
+		return nil
+	}
+
+	return &n.Graph.AcyclicGraph.Graph
+}
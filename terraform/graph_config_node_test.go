@@ -16,6 +16,30 @@ func TestGraphNodeConfigModule_impl(t *testing.T) {
 	var _ GraphNodeExpandable = new(GraphNodeConfigModule)
 }
 
+func TestGraphNodeConfigModule_DependentOn(t *testing.T) {
+	rc, err := config.NewRawConfig(map[string]interface{}{
+		"foo": "${aws_instance.foo.id}",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	node := &GraphNodeConfigModule{
+		Path: []string{RootModuleName, "child"},
+		Module: &config.Module{
+			Name:      "child",
+			RawConfig: rc,
+			DependsOn: []string{"aws_vpc.main", "module.network"},
+		},
+	}
+
+	actual := node.DependentOn()
+	expected := []string{"aws_vpc.main", "module.network", "aws_instance.foo"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
 func TestGraphNodeConfigModuleExpand(t *testing.T) {
 	mod := testModule(t, "graph-node-module-expand")
 
@@ -41,6 +65,40 @@ func TestGraphNodeConfigModuleExpand(t *testing.T) {
 	}
 }
 
+func TestGraphNodeConfigModuleExpand_count(t *testing.T) {
+	mod := testModule(t, "graph-node-module-expand")
+
+	count, err := config.NewRawConfig(map[string]interface{}{
+		"count": "2",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	count.Key = "count"
+
+	node := &GraphNodeConfigModule{
+		Path:   []string{RootModuleName, "child"},
+		Module: &config.Module{Name: "child", RawCount: count},
+		Tree:   nil,
+		Count:  2,
+	}
+
+	g, err := node.Expand(&BasicGraphBuilder{
+		Steps: []GraphTransformer{
+			&ConfigTransformer{Module: mod},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.Subgraph().String())
+	expected := strings.TrimSpace(testGraphNodeModuleExpandCountStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
 func TestGraphNodeConfigProvider_impl(t *testing.T) {
 	var _ dag.Vertex = new(GraphNodeConfigProvider)
 	var _ dag.NamedVertex = new(GraphNodeConfigProvider)
@@ -76,6 +134,16 @@ func TestGraphNodeConfigResource_ProvidedBy(t *testing.T) {
 	}
 }
 
+func TestGraphNodeConfigResource_ProvidedBy_alias(t *testing.T) {
+	n := &GraphNodeConfigResource{
+		Resource: &config.Resource{Type: "aws_instance", Provider: "aws.west"},
+	}
+
+	if v := n.ProvidedBy(); v[0] != "aws.west" {
+		t.Fatalf("bad: %#v", v)
+	}
+}
+
 func TestGraphNodeConfigResource_ProvisionedBy(t *testing.T) {
 	n := &GraphNodeConfigResource{
 		Resource: &config.Resource{
@@ -94,6 +162,11 @@ func TestGraphNodeConfigResource_ProvisionedBy(t *testing.T) {
 	}
 }
 
+const testGraphNodeModuleExpandCountStr = `
+module.child.0 (expanded)
+module.child.1 (expanded)
+`
+
 const testGraphNodeModuleExpandStr = `
 aws_instance.bar
   aws_instance.foo
@@ -1,11 +1,6 @@
 package terraform
 
 import (
-	"bufio"
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/hashicorp/terraform/dag"
 )
 
@@ -19,53 +14,19 @@ type GraphNodeDotter interface {
 }
 
 // GraphDotOpts are the options for generating a dot formatted Graph.
-type GraphDotOpts struct{}
+type GraphDotOpts struct {
+	// ModuleDepth is how many levels of modules are drawn as clusters
+	// of their resources. Zero draws each module as a single node, and
+	// a negative value draws every level.
+	ModuleDepth int
+}
 
 // GraphDot returns the dot formatting of a visual representation of
 // the given Terraform graph.
 func GraphDot(g *Graph, opts *GraphDotOpts) string {
-	buf := new(bytes.Buffer)
-
-	// Start the graph
-	buf.WriteString("digraph {\n")
-	buf.WriteString("\tcompound = true;\n")
-
-	// Go through all the vertices and draw it
-	vertices := g.Vertices()
-	dotVertices := make(map[dag.Vertex]struct{}, len(vertices))
-	for _, v := range vertices {
-		if dn, ok := v.(GraphNodeDotter); !ok {
-			continue
-		} else if dn.Dot("fake") == "" {
-			continue
-		}
-
-		dotVertices[v] = struct{}{}
-	}
-
-	for v, _ := range dotVertices {
-		dn := v.(GraphNodeDotter)
-		scanner := bufio.NewScanner(strings.NewReader(
-			dn.Dot(dag.VertexName(v))))
-		for scanner.Scan() {
-			buf.WriteString("\t" + scanner.Text() + "\n")
-		}
-
-		// Draw all the edges
-		for _, t := range g.DownEdges(v).List() {
-			target := t.(dag.Vertex)
-			if _, ok := dotVertices[target]; !ok {
-				continue
-			}
-
-			buf.WriteString(fmt.Sprintf(
-				"\t\"%s\" -> \"%s\";\n",
-				dag.VertexName(v),
-				dag.VertexName(target)))
-		}
+	if opts == nil {
+		opts = new(GraphDotOpts)
 	}
 
-	// End the graph
-	buf.WriteString("}\n")
-	return buf.String()
+	return string(g.Dot(&dag.DotOpts{MaxDepth: opts.ModuleDepth}))
 }
@@ -1,8 +1,13 @@
 package terraform
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/dag"
 )
 
 func TestGraphAdd(t *testing.T) {
@@ -20,7 +25,7 @@ func TestGraphAdd(t *testing.T) {
 
 func TestGraphConnectDependent(t *testing.T) {
 	var g Graph
-	g.Add(&testGraphDependable{VertexName: "a", Mock: []string{"a"}})
+	a := g.Add(&testGraphDependable{VertexName: "a", Mock: []string{"a"}})
 	b := g.Add(&testGraphDependable{
 		VertexName:      "b",
 		DependentOnMock: []string{"a"},
@@ -35,6 +40,61 @@ func TestGraphConnectDependent(t *testing.T) {
 	if actual != expected {
 		t.Fatalf("bad: %s", actual)
 	}
+
+	if label := dag.EdgeLabel(g.Edge(b, a)); label != "a" {
+		t.Fatalf("bad: %#v", label)
+	}
+}
+
+func TestGraphWalk_parallelism(t *testing.T) {
+	var g Graph
+	for i := 0; i < 10; i++ {
+		g.Add(i)
+	}
+
+	w := &testGraphParallelismWalker{Parallelism: 2}
+	if err := g.Walk(w); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if w.max != 2 {
+		t.Fatalf("bad: %d", w.max)
+	}
+}
+
+func TestGraphWalk_contextParallelism(t *testing.T) {
+	var running, max int
+	var lock sync.Mutex
+	eval := &testGraphParallelismEval{
+		Running: &running,
+		Max:     &max,
+		Lock:    &lock,
+	}
+
+	// The parallelism is shared with the walk of the module, rather than
+	// each walk having a limit of its own.
+	sub := &Graph{Path: []string{"root", "child"}}
+	for i := 0; i < 5; i++ {
+		sub.Add(&testGraphParallelismVertex{ID: i, Eval: eval})
+	}
+
+	g := &Graph{Path: rootModulePath}
+	for i := 0; i < 5; i++ {
+		g.Add(&testGraphParallelismVertex{ID: i, Eval: eval})
+	}
+	g.Add(&testGraphParallelismModule{Eval: eval, SubgraphValue: sub})
+
+	w := &ContextGraphWalker{
+		Context:   NewContext(&ContextOpts{Parallelism: 2}),
+		Operation: walkApply,
+	}
+	if err := g.Walk(w); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if max != 2 {
+		t.Fatalf("bad: %d", max)
+	}
 }
 
 type testGraphDependable struct {
@@ -55,6 +115,99 @@ func (v *testGraphDependable) DependentOn() []string {
 	return v.DependentOnMock
 }
 
+// testGraphParallelismWalker is a GraphWalker that records the most
+// vertices that were walked at the same time.
+type testGraphParallelismWalker struct {
+	NullGraphWalker
+	Parallelism int
+
+	lock    sync.Mutex
+	running int
+	max     int
+}
+
+func (w *testGraphParallelismWalker) EnterGraph(*Graph) EvalContext {
+	return &MockEvalContext{PathPath: rootModulePath}
+}
+
+func (w *testGraphParallelismWalker) EnterVertex(dag.Vertex) {
+	w.lock.Lock()
+	w.running++
+	if w.running > w.max {
+		w.max = w.running
+	}
+	w.lock.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+}
+
+func (w *testGraphParallelismWalker) ExitVertex(dag.Vertex, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.running--
+}
+
+func (w *testGraphParallelismWalker) WalkOpts(*Graph) *dag.WalkOpts {
+	return &dag.WalkOpts{Parallelism: w.Parallelism}
+}
+
+// testGraphParallelismVertex is a vertex that evaluates Eval.
+type testGraphParallelismVertex struct {
+	ID   int
+	Eval EvalNode
+}
+
+func (v *testGraphParallelismVertex) Name() string {
+	return fmt.Sprintf("vertex.%d", v.ID)
+}
+
+func (v *testGraphParallelismVertex) EvalTree() EvalNode {
+	return v.Eval
+}
+
+// testGraphParallelismModule is a vertex that evaluates Eval and then
+// walks its subgraph, like a module.
+type testGraphParallelismModule struct {
+	Eval          EvalNode
+	SubgraphValue *Graph
+}
+
+func (v *testGraphParallelismModule) Name() string {
+	return "module.child"
+}
+
+func (v *testGraphParallelismModule) EvalTree() EvalNode {
+	return v.Eval
+}
+
+func (v *testGraphParallelismModule) Subgraph() *Graph {
+	return v.SubgraphValue
+}
+
+// testGraphParallelismEval is an EvalNode that records the most
+// evaluations that ran at the same time.
+type testGraphParallelismEval struct {
+	Running *int
+	Max     *int
+	Lock    *sync.Mutex
+}
+
+func (n *testGraphParallelismEval) Eval(EvalContext) (interface{}, error) {
+	n.Lock.Lock()
+	*n.Running++
+	if *n.Running > *n.Max {
+		*n.Max = *n.Running
+	}
+	n.Lock.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	n.Lock.Lock()
+	defer n.Lock.Unlock()
+	*n.Running--
+	return nil, nil
+}
+
 const testGraphAddStr = `
 42
 84
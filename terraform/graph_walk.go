@@ -13,6 +13,7 @@ type GraphWalker interface {
 	ExitVertex(dag.Vertex, error)
 	EnterEvalTree(dag.Vertex, EvalNode) EvalNode
 	ExitEvalTree(dag.Vertex, interface{}, error) error
+	WalkOpts(*Graph) *dag.WalkOpts
 }
 
 // NullGraphWalker is a GraphWalker implementation that does nothing.
@@ -28,3 +29,4 @@ func (NullGraphWalker) EnterEvalTree(v dag.Vertex, n EvalNode) EvalNode { return
 func (NullGraphWalker) ExitEvalTree(dag.Vertex, interface{}, error) error {
 	return nil
 }
+func (NullGraphWalker) WalkOpts(*Graph) *dag.WalkOpts { return nil }
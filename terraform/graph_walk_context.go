@@ -2,7 +2,10 @@ package terraform
 
 import (
 	"fmt"
+	"log"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform/dag"
@@ -16,6 +19,7 @@ type ContextGraphWalker struct {
 	// Configurable values
 	Context   *Context
 	Operation walkOperation
+	StopCh    <-chan struct{}
 
 	// Outputs, do not set these. Do not read these while the graph
 	// is being walked.
@@ -83,8 +87,21 @@ func (w *ContextGraphWalker) EnterGraph(g *Graph) EvalContext {
 	return ctx
 }
 
+func (w *ContextGraphWalker) WalkOpts(g *Graph) *dag.WalkOpts {
+	// The walk itself isn't limited. The parallelism of the context is
+	// enforced by the semaphore in EnterEvalTree instead, which is shared
+	// by the walks of all modules and expanded subgraphs. When the
+	// context is stopped, nothing new is started.
+	return &dag.WalkOpts{
+		StopCh: w.StopCh,
+		Hook:   &graphWalkLogHook{Path: strings.Join(g.Path, ".")},
+	}
+}
+
 func (w *ContextGraphWalker) EnterEvalTree(v dag.Vertex, n EvalNode) EvalNode {
-	// Acquire a lock on the semaphore
+	// Acquire a lock on the semaphore. It is released in ExitEvalTree,
+	// before any subgraph of the vertex is walked, so a vertex never
+	// holds it while waiting on the vertices of a subgraph.
 	w.Context.parallelSem.Acquire()
 
 	// We want to filter the evaluation tree to only include operations
@@ -132,3 +149,39 @@ func (w *ContextGraphWalker) init() {
 	w.providerConfigCache = make(map[string]*ResourceConfig, 5)
 	w.provisionerCache = make(map[string]ResourceProvisioner, 5)
 }
+
+// graphWalkLogHook is a dag.WalkHook that logs how long each vertex took
+// and which vertices were skipped because a dependency failed.
+type graphWalkLogHook struct {
+	Path string
+
+	lock  sync.Mutex
+	start map[dag.Vertex]time.Time
+}
+
+func (h *graphWalkLogHook) VertexStarted(v dag.Vertex) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.start == nil {
+		h.start = make(map[dag.Vertex]time.Time)
+	}
+	h.start[v] = time.Now()
+}
+
+func (h *graphWalkLogHook) VertexFinished(v dag.Vertex, err error) {
+	h.lock.Lock()
+	start := h.start[v]
+	delete(h.start, v)
+	h.lock.Unlock()
+
+	log.Printf(
+		"[DEBUG] vertex %s.%s: done in %s",
+		h.Path, dag.VertexName(v), time.Since(start))
+}
+
+func (h *graphWalkLogHook) VertexSkipped(v dag.Vertex) {
+	log.Printf(
+		"[DEBUG] vertex %s.%s: skipped, a dependency failed or the walk stopped",
+		h.Path, dag.VertexName(v))
+}
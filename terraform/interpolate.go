@@ -3,6 +3,7 @@ package terraform
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -41,12 +42,14 @@ func (i *Interpolater) Values(
 		if len(scope.Path) > 1 {
 			mod = i.Module.Child(scope.Path[1:])
 		}
-		for _, v := range mod.Config().Variables {
-			for k, val := range v.DefaultsMap() {
-				result[k] = ast.Variable{
-					Value: val,
-					Type:  ast.TypeString,
-				}
+		defaults, err := mod.Config().VariableDefaults(i.Variables)
+		if err != nil {
+			return nil, err
+		}
+		for k, val := range defaults {
+			result[k] = ast.Variable{
+				Value: val,
+				Type:  ast.TypeString,
 			}
 		}
 	}
@@ -56,6 +59,11 @@ func (i *Interpolater) Values(
 		switch v := rawV.(type) {
 		case *config.CountVariable:
 			err = i.valueCountVar(scope, n, v, result)
+		case *config.EachVariable:
+			// These are set while expanding dynamic blocks
+			continue
+		case *config.LocalVariable:
+			err = i.valueLocalVar(scope, n, v, result)
 		case *config.ModuleVariable:
 			err = i.valueModuleVar(scope, n, v, result)
 		case *config.PathVariable:
@@ -95,6 +103,88 @@ func (i *Interpolater) valueCountVar(
 	}
 }
 
+func (i *Interpolater) valueLocalVar(
+	scope *InterpolationScope,
+	n string,
+	v *config.LocalVariable,
+	result map[string]ast.Variable) error {
+	mod := i.Module
+	if len(scope.Path) > 1 {
+		mod = i.Module.Child(scope.Path[1:])
+	}
+
+	var local *config.Local
+	for _, l := range mod.Config().Locals {
+		if l.Name == v.Name {
+			local = l
+			break
+		}
+	}
+	if local == nil {
+		return fmt.Errorf("%s: unknown local %s", n, v.Name)
+	}
+
+	// Locals are evaluated every time they're referenced. The graph
+	// makes sure that everything the local references is available
+	// by now. We interpolate a fresh RawConfig so that concurrent
+	// references to the same local don't step on each other.
+	vs, err := i.Values(scope, local.RawConfig.Variables)
+	if err != nil {
+		return err
+	}
+	rc, err := config.NewRawConfig(local.RawConfig.Raw)
+	if err != nil {
+		return err
+	}
+	if err := rc.Interpolate(vs); err != nil {
+		return fmt.Errorf("%s: %s", n, err)
+	}
+
+	value, ok := rc.Config()["value"]
+	if !ok {
+		// The value was removed because it is computed or null
+		if len(rc.UnknownKeys()) == 0 {
+			result[n] = ast.Variable{Type: ast.TypeNull}
+			return nil
+		}
+
+		result[n] = ast.Variable{
+			Value: config.UnknownVariableValue,
+			Type:  ast.TypeString,
+		}
+		return nil
+	}
+
+	switch val := value.(type) {
+	case string:
+		result[n] = ast.Variable{
+			Value: val,
+			Type:  ast.TypeString,
+		}
+	case []interface{}:
+		list := make([]ast.Variable, len(val))
+		for idx, elem := range val {
+			s, ok := elem.(string)
+			if !ok {
+				return fmt.Errorf(
+					"%s: list elements must be strings", n)
+			}
+
+			list[idx] = ast.Variable{Value: s, Type: ast.TypeString}
+		}
+
+		result[n] = ast.Variable{
+			Value: list,
+			Type:  ast.TypeList,
+		}
+	default:
+		return fmt.Errorf(
+			"%s: local must be a string or a list, got %T", n, value)
+	}
+
+	return nil
+}
+
 func (i *Interpolater) valueModuleVar(
 	scope *InterpolationScope,
 	n string,
@@ -102,7 +192,7 @@ func (i *Interpolater) valueModuleVar(
 	result map[string]ast.Variable) error {
 	// If we're computing all dynamic fields, then module vars count
 	// and we mark it as computed.
-	if i.Operation == walkValidate {
+	if i.Operation == walkValidate || i.Operation == walkInput {
 		result[n] = ast.Variable{
 			Value: config.UnknownVariableValue,
 			Type:  ast.TypeString,
@@ -110,18 +200,104 @@ func (i *Interpolater) valueModuleVar(
 		return nil
 	}
 
-	// Build the path to the child module we want
-	path := make([]string, len(scope.Path), len(scope.Path)+1)
-	copy(path, scope.Path)
-	path = append(path, v.Name)
+	// All the instances of a module with a count are a list
+	if v.Multi && v.Index == -1 {
+		values, err := i.computeModuleMultiVariable(scope, v)
+		if err != nil {
+			return err
+		}
+
+		list := make([]ast.Variable, len(values))
+		for idx, value := range values {
+			list[idx] = ast.Variable{
+				Value: value,
+				Type:  ast.TypeString,
+			}
+		}
+
+		result[n] = ast.Variable{
+			Value: list,
+			Type:  ast.TypeList,
+		}
+		return nil
+	}
+
+	// Instances of a module with a count have the index in their name
+	name := v.Name
+	if v.Multi {
+		name = fmt.Sprintf("%s.%d", v.Name, v.Index)
+	}
 
 	// Grab the lock so that if other interpolations are running or
 	// state is being modified, we'll be safe.
 	i.StateLock.RLock()
 	defer i.StateLock.RUnlock()
 
+	result[n] = ast.Variable{
+		Value: i.moduleOutput(scope, name, v.Field),
+		Type:  ast.TypeString,
+	}
+	return nil
+}
+
+func (i *Interpolater) computeModuleMultiVariable(
+	scope *InterpolationScope,
+	v *config.ModuleVariable) ([]string, error) {
+	// Get the module from the configuration so we know its count
+	modTree := i.Module
+	if len(scope.Path) > 1 {
+		modTree = i.Module.Child(scope.Path[1:])
+	}
+
+	var m *config.Module
+	for _, cm := range modTree.Config().Modules {
+		if cm.Name == v.Name {
+			m = cm
+			break
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf(
+			"Module '%s' not found for variable '%s'",
+			v.Name,
+			v.FullKey())
+	}
+
+	// The count is computed the same way as it is when the graph is
+	// built, so we have exactly the instances that are in the graph.
+	t := &ConfigTransformer{Module: i.Module, Variables: i.Variables}
+	count, err := t.moduleCount(scope.Path, m)
+	if err != nil {
+		return nil, err
+	}
+
+	i.StateLock.RLock()
+	defer i.StateLock.RUnlock()
+
+	// A module without a count is a single instance without an index
+	if m.RawCount == nil {
+		return []string{i.moduleOutput(scope, v.Name, v.Field)}, nil
+	}
+
+	values := make([]string, count)
+	for idx := 0; idx < count; idx++ {
+		values[idx] = i.moduleOutput(
+			scope, fmt.Sprintf("%s.%d", v.Name, idx), v.Field)
+	}
+
+	return values, nil
+}
+
+// moduleOutput returns the value of an output of the child module with
+// the given name in the state. The state lock must be held.
+func (i *Interpolater) moduleOutput(
+	scope *InterpolationScope, name, field string) string {
+	// Build the path to the child module we want
+	path := make([]string, len(scope.Path), len(scope.Path)+1)
+	copy(path, scope.Path)
+	path = append(path, name)
+
 	// Get the module where we're looking for the value
-	var value string
 	mod := i.State.ModuleByPath(path)
 	if mod == nil {
 		// If the module doesn't exist, then we can return an empty string.
@@ -130,22 +306,17 @@ func (i *Interpolater) valueModuleVar(
 		// modules reference other modules, and graph ordering should
 		// ensure that the module is in the state, so if we reach this
 		// point otherwise it really is a panic.
-		value = config.UnknownVariableValue
-	} else {
-		// Get the value from the outputs
-		var ok bool
-		value, ok = mod.Outputs[v.Field]
-		if !ok {
-			// Same reasons as the comment above.
-			value = config.UnknownVariableValue
-		}
+		return config.UnknownVariableValue
 	}
 
-	result[n] = ast.Variable{
-		Value: value,
-		Type:  ast.TypeString,
+	// Get the value from the outputs
+	value, ok := mod.Outputs[field]
+	if !ok {
+		// Same reasons as the comment above.
+		return config.UnknownVariableValue
 	}
-	return nil
+
+	return value
 }
 
 func (i *Interpolater) valuePathVar(
@@ -193,7 +364,8 @@ func (i *Interpolater) valueResourceVar(
 	result map[string]ast.Variable) error {
 	// If we're computing all dynamic fields, then module vars count
 	// and we mark it as computed.
-	if i.Operation == walkValidate || i.Operation == walkRefresh {
+	if i.Operation == walkValidate || i.Operation == walkRefresh ||
+		i.Operation == walkInput {
 		result[n] = ast.Variable{
 			Value: config.UnknownVariableValue,
 			Type:  ast.TypeString,
@@ -201,13 +373,32 @@ func (i *Interpolater) valueResourceVar(
 		return nil
 	}
 
-	var attr string
-	var err error
+	if v.AllAttributes() {
+		return i.valueResourceAttributesVar(scope, n, v, result)
+	}
+
 	if v.Multi && v.Index == -1 {
-		attr, err = i.computeResourceMultiVariable(scope, v)
-	} else {
-		attr, err = i.computeResourceVariable(scope, v)
+		values, err := i.computeResourceMultiVariable(scope, v)
+		if err != nil {
+			return err
+		}
+
+		list := make([]ast.Variable, len(values))
+		for idx, value := range values {
+			list[idx] = ast.Variable{
+				Value: value,
+				Type:  ast.TypeString,
+			}
+		}
+
+		result[n] = ast.Variable{
+			Value: list,
+			Type:  ast.TypeList,
+		}
+		return nil
 	}
+
+	attr, err := i.computeResourceVariable(scope, v)
 	if err != nil {
 		return err
 	}
@@ -219,6 +410,49 @@ func (i *Interpolater) valueResourceVar(
 	return nil
 }
 
+// valueResourceAttributesVar sets the value of a variable referencing
+// every attribute of a resource, such as "aws_instance.foo.*". The value
+// is a list of the flattened attributes as "key=value", sorted by key.
+// If any of the attributes aren't known yet, the whole value is unknown.
+func (i *Interpolater) valueResourceAttributesVar(
+	scope *InterpolationScope,
+	n string,
+	v *config.ResourceVariable,
+	result map[string]ast.Variable) error {
+	attrs, err := i.computeResourceAttributes(scope, v)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k, attr := range attrs {
+		if attr == config.UnknownVariableValue {
+			result[n] = ast.Variable{
+				Value: config.UnknownVariableValue,
+				Type:  ast.TypeString,
+			}
+			return nil
+		}
+
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]ast.Variable, len(keys))
+	for idx, k := range keys {
+		list[idx] = ast.Variable{
+			Value: fmt.Sprintf("%s=%s", k, attrs[k]),
+			Type:  ast.TypeString,
+		}
+	}
+
+	result[n] = ast.Variable{
+		Value: list,
+		Type:  ast.TypeList,
+	}
+	return nil
+}
+
 func (i *Interpolater) valueSelfVar(
 	scope *InterpolationScope,
 	n string,
@@ -251,7 +485,8 @@ func (i *Interpolater) valueUserVar(
 		return nil
 	}
 
-	if _, ok := result[n]; !ok && i.Operation == walkValidate {
+	if _, ok := result[n]; !ok &&
+		(i.Operation == walkValidate || i.Operation == walkInput) {
 		result[n] = ast.Variable{
 			Value: config.UnknownVariableValue,
 			Type:  ast.TypeString,
@@ -361,9 +596,48 @@ MISSING:
 		v.FullKey())
 }
 
+func (i *Interpolater) computeResourceAttributes(
+	scope *InterpolationScope,
+	v *config.ResourceVariable) (map[string]string, error) {
+	id := v.ResourceId()
+	if v.Multi {
+		id = fmt.Sprintf("%s.%d", id, v.Index)
+	}
+
+	i.StateLock.RLock()
+	defer i.StateLock.RUnlock()
+
+	module, _, err := i.resourceVariableInfo(scope, v)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we have no module in the state yet or count, return empty
+	if module == nil || len(module.Resources) == 0 {
+		return nil, nil
+	}
+
+	r, ok := module.Resources[id]
+	if !ok && v.Multi && v.Index == 0 {
+		r, ok = module.Resources[v.ResourceId()]
+	}
+	if !ok || r == nil {
+		return nil, fmt.Errorf(
+			"Resource '%s' not found for variable '%s'",
+			id,
+			v.FullKey())
+	}
+
+	if r.Primary == nil {
+		return nil, nil
+	}
+
+	return r.Primary.Attributes, nil
+}
+
 func (i *Interpolater) computeResourceMultiVariable(
 	scope *InterpolationScope,
-	v *config.ResourceVariable) (string, error) {
+	v *config.ResourceVariable) ([]string, error) {
 	i.StateLock.RLock()
 	defer i.StateLock.RUnlock()
 
@@ -371,13 +645,13 @@ func (i *Interpolater) computeResourceMultiVariable(
 	// that it exists and such.
 	module, cr, err := i.resourceVariableInfo(scope, v)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Get the count so we know how many to iterate over
 	count, err := cr.Count()
 	if err != nil {
-		return "", fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Error reading %s count: %s",
 			v.ResourceId(),
 			err)
@@ -385,7 +659,7 @@ func (i *Interpolater) computeResourceMultiVariable(
 
 	// If we have no module in the state yet or count, return empty
 	if module == nil || len(module.Resources) == 0 || count == 0 {
-		return "", nil
+		return nil, nil
 	}
 
 	var values []string
@@ -416,7 +690,7 @@ func (i *Interpolater) computeResourceMultiVariable(
 	}
 
 	if len(values) == 0 {
-		return "", fmt.Errorf(
+		return nil, fmt.Errorf(
 			"Resource '%s' does not have attribute '%s' "+
 				"for variable '%s'",
 			v.ResourceId(),
@@ -424,7 +698,7 @@ func (i *Interpolater) computeResourceMultiVariable(
 			v.FullKey())
 	}
 
-	return strings.Join(values, config.InterpSplitDelim), nil
+	return values, nil
 }
 
 func (i *Interpolater) resourceVariableInfo(
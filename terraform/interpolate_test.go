@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/config/lang/ast"
+	"github.com/hashicorp/terraform/config/module"
 )
 
 func TestInterpolater_countIndex(t *testing.T) {
@@ -24,6 +25,21 @@ func TestInterpolater_countIndex(t *testing.T) {
 	})
 }
 
+func TestInterpolater_local(t *testing.T) {
+	i := &Interpolater{
+		Module: testModule(t, "interpolate-local"),
+	}
+
+	scope := &InterpolationScope{
+		Path: rootModulePath,
+	}
+
+	testInterpolate(t, i, scope, "local.full", ast.Variable{
+		Value: "web-prod-1",
+		Type:  ast.TypeString,
+	})
+}
+
 func TestInterpolater_moduleVariable(t *testing.T) {
 	lock := new(sync.RWMutex)
 	state := &State{
@@ -63,6 +79,179 @@ func TestInterpolater_moduleVariable(t *testing.T) {
 	})
 }
 
+func TestInterpolater_moduleMultiVar(t *testing.T) {
+	lock := new(sync.RWMutex)
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+			},
+			&ModuleState{
+				Path: []string{RootModuleName, "child.0"},
+				Outputs: map[string]string{
+					"name": "foo",
+				},
+			},
+			&ModuleState{
+				Path: []string{RootModuleName, "child.1"},
+				Outputs: map[string]string{
+					"name": "bar",
+				},
+			},
+			&ModuleState{
+				Path: []string{RootModuleName, "child.2"},
+				Outputs: map[string]string{
+					"name": "orphan",
+				},
+			},
+		},
+	}
+
+	i := &Interpolater{
+		Module:    testModule(t, "interpolate-module-multi-var"),
+		State:     state,
+		StateLock: lock,
+	}
+
+	scope := &InterpolationScope{
+		Path: rootModulePath,
+	}
+
+	testInterpolate(t, i, scope, "module.child.*.name", ast.Variable{
+		Value: []ast.Variable{
+			ast.Variable{
+				Value: "foo",
+				Type:  ast.TypeString,
+			},
+			ast.Variable{
+				Value: "bar",
+				Type:  ast.TypeString,
+			},
+		},
+		Type: ast.TypeList,
+	})
+
+	testInterpolate(t, i, scope, "module.child.1.name", ast.Variable{
+		Value: "bar",
+		Type:  ast.TypeString,
+	})
+}
+
+func TestInterpolater_multiVar(t *testing.T) {
+	lock := new(sync.RWMutex)
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.web.0": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "bar",
+							Attributes: map[string]string{
+								"name": "foo,bar",
+							},
+						},
+					},
+					"aws_instance.web.1": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "baz",
+							Attributes: map[string]string{
+								"name": "baz",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	i := &Interpolater{
+		Module:    testModule(t, "interpolate-multi-var"),
+		State:     state,
+		StateLock: lock,
+	}
+
+	scope := &InterpolationScope{
+		Path: rootModulePath,
+	}
+
+	testInterpolate(t, i, scope, "aws_instance.web.*.name", ast.Variable{
+		Value: []ast.Variable{
+			ast.Variable{
+				Value: "foo,bar",
+				Type:  ast.TypeString,
+			},
+			ast.Variable{
+				Value: "baz",
+				Type:  ast.TypeString,
+			},
+		},
+		Type: ast.TypeList,
+	})
+}
+
+func TestInterpolater_resourceAllAttributes(t *testing.T) {
+	lock := new(sync.RWMutex)
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: rootModulePath,
+				Resources: map[string]*ResourceState{
+					"aws_instance.web": &ResourceState{
+						Type: "aws_instance",
+						Primary: &InstanceState{
+							ID: "bar",
+							Attributes: map[string]string{
+								"id":       "bar",
+								"tags.#":   "1",
+								"tags.foo": "baz",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	i := &Interpolater{
+		Module: module.NewTree("", &config.Config{
+			Resources: []*config.Resource{
+				&config.Resource{
+					Name: "web",
+					Type: "aws_instance",
+				},
+			},
+		}),
+		State:     state,
+		StateLock: lock,
+	}
+
+	scope := &InterpolationScope{
+		Path: rootModulePath,
+	}
+
+	expected := ast.Variable{
+		Value: []ast.Variable{
+			ast.Variable{Value: "id=bar", Type: ast.TypeString},
+			ast.Variable{Value: "tags.#=1", Type: ast.TypeString},
+			ast.Variable{Value: "tags.foo=baz", Type: ast.TypeString},
+		},
+		Type: ast.TypeList,
+	}
+	testInterpolate(t, i, scope, "aws_instance.web.*", expected)
+	testInterpolate(t, i, scope, "aws_instance.web.0.*", expected)
+
+	// Unknown attributes make the whole value unknown
+	state.RootModule().Resources["aws_instance.web"].Primary.Attributes["foo"] =
+		config.UnknownVariableValue
+	testInterpolate(t, i, scope, "aws_instance.web.*", ast.Variable{
+		Value: config.UnknownVariableValue,
+		Type:  ast.TypeString,
+	})
+}
+
 func TestInterpolater_pathCwd(t *testing.T) {
 	i := &Interpolater{}
 	scope := &InterpolationScope{}
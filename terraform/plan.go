@@ -30,10 +30,16 @@ type PlanOpts struct {
 // Plan represents a single Terraform execution plan, which contains
 // all the information necessary to make an infrastructure change.
 type Plan struct {
-	Diff   *Diff
-	Module *module.Tree
-	State  *State
-	Vars   map[string]string
+	Diff    *Diff
+	Module  *module.Tree
+	State   *State
+	Vars    map[string]string
+	Targets []string
+
+	// Destroy is true if this is a plan to destroy resources. The graph
+	// of a destroy keeps what depends on the targets rather than what
+	// they depend on, so the apply must know this too.
+	Destroy bool
 
 	once sync.Once
 }
@@ -41,11 +47,13 @@ type Plan struct {
 // Context returns a Context with the data encapsulated in this plan.
 //
 // The following fields in opts are overridden by the plan: Config,
-// Diff, State, Variables.
+// Destroy, Diff, State, Targets, Variables.
 func (p *Plan) Context(opts *ContextOpts) *Context {
+	opts.Destroy = p.Destroy
 	opts.Diff = p.Diff
 	opts.Module = p.Module
 	opts.State = p.State
+	opts.Targets = p.Targets
 	opts.Variables = p.Vars
 	return NewContext(opts)
 }
@@ -90,6 +90,7 @@ func (i *InstanceInfo) HumanId() string {
 // methods can be added to it to make dealing with it easier.
 type ResourceConfig struct {
 	ComputedKeys []string
+	NullKeys     []string
 	Raw          map[string]interface{}
 	Config       map[string]interface{}
 
@@ -130,12 +131,22 @@ func (c *ResourceConfig) Get(k string) (interface{}, bool) {
 		return result, ok
 	}
 
+	// Null values aren't set at all, so don't fall back to the raw
+	// interpolation for them.
+	if c.isNull(k) {
+		return nil, false
+	}
+
 	// Otherwise, just get it from the raw config
 	return c.get(k, c.Raw)
 }
 
 // IsComputed returns whether the given key is computed or not.
 func (c *ResourceConfig) IsComputed(k string) bool {
+	if c.isNull(k) {
+		return false
+	}
+
 	_, ok := c.get(k, c.Config)
 	_, okRaw := c.get(k, c.Raw)
 	return !ok && okRaw
@@ -165,6 +176,34 @@ func (c *ResourceConfig) IsSet(k string) bool {
 	return false
 }
 
+// isNull returns whether the given key, or one of its parents, was
+// removed from the configuration because it was null. The null keys
+// don't contain list indexes, so those are ignored.
+func (c *ResourceConfig) isNull(k string) bool {
+	parts := strings.Split(k, ".")
+	key := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "#" {
+			continue
+		}
+		if _, err := strconv.ParseInt(part, 0, 0); err == nil {
+			continue
+		}
+
+		key = append(key, part)
+	}
+
+	for _, nk := range c.NullKeys {
+		for i := 1; i <= len(key); i++ {
+			if strings.Join(key[:i], ".") == nk {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (c *ResourceConfig) get(
 	k string, raw map[string]interface{}) (interface{}, bool) {
 	parts := strings.Split(k, ".")
@@ -220,6 +259,7 @@ func (c *ResourceConfig) interpolateForce() {
 	}
 
 	c.ComputedKeys = c.raw.UnknownKeys()
+	c.NullKeys = c.raw.NullKeys()
 	c.Raw = c.raw.Raw
 	c.Config = c.raw.Config()
 }
@@ -69,6 +69,35 @@ type ResourceProvider interface {
 	// Refresh refreshes a resource and updates all of its attributes
 	// with the latest information.
 	Refresh(*InstanceInfo, *InstanceState) (*InstanceState, error)
+
+	// ImportState requests that the resource with the given ID be
+	// imported. The InstanceInfo only has the type of the resource.
+	//
+	// Importing may return the state of multiple resources, such as a
+	// security group along with each of its rules. The type of any
+	// resource that differs from the requested one is set in
+	// InstanceState.Ephemeral.Type.
+	ImportState(*InstanceInfo, string) ([]*InstanceState, error)
+
+	// ValidateDataSource is called once at the beginning with the raw
+	// configuration (no interpolation done) of a data source and can
+	// return a list of warnings and/or errors, the same as
+	// ValidateResource does for resources.
+	ValidateDataSource(string, *ResourceConfig) ([]string, []error)
+
+	// DataSources returns all the available data source types that this
+	// provider knows how to read.
+	DataSources() []DataSource
+
+	// ReadDataDiff returns the diff of reading a data source with the
+	// given configuration. The state of a data source is always read
+	// from scratch, so there is no prior state to diff against.
+	ReadDataDiff(*InstanceInfo, *ResourceConfig) (*InstanceDiff, error)
+
+	// ReadDataApply reads the data source and returns its state. The diff
+	// is the one returned by ReadDataDiff, with the configuration fully
+	// known.
+	ReadDataApply(*InstanceInfo, *InstanceDiff) (*InstanceState, error)
 }
 
 // ResourceType is a type of resource that a resource provider can manage.
@@ -76,6 +105,11 @@ type ResourceType struct {
 	Name string
 }
 
+// DataSource is a type of data source that a resource provider can read.
+type DataSource struct {
+	Name string
+}
+
 // ResourceProviderFactory is a function type that creates a new instance
 // of a resource provider.
 type ResourceProviderFactory func() (ResourceProvider, error)
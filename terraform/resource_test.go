@@ -88,6 +88,15 @@ func TestResourceConfigGet(t *testing.T) {
 			Key:   "foo.5",
 			Value: nil,
 		},
+
+		{
+			Config: map[string]interface{}{
+				"foo": `${var.foo == "" ? null : var.foo}`,
+			},
+			Vars:  map[string]string{"foo": ""},
+			Key:   "foo",
+			Value: nil,
+		},
 	}
 
 	for i, tc := range cases {
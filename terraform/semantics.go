@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/config"
@@ -85,6 +86,12 @@ func smcUserVariables(c *config.Config, vs map[string]string) []error {
 		}
 	}
 	for k, _ := range vs {
+		// Setting an element of a map, such as an attribute of an
+		// object, also sets the map.
+		if idx := strings.Index(k, "."); idx >= 0 {
+			delete(required, k[:idx])
+		}
+
 		delete(required, k)
 	}
 	if len(required) > 0 {
@@ -112,3 +119,62 @@ func smcUserVariables(c *config.Config, vs map[string]string) []error {
 
 	return errs
 }
+
+// smcVariableValidations checks the given variable values, or the
+// defaults of the variables that aren't set, against the validation
+// rules and object types of the variables in the configuration.
+func smcVariableValidations(c *config.Config, vs map[string]string) []error {
+	defaults, err := c.VariableDefaults(vs)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, v := range c.Variables {
+		if len(v.Attributes) > 0 {
+			errs = append(errs, v.ValidateAttributes(
+				variableAttributeValues(v, vs))...)
+			continue
+		}
+
+		if len(v.Validations) == 0 || v.Type() != config.VariableTypeString {
+			continue
+		}
+
+		value, ok := vs[v.Name]
+		if !ok {
+			if v.Default == nil {
+				// Required variables that aren't set are checked elsewhere
+				continue
+			}
+
+			value = defaults["var."+v.Name]
+		}
+
+		errs = append(errs, v.ValidateValue(value)...)
+	}
+
+	return errs
+}
+
+// variableAttributeValues returns the values of the attributes of a
+// variable with an object type: the default, overridden by any values
+// set for its elements.
+func variableAttributeValues(
+	v *config.Variable, vs map[string]string) map[string]string {
+	result := make(map[string]string)
+	if m, ok := v.Default.(map[string]string); ok {
+		for k, val := range m {
+			result[k] = val
+		}
+	}
+
+	prefix := v.Name + "."
+	for k, val := range vs {
+		if strings.HasPrefix(k, prefix) {
+			result[k[len(prefix):]] = val
+		}
+	}
+
+	return result
+}
@@ -2,6 +2,8 @@ package terraform
 
 import (
 	"testing"
+
+	"github.com/hashicorp/terraform/config"
 )
 
 func TestSMCUserVariables(t *testing.T) {
@@ -38,3 +40,91 @@ func TestSMCUserVariables(t *testing.T) {
 	}
 
 }
+
+func TestSMCVariableValidations(t *testing.T) {
+	rc, err := config.NewRawConfig(map[string]interface{}{
+		"condition": `${var.foo != "bad"}`,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	c := &config.Config{
+		Variables: []*config.Variable{
+			&config.Variable{
+				Name:    "foo",
+				Default: "good",
+				Validations: []*config.VariableValidation{
+					&config.VariableValidation{
+						RawConfig:    rc,
+						ErrorMessage: "foo can't be bad",
+					},
+				},
+			},
+		},
+	}
+
+	// Default value
+	errs := smcVariableValidations(c, nil)
+	if len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+
+	// Value set
+	errs = smcVariableValidations(c, map[string]string{"foo": "bad"})
+	if len(errs) == 0 {
+		t.Fatal("should have errors")
+	}
+
+	// Value not known yet
+	errs = smcVariableValidations(c, map[string]string{
+		"foo": config.UnknownVariableValue,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+}
+
+func TestSMCVariableValidations_object(t *testing.T) {
+	c := &config.Config{
+		Variables: []*config.Variable{
+			&config.Variable{
+				Name: "subnet",
+				Attributes: []*config.VariableAttribute{
+					&config.VariableAttribute{
+						Name: "cidr_block",
+						Type: config.VariableAttributeTypeString,
+					},
+					&config.VariableAttribute{
+						Name: "public",
+						Type: config.VariableAttributeTypeBool,
+					},
+				},
+			},
+		},
+	}
+
+	// Required variable set by its attributes
+	vs := map[string]string{
+		"subnet.cidr_block": "10.0.1.0/24",
+		"subnet.public":     "true",
+	}
+	if errs := smcUserVariables(c, vs); len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+	if errs := smcVariableValidations(c, vs); len(errs) != 0 {
+		t.Fatalf("err: %#v", errs)
+	}
+
+	// Bad attribute value
+	vs["subnet.public"] = "sometimes"
+	if errs := smcVariableValidations(c, vs); len(errs) == 0 {
+		t.Fatal("should have errors")
+	}
+
+	// Missing attribute
+	delete(vs, "subnet.public")
+	if errs := smcVariableValidations(c, vs); len(errs) == 0 {
+		t.Fatal("should have errors")
+	}
+}
@@ -115,7 +115,14 @@ func (s *State) ModuleOrphans(path []string, c *config.Config) [][]string {
 	// keys.
 	var orphans [][]string
 	for _, m := range s.Children(path) {
-		if _, ok := childrenKeys[m.Path[len(m.Path)-1]]; ok {
+		// Instances of modules with a count have an index suffix. Those
+		// are only orphans if the module is gone altogether here.
+		name := m.Path[len(m.Path)-1]
+		if idx := strings.Index(name, "."); idx != -1 {
+			name = name[:idx]
+		}
+
+		if _, ok := childrenKeys[name]; ok {
 			continue
 		}
 
@@ -632,6 +639,12 @@ type ResourceState struct {
 	// worry about it.
 	Dependencies []string `json:"depends_on,omitempty"`
 
+	// Provider is the full name of the provider configuration used for
+	// this resource, such as "aws.west". It is only set if the resource
+	// was configured with an explicit provider; otherwise the provider
+	// comes from the type, such as "aws" for "aws_instance".
+	Provider string `json:"provider,omitempty"`
+
 	// Primary is the current active instance for this resource.
 	// It can be replaced but only after a successful creation.
 	// This is the instances on which providers will act.
@@ -652,6 +665,10 @@ func (s *ResourceState) Equal(other *ResourceState) bool {
 		return false
 	}
 
+	if s.Provider != other.Provider {
+		return false
+	}
+
 	// Dependencies must be equal
 	sort.Strings(s.Dependencies)
 	sort.Strings(other.Dependencies)
@@ -716,6 +733,44 @@ func (r *ResourceState) Taint() {
 	r.Primary = nil
 }
 
+// Untaint restores the tainted instance at the given index to be the
+// primary instance. If index is -1, the only tainted instance is used,
+// and it is an error if there is more than one. It is also an error if
+// there is already a primary instance, since it would be overwritten.
+func (r *ResourceState) Untaint(index int) error {
+	if len(r.Tainted) == 0 {
+		return fmt.Errorf("Nothing to untaint.")
+	}
+	if r.Primary != nil {
+		return fmt.Errorf(
+			"The resource has a primary instance that would be overwritten\n" +
+				"by untainting. To restore a tainted instance, taint the\n" +
+				"primary instance first.")
+	}
+	if index == -1 {
+		if len(r.Tainted) > 1 {
+			return fmt.Errorf(
+				"There are %d tainted instances for this resource, "+
+					"an index must be given.", len(r.Tainted))
+		}
+
+		index = 0
+	}
+	if index < 0 || index >= len(r.Tainted) {
+		return fmt.Errorf(
+			"Invalid index %d, there are %d tainted instances for this resource.",
+			index, len(r.Tainted))
+	}
+
+	r.Primary = r.Tainted[index]
+	r.Tainted = append(r.Tainted[:index], r.Tainted[index+1:]...)
+	if len(r.Tainted) == 0 {
+		r.Tainted = nil
+	}
+
+	return nil
+}
+
 func (r *ResourceState) init() {
 	if r.Primary == nil {
 		r.Primary = &InstanceState{}
@@ -731,6 +786,7 @@ func (r *ResourceState) deepcopy() *ResourceState {
 	n := &ResourceState{
 		Type:         r.Type,
 		Dependencies: nil,
+		Provider:     r.Provider,
 		Primary:      r.Primary.deepcopy(),
 		Tainted:      nil,
 	}
@@ -790,6 +846,17 @@ type InstanceState struct {
 	// ${resourcetype.name.attribute}.
 	Attributes map[string]string `json:"attributes,omitempty"`
 
+	// Sensitive is the sorted list of keys of Attributes whose values
+	// shouldn't be shown, such as passwords. They're still stored so
+	// that changes can be detected.
+	Sensitive []string `json:"sensitive,omitempty"`
+
+	// Meta is a simple key/value map that is stored in the state but
+	// otherwise ignored by Terraform core. Providers use it to keep
+	// track of data such as the version of the schema that the
+	// attributes were written with.
+	Meta map[string]string `json:"meta,omitempty"`
+
 	// Ephemeral is used to store any state associated with this instance
 	// that is necessary for the Terraform run to complete, but is not
 	// persisted to a state file.
@@ -817,9 +884,30 @@ func (i *InstanceState) deepcopy() *InstanceState {
 			n.Attributes[k] = v
 		}
 	}
+	if i.Sensitive != nil {
+		n.Sensitive = make([]string, len(i.Sensitive))
+		copy(n.Sensitive, i.Sensitive)
+	}
+	if i.Meta != nil {
+		n.Meta = make(map[string]string, len(i.Meta))
+		for k, v := range i.Meta {
+			n.Meta[k] = v
+		}
+	}
 	return n
 }
 
+// IsSensitive returns true if the value of the given attribute
+// shouldn't be shown.
+func (s *InstanceState) IsSensitive(k string) bool {
+	if s == nil {
+		return false
+	}
+
+	i := sort.SearchStrings(s.Sensitive, k)
+	return i < len(s.Sensitive) && s.Sensitive[i] == k
+}
+
 func (s *InstanceState) Equal(other *InstanceState) bool {
 	// Short circuit some nil checks
 	if s == nil || other == nil {
@@ -925,6 +1013,11 @@ type EphemeralState struct {
 	// used to connect to the resource for provisioning. For example,
 	// this could contain SSH or WinRM credentials.
 	ConnInfo map[string]string `json:"-"`
+
+	// Type is the type of the resource, which is set by providers when
+	// importing returns resources of a type that differs from the one
+	// that was requested.
+	Type string `json:"-"`
 }
 
 func (e *EphemeralState) init() {
@@ -937,7 +1030,7 @@ func (e *EphemeralState) deepcopy() *EphemeralState {
 	if e == nil {
 		return nil
 	}
-	n := &EphemeralState{}
+	n := &EphemeralState{Type: e.Type}
 	if e.ConnInfo != nil {
 		n.ConnInfo = make(map[string]string, len(e.ConnInfo))
 		for k, v := range e.ConnInfo {
@@ -322,6 +322,105 @@ func TestResourceStateTaint(t *testing.T) {
 	}
 }
 
+func TestResourceStateUntaint(t *testing.T) {
+	cases := map[string]struct {
+		Input  *ResourceState
+		Index  int
+		Output *ResourceState
+		Err    bool
+	}{
+		"nothing tainted": {
+			&ResourceState{
+				Primary: &InstanceState{ID: "foo"},
+			},
+			-1,
+			nil,
+			true,
+		},
+
+		"one tainted": {
+			&ResourceState{
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "foo"},
+				},
+			},
+			-1,
+			&ResourceState{
+				Primary: &InstanceState{ID: "foo"},
+			},
+			false,
+		},
+
+		"with primary": {
+			&ResourceState{
+				Primary: &InstanceState{ID: "foo"},
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "bar"},
+				},
+			},
+			-1,
+			nil,
+			true,
+		},
+
+		"many tainted, no index": {
+			&ResourceState{
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "foo"},
+					&InstanceState{ID: "bar"},
+				},
+			},
+			-1,
+			nil,
+			true,
+		},
+
+		"many tainted, index": {
+			&ResourceState{
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "foo"},
+					&InstanceState{ID: "bar"},
+				},
+			},
+			1,
+			&ResourceState{
+				Primary: &InstanceState{ID: "bar"},
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "foo"},
+				},
+			},
+			false,
+		},
+
+		"bad index": {
+			&ResourceState{
+				Tainted: []*InstanceState{
+					&InstanceState{ID: "foo"},
+				},
+			},
+			1,
+			nil,
+			true,
+		},
+	}
+
+	for k, tc := range cases {
+		err := tc.Input.Untaint(tc.Index)
+		if (err != nil) != tc.Err {
+			t.Fatalf("Failure: %s\n\nerr: %s", k, err)
+		}
+		if tc.Err {
+			continue
+		}
+
+		if !reflect.DeepEqual(tc.Input, tc.Output) {
+			t.Fatalf(
+				"Failure: %s\n\nExpected: %#v\n\nGot: %#v",
+				k, tc.Output, tc.Input)
+		}
+	}
+}
+
 func TestInstanceStateEqual(t *testing.T) {
 	cases := []struct {
 		Result   bool
@@ -524,6 +623,29 @@ func TestInstanceState_MergeDiff_nilDiff(t *testing.T) {
 	}
 }
 
+func TestInstanceState_IsSensitive(t *testing.T) {
+	is := &InstanceState{
+		ID: "foo",
+		Attributes: map[string]string{
+			"password": "secret",
+			"user":     "root",
+		},
+		Sensitive: []string{"password"},
+	}
+
+	if !is.IsSensitive("password") {
+		t.Fatal("password should be sensitive")
+	}
+	if is.IsSensitive("user") {
+		t.Fatal("user should not be sensitive")
+	}
+
+	is2 := is.MergeDiff(nil)
+	if !is2.IsSensitive("password") {
+		t.Fatal("merged password should be sensitive")
+	}
+}
+
 func TestReadUpgradeState(t *testing.T) {
 	state := &StateV1{
 		Resources: map[string]*ResourceStateV1{
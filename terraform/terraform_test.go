@@ -164,6 +164,26 @@ aws_instance.foo:
   type = aws_instance
 `
 
+const testTerraformApplyDataSourceComputedStr = `
+aws_instance.baz:
+  ID = foo
+  foo = foo
+  type = aws_instance
+
+  Dependencies:
+    data.aws_data_source.bar
+aws_instance.foo:
+  ID = foo
+  num = 2
+  type = aws_instance
+data.aws_data_source.bar:
+  ID = bar
+  foo = foo
+
+  Dependencies:
+    aws_instance.foo
+`
+
 const testTerraformApplyEmptyModuleStr = `
 <no state>
 Outputs:
@@ -388,6 +408,11 @@ aws_instance.bar:
   type = aws_instance
 `
 
+const testTerraformApplyDestroyTargetedPlanFileStr = `
+aws_instance.baz:
+  ID = baz
+`
+
 const testTerraformApplyDestroyStr = `
 <no state>
 `
@@ -583,6 +608,21 @@ STATE:
 <no state>
 `
 
+const testTerraformPlanTargetedStr = `
+DIFF:
+
+CREATE: aws_instance.bar
+  foo:  "" => "2"
+  type: "" => "aws_instance"
+CREATE: aws_instance.foo
+  num:  "" => "2"
+  type: "" => "aws_instance"
+
+STATE:
+
+<no state>
+`
+
 const testTerraformPlanComputedStr = `
 DIFF:
 
@@ -0,0 +1,11 @@
+resource "aws_instance" "foo" {
+    num = "2"
+}
+
+data "aws_data_source" "bar" {
+    foo = "${aws_instance.foo.id}"
+}
+
+resource "aws_instance" "baz" {
+    foo = "${data.aws_data_source.bar.foo}"
+}
@@ -0,0 +1,17 @@
+provider "aws" {
+    region = "east"
+}
+
+provider "aws" {
+    alias = "west"
+    region = "west"
+}
+
+resource "aws_instance" "foo" {
+    num = "2"
+}
+
+resource "aws_instance" "bar" {
+    provider = "aws.west"
+    num = "3"
+}
@@ -0,0 +1,9 @@
+resource "aws_instance" "foo" {}
+
+locals {
+    name = "${aws_instance.foo.id}-web"
+}
+
+resource "aws_instance" "bar" {
+    name = "${local.name}"
+}
@@ -0,0 +1,8 @@
+variable "env" {
+    default = "prod"
+}
+
+locals {
+    name = "web-${var.env}"
+    full = "${local.name}-1"
+}
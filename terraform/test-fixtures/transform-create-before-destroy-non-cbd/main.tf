@@ -0,0 +1,7 @@
+resource "aws_lc" "foo" {}
+
+resource "aws_autoscale" "bar" {
+    lc = "${aws_lc.foo.id}"
+
+    lifecycle { create_before_destroy = true }
+}
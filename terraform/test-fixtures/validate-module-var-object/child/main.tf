@@ -0,0 +1,10 @@
+variable "subnet" {
+    type {
+        cidr_block = "string"
+        public = "bool"
+    }
+}
+
+resource "aws_instance" "foo" {
+    foo = "${var.subnet.cidr_block}"
+}
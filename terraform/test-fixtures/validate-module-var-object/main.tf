@@ -0,0 +1,8 @@
+module "child" {
+    source = "./child"
+
+    subnet {
+        cidr_block = "10.0.1.0/24"
+        public = "maybe"
+    }
+}
@@ -0,0 +1,10 @@
+variable "cidr" {
+    validation {
+        condition = "${length(regexall("/[0-9]+$", var.cidr)) > 0}"
+        error_message = "cidr must include a prefix length"
+    }
+}
+
+resource "aws_instance" "foo" {
+    foo = "${var.cidr}"
+}
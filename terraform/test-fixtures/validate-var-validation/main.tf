@@ -0,0 +1,12 @@
+variable "instance_type" {
+    default = "t2.micro"
+
+    validation {
+        condition = "${var.instance_type == "t2.micro" || var.instance_type == "m3.large"}"
+        error_message = "instance_type must be t2.micro or m3.large"
+    }
+}
+
+resource "aws_instance" "foo" {
+    foo = "${var.instance_type}"
+}
@@ -3,9 +3,11 @@ package terraform
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/config/lang/ast"
 	"github.com/hashicorp/terraform/config/module"
 )
 
@@ -15,6 +17,11 @@ import (
 // Graph.
 type ConfigTransformer struct {
 	Module *module.Tree
+
+	// Variables are the values of the user variables for the root
+	// module. These are used to compute the count of modules, since
+	// that must be known to build the graph.
+	Variables map[string]string
 }
 
 func (t *ConfigTransformer) Transform(g *Graph) error {
@@ -37,7 +44,8 @@ func (t *ConfigTransformer) Transform(g *Graph) error {
 
 	// Create the node list we'll use for the graph
 	nodes := make([]graphNodeConfig, 0,
-		(len(config.ProviderConfigs)+len(config.Modules)+len(config.Resources))*2)
+		(len(config.ProviderConfigs)+len(config.Modules)+len(config.Resources)+
+			len(config.Locals))*2)
 
 	// Write all the provider configs out
 	for _, pc := range config.ProviderConfigs {
@@ -49,6 +57,9 @@ func (t *ConfigTransformer) Transform(g *Graph) error {
 		nodes = append(nodes, &GraphNodeConfigResource{Resource: r})
 	}
 
+	// Err is where the final error value will go if there is one
+	var err error
+
 	// Write all the modules out
 	children := module.Children()
 	for _, m := range config.Modules {
@@ -56,21 +67,30 @@ func (t *ConfigTransformer) Transform(g *Graph) error {
 		copy(path, g.Path)
 		path = append(path, m.Name)
 
+		count, cerr := t.moduleCount(g.Path, m)
+		if cerr != nil {
+			err = multierror.Append(err, cerr)
+			continue
+		}
+
 		nodes = append(nodes, &GraphNodeConfigModule{
 			Path:   path,
 			Module: m,
 			Tree:   children[m.Name],
+			Count:  count,
 		})
 	}
 
+	// Write all the locals out
+	for _, l := range config.Locals {
+		nodes = append(nodes, &GraphNodeConfigLocal{Local: l})
+	}
+
 	// Write all the outputs out
 	for _, o := range config.Outputs {
 		nodes = append(nodes, &GraphNodeConfigOutput{Output: o})
 	}
 
-	// Err is where the final error value will go if there is one
-	var err error
-
 	// Build the graph vertices
 	for _, n := range nodes {
 		g.Add(n)
@@ -90,11 +110,167 @@ func (t *ConfigTransformer) Transform(g *Graph) error {
 	return err
 }
 
+// moduleCount returns the number of instances of the module m that
+// is called from the module at path. The count can only reference user
+// variables whose values are known before anything is applied.
+func (t *ConfigTransformer) moduleCount(path []string, m *config.Module) (int, error) {
+	if m.RawCount == nil {
+		return 1, nil
+	}
+
+	known := t.knownVariables(path)
+	vs := make(map[string]ast.Variable)
+	for n, _ := range m.RawCount.Variables {
+		v, ok := known[n]
+		if !ok {
+			return 0, fmt.Errorf(
+				"module.%s: count must be known before apply, but %s "+
+					"isn't known yet", m.Name, n)
+		}
+
+		vs[n] = ast.Variable{Value: v, Type: ast.TypeString}
+	}
+
+	rc, err := config.NewRawConfig(m.RawCount.Raw)
+	if err != nil {
+		return 0, err
+	}
+	rc.Key = m.RawCount.Key
+	if err := rc.Interpolate(vs); err != nil {
+		return 0, fmt.Errorf("module.%s: count: %s", m.Name, err)
+	}
+
+	instance := *m
+	instance.RawCount = rc
+	count, err := instance.Count()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"module.%s: count must be an integer", m.Name)
+	}
+	if count < 0 {
+		return 0, fmt.Errorf(
+			"module.%s: count must be positive", m.Name)
+	}
+
+	return count, nil
+}
+
+// knownVariables returns the values of the user variables of the module
+// at path that are known before anything is applied, keyed by their
+// full name such as "var.foo". For the root module these come from the
+// defaults and Variables. For child modules they come from the defaults
+// and from the inputs of the parent that only reference known variables.
+// Defaults that reference other variables are known if those are.
+func (t *ConfigTransformer) knownVariables(path []string) map[string]string {
+	result := make(map[string]string)
+	tree := t.Module.Child(path[1:])
+	if tree == nil {
+		return result
+	}
+
+	// set are the values of the variables that are set, keyed by name.
+	// Values that aren't known are UnknownVariableValue so that they
+	// still override the defaults.
+	set := make(map[string]string)
+	if len(path) == 1 {
+		for k, v := range t.Variables {
+			set[k] = v
+		}
+	} else {
+		for k, v := range t.moduleInputs(path) {
+			set[k] = v
+		}
+	}
+
+	defaults, err := tree.Config().VariableDefaults(set)
+	if err != nil {
+		return result
+	}
+	for k, v := range defaults {
+		if v != config.UnknownVariableValue {
+			result[k] = v
+		}
+	}
+	for k, v := range set {
+		if v != config.UnknownVariableValue {
+			result["var."+k] = v
+		} else {
+			delete(result, "var."+k)
+		}
+	}
+
+	return result
+}
+
+// moduleInputs returns the values that the parent of the module at path
+// gives to its variables, keyed by variable name. Values that aren't
+// known before anything is applied are UnknownVariableValue.
+func (t *ConfigTransformer) moduleInputs(path []string) map[string]string {
+	result := make(map[string]string)
+
+	// Find the module block in the parent that calls us
+	parentPath := path[:len(path)-1]
+	name := path[len(path)-1]
+	if idx := strings.Index(name, "."); idx != -1 {
+		name = name[:idx]
+	}
+
+	var call *config.Module
+	if parent := t.Module.Child(parentPath[1:]); parent != nil {
+		for _, m := range parent.Config().Modules {
+			if m.Name == name {
+				call = m
+				break
+			}
+		}
+	}
+	if call == nil {
+		return result
+	}
+
+	parentKnown := t.knownVariables(parentPath)
+	for k, raw := range call.RawConfig.Raw {
+		// Whatever the default was, it is overridden
+		result[k] = config.UnknownVariableValue
+
+		rc, err := config.NewRawConfig(map[string]interface{}{
+			"value": raw,
+		})
+		if err != nil {
+			continue
+		}
+
+		vs := make(map[string]ast.Variable)
+		for n, _ := range rc.Variables {
+			v, ok := parentKnown[n]
+			if !ok {
+				break
+			}
+
+			vs[n] = ast.Variable{Value: v, Type: ast.TypeString}
+		}
+		if len(vs) != len(rc.Variables) {
+			continue
+		}
+
+		if err := rc.Interpolate(vs); err != nil {
+			continue
+		}
+		if v, ok := rc.Config()["value"].(string); ok {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
 // varNameForVar returns the VarName value for an interpolated variable.
 // This value is compared to the VarName() value for the nodes within the
 // graph to build the graph edges.
 func varNameForVar(raw config.InterpolatedVariable) string {
 	switch v := raw.(type) {
+	case *config.LocalVariable:
+		return fmt.Sprintf("local.%s", v.Name)
 	case *config.ModuleVariable:
 		return fmt.Sprintf("module.%s", v.Name)
 	case *config.ResourceVariable:
@@ -58,6 +58,20 @@ func TestConfigTransformer_dependsOn(t *testing.T) {
 	}
 }
 
+func TestConfigTransformer_locals(t *testing.T) {
+	g := Graph{Path: RootModulePath}
+	tf := &ConfigTransformer{Module: testModule(t, "graph-locals")}
+	if err := tf.Transform(&g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testGraphLocalsStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
 func TestConfigTransformer_modules(t *testing.T) {
 	g := Graph{Path: RootModulePath}
 	tf := &ConfigTransformer{Module: testModule(t, "graph-modules")}
@@ -111,6 +125,14 @@ aws_instance.db
 aws_instance.web
 `
 
+const testGraphLocalsStr = `
+aws_instance.bar
+  local.name
+aws_instance.foo
+local.name
+  aws_instance.foo
+`
+
 const testGraphModulesStr = `
 aws_instance.web
   aws_security_group.firewall
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"log"
+
 	"github.com/hashicorp/terraform/dag"
 )
 
@@ -37,6 +39,15 @@ type GraphNodeDestroy interface {
 	CreateNode() dag.Vertex
 }
 
+// GraphNodeDestroyForceable is an interface that destroy nodes can
+// implement to allow create before destroy to be turned on for them even
+// though it wasn't configured. This is done when something that depends
+// on the node is create before destroy, since otherwise the graph has a
+// cycle. ForceCreateBeforeDestroy returns true if it was turned on.
+type GraphNodeDestroyForceable interface {
+	ForceCreateBeforeDestroy() bool
+}
+
 // GraphNodeDestroyPrunable is the interface that can be implemented to
 // signal that this node can be pruned depending on state.
 type GraphNodeDestroyPrunable interface {
@@ -139,9 +150,18 @@ func (t *DestroyTransformer) transform(
 // CreateBeforeDestroyTransformer is a GraphTransformer that modifies
 // the destroys of some nodes so that the creation happens before the
 // destroy.
+//
+// If a node that is create before destroy depends on one that isn't,
+// the dependency is made create before destroy as well. Otherwise the
+// dependency would have to be destroyed before it is created, which must
+// happen before the dependent is created, which must happen before the
+// dependent is destroyed, which in turn must happen before the dependency
+// is destroyed: a cycle.
 type CreateBeforeDestroyTransformer struct{}
 
 func (t *CreateBeforeDestroyTransformer) Transform(g *Graph) error {
+	t.force(g)
+
 	// We "stage" the edge connections/destroys in these slices so that
 	// while we're doing the edge transformations (transpositions) in
 	// the graph, we're not affecting future edge transpositions. These
@@ -196,6 +216,40 @@ func (t *CreateBeforeDestroyTransformer) Transform(g *Graph) error {
 	return nil
 }
 
+// force turns on create before destroy for the destroy nodes that have
+// a create before destroy node depending on them.
+func (t *CreateBeforeDestroyTransformer) force(g *Graph) {
+	var cbd []dag.Vertex
+	for _, v := range g.Vertices() {
+		if dn, ok := v.(GraphNodeDestroy); ok && dn.CreateBeforeDestroy() {
+			cbd = append(cbd, dn.CreateNode())
+		}
+	}
+	if len(cbd) == 0 {
+		return
+	}
+
+	// Everything that the create before destroy nodes depend on. Since
+	// this is transitive, forcing a node never requires forcing more.
+	deps := g.Ancestors(cbd...)
+	for _, v := range g.Vertices() {
+		dn, ok := v.(GraphNodeDestroy)
+		if !ok || dn.CreateBeforeDestroy() {
+			continue
+		}
+		fn, ok := v.(GraphNodeDestroyForceable)
+		if !ok || !deps.Include(dn.CreateNode()) {
+			continue
+		}
+
+		if fn.ForceCreateBeforeDestroy() {
+			log.Printf(
+				"[DEBUG] %s: forcing create_before_destroy, a dependent has it",
+				dag.VertexName(v))
+		}
+	}
+}
+
 // PruneDestroyTransformer is a GraphTransformer that removes the destroy
 // nodes that aren't in the diff.
 type PruneDestroyTransformer struct {
@@ -94,6 +94,60 @@ func TestCreateBeforeDestroyTransformer_twice(t *testing.T) {
 	}
 }
 
+// A create before destroy node that depends on one that isn't forces the
+// dependency to be create before destroy, so the result is the same as if
+// both were configured that way.
+func TestCreateBeforeDestroyTransformer_nonCBD(t *testing.T) {
+	mod := testModule(t, "transform-create-before-destroy-non-cbd")
+
+	g := Graph{Path: RootModulePath}
+	{
+		tf := &ConfigTransformer{Module: mod}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		tf := &DestroyTransformer{}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	{
+		tf := &CreateBeforeDestroyTransformer{}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testTransformCreateBeforeDestroyTwiceStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+
+	if err := g.Validate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The flag is forced on the graph nodes, not in the configuration
+	for _, v := range g.Vertices() {
+		n, ok := v.(*GraphNodeConfigResource)
+		if !ok || n.Resource.Id() != "aws_lc.foo" {
+			continue
+		}
+
+		if !n.createBeforeDestroy() {
+			t.Fatal("should be forced")
+		}
+		if n.Resource.Lifecycle.CreateBeforeDestroy {
+			t.Fatal("should not change config")
+		}
+	}
+}
+
 func TestPruneDestroyTransformer(t *testing.T) {
 	var diff *Diff
 	mod := testModule(t, "transform-destroy-basic")
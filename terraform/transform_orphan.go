@@ -2,6 +2,8 @@ package terraform
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/config/module"
@@ -76,6 +78,8 @@ func (t *OrphanTransformer) Transform(g *Graph) error {
 			resourceVertexes[i] = g.Add(&graphNodeOrphanResource{
 				ResourceName: k,
 				ResourceType: rs.Type,
+				ResourceMode: stateKeyResourceMode(k),
+				Provider:     rs.Provider,
 				dependentOn:  rs.Dependencies,
 			})
 		}
@@ -84,6 +88,7 @@ func (t *OrphanTransformer) Transform(g *Graph) error {
 	// Go over each module orphan and add it to the graph. We store the
 	// vertexes and states outside so that we can connect dependencies later.
 	moduleOrphans := t.State.ModuleOrphans(g.Path, config)
+	moduleOrphans = append(moduleOrphans, t.moduleCountOrphans(g)...)
 	moduleVertexes := make([]dag.Vertex, len(moduleOrphans))
 	for i, path := range moduleOrphans {
 		moduleVertexes[i] = g.Add(&graphNodeOrphanModule{
@@ -109,6 +114,53 @@ func (t *OrphanTransformer) Transform(g *Graph) error {
 	return nil
 }
 
+// moduleCountOrphans returns the paths of the module instances in the
+// state that are no longer in the configuration because the count of a
+// module changed, or because a count was added to or removed from it.
+func (t *OrphanTransformer) moduleCountOrphans(g *Graph) [][]string {
+	modules := make(map[string]*GraphNodeConfigModule)
+	for _, v := range g.Vertices() {
+		if n, ok := v.(*GraphNodeConfigModule); ok {
+			modules[n.Module.Name] = n
+		}
+	}
+
+	var orphans [][]string
+	for _, m := range t.State.Children(g.Path) {
+		name := m.Path[len(m.Path)-1]
+		index := -1
+		if idx := strings.Index(name, "."); idx != -1 {
+			i, err := strconv.ParseInt(name[idx+1:], 0, 0)
+			if err != nil {
+				continue
+			}
+
+			name = name[:idx]
+			index = int(i)
+		}
+
+		n, ok := modules[name]
+		if !ok {
+			// Not in the config at all, so ModuleOrphans has it
+			continue
+		}
+
+		if n.Module.RawCount == nil {
+			if index >= 0 {
+				orphans = append(orphans, m.Path)
+			}
+
+			continue
+		}
+
+		if index < 0 || index >= n.Count {
+			orphans = append(orphans, m.Path)
+		}
+	}
+
+	return orphans
+}
+
 // graphNodeOrphanModule is the graph vertex representing an orphan resource..
 type graphNodeOrphanModule struct {
 	Path []string
@@ -128,6 +180,11 @@ func (n *graphNodeOrphanModule) Name() string {
 	return fmt.Sprintf("%s (orphan)", n.dependableName())
 }
 
+// GraphNodeTargetable impl.
+func (n *graphNodeOrphanModule) TargetName() string {
+	return n.dependableName()
+}
+
 func (n *graphNodeOrphanModule) dependableName() string {
 	return fmt.Sprintf("module.%s", n.Path[len(n.Path)-1])
 }
@@ -149,6 +206,8 @@ func (n *graphNodeOrphanModule) Expand(b GraphBuilder) (GraphNodeSubgraph, error
 type graphNodeOrphanResource struct {
 	ResourceName string
 	ResourceType string
+	ResourceMode config.ResourceMode
+	Provider     string
 
 	dependentOn []string
 }
@@ -165,8 +224,29 @@ func (n *graphNodeOrphanResource) Name() string {
 	return fmt.Sprintf("%s (orphan)", n.ResourceName)
 }
 
+// GraphNodeTargetable impl. Every instance of an orphan with a count is
+// targeted by the name of the resource.
+func (n *graphNodeOrphanResource) TargetName() string {
+	parts := strings.Split(n.ResourceName, ".")
+	size := 3
+	if n.ResourceMode == config.DataResourceMode {
+		size = 4
+	}
+	if len(parts) == size {
+		if _, err := strconv.Atoi(parts[size-1]); err == nil {
+			parts = parts[:size-1]
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
 func (n *graphNodeOrphanResource) ProvidedBy() []string {
-	return []string{resourceProvider(n.ResourceName)}
+	if n.ResourceMode == config.DataResourceMode {
+		return []string{resourceProvider(n.ResourceType, n.Provider)}
+	}
+
+	return []string{resourceProvider(n.ResourceName, n.Provider)}
 }
 
 // GraphNodeEvalable impl.
@@ -180,6 +260,12 @@ func (n *graphNodeOrphanResource) EvalTree() EvalNode {
 	info := &InstanceInfo{Id: n.ResourceName, Type: n.ResourceType}
 	seq.Nodes = append(seq.Nodes, &EvalInstanceInfo{Info: info})
 
+	// Data sources aren't refreshed or destroyed, they're only forgotten
+	if n.ResourceMode == config.DataResourceMode {
+		seq.Nodes = append(seq.Nodes, n.dataResourceEvalNodes(info)...)
+		return seq
+	}
+
 	// Refresh the resource
 	seq.Nodes = append(seq.Nodes, &EvalOpFilter{
 		Ops: []walkOperation{walkRefresh},
@@ -200,10 +286,11 @@ func (n *graphNodeOrphanResource) EvalTree() EvalNode {
 					Output:   &state,
 				},
 				&EvalWriteState{
-					Name:         n.ResourceName,
-					ResourceType: n.ResourceType,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.ResourceName,
+					ResourceType:     n.ResourceType,
+					ResourceProvider: n.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 			},
 		},
@@ -257,10 +344,11 @@ func (n *graphNodeOrphanResource) EvalTree() EvalNode {
 					Output:   &state,
 				},
 				&EvalWriteState{
-					Name:         n.ResourceName,
-					ResourceType: n.ResourceType,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.ResourceName,
+					ResourceType:     n.ResourceType,
+					ResourceProvider: n.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 				&EvalUpdateStateHook{},
 			},
@@ -270,6 +358,76 @@ func (n *graphNodeOrphanResource) EvalTree() EvalNode {
 	return seq
 }
 
+// dataResourceEvalNodes returns the operations for an orphaned data
+// source, which is removed from the state without calling the provider.
+func (n *graphNodeOrphanResource) dataResourceEvalNodes(
+	info *InstanceInfo) []EvalNode {
+	var diff *InstanceDiff
+	var state *InstanceState
+
+	return []EvalNode{
+		&EvalOpFilter{
+			Ops: []walkOperation{walkPlan, walkPlanDestroy},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalReadState{
+						Name:   n.ResourceName,
+						Output: &state,
+					},
+					&EvalDiffDestroy{
+						Info:   info,
+						State:  &state,
+						Output: &diff,
+					},
+					&EvalWriteDiff{
+						Name: n.ResourceName,
+						Diff: &diff,
+					},
+				},
+			},
+		},
+		&EvalOpFilter{
+			Ops: []walkOperation{walkApply},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalReadDiff{
+						Name: n.ResourceName,
+						Diff: &diff,
+					},
+					&EvalIf{
+						If: func(ctx EvalContext) (bool, error) {
+							if diff == nil || !diff.Destroy {
+								return true, EvalEarlyExitError{}
+							}
+
+							return true, nil
+						},
+						Node: EvalNoop{},
+					},
+					&EvalWriteState{
+						Name:             n.ResourceName,
+						ResourceType:     n.ResourceType,
+						ResourceProvider: n.Provider,
+						Dependencies:     n.DependentOn(),
+						State:            &state,
+					},
+					&EvalUpdateStateHook{},
+				},
+			},
+		},
+	}
+}
+
 func (n *graphNodeOrphanResource) dependableName() string {
 	return n.ResourceName
 }
+
+// stateKeyResourceMode returns the mode of the resource with the given
+// key in the state.
+func stateKeyResourceMode(k string) config.ResourceMode {
+	if strings.HasPrefix(k, "data.") {
+		return config.DataResourceMode
+	}
+
+	return config.ManagedResourceMode
+}
@@ -104,6 +104,47 @@ func TestOrphanTransformer_modules(t *testing.T) {
 	}
 }
 
+func TestOrphanTransformer_modulesCount(t *testing.T) {
+	mod := testModule(t, "transform-orphan-modules-count")
+	state := &State{
+		Modules: []*ModuleState{
+			&ModuleState{
+				Path: RootModulePath,
+			},
+			&ModuleState{
+				Path: []string{RootModuleName, "child.0"},
+			},
+			&ModuleState{
+				Path: []string{RootModuleName, "child.1"},
+			},
+
+			// Orphan since the count decreased
+			&ModuleState{
+				Path: []string{RootModuleName, "child.2"},
+			},
+		},
+	}
+
+	g := Graph{Path: RootModulePath}
+	{
+		tf := &ConfigTransformer{Module: mod}
+		if err := tf.Transform(&g); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	transform := &OrphanTransformer{State: state, Module: mod}
+	if err := transform.Transform(&g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testTransformOrphanModulesCountStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
 func TestOrphanTransformer_modulesDeps(t *testing.T) {
 	mod := testModule(t, "transform-orphan-modules")
 	state := &State{
@@ -342,6 +383,16 @@ func TestGraphNodeOrphanResource_ProvidedBy(t *testing.T) {
 	}
 }
 
+func TestGraphNodeOrphanResource_ProvidedBy_alias(t *testing.T) {
+	n := &graphNodeOrphanResource{
+		ResourceName: "aws_instance.foo",
+		Provider:     "aws.west",
+	}
+	if v := n.ProvidedBy(); v[0] != "aws.west" {
+		t.Fatalf("bad: %#v", v)
+	}
+}
+
 const testTransformOrphanBasicStr = `
 aws_instance.db (orphan)
 aws_instance.web
@@ -352,6 +403,11 @@ aws_instance.foo
 module.foo (orphan)
 `
 
+const testTransformOrphanModulesCountStr = `
+module.child
+module.child.2 (orphan)
+`
+
 const testTransformOrphanModulesDepsStr = `
 aws_instance.foo
 module.foo (orphan)
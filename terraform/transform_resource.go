@@ -12,6 +12,11 @@ import (
 type ResourceCountTransformer struct {
 	Resource *config.Resource
 	Destroy  bool
+
+	// CreateBeforeDestroy is true if the resource is created before it
+	// is destroyed. This may be forced on even if the configuration of
+	// the resource doesn't enable it.
+	CreateBeforeDestroy bool
 }
 
 func (t *ResourceCountTransformer) Transform(g *Graph) error {
@@ -40,8 +45,9 @@ func (t *ResourceCountTransformer) Transform(g *Graph) error {
 		// proper node depending on if we're just a destroy node or if
 		// were a regular node.
 		var node dag.Vertex = &graphNodeExpandedResource{
-			Index:    index,
-			Resource: t.Resource,
+			Index:               index,
+			Resource:            t.Resource,
+			CreateBeforeDestroy: t.CreateBeforeDestroy,
 		}
 		if t.Destroy {
 			node = &graphNodeExpandedResourceDestroy{
@@ -65,8 +71,9 @@ func (t *ResourceCountTransformer) Transform(g *Graph) error {
 }
 
 type graphNodeExpandedResource struct {
-	Index    int
-	Resource *config.Resource
+	Index               int
+	Resource            *config.Resource
+	CreateBeforeDestroy bool
 }
 
 func (n *graphNodeExpandedResource) Name() string {
@@ -93,7 +100,7 @@ func (n *graphNodeExpandedResource) DependentOn() []string {
 
 // GraphNodeProviderConsumer
 func (n *graphNodeExpandedResource) ProvidedBy() []string {
-	return []string{resourceProvider(n.Resource.Type)}
+	return []string{resourceProvider(n.Resource.Type, n.Resource.Provider)}
 }
 
 // GraphNodeEvalable impl.
@@ -133,6 +140,7 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 		Config:       &resourceConfig,
 		ResourceName: n.Resource.Name,
 		ResourceType: n.Resource.Type,
+		ResourceMode: n.Resource.Mode,
 	})
 
 	// Validate all the provisioners
@@ -161,6 +169,12 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 	info := n.instanceInfo()
 	seq.Nodes = append(seq.Nodes, &EvalInstanceInfo{Info: info})
 
+	// Data sources are only ever read, so they have their own lifecycle
+	if n.Resource.Mode == config.DataResourceMode {
+		seq.Nodes = append(seq.Nodes, n.dataResourceEvalNodes(info, resource)...)
+		return seq
+	}
+
 	// Refresh the resource
 	seq.Nodes = append(seq.Nodes, &EvalOpFilter{
 		Ops: []walkOperation{walkRefresh},
@@ -181,10 +195,11 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 					Output:   &state,
 				},
 				&EvalWriteState{
-					Name:         n.stateId(),
-					ResourceType: n.Resource.Type,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.stateId(),
+					ResourceType:     n.Resource.Type,
+					ResourceProvider: n.Resource.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 			},
 		},
@@ -213,14 +228,20 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 					Config:      &resourceConfig,
 					Provider:    &provider,
 					State:       &state,
+					Resource:    n.Resource,
 					Output:      &diff,
 					OutputState: &state,
 				},
+				&EvalCheckPreventDestroy{
+					Resource: n.Resource,
+					Diff:     &diff,
+				},
 				&EvalWriteState{
-					Name:         n.stateId(),
-					ResourceType: n.Resource.Type,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.stateId(),
+					ResourceType:     n.Resource.Type,
+					ResourceProvider: n.Resource.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 				&EvalDiffTainted{
 					Diff: &diff,
@@ -248,6 +269,10 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 					State:  &state,
 					Output: &diff,
 				},
+				&EvalCheckPreventDestroy{
+					Resource: n.Resource,
+					Diff:     &diff,
+				},
 				&EvalWriteDiff{
 					Name: n.stateId(),
 					Diff: &diff,
@@ -296,7 +321,7 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 						}
 
 						createBeforeDestroyEnabled =
-							n.Resource.Lifecycle.CreateBeforeDestroy &&
+							n.CreateBeforeDestroy &&
 								destroy
 
 						return createBeforeDestroyEnabled, nil
@@ -325,6 +350,7 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 					Config:   &resourceConfig,
 					Provider: &provider,
 					State:    &state,
+					Resource: n.Resource,
 					Output:   &diffApply,
 				},
 
@@ -359,10 +385,11 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 					CreateNew: &createNew,
 				},
 				&EvalWriteState{
-					Name:         n.stateId(),
-					ResourceType: n.Resource.Type,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.stateId(),
+					ResourceType:     n.Resource.Type,
+					ResourceProvider: n.Resource.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 				&EvalApplyProvisioners{
 					Info:           info,
@@ -398,11 +425,12 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 				&EvalWriteState{
 					Name:                n.stateId(),
 					ResourceType:        n.Resource.Type,
+					ResourceProvider:    n.Resource.Provider,
 					Dependencies:        n.DependentOn(),
 					State:               &state,
 					Tainted:             &tainted,
 					TaintedIndex:        -1,
-					TaintedClearPrimary: !n.Resource.Lifecycle.CreateBeforeDestroy,
+					TaintedClearPrimary: !n.CreateBeforeDestroy,
 				},
 				&EvalApplyPost{
 					Info:  info,
@@ -417,6 +445,214 @@ func (n *graphNodeExpandedResource) EvalTree() EvalNode {
 	return seq
 }
 
+// dataResourceEvalNodes returns the operations for a data source. A data
+// source is read during refresh if its configuration is known by then.
+// Otherwise the plan records a diff and it is read during apply.
+func (n *graphNodeExpandedResource) dataResourceEvalNodes(
+	info *InstanceInfo, resource *Resource) []EvalNode {
+	var diff *InstanceDiff
+	var provider ResourceProvider
+	var resourceConfig *ResourceConfig
+	var state *InstanceState
+
+	// configComputed is true if the configuration can't be fully known
+	// until other resources are applied.
+	configComputed := func() bool {
+		return resourceConfig != nil && len(resourceConfig.ComputedKeys) > 0
+	}
+
+	return []EvalNode{
+		// Read the data source during refresh if we can
+		&EvalOpFilter{
+			Ops: []walkOperation{walkRefresh},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalInterpolate{
+						Config:   n.Resource.RawConfig,
+						Resource: resource,
+						Output:   &resourceConfig,
+					},
+					&EvalIf{
+						If: func(ctx EvalContext) (bool, error) {
+							if configComputed() {
+								return true, EvalEarlyExitError{}
+							}
+
+							return true, nil
+						},
+						Node: EvalNoop{},
+					},
+					&EvalGetProvider{
+						Name:   n.ProvidedBy()[0],
+						Output: &provider,
+					},
+					&EvalReadDataDiff{
+						Info:     info,
+						Config:   &resourceConfig,
+						Provider: &provider,
+						Output:   &diff,
+					},
+					&EvalReadDataApply{
+						Info:     info,
+						Provider: &provider,
+						Diff:     &diff,
+						Output:   &state,
+					},
+					&EvalWriteState{
+						Name:             n.stateId(),
+						ResourceType:     n.Resource.Type,
+						ResourceProvider: n.Resource.Provider,
+						Dependencies:     n.DependentOn(),
+						State:            &state,
+					},
+					&EvalUpdateStateHook{},
+				},
+			},
+		},
+
+		// Plan to read the data source during apply if it wasn't read
+		// during refresh
+		&EvalOpFilter{
+			Ops: []walkOperation{walkPlan},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalInterpolate{
+						Config:   n.Resource.RawConfig,
+						Resource: resource,
+						Output:   &resourceConfig,
+					},
+					&EvalReadState{
+						Name:   n.stateId(),
+						Output: &state,
+					},
+					&EvalIf{
+						If: func(ctx EvalContext) (bool, error) {
+							if !configComputed() && state != nil {
+								return true, EvalEarlyExitError{}
+							}
+
+							return true, nil
+						},
+						Node: EvalNoop{},
+					},
+					&EvalGetProvider{
+						Name:   n.ProvidedBy()[0],
+						Output: &provider,
+					},
+					&EvalReadDataDiff{
+						Info:        info,
+						Config:      &resourceConfig,
+						Provider:    &provider,
+						Output:      &diff,
+						OutputState: &state,
+					},
+					&EvalWriteState{
+						Name:             n.stateId(),
+						ResourceType:     n.Resource.Type,
+						ResourceProvider: n.Resource.Provider,
+						Dependencies:     n.DependentOn(),
+						State:            &state,
+					},
+					&EvalWriteDiff{
+						Name: n.stateId(),
+						Diff: &diff,
+					},
+				},
+			},
+		},
+
+		// Forget the data source when destroying
+		&EvalOpFilter{
+			Ops: []walkOperation{walkPlanDestroy},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalReadState{
+						Name:   n.stateId(),
+						Output: &state,
+					},
+					&EvalDiffDestroy{
+						Info:   info,
+						State:  &state,
+						Output: &diff,
+					},
+					&EvalWriteDiff{
+						Name: n.stateId(),
+						Diff: &diff,
+					},
+				},
+			},
+		},
+
+		// Read the data source, or forget it if the diff is a destroy
+		&EvalOpFilter{
+			Ops: []walkOperation{walkApply},
+			Node: &EvalSequence{
+				Nodes: []EvalNode{
+					&EvalReadDiff{
+						Name: n.stateId(),
+						Diff: &diff,
+					},
+					&EvalIf{
+						If: func(ctx EvalContext) (bool, error) {
+							if diff == nil {
+								return true, EvalEarlyExitError{}
+							}
+
+							return true, nil
+						},
+						Node: EvalNoop{},
+					},
+					&EvalGetProvider{
+						Name:   n.ProvidedBy()[0],
+						Output: &provider,
+					},
+
+					// The configuration is known now, so the diff is
+					// computed again from it.
+					&EvalIf{
+						If: func(ctx EvalContext) (bool, error) {
+							return !diff.Destroy, nil
+						},
+						Node: &EvalSequence{
+							Nodes: []EvalNode{
+								&EvalInterpolate{
+									Config:   n.Resource.RawConfig,
+									Resource: resource,
+									Output:   &resourceConfig,
+								},
+								&EvalReadDataDiff{
+									Info:     info,
+									Config:   &resourceConfig,
+									Provider: &provider,
+									Output:   &diff,
+								},
+							},
+						},
+					},
+					&EvalReadDataApply{
+						Info:     info,
+						Provider: &provider,
+						Diff:     &diff,
+						Output:   &state,
+					},
+					&EvalWriteState{
+						Name:             n.stateId(),
+						ResourceType:     n.Resource.Type,
+						ResourceProvider: n.Resource.Provider,
+						Dependencies:     n.DependentOn(),
+						State:            &state,
+					},
+					&EvalWriteDiff{
+						Name: n.stateId(),
+						Diff: nil,
+					},
+					&EvalUpdateStateHook{},
+				},
+			},
+		},
+	}
+}
+
 // instanceInfo is used for EvalTree.
 func (n *graphNodeExpandedResource) instanceInfo() *InstanceInfo {
 	return &InstanceInfo{Id: n.stateId(), Type: n.Resource.Type}
@@ -490,7 +726,7 @@ func (n *graphNodeExpandedResourceDestroy) EvalTree() EvalNode {
 				&EvalReadState{
 					Name:         n.stateId(),
 					Output:       &state,
-					Tainted:      n.Resource.Lifecycle.CreateBeforeDestroy,
+					Tainted:      n.CreateBeforeDestroy,
 					TaintedIndex: -1,
 				},
 				&EvalRequireState{
@@ -505,10 +741,11 @@ func (n *graphNodeExpandedResourceDestroy) EvalTree() EvalNode {
 					Error:    &err,
 				},
 				&EvalWriteState{
-					Name:         n.stateId(),
-					ResourceType: n.Resource.Type,
-					Dependencies: n.DependentOn(),
-					State:        &state,
+					Name:             n.stateId(),
+					ResourceType:     n.Resource.Type,
+					ResourceProvider: n.Resource.Provider,
+					Dependencies:     n.DependentOn(),
+					State:            &state,
 				},
 				&EvalApplyPost{
 					Info:  info,
@@ -62,6 +62,7 @@ func (t *TaintedTransformer) Transform(g *Graph) error {
 				Index:        i,
 				ResourceName: k,
 				ResourceType: rs.Type,
+				Provider:     rs.Provider,
 			})
 		}
 	}
@@ -74,6 +75,7 @@ type graphNodeTaintedResource struct {
 	Index        int
 	ResourceName string
 	ResourceType string
+	Provider     string
 }
 
 func (n *graphNodeTaintedResource) Name() string {
@@ -81,7 +83,7 @@ func (n *graphNodeTaintedResource) Name() string {
 }
 
 func (n *graphNodeTaintedResource) ProvidedBy() []string {
-	return []string{resourceProvider(n.ResourceName)}
+	return []string{resourceProvider(n.ResourceName, n.Provider)}
 }
 
 // GraphNodeEvalable impl.
@@ -118,11 +120,12 @@ func (n *graphNodeTaintedResource) EvalTree() EvalNode {
 					Output:   &state,
 				},
 				&EvalWriteState{
-					Name:         n.ResourceName,
-					ResourceType: n.ResourceType,
-					State:        &state,
-					Tainted:      &tainted,
-					TaintedIndex: n.Index,
+					Name:             n.ResourceName,
+					ResourceType:     n.ResourceType,
+					ResourceProvider: n.Provider,
+					State:            &state,
+					Tainted:          &tainted,
+					TaintedIndex:     n.Index,
 				},
 			},
 		},
@@ -157,11 +160,12 @@ func (n *graphNodeTaintedResource) EvalTree() EvalNode {
 					Output:   &state,
 				},
 				&EvalWriteState{
-					Name:         n.ResourceName,
-					ResourceType: n.ResourceType,
-					State:        &state,
-					Tainted:      &tainted,
-					TaintedIndex: n.Index,
+					Name:             n.ResourceName,
+					ResourceType:     n.ResourceType,
+					ResourceProvider: n.Provider,
+					State:            &state,
+					Tainted:          &tainted,
+					TaintedIndex:     n.Index,
 				},
 				&EvalUpdateStateHook{},
 			},
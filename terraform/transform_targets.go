@@ -0,0 +1,196 @@
+package terraform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+// GraphNodeTargetable is an interface that can be implemented by nodes
+// that can be targeted with -target, such as resources and modules.
+// When targeting, targetable nodes that aren't targeted and aren't needed
+// by a target are removed from the graph. Nodes that aren't targetable,
+// such as providers, are always kept.
+type GraphNodeTargetable interface {
+	// TargetName is the name used to target the node, such as
+	// "aws_instance.foo" or "module.foo".
+	TargetName() string
+}
+
+// TargetsTransformer is a GraphTransformer that, when the user specifies
+// a list of resources to target, limits the graph to only those resources
+// and what they depend on. When destroying, it's limited to the targeted
+// resources and what depends on them instead, since those must be
+// destroyed first.
+//
+// A target is the name of a resource, such as "aws_instance.foo", of a
+// data source, such as "data.aws_ami.foo", or of a module, such as
+// "module.foo", optionally within modules, such as
+// "module.foo.aws_instance.bar". Targeting a module targets everything
+// within it.
+type TargetsTransformer struct {
+	Targets []string
+	Destroy bool
+}
+
+func (t *TargetsTransformer) Transform(g *Graph) error {
+	if len(t.Targets) == 0 {
+		return nil
+	}
+
+	targets, err := parseTargets(t.Targets)
+	if err != nil {
+		return err
+	}
+
+	// Find the names of the nodes that are targeted in this graph. If
+	// nothing targets anything within this module, then it is only here
+	// because something needs it, so we leave the whole graph.
+	names := make(map[string]struct{})
+	path := normalizeTargetPath(g.Path[1:])
+	for _, target := range targets {
+		name, whole := target.match(path)
+		if whole {
+			return nil
+		}
+		if name != "" {
+			names[name] = struct{}{}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Find the targeted nodes and everything that they need
+	var targeted []dag.Vertex
+	for _, v := range g.Vertices() {
+		if tv, ok := v.(GraphNodeTargetable); ok {
+			if _, ok := names[tv.TargetName()]; ok {
+				targeted = append(targeted, v)
+			}
+		}
+	}
+
+	keep := g.Ancestors(targeted...)
+	if t.Destroy {
+		keep = g.Descendants(targeted...)
+	}
+	for _, v := range targeted {
+		keep.Add(v)
+	}
+
+	// Remove everything else that is targetable
+	for _, v := range g.Vertices() {
+		if _, ok := v.(GraphNodeTargetable); ok && !keep.Include(v) {
+			g.Remove(v)
+		}
+	}
+
+	return nil
+}
+
+// resourceTarget is a parsed -target value.
+type resourceTarget struct {
+	// Path is the path of module names to the target, not including the
+	// root module.
+	Path []string
+
+	// Name is the TargetName of the target within the module at Path,
+	// or empty if the whole module is targeted.
+	Name string
+}
+
+func parseTargets(raw []string) ([]*resourceTarget, error) {
+	result := make([]*resourceTarget, 0, len(raw))
+	for _, r := range raw {
+		parts := strings.Split(r, ".")
+		target := new(resourceTarget)
+		for len(parts) >= 2 && parts[0] == "module" {
+			target.Path = append(target.Path, parts[1])
+			parts = parts[2:]
+		}
+
+		switch len(parts) {
+		case 0:
+			if len(target.Path) == 0 {
+				return nil, fmt.Errorf("Invalid target: %q", r)
+			}
+		case 2:
+			if parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("Invalid target: %q", r)
+			}
+
+			target.Name = strings.Join(parts, ".")
+		case 3:
+			if parts[0] != "data" || parts[1] == "" || parts[2] == "" {
+				return nil, fmt.Errorf("Invalid target: %q", r)
+			}
+
+			target.Name = strings.Join(parts, ".")
+		default:
+			return nil, fmt.Errorf(
+				"Invalid target %q: must be a resource such as "+
+					"\"aws_instance.foo\" or a module such as \"module.foo\"", r)
+		}
+
+		result = append(result, target)
+	}
+
+	return result, nil
+}
+
+// match returns the TargetName of the node that this target targets in
+// the graph of the module at path, if any. whole is true if the target
+// covers the whole module, in which case nothing in the graph should be
+// removed.
+func (t *resourceTarget) match(path []string) (name string, whole bool) {
+	// A target that isn't within this module doesn't target anything
+	// in this graph, unless it targets a module that we're in.
+	if !targetPathPrefix(path, t.Path) {
+		return "", t.Name == "" && targetPathPrefix(t.Path, path)
+	}
+
+	// A target within one of our child modules targets that module.
+	if len(t.Path) > len(path) {
+		return fmt.Sprintf("module.%s", t.Path[len(path)]), false
+	}
+
+	// The target is in this module. If it is the module itself, the
+	// whole graph is targeted.
+	return t.Name, t.Name == ""
+}
+
+// targetPathPrefix returns true if prefix is a prefix of path.
+func targetPathPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for i, p := range prefix {
+		if path[i] != p {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeTargetPath returns the module path with the index of the
+// instances of modules with a count removed, so that targeting a module
+// targets all its instances.
+func normalizeTargetPath(path []string) []string {
+	result := make([]string, len(path))
+	for i, p := range path {
+		if idx := strings.LastIndex(p, "."); idx != -1 {
+			if _, err := strconv.Atoi(p[idx+1:]); err == nil {
+				p = p[:idx]
+			}
+		}
+
+		result[i] = p
+	}
+
+	return result
+}
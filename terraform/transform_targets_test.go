@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+func TestTargetsTransformer(t *testing.T) {
+	g := testTargetsGraph(RootModulePath)
+	tf := &TargetsTransformer{Targets: []string{"aws_instance.web"}}
+	if err := tf.Transform(g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testTransformTargetsBasicStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestTargetsTransformer_destroy(t *testing.T) {
+	g := testTargetsGraph(RootModulePath)
+	tf := &TargetsTransformer{
+		Targets: []string{"aws_instance.db"},
+		Destroy: true,
+	}
+	if err := tf.Transform(g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testTransformTargetsDestroyStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+func TestTargetsTransformer_module(t *testing.T) {
+	cases := []struct {
+		Path     []string
+		Targets  []string
+		Expected string
+	}{
+		// A target in a child module keeps the module and what it needs
+		{
+			RootModulePath,
+			[]string{"module.child.aws_instance.foo"},
+			testTransformTargetsModuleStr,
+		},
+
+		// Within the module, only the target is kept
+		{
+			[]string{"root", "child"},
+			[]string{"module.child.aws_instance.web"},
+			testTransformTargetsBasicStr,
+		},
+
+		// Every instance of a module with a count is targeted
+		{
+			[]string{"root", "child.1"},
+			[]string{"module.child.aws_instance.web"},
+			testTransformTargetsBasicStr,
+		},
+
+		// Targeting the module keeps everything in it
+		{
+			[]string{"root", "child", "grandchild"},
+			[]string{"module.child"},
+			testTransformTargetsAllStr,
+		},
+
+		// A module that nothing targets is only here because it is
+		// needed, so everything in it is kept
+		{
+			[]string{"root", "other"},
+			[]string{"module.child.aws_instance.web"},
+			testTransformTargetsAllStr,
+		},
+	}
+
+	for i, tc := range cases {
+		g := testTargetsGraph(tc.Path)
+		tf := &TargetsTransformer{Targets: tc.Targets}
+		if err := tf.Transform(g); err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		actual := strings.TrimSpace(g.String())
+		expected := strings.TrimSpace(tc.Expected)
+		if actual != expected {
+			t.Fatalf("%d: bad:\n\n%s", i, actual)
+		}
+	}
+}
+
+func TestTargetsTransformer_invalid(t *testing.T) {
+	invalid := []string{
+		"aws_instance",
+		"aws_instance.foo.bar",
+		"module",
+		"aws_instance.",
+	}
+
+	for _, target := range invalid {
+		g := testTargetsGraph(RootModulePath)
+		tf := &TargetsTransformer{Targets: []string{target}}
+		if err := tf.Transform(g); err == nil {
+			t.Fatalf("%s: should error", target)
+		}
+	}
+}
+
+// testTargetsGraph returns a graph where aws_instance.web depends on
+// aws_instance.db and the provider, aws_instance.db depends on the
+// provider, and aws_instance.other and module.child don't depend on
+// anything.
+func testTargetsGraph(path []string) *Graph {
+	g := &Graph{Path: path}
+	provider := g.Add("provider.aws")
+	web := g.Add(&testTargetable{"aws_instance.web"})
+	db := g.Add(&testTargetable{"aws_instance.db"})
+	g.Add(&testTargetable{"aws_instance.other"})
+	g.Add(&testTargetable{"module.child"})
+	g.Connect(dag.BasicEdge(web, db))
+	g.Connect(dag.BasicEdge(web, provider))
+	g.Connect(dag.BasicEdge(db, provider))
+	return g
+}
+
+type testTargetable struct {
+	NameValue string
+}
+
+func (n *testTargetable) Name() string {
+	return n.NameValue
+}
+
+func (n *testTargetable) TargetName() string {
+	return n.NameValue
+}
+
+const testTransformTargetsBasicStr = `
+aws_instance.db
+  provider.aws
+aws_instance.web
+  aws_instance.db
+  provider.aws
+provider.aws
+`
+
+const testTransformTargetsDestroyStr = `
+aws_instance.db
+  provider.aws
+aws_instance.web
+  aws_instance.db
+  provider.aws
+provider.aws
+`
+
+const testTransformTargetsModuleStr = `
+module.child
+provider.aws
+`
+
+const testTransformTargetsAllStr = `
+aws_instance.db
+  provider.aws
+aws_instance.other
+aws_instance.web
+  aws_instance.db
+  provider.aws
+module.child
+provider.aws
+`
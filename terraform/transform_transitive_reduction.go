@@ -0,0 +1,22 @@
+package terraform
+
+// TransitiveReductionTransformer is a GraphTransformer that performs
+// the transitive reduction of the graph, removing any dependency edge
+// that is already implied by other edges. This doesn't change the order
+// the graph is walked in, but makes it faster to walk and easier to read.
+//
+// The graph must be valid before this transform runs, so it should
+// generally be the last step.
+type TransitiveReductionTransformer struct{}
+
+func (t *TransitiveReductionTransformer) Transform(g *Graph) error {
+	// The reduction is undefined for an invalid graph, so leave it as
+	// is. The graph builder validates the graph after all its steps and
+	// reports the error there.
+	if err := g.Validate(); err != nil {
+		return nil
+	}
+
+	g.TransitiveReduction()
+	return nil
+}
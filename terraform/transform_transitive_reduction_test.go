@@ -0,0 +1,37 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+func TestTransitiveReductionTransformer(t *testing.T) {
+	g := Graph{Path: RootModulePath}
+	g.Add("a")
+	g.Add("b")
+	g.Add("provider")
+	g.Connect(dag.BasicEdge("a", "b"))
+	g.Connect(dag.BasicEdge("a", "provider"))
+	g.Connect(dag.BasicEdge("b", "provider"))
+
+	tf := &TransitiveReductionTransformer{}
+	if err := tf.Transform(&g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := strings.TrimSpace(g.String())
+	expected := strings.TrimSpace(testTransformTransitiveReductionStr)
+	if actual != expected {
+		t.Fatalf("bad:\n\n%s", actual)
+	}
+}
+
+const testTransformTransitiveReductionStr = `
+a
+  b
+b
+  provider
+provider
+`
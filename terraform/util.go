@@ -46,8 +46,14 @@ func (s Semaphore) Release() {
 	}
 }
 
-// resourceProvider returns the provider name for the given type.
-func resourceProvider(t string) string {
+// resourceProvider returns the provider name for the given type. If an
+// explicit provider is given, such as "aws.west" from the configuration
+// or state of the resource, then that is returned instead.
+func resourceProvider(t, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+
 	idx := strings.IndexRune(t, '_')
 	if idx == -1 {
 		return ""